@@ -1,7 +1,7 @@
 package chain
 
 import (
-	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/crypto/ed25519"
 	"github.com/vitelabs/go-vite/ledger"
@@ -77,7 +77,7 @@ func (c *chain) newAccountId() (uint64, error) {
 	return lastAccountId + 1, nil
 }
 
-func (c *chain) createAccount(batch *leveldb.Batch, accountId uint64, address *types.Address, publicKey ed25519.PublicKey) (*ledger.Account, error) {
+func (c *chain) createAccount(batch *database.Batch, accountId uint64, address *types.Address, publicKey ed25519.PublicKey) (*ledger.Account, error) {
 	account := &ledger.Account{
 		AccountAddress: *address,
 		AccountId:      accountId,
@@ -5,7 +5,7 @@ import (
 	"math/big"
 
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/monitor"
@@ -33,7 +33,7 @@ func (c *chain) InsertAccountBlocks(vmAccountBlocks []*vm_context.VmAccountBlock
 
 	monitor.LogEventNum("chain", "InsertAccountBlocks", len(vmAccountBlocks))
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 
 	trieSaveCallback := make([]func(), 0)
 	var account *ledger.Account
@@ -425,7 +425,7 @@ func (c *chain) GetAccountBlockByHeight(addr *types.Address, height uint64) (*le
 
 	block, err := c.chainDb.Ac.GetBlockByHeight(account.AccountId, height)
 	if err != nil {
-		if err == leveldb.ErrNotFound {
+		if err == database.ErrNotFound {
 			return nil, nil
 		}
 
@@ -441,6 +441,17 @@ func (c *chain) GetAccountBlockByHeight(addr *types.Address, height uint64) (*le
 	return block, nil
 }
 
+// GetArchivedAccountBlockByHash reads the block from the db, then from the compressed ledger files if ArchiveFallback
+// is set. The offset indexes of the files are searched one by one, so it's for the historical queries of rpc only,
+// the hot paths use GetAccountBlockByHash. The blocks read from the files have no meta.
+func (c *chain) GetArchivedAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error) {
+	block, err := c.GetAccountBlockByHash(blockHash)
+	if err != nil || block != nil || !c.cfg.ArchiveFallback {
+		return block, err
+	}
+	return c.compressor.GetAccountBlockByHash(blockHash)
+}
+
 // With block meta
 func (c *chain) GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error) {
 	monitorTags := []string{"chain", "GetAccountBlockByHash"}
@@ -450,11 +461,7 @@ func (c *chain) GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlo
 	}
 
 	block, err := c.chainDb.Ac.GetBlock(blockHash)
-	if err != nil {
-		if err == leveldb.ErrNotFound {
-			return nil, nil
-		}
-
+	if err != nil && err != database.ErrNotFound {
 		c.log.Error("Query block failed. Error is "+err.Error(), "method", "GetAccountBlockByHash")
 		return nil, err
 	}
@@ -646,7 +653,7 @@ func (c *chain) DeleteAccountBlocks(addr *types.Address, toHeight uint64) (map[t
 		return nil, getErr
 	}
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 	deleteAccountBlocks, deleteAccountBlocksErr := c.chainDb.Ac.Delete(batch, deleteMap)
 	if len(deleteAccountBlocks) <= 0 {
 		return nil, nil
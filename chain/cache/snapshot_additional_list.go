@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
@@ -234,7 +232,7 @@ func (al *AdditionList) build() error {
 	return nil
 }
 
-func (al *AdditionList) flush(batch *leveldb.Batch, isLock bool) error {
+func (al *AdditionList) flush(batch *database.Batch, isLock bool) error {
 	if isLock {
 		al.modifyLock.Lock()
 		defer al.modifyLock.Unlock()
@@ -295,7 +293,7 @@ func (al *AdditionList) flush(batch *leveldb.Batch, isLock bool) error {
 	return nil
 }
 
-func (al *AdditionList) clearStaleData(batch *leveldb.Batch) {
+func (al *AdditionList) clearStaleData(batch *database.Batch) {
 	count := len(al.list)
 	if count <= 0 {
 		return
@@ -330,22 +328,22 @@ func (al *AdditionList) clearStaleData(batch *leveldb.Batch) {
 	al.list = al.list[needClearCount:]
 }
 
-func (al *AdditionList) deleteAllFrags(batch *leveldb.Batch) error {
+func (al *AdditionList) deleteAllFrags(batch *database.Batch) error {
 	isCommit := false
 	if batch == nil {
 		isCommit = true
-		batch = new(leveldb.Batch)
+		batch = new(database.Batch)
 	}
 
 	db := al.chain.ChainDb().Db()
-	iter := db.NewIterator(util.BytesPrefix([]byte{database.DBKP_ADDITIONAL_LIST}), nil)
+	iter := db.NewIterator(database.BytesPrefix([]byte{database.DBKP_ADDITIONAL_LIST}))
 	defer iter.Release()
 
 	for iter.Next() {
 		batch.Delete(iter.Key())
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return err
 	}
 	if isCommit {
@@ -354,11 +352,11 @@ func (al *AdditionList) deleteAllFrags(batch *leveldb.Batch) error {
 	return nil
 }
 
-func (al *AdditionList) deleteFrags(batch *leveldb.Batch, fragments []*Fragment) error {
+func (al *AdditionList) deleteFrags(batch *database.Batch, fragments []*Fragment) error {
 	isCommit := false
 	if batch == nil {
 		isCommit = true
-		batch = new(leveldb.Batch)
+		batch = new(database.Batch)
 	}
 
 	for _, fragment := range fragments {
@@ -372,11 +370,11 @@ func (al *AdditionList) deleteFrags(batch *leveldb.Batch, fragments []*Fragment)
 	return nil
 }
 
-func (al *AdditionList) saveFrag(batch *leveldb.Batch, fragment *Fragment) error {
+func (al *AdditionList) saveFrag(batch *database.Batch, fragment *Fragment) error {
 	isCommit := false
 	if batch == nil {
 		isCommit = true
-		batch = new(leveldb.Batch)
+		batch = new(database.Batch)
 	}
 
 	key := fragment.GetDbKey()
@@ -395,21 +393,21 @@ func (al *AdditionList) saveFrag(batch *leveldb.Batch, fragment *Fragment) error
 func (al *AdditionList) clearDb() error {
 	db := al.chain.ChainDb().Db()
 
-	iter := db.NewIterator(util.BytesPrefix([]byte{database.DBKP_ADDITIONAL_LIST}), nil)
+	iter := db.NewIterator(database.BytesPrefix([]byte{database.DBKP_ADDITIONAL_LIST}))
 	defer iter.Release()
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 	for iter.Next() {
 		batch.Delete(iter.Key())
 	}
 
-	return db.Write(batch, nil)
+	return db.Write(batch)
 }
 
 func (al *AdditionList) loadFromDb() error {
 	db := al.chain.ChainDb().Db()
 
-	iter := db.NewIterator(util.BytesPrefix([]byte{database.DBKP_ADDITIONAL_LIST}), nil)
+	iter := db.NewIterator(database.BytesPrefix([]byte{database.DBKP_ADDITIONAL_LIST}))
 	defer iter.Release()
 
 	var frags []*Fragment
@@ -448,7 +446,7 @@ func (al *AdditionList) loadFromDb() error {
 		lastHeight = frag.HeadHeight
 	}
 	if err := iter.Error(); err != nil &&
-		err != leveldb.ErrNotFound {
+		err != database.ErrNotFound {
 		return err
 	}
 	al.frags = frags
@@ -495,7 +493,7 @@ func (al *AdditionList) Add(block *ledger.SnapshotBlock, quota uint64) {
 	al.add(block, quota)
 }
 
-func (al *AdditionList) DeleteStartWith(batch *leveldb.Batch, block *ledger.SnapshotBlock) error {
+func (al *AdditionList) DeleteStartWith(batch *database.Batch, block *ledger.SnapshotBlock) error {
 	al.modifyLock.Lock()
 	defer al.modifyLock.Unlock()
 
@@ -3,12 +3,12 @@ package chain
 import (
 	"fmt"
 	"github.com/pkg/errors"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain/cache"
 	"github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/chain/sender"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/config"
 	"github.com/vitelabs/go-vite/ledger"
@@ -27,6 +27,9 @@ type chain struct {
 	chainDb    *chain_db.ChainDb
 	compressor *compress.Compressor
 
+	// store of the ledger, nil means a LevelDB in the ledger directory
+	store database.Store
+
 	trieNodePool  *trie.TrieNodePool
 	stateTriePool *StateTriePool
 
@@ -44,7 +47,7 @@ type chain struct {
 
 	cfg         *config.Chain
 	globalCfg   *config.Config
-	kafkaSender *sender.KafkaSender
+	eventSender *sender.Sender
 	trieGc      trie_gc.Collector
 
 	saveTrieLock sync.RWMutex
@@ -52,8 +55,10 @@ type chain struct {
 	saveTrieStatus     uint8
 	saveTrieStatusLock sync.Mutex
 
-	saList *chain_cache.AdditionList
-	fti    *chain_index.FilterTokenIndex
+	saList       *chain_cache.AdditionList
+	indexManager *chain_index.Manager
+	fti          *chain_index.FilterTokenIndex
+	flatState    *flatState
 }
 
 func NewChain(cfg *config.Config) Chain {
@@ -70,13 +75,13 @@ func NewChain(cfg *config.Config) Chain {
 		chain.cfg = &config.Chain{}
 	}
 
-	if chain.cfg.OpenFilterTokenIndex {
-		var err error
-		chain.fti, err = chain_index.NewFilterTokenIndex(cfg, chain)
-		if err != nil {
-			chain.log.Crit("NewFilterTokenIndex failed, error is "+err.Error(), "method", "NewChain")
-			return nil
-		}
+	if err := chain.initIndexes(); err != nil {
+		chain.log.Crit("initIndexes failed, error is "+err.Error(), "method", "NewChain")
+		return nil
+	}
+
+	if chain.cfg.OpenFlatState {
+		chain.flatState = newFlatState(chain)
 	}
 
 	chain.needSnapshotCache = chain_cache.NewNeedSnapshotCache(chain)
@@ -89,6 +94,17 @@ func NewChain(cfg *config.Config) Chain {
 	return chain
 }
 
+// NewChainWithStore creates a chain which keeps the ledger in store, the ledger files and the indexes are still in
+// cfg.DataDir. Unit tests use it with database.NewMemStore.
+func NewChainWithStore(cfg *config.Config, store database.Store) Chain {
+	c := NewChain(cfg)
+	if c == nil {
+		return nil
+	}
+	c.(*chain).store = store
+	return c
+}
+
 func (c *chain) Init() {
 	// Start initialize
 	c.log.Info("Init chain module")
@@ -100,12 +116,24 @@ func (c *chain) Init() {
 	c.em = newEventManager()
 
 	// chainDb
-	chainDb := chain_db.NewChainDb(filepath.Join(c.dataDir, c.ledgerDirName))
+	var chainDb *chain_db.ChainDb
+	if c.store != nil {
+		chainDb = chain_db.NewChainDbWithStore(c.store)
+	} else {
+		chainDb = chain_db.NewChainDb(filepath.Join(c.dataDir, c.ledgerDirName))
+	}
 	if chainDb == nil {
 		c.log.Crit("NewChain failed, db init failed", "method", "Init")
 	}
 	c.chainDb = chainDb
 
+	// flat state
+	if c.flatState != nil {
+		if err := c.flatState.loadVersion(); err != nil {
+			c.log.Crit("flatState.loadVersion failed, error is "+err.Error(), "method", "Init")
+		}
+	}
+
 	// cache
 	c.initCache()
 
@@ -121,22 +149,23 @@ func (c *chain) Init() {
 
 	// compressor
 	compressor := compress.NewCompressor(c, c.dataDir)
+	compressor.SetVerifyFileHash(c.cfg.VerifyLedgerFiles)
 	c.compressor = compressor
 
-	// kafka sender
-	if len(c.cfg.KafkaProducers) > 0 {
-		var newKafkaErr error
-		c.kafkaSender, newKafkaErr = sender.NewKafkaSender(c, filepath.Join(c.dataDir, "ledger_mq"))
-		if newKafkaErr != nil {
-			c.log.Crit("NewKafkaSender failed, error is " + newKafkaErr.Error())
+	// event sender
+	if len(c.cfg.KafkaProducers) > 0 || len(c.cfg.EventSinks) > 0 {
+		var newSenderErr error
+		c.eventSender, newSenderErr = sender.NewSender(c, filepath.Join(c.dataDir, "ledger_mq"))
+		if newSenderErr != nil {
+			c.log.Crit("NewSender failed, error is " + newSenderErr.Error())
 		}
 	}
 	// Finish initialize
 	c.log.Info("Chain module initialized")
 }
 
-func (c *chain) KafkaSender() *sender.KafkaSender {
-	return c.kafkaSender
+func (c *chain) Sender() *sender.Sender {
+	return c.eventSender
 }
 
 func (c *chain) checkData() bool {
@@ -233,6 +262,12 @@ func (c *chain) clearData() {
 	if err2 != nil {
 		c.log.Crit("ChainDb clear data failed, error is " + err2.Error())
 	}
+	// flat state clear
+	if c.flatState != nil {
+		if err := c.flatState.loadVersion(); err != nil {
+			c.log.Crit("flatState.loadVersion failed, error is " + err.Error())
+		}
+	}
 }
 
 func (c *chain) initData() {
@@ -314,6 +349,50 @@ func (c *chain) Fti() *chain_index.FilterTokenIndex {
 	return c.fti
 }
 
+func (c *chain) IndexManager() *chain_index.Manager {
+	return c.indexManager
+}
+
+// initIndexes registers the opened indexes, the index manager isn't created when no index is opened.
+func (c *chain) initIndexes() error {
+	var indexes []chain_index.Index
+	if c.cfg.OpenFilterTokenIndex {
+		c.fti = chain_index.NewFilterTokenIndex(c)
+		indexes = append(indexes, c.fti)
+	}
+	if c.cfg.OpenCounterpartyIndex {
+		indexes = append(indexes, chain_index.NewCounterpartyIndex())
+	}
+	if c.cfg.OpenVmLogIndex {
+		indexes = append(indexes, chain_index.NewVmLogIndex(c))
+	}
+	if c.cfg.OpenTimeIndex {
+		indexes = append(indexes, chain_index.NewTimeIndex(c))
+	}
+	if c.cfg.OpenTokenHolderIndex {
+		// the index is built from the states of all the snapshot blocks, the ledger gc prunes them
+		if c.cfg.LedgerGc {
+			return errors.New("OpenTokenHolderIndex needs LedgerGc to be false")
+		}
+		indexes = append(indexes, chain_index.NewTokenHolderIndex(c))
+	}
+
+	if len(indexes) <= 0 {
+		return nil
+	}
+
+	var err error
+	if c.indexManager, err = chain_index.NewManager(filepath.Join(c.dataDir, "ledger_index"), c); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if err := c.indexManager.Register(index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *chain) Start() {
 	// saList start
 	c.saList.Start()
@@ -327,14 +406,32 @@ func (c *chain) Start() {
 	// start compressor
 	c.compressor.Start()
 
-	// start kafka sender
-	if c.kafkaSender != nil {
+	// start event sender
+	if c.eventSender != nil {
 		for _, producer := range c.cfg.KafkaProducers {
-			startErr := c.kafkaSender.Start(producer.BrokerList, producer.Topic)
+			if producer == nil {
+				continue
+			}
+			startErr := c.eventSender.Start(&sender.SinkConfig{
+				Kind:       sender.SinkKafka,
+				Format:     producer.Format,
+				BrokerList: producer.BrokerList,
+				Topic:      producer.Topic,
+			})
 			if startErr != nil {
 				c.log.Crit("Start kafka sender failed, error is " + startErr.Error())
 			}
 		}
+		for _, eventSink := range c.cfg.EventSinks {
+			startErr := c.eventSender.Start(&sender.SinkConfig{
+				Kind: eventSink.Kind,
+				Path: eventSink.Path,
+				Url:  eventSink.Url,
+			})
+			if startErr != nil {
+				c.log.Crit("Start event sender failed, error is " + startErr.Error())
+			}
+		}
 	}
 
 	// check trie
@@ -349,20 +446,29 @@ func (c *chain) Start() {
 		c.TrieGc().Start()
 	}
 
-	// start build filter token index
-	if c.fti != nil {
-		fmt.Printf("FilterTokenIndex is being initialized...\n")
-		c.fti.Start()
-		fmt.Printf("FilterTokenIndex initialization complete\n")
+	// rebuild flat state
+	if c.flatState != nil && !c.flatState.isSynced() {
+		fmt.Printf("FlatState is being rebuilt...\n")
+		if err := c.flatState.Rebuild(); err != nil {
+			c.log.Crit("flatState.Rebuild failed, error is "+err.Error(), "method", "Start")
+		}
+		fmt.Printf("FlatState rebuild complete\n")
+	}
+
+	// start build indexes
+	if c.indexManager != nil {
+		fmt.Printf("Indexes are being initialized...\n")
+		c.indexManager.Start()
+		fmt.Printf("Indexes initialization complete\n")
 	}
 
 	c.log.Info("Chain module started")
 }
 
 func (c *chain) Stop() {
-	// stop build filter token index
-	if c.fti != nil {
-		c.fti.Stop()
+	// stop build indexes
+	if c.indexManager != nil {
+		c.indexManager.Stop()
 	}
 
 	// saList top
@@ -378,9 +484,9 @@ func (c *chain) Stop() {
 	// stop compressor
 	c.compressor.Stop()
 
-	// stop kafka sender
-	if c.kafkaSender != nil {
-		c.kafkaSender.StopAll()
+	// stop event sender
+	if c.eventSender != nil {
+		c.eventSender.StopAll()
 	}
 
 	c.log.Info("Chain module stopped")
@@ -395,6 +501,12 @@ func (c *chain) Destroy() {
 	c.chainDb.Db().Close()
 	c.chainDb = nil
 
+	// close index db
+	if c.indexManager != nil {
+		c.indexManager.Close()
+		c.indexManager = nil
+	}
+
 	// compressor
 	c.compressor = nil
 
@@ -404,8 +516,8 @@ func (c *chain) Destroy() {
 	// needSnapshotCache
 	c.needSnapshotCache = nil
 
-	// kafka sender
-	c.kafkaSender = nil
+	// event sender
+	c.eventSender = nil
 
 	c.log.Info("Chain module destroyed")
 }
@@ -413,6 +525,6 @@ func (c *chain) TrieGc() trie_gc.Collector {
 	return c.trieGc
 }
 
-func (c *chain) TrieDb() *leveldb.DB {
+func (c *chain) TrieDb() database.Store {
 	return c.ChainDb().Db()
 }
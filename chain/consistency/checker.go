@@ -0,0 +1,487 @@
+package consistency
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/access"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/trie"
+)
+
+const defaultMaxViolations = 1000
+
+type Chain interface {
+	ChainDb() *chain_db.ChainDb
+	IsGenesisAccountBlock(block *ledger.AccountBlock) bool
+}
+
+// Checker walks the chain db and cross-checks the invariants between the account chains, the snapshot chain, the
+// state tries, the onroad metas and the block events. It reads from a snapshot of the db, so it can run while the
+// node is inserting blocks.
+type Checker struct {
+	chain Chain
+	log   log15.Logger
+
+	// the state tries below the height may have been collected by trie gc
+	stateCheckFromHeight uint64
+	maxViolations        int
+
+	chainDb *chain_db.ChainDb
+	report  *Report
+
+	// the height of the latest block of the account confirmed by the snapshot chain
+	confirmedHeights map[types.Address]uint64
+}
+
+func NewChecker(chain Chain, stateCheckFromHeight uint64) *Checker {
+	if stateCheckFromHeight <= 0 {
+		stateCheckFromHeight = 1
+	}
+	return &Checker{
+		chain:                chain,
+		log:                  log15.New("module", "consistency"),
+		stateCheckFromHeight: stateCheckFromHeight,
+		maxViolations:        defaultMaxViolations,
+	}
+}
+
+// SetMaxViolations sets the max number of violations kept in the report, the rest are only counted.
+func (checker *Checker) SetMaxViolations(maxViolations int) {
+	checker.maxViolations = maxViolations
+}
+
+func (checker *Checker) Check() (*Report, error) {
+	snapshot, err := checker.chain.ChainDb().Db().GetSnapshot()
+	if err != nil {
+		checker.log.Error("GetSnapshot failed, error is "+err.Error(), "method", "Check")
+		return nil, err
+	}
+
+	checker.chainDb = chain_db.NewChainDbWithStore(database.NewSnapshotStore(snapshot))
+	defer checker.chainDb.Db().Close()
+
+	checker.report = &Report{
+		StateCheckFromHeight: checker.stateCheckFromHeight,
+	}
+	checker.confirmedHeights = make(map[types.Address]uint64)
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"checkSnapshotChain", checker.checkSnapshotChain},
+		{"checkAccountChains", checker.checkAccountChains},
+		{"checkOnroad", checker.checkOnroad},
+		{"checkBlockEvents", checker.checkBlockEvents},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			checker.log.Error(step.name+" failed, error is "+err.Error(), "method", "Check")
+			return nil, err
+		}
+	}
+
+	return checker.report, nil
+}
+
+func (checker *Checker) addViolation(violationType string, addr *types.Address, height uint64, hash *types.Hash, message string) {
+	violation := &Violation{
+		Type:    violationType,
+		Height:  height,
+		Message: message,
+	}
+	if addr != nil {
+		violationAddr := *addr
+		violation.Address = &violationAddr
+	}
+	if hash != nil {
+		violationHash := *hash
+		violation.Hash = &violationHash
+	}
+	checker.report.add(violation, checker.maxViolations)
+}
+
+// checkSnapshotChain checks the snapshot chain and the account blocks which are referred by the snapshot contents.
+func (checker *Checker) checkSnapshotChain() error {
+	db := checker.chainDb.Db()
+	iter := db.NewIterator(database.BytesPrefix([]byte{database.DBKP_SNAPSHOTBLOCK}))
+	defer iter.Release()
+
+	var prevBlock *ledger.SnapshotBlock
+	for iter.Next() {
+		key := iter.Key()
+		height := binary.BigEndian.Uint64(key[1:9])
+		hash, err := types.BytesToHash(key[9:])
+		if err != nil {
+			checker.addViolation(ViolationSnapshotChain, nil, height, nil, "the key of snapshot block is malformed")
+			continue
+		}
+
+		block := &ledger.SnapshotBlock{}
+		if err := block.Deserialize(iter.Value()); err != nil {
+			checker.addViolation(ViolationSnapshotChain, nil, height, &hash, "deserialize snapshot block failed, error is "+err.Error())
+			continue
+		}
+		block.Hash = hash
+		checker.report.SnapshotBlockCount++
+
+		if block.Height != height {
+			checker.addViolation(ViolationSnapshotChain, nil, height, &hash, fmt.Sprintf("the height in block is %d", block.Height))
+		}
+		if prevBlock == nil {
+			if height != 1 {
+				checker.addViolation(ViolationSnapshotChain, nil, height, &hash, "the snapshot chain doesn't start from 1")
+			}
+		} else if height != prevBlock.Height+1 {
+			checker.addViolation(ViolationSnapshotChain, nil, height, &hash, fmt.Sprintf("the previous height is %d", prevBlock.Height))
+		} else if block.PrevHash != prevBlock.Hash {
+			checker.addViolation(ViolationSnapshotChain, nil, height, &hash, fmt.Sprintf("prev hash is %s, the previous block is %s", block.PrevHash, prevBlock.Hash))
+		}
+
+		if indexHeight, err := checker.chainDb.Sc.GetSnapshotBlockHeight(&hash); err != nil {
+			return err
+		} else if indexHeight != height {
+			checker.addViolation(ViolationSnapshotChain, nil, height, &hash, fmt.Sprintf("the height in hash index is %d", indexHeight))
+		}
+
+		if height >= checker.stateCheckFromHeight {
+			if ok, err := trie.ShallowCheck(db, &block.StateHash); err != nil {
+				return err
+			} else if !ok {
+				checker.addViolation(ViolationStateHash, nil, height, &hash, fmt.Sprintf("state hash %s isn't in the trie db", block.StateHash))
+			}
+		}
+
+		snapshotContent, err := checker.chainDb.Sc.GetSnapshotContent(height)
+		if err != nil {
+			return err
+		}
+		for addr, hashHeight := range snapshotContent {
+			if err := checker.checkSnapshotContent(block, addr, hashHeight); err != nil {
+				return err
+			}
+		}
+
+		prevBlock = block
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+
+	if prevBlock != nil {
+		checker.report.LatestSnapshotHeight = prevBlock.Height
+	}
+	return nil
+}
+
+func (checker *Checker) checkSnapshotContent(block *ledger.SnapshotBlock, addr types.Address, hashHeight *ledger.HashHeight) error {
+	if prevHeight, ok := checker.confirmedHeights[addr]; ok && hashHeight.Height <= prevHeight {
+		checker.addViolation(ViolationConfirm, &addr, hashHeight.Height, &hashHeight.Hash,
+			fmt.Sprintf("snapshot block %d confirms height %d, which has been confirmed", block.Height, hashHeight.Height))
+	}
+	checker.confirmedHeights[addr] = hashHeight.Height
+
+	account, err := checker.chainDb.Account.GetAccountByAddress(&addr)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		checker.addViolation(ViolationConfirm, &addr, hashHeight.Height, &hashHeight.Hash,
+			fmt.Sprintf("snapshot block %d confirms an account which doesn't exist", block.Height))
+		return nil
+	}
+
+	hash, err := checker.chainDb.Ac.GetHashByHeight(account.AccountId, hashHeight.Height)
+	if err != nil {
+		return err
+	}
+	if hash == nil || *hash != hashHeight.Hash {
+		checker.addViolation(ViolationConfirm, &addr, hashHeight.Height, &hashHeight.Hash,
+			fmt.Sprintf("snapshot block %d confirms a block which isn't in the account chain", block.Height))
+		return nil
+	}
+
+	meta, err := checker.chainDb.Ac.GetBlockMeta(hash)
+	if err != nil {
+		return err
+	}
+	if meta != nil && meta.SnapshotHeight != block.Height {
+		checker.addViolation(ViolationConfirm, &addr, hashHeight.Height, &hashHeight.Hash,
+			fmt.Sprintf("the snapshot height in meta is %d, but the block is confirmed by snapshot block %d", meta.SnapshotHeight, block.Height))
+	}
+	return nil
+}
+
+func (checker *Checker) checkAccountChains() error {
+	lastAccountId, err := checker.chainDb.Account.GetLastAccountId()
+	if err != nil {
+		return err
+	}
+
+	for accountId := uint64(1); accountId <= lastAccountId; accountId++ {
+		addr, err := checker.chainDb.Account.GetAddressById(accountId)
+		if err != nil {
+			if err != database.ErrNotFound {
+				return err
+			}
+			checker.addViolation(ViolationAccount, nil, 0, nil, fmt.Sprintf("account id %d isn't in the id index", accountId))
+			continue
+		}
+		checker.report.AccountCount++
+
+		account, err := checker.chainDb.Account.GetAccountByAddress(addr)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			checker.addViolation(ViolationAccount, addr, 0, nil, fmt.Sprintf("account id %d refers to an account which doesn't exist", accountId))
+			continue
+		}
+		if account.AccountId != accountId {
+			checker.addViolation(ViolationAccount, addr, 0, nil, fmt.Sprintf("the account id is %d, but the id index is %d", account.AccountId, accountId))
+			continue
+		}
+
+		if err := checker.checkAccountChain(accountId, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (checker *Checker) checkAccountChain(accountId uint64, addr *types.Address) error {
+	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId)
+	iter := checker.chainDb.Db().NewIterator(database.BytesPrefix(key))
+	defer iter.Release()
+
+	confirmedHeight := checker.confirmedHeights[*addr]
+
+	var prevBlock *ledger.AccountBlock
+	var prevSnapshotHeight uint64
+	var snapshotContent ledger.SnapshotContent
+	for iter.Next() {
+		key := iter.Key()
+		height := binary.BigEndian.Uint64(key[9:17])
+		hash, err := types.BytesToHash(key[17:])
+		if err != nil {
+			checker.addViolation(ViolationAccountChain, addr, height, nil, "the key of account block is malformed")
+			continue
+		}
+
+		block := &ledger.AccountBlock{}
+		if err := block.DbDeserialize(iter.Value()); err != nil {
+			checker.addViolation(ViolationAccountChain, addr, height, &hash, "deserialize account block failed, error is "+err.Error())
+			continue
+		}
+		block.Hash = hash
+		block.AccountAddress = *addr
+		checker.report.AccountBlockCount++
+
+		if block.Height != height {
+			checker.addViolation(ViolationAccountChain, addr, height, &hash, fmt.Sprintf("the height in block is %d", block.Height))
+		}
+		if prevBlock == nil {
+			if height != 1 {
+				checker.addViolation(ViolationAccountChain, addr, height, &hash, "the account chain doesn't start from 1")
+			} else if block.PrevHash != types.ZERO_HASH {
+				checker.addViolation(ViolationAccountChain, addr, height, &hash, "prev hash of the first block isn't empty")
+			}
+		} else if height != prevBlock.Height+1 {
+			checker.addViolation(ViolationAccountChain, addr, height, &hash, fmt.Sprintf("the previous height is %d", prevBlock.Height))
+		} else if block.PrevHash != prevBlock.Hash {
+			checker.addViolation(ViolationAccountChain, addr, height, &hash, fmt.Sprintf("prev hash is %s, the previous block is %s", block.PrevHash, prevBlock.Hash))
+		}
+		prevBlock = block
+
+		meta, err := checker.chainDb.Ac.GetBlockMeta(&hash)
+		if err != nil {
+			return err
+		}
+		if meta == nil {
+			checker.addViolation(ViolationBlockMeta, addr, height, &hash, "the block meta is missing")
+			continue
+		}
+		if meta.AccountId != accountId || meta.Height != height {
+			checker.addViolation(ViolationBlockMeta, addr, height, &hash,
+				fmt.Sprintf("the account id in meta is %d, the height in meta is %d", meta.AccountId, meta.Height))
+		}
+
+		// confirm
+		if meta.SnapshotHeight <= 0 {
+			if height <= confirmedHeight {
+				checker.addViolation(ViolationConfirm, addr, height, &hash,
+					fmt.Sprintf("the snapshot height in meta is empty, but the account is confirmed to height %d", confirmedHeight))
+			}
+		} else if height > confirmedHeight {
+			checker.addViolation(ViolationConfirm, addr, height, &hash,
+				fmt.Sprintf("the snapshot height in meta is %d, but the account is confirmed to height %d", meta.SnapshotHeight, confirmedHeight))
+		} else if meta.SnapshotHeight < prevSnapshotHeight {
+			checker.addViolation(ViolationConfirm, addr, height, &hash,
+				fmt.Sprintf("the snapshot height in meta is %d, the previous block is %d", meta.SnapshotHeight, prevSnapshotHeight))
+		} else {
+			if meta.SnapshotHeight != prevSnapshotHeight || snapshotContent == nil {
+				if snapshotContent, err = checker.chainDb.Sc.GetSnapshotContent(meta.SnapshotHeight); err != nil {
+					return err
+				}
+			}
+			if hashHeight, ok := snapshotContent[*addr]; !ok || hashHeight.Height < height {
+				checker.addViolation(ViolationConfirm, addr, height, &hash,
+					fmt.Sprintf("the snapshot height in meta is %d, but the snapshot content doesn't confirm the block", meta.SnapshotHeight))
+			}
+			prevSnapshotHeight = meta.SnapshotHeight
+		}
+
+		// receive and onroad
+		if block.IsSendBlock() {
+			if err := checker.checkSendBlock(block, meta); err != nil {
+				return err
+			}
+		} else if block.IsReceiveBlock() {
+			if err := checker.checkReceiveBlock(block); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+
+	if confirmedHeight > 0 && (prevBlock == nil || prevBlock.Height < confirmedHeight) {
+		checker.addViolation(ViolationConfirm, addr, confirmedHeight, nil, "the confirmed height is higher than the latest block")
+	}
+	return nil
+}
+
+func (checker *Checker) getAccountBlockByHeight(addr *types.Address, height uint64) (*ledger.AccountBlock, error) {
+	account, err := checker.chainDb.Account.GetAccountByAddress(addr)
+	if err != nil || account == nil {
+		return nil, err
+	}
+	return checker.chainDb.Ac.GetBlockByHeight(account.AccountId, height)
+}
+
+// checkSendBlock checks the receive heights of the send block in the meta, and the send block is onroad if and only
+// if it hasn't been received successfully.
+func (checker *Checker) checkSendBlock(block *ledger.AccountBlock, meta *ledger.AccountBlockMeta) error {
+	isReceived := false
+	for _, receiveHeight := range meta.ReceiveBlockHeights {
+		receiveBlock, err := checker.getAccountBlockByHeight(&block.ToAddress, receiveHeight)
+		if err != nil {
+			return err
+		}
+		if receiveBlock == nil || !receiveBlock.IsReceiveBlock() || receiveBlock.FromBlockHash != block.Hash {
+			checker.addViolation(ViolationReceive, &block.AccountAddress, block.Height, &block.Hash,
+				fmt.Sprintf("the block at receive height %d of %s doesn't receive the block", receiveHeight, block.ToAddress))
+			continue
+		}
+		if receiveBlock.BlockType == ledger.BlockTypeReceive {
+			isReceived = true
+		}
+	}
+
+	onroadMeta, err := checker.chainDb.OnRoad.GetMeta(&block.ToAddress, &block.Hash)
+	if err != nil {
+		return err
+	}
+	if isReceived && onroadMeta != nil {
+		checker.addViolation(ViolationOnroad, &block.AccountAddress, block.Height, &block.Hash, "the block has been received, but it's still onroad")
+	} else if !isReceived && onroadMeta == nil && !checker.chain.IsGenesisAccountBlock(block) {
+		checker.addViolation(ViolationOnroad, &block.AccountAddress, block.Height, &block.Hash, "the block hasn't been received, but it isn't onroad")
+	}
+	return nil
+}
+
+func (checker *Checker) checkReceiveBlock(block *ledger.AccountBlock) error {
+	sendMeta, err := checker.chainDb.Ac.GetBlockMeta(&block.FromBlockHash)
+	if err != nil {
+		return err
+	}
+	if sendMeta == nil {
+		checker.addViolation(ViolationReceive, &block.AccountAddress, block.Height, &block.Hash,
+			fmt.Sprintf("the send block %s doesn't exist", block.FromBlockHash))
+		return nil
+	}
+
+	for _, receiveHeight := range sendMeta.ReceiveBlockHeights {
+		if receiveHeight == block.Height {
+			return nil
+		}
+	}
+	checker.addViolation(ViolationReceive, &block.AccountAddress, block.Height, &block.Hash,
+		fmt.Sprintf("the receive heights of the send block %s don't contain the block", block.FromBlockHash))
+	return nil
+}
+
+// checkOnroad checks that every onroad meta refers to a send block to the address.
+func (checker *Checker) checkOnroad() error {
+	iter := checker.chainDb.Db().NewIterator(database.BytesPrefix([]byte{database.DBKP_ONROADMETA}))
+	defer iter.Release()
+
+	for iter.Next() {
+		key := iter.Key()
+		checker.report.OnroadCount++
+
+		if len(key) != 1+types.AddressSize+types.HashSize {
+			checker.addViolation(ViolationOnroad, nil, 0, nil, fmt.Sprintf("the key of onroad meta is malformed, the length is %d", len(key)))
+			continue
+		}
+		addr, _ := types.BytesToAddress(key[1 : 1+types.AddressSize])
+		hash, _ := types.BytesToHash(key[1+types.AddressSize:])
+
+		sendBlock, err := checker.chainDb.Ac.GetBlock(&hash)
+		if err != nil {
+			return err
+		}
+		if sendBlock == nil {
+			checker.addViolation(ViolationOnroad, &addr, 0, &hash, "the onroad block doesn't exist")
+		} else if !sendBlock.IsSendBlock() || sendBlock.ToAddress != addr {
+			checker.addViolation(ViolationOnroad, &addr, sendBlock.Height, &hash, "the onroad block isn't sent to the address")
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// checkBlockEvents checks that the event ids start from 1 and increase one by one, and every event can be parsed.
+func (checker *Checker) checkBlockEvents() error {
+	iter := checker.chainDb.Db().NewIterator(database.BytesPrefix([]byte{database.DBKP_BLOCK_EVENT}))
+	defer iter.Release()
+
+	prevEventId := uint64(0)
+	for iter.Next() {
+		key := iter.Key()
+		value := iter.Value()
+		checker.report.BlockEventCount++
+
+		if len(key) != 9 {
+			checker.addViolation(ViolationBlockEvent, nil, 0, nil, fmt.Sprintf("the key of block event is malformed, the length is %d", len(key)))
+			continue
+		}
+		eventId := binary.BigEndian.Uint64(key[1:])
+		if eventId != prevEventId+1 {
+			checker.addViolation(ViolationBlockEvent, nil, eventId, nil, fmt.Sprintf("the previous event id is %d", prevEventId))
+		}
+		prevEventId = eventId
+
+		if len(value) <= 1 || (len(value)-1)%types.HashSize != 0 {
+			checker.addViolation(ViolationBlockEvent, nil, eventId, nil, fmt.Sprintf("the length of event is %d", len(value)))
+			continue
+		}
+		switch value[0] {
+		case access.AddAccountBlocksEvent, access.DeleteAccountBlocksEvent, access.AddSnapshotBlocksEvent, access.DeleteSnapshotBlocksEvent:
+		default:
+			checker.addViolation(ViolationBlockEvent, nil, eventId, nil, fmt.Sprintf("the event type %d is unknown", value[0]))
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+	return nil
+}
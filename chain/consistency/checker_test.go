@@ -0,0 +1,202 @@
+package consistency
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type testChain struct {
+	chainDb *chain_db.ChainDb
+}
+
+func (c *testChain) ChainDb() *chain_db.ChainDb {
+	return c.chainDb
+}
+
+func (c *testChain) IsGenesisAccountBlock(block *ledger.AccountBlock) bool {
+	return false
+}
+
+func testHash(data string) types.Hash {
+	hash, _ := types.BytesToHash(crypto.Hash256([]byte(data)))
+	return hash
+}
+
+type testLedger struct {
+	chainDb *chain_db.ChainDb
+
+	addrA, addrB types.Address
+	sendBlock    *ledger.AccountBlock
+	receiveBlock *ledger.AccountBlock
+	sendBlock2   *ledger.AccountBlock
+}
+
+// newTestLedger writes two accounts: A sends twice to B, B receives the first send. The snapshot block 1 confirms
+// the first send and the receive, the second send is unconfirmed and onroad.
+func newTestLedger(t *testing.T) *testLedger {
+	tl := &testLedger{
+		chainDb: chain_db.NewChainDbWithStore(database.NewMemStore()),
+	}
+	tl.addrA, _ = types.BytesToAddress(testHash("A").Bytes()[:types.AddressSize])
+	tl.addrB, _ = types.BytesToAddress(testHash("B").Bytes()[:types.AddressSize])
+
+	now := time.Now()
+	tl.sendBlock = &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeSendCall,
+		Hash:           testHash("send"),
+		Height:         1,
+		AccountAddress: tl.addrA,
+		ToAddress:      tl.addrB,
+		Amount:         big.NewInt(1),
+		Timestamp:      &now,
+	}
+	tl.receiveBlock = &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeReceive,
+		Hash:           testHash("receive"),
+		Height:         1,
+		AccountAddress: tl.addrB,
+		FromBlockHash:  tl.sendBlock.Hash,
+		Timestamp:      &now,
+	}
+	tl.sendBlock2 = &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeSendCall,
+		Hash:           testHash("send2"),
+		PrevHash:       tl.sendBlock.Hash,
+		Height:         2,
+		AccountAddress: tl.addrA,
+		ToAddress:      tl.addrB,
+		Amount:         big.NewInt(1),
+		Timestamp:      &now,
+	}
+
+	db := tl.chainDb
+	batch := new(database.Batch)
+	for accountId, addr := range []types.Address{tl.addrA, tl.addrB} {
+		db.Account.WriteAccountIndex(batch, uint64(accountId+1), &addr)
+		if err := db.Account.WriteAccount(batch, &ledger.Account{AccountAddress: addr, AccountId: uint64(accountId + 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeBlock := func(accountId uint64, block *ledger.AccountBlock, meta *ledger.AccountBlockMeta) {
+		if err := db.Ac.WriteBlock(batch, accountId, block); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Ac.WriteBlockMeta(batch, &block.Hash, meta); err != nil {
+			t.Fatal(err)
+		}
+		if meta.SnapshotHeight > 0 {
+			db.Ac.WriteBeSnapshot(batch, &block.Hash, meta.SnapshotHeight)
+		}
+	}
+	writeBlock(1, tl.sendBlock, &ledger.AccountBlockMeta{AccountId: 1, Height: 1, ReceiveBlockHeights: []uint64{1}, SnapshotHeight: 1})
+	writeBlock(2, tl.receiveBlock, &ledger.AccountBlockMeta{AccountId: 2, Height: 1, SnapshotHeight: 1})
+	writeBlock(1, tl.sendBlock2, &ledger.AccountBlockMeta{AccountId: 1, Height: 2})
+	onroadKey, _ := database.EncodeKey(database.DBKP_ONROADMETA, tl.addrB.Bytes(), tl.sendBlock2.Hash.Bytes())
+	batch.Put(onroadKey, []byte{0})
+
+	snapshotBlock := &ledger.SnapshotBlock{
+		Hash:      testHash("snapshot"),
+		Height:    1,
+		Timestamp: &now,
+		StateHash: testHash("state"),
+		SnapshotContent: ledger.SnapshotContent{
+			tl.addrA: {Height: 1, Hash: tl.sendBlock.Hash},
+			tl.addrB: {Height: 1, Hash: tl.receiveBlock.Hash},
+		},
+	}
+	if err := db.Sc.WriteSnapshotBlock(batch, snapshotBlock); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Sc.WriteSnapshotContent(batch, snapshotBlock.Height, snapshotBlock.SnapshotContent); err != nil {
+		t.Fatal(err)
+	}
+	db.Sc.WriteSnapshotHash(batch, &snapshotBlock.Hash, snapshotBlock.Height)
+	trieNodeKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, snapshotBlock.StateHash.Bytes())
+	batch.Put(trieNodeKey, []byte{0})
+
+	db.Be.AddAccountBlocks(batch, []types.Hash{tl.sendBlock.Hash, tl.receiveBlock.Hash})
+	db.Be.AddSnapshotBlocks(batch, []types.Hash{snapshotBlock.Hash})
+	db.Be.AddAccountBlocks(batch, []types.Hash{tl.sendBlock2.Hash})
+
+	if err := db.Commit(batch); err != nil {
+		t.Fatal(err)
+	}
+	return tl
+}
+
+func check(t *testing.T, tl *testLedger) *Report {
+	report, err := NewChecker(&testChain{tl.chainDb}, 1).Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func expectViolation(t *testing.T, report *Report, violationType string) {
+	for _, violation := range report.Violations {
+		if violation.Type == violationType {
+			return
+		}
+	}
+	t.Fatalf("expect a %s violation, violations are %+v", violationType, report.Violations)
+}
+
+func TestChecker(t *testing.T) {
+	tl := newTestLedger(t)
+
+	report := check(t, tl)
+	if !report.IsConsistent() {
+		for _, violation := range report.Violations {
+			t.Logf("%+v", violation)
+		}
+		t.Fatal("the test ledger should be consistent")
+	}
+	if report.LatestSnapshotHeight != 1 || report.AccountCount != 2 || report.AccountBlockCount != 3 ||
+		report.OnroadCount != 1 || report.BlockEventCount != 3 {
+		t.Fatalf("report is %+v", report)
+	}
+}
+
+func TestCheckerViolations(t *testing.T) {
+	cases := []struct {
+		violationType string
+		corrupt       func(tl *testLedger)
+	}{
+		{ViolationAccountChain, func(tl *testLedger) {
+			batch := new(database.Batch)
+			tl.chainDb.Ac.DeleteBlock(batch, 1, 1, &tl.sendBlock.Hash)
+			tl.chainDb.Commit(batch)
+		}},
+		{ViolationConfirm, func(tl *testLedger) {
+			batch := new(database.Batch)
+			tl.chainDb.Ac.WriteBeSnapshot(batch, &tl.sendBlock2.Hash, 1)
+			tl.chainDb.Commit(batch)
+		}},
+		{ViolationStateHash, func(tl *testLedger) {
+			key, _ := database.EncodeKey(database.DBKP_TRIE_NODE, testHash("state").Bytes())
+			tl.chainDb.Db().Delete(key)
+		}},
+		{ViolationOnroad, func(tl *testLedger) {
+			key, _ := database.EncodeKey(database.DBKP_ONROADMETA, tl.addrB.Bytes(), tl.sendBlock.Hash.Bytes())
+			tl.chainDb.Db().Put(key, []byte{0})
+		}},
+		{ViolationBlockEvent, func(tl *testLedger) {
+			key, _ := database.EncodeKey(database.DBKP_BLOCK_EVENT, uint64(2))
+			tl.chainDb.Db().Delete(key)
+		}},
+	}
+
+	for _, c := range cases {
+		tl := newTestLedger(t)
+		c.corrupt(tl)
+		expectViolation(t, check(t, tl), c.violationType)
+	}
+}
@@ -0,0 +1,60 @@
+package consistency
+
+import (
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+const (
+	// the account id index, the account and the account blocks don't agree with each other
+	ViolationAccount = "account"
+	// the heights or the prev hashes of an account chain aren't contiguous
+	ViolationAccountChain = "accountChain"
+	// the meta of an account block is missing or doesn't match the block
+	ViolationBlockMeta = "blockMeta"
+	// the snapshot height of an account block doesn't match the snapshot content which confirmed the block
+	ViolationConfirm = "confirm"
+	// the heights or the prev hashes of the snapshot chain aren't contiguous
+	ViolationSnapshotChain = "snapshotChain"
+	// the state trie of a snapshot block isn't in the trie db
+	ViolationStateHash = "stateHash"
+	// the receive heights of a send block don't match the receive blocks
+	ViolationReceive = "receive"
+	// the onroad meta doesn't agree with the receive blocks
+	ViolationOnroad = "onroad"
+	// the block events aren't monotonic or can't be parsed
+	ViolationBlockEvent = "blockEvent"
+)
+
+type Violation struct {
+	Type    string         `json:"type"`
+	Address *types.Address `json:"address,omitempty"`
+	Height  uint64         `json:"height,omitempty"`
+	Hash    *types.Hash    `json:"hash,omitempty"`
+	Message string         `json:"message"`
+}
+
+// Report is the result of a check. At most maxViolations violations are kept, ViolationCount counts all of them.
+type Report struct {
+	LatestSnapshotHeight uint64 `json:"latestSnapshotHeight"`
+	StateCheckFromHeight uint64 `json:"stateCheckFromHeight"`
+
+	SnapshotBlockCount uint64 `json:"snapshotBlockCount"`
+	AccountCount       uint64 `json:"accountCount"`
+	AccountBlockCount  uint64 `json:"accountBlockCount"`
+	OnroadCount        uint64 `json:"onroadCount"`
+	BlockEventCount    uint64 `json:"blockEventCount"`
+
+	ViolationCount uint64       `json:"violationCount"`
+	Violations     []*Violation `json:"violations"`
+}
+
+func (report *Report) IsConsistent() bool {
+	return report.ViolationCount <= 0
+}
+
+func (report *Report) add(violation *Violation, maxViolations int) {
+	report.ViolationCount++
+	if len(report.Violations) < maxViolations {
+		report.Violations = append(report.Violations, violation)
+	}
+}
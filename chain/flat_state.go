@@ -0,0 +1,393 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/trie"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
+)
+
+const flatStateRebuildBatchSize = 10000
+
+// flatState keeps the storage of every account at the latest snapshot block as plain key/value pairs, the state
+// tries stay the authoritative source of the state root. The flat state is only read when its version equals the
+// snapshot block of the read, otherwise the readers fall back to the tries.
+type flatState struct {
+	chain *chain
+	log   log15.Logger
+
+	// lock is held by the readers while they read, and by the writers while they update the version
+	lock    sync.RWMutex
+	version *types.Hash
+}
+
+func newFlatState(chain *chain) *flatState {
+	return &flatState{
+		chain: chain,
+		log:   log15.New("module", "chain/flat_state"),
+	}
+}
+
+func (fs *flatState) loadVersion() error {
+	_, version, err := fs.chain.chainDb.FlatState.GetVersion()
+	if err != nil {
+		return err
+	}
+
+	fs.lock.Lock()
+	fs.version = version
+	fs.lock.Unlock()
+	return nil
+}
+
+func (fs *flatState) Version() *types.Hash {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+
+	return fs.version
+}
+
+func (fs *flatState) isSynced() bool {
+	version := fs.Version()
+	return version != nil && *version == fs.chain.GetLatestSnapshotBlock().Hash
+}
+
+// stateTrie returns the account state trie of stateHash, the zero hash is the empty trie.
+func (fs *flatState) stateTrie(stateHash *types.Hash) (*trie.Trie, error) {
+	if *stateHash == (types.Hash{}) {
+		return fs.chain.NewStateTrie(), nil
+	}
+
+	if ok, err := fs.chain.ShallowCheckStateTrie(stateHash); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.New(fmt.Sprintf("the account state trie %s is not existed", stateHash))
+	}
+	return fs.chain.GetStateTrie(stateHash), nil
+}
+
+func (fs *flatState) accountStorage(stateHash *types.Hash) (map[string][]byte, error) {
+	stateTrie, err := fs.stateTrie(stateHash)
+	if err != nil {
+		return nil, err
+	}
+
+	storage := make(map[string][]byte)
+	iter := stateTrie.NewIterator(nil)
+	for {
+		key, value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		storage[string(key)] = value
+	}
+	return storage, nil
+}
+
+func (fs *flatState) writeAccountState(batch *database.Batch, addr *types.Address, stateHash *types.Hash) error {
+	flatStateDb := fs.chain.chainDb.FlatState
+	if stateHash == nil {
+		return flatStateDb.WriteAccountState(batch, addr, nil, nil)
+	}
+
+	prevStateHash, err := flatStateDb.GetStateHash(addr)
+	if err != nil {
+		return err
+	}
+	if prevStateHash != nil && *prevStateHash == *stateHash {
+		return nil
+	}
+
+	// the account isn't in the flat state yet, write all of its storage
+	if prevStateHash == nil {
+		storage, err := fs.accountStorage(stateHash)
+		if err != nil {
+			return err
+		}
+		return flatStateDb.WriteAccountState(batch, addr, stateHash, storage)
+	}
+
+	// only the keys changed since the previous state are written
+	prevStateTrie, err := fs.stateTrie(prevStateHash)
+	if err != nil {
+		return err
+	}
+	stateTrie, err := fs.stateTrie(stateHash)
+	if err != nil {
+		return err
+	}
+	stateTrie.Diff(prevStateTrie, func(key, value, prevValue []byte) {
+		flatStateDb.WriteValue(batch, addr, key, value)
+	})
+	flatStateDb.WriteStateHash(batch, addr, stateHash)
+	return nil
+}
+
+// WriteSnapshotBlock writes the storage changes of the accounts in the snapshot content into batch, it returns the
+// new version. nil version means the flat state is out of sync and it will be rebuilt on the next start.
+func (fs *flatState) WriteSnapshotBlock(batch *database.Batch, snapshotBlock *ledger.SnapshotBlock) *types.Hash {
+	version := fs.Version()
+	if version == nil && snapshotBlock.Height > 1 || version != nil && *version != snapshotBlock.PrevHash {
+		return fs.invalidate(batch)
+	}
+
+	for addr := range snapshotBlock.SnapshotContent {
+		var stateHash *types.Hash
+		if stateHashBytes := snapshotBlock.StateTrie.GetValue(addr.Bytes()); len(stateHashBytes) > 0 {
+			hash, err := types.BytesToHash(stateHashBytes)
+			if err != nil {
+				fs.log.Error("BytesToHash failed, error is "+err.Error(), "method", "WriteSnapshotBlock")
+				return fs.invalidate(batch)
+			}
+			stateHash = &hash
+		}
+
+		if err := fs.writeAccountState(batch, &addr, stateHash); err != nil {
+			fs.log.Error("writeAccountState failed, error is "+err.Error(), "method", "WriteSnapshotBlock")
+			return fs.invalidate(batch)
+		}
+	}
+
+	fs.chain.chainDb.FlatState.WriteVersion(batch, snapshotBlock.Height, &snapshotBlock.Hash)
+	return &snapshotBlock.Hash
+}
+
+// WriteDeleteSnapshotBlocks writes the storage of the accounts in the deleted snapshot contents at prevSnapshotBlock
+// into batch, it returns the new version.
+func (fs *flatState) WriteDeleteSnapshotBlocks(batch *database.Batch, snapshotBlocks []*ledger.SnapshotBlock, prevSnapshotBlock *ledger.SnapshotBlock) *types.Hash {
+	version := fs.Version()
+	if version == nil || prevSnapshotBlock == nil || *version != snapshotBlocks[len(snapshotBlocks)-1].Hash {
+		return fs.invalidate(batch)
+	}
+
+	if ok, err := fs.chain.ShallowCheckStateTrie(&prevSnapshotBlock.StateHash); err != nil || !ok {
+		fs.log.Error(fmt.Sprintf("the state trie of snapshot block %d is not existed", prevSnapshotBlock.Height),
+			"method", "WriteDeleteSnapshotBlocks")
+		return fs.invalidate(batch)
+	}
+	prevStateTrie := fs.chain.GetStateTrie(&prevSnapshotBlock.StateHash)
+
+	addrSet := make(map[types.Address]struct{})
+	for _, snapshotBlock := range snapshotBlocks {
+		for addr := range snapshotBlock.SnapshotContent {
+			addrSet[addr] = struct{}{}
+		}
+	}
+
+	for addr := range addrSet {
+		var stateHash *types.Hash
+		if stateHashBytes := prevStateTrie.GetValue(addr.Bytes()); len(stateHashBytes) > 0 {
+			hash, err := types.BytesToHash(stateHashBytes)
+			if err != nil {
+				fs.log.Error("BytesToHash failed, error is "+err.Error(), "method", "WriteDeleteSnapshotBlocks")
+				return fs.invalidate(batch)
+			}
+			stateHash = &hash
+		}
+
+		if err := fs.writeAccountState(batch, &addr, stateHash); err != nil {
+			fs.log.Error("writeAccountState failed, error is "+err.Error(), "method", "WriteDeleteSnapshotBlocks")
+			return fs.invalidate(batch)
+		}
+	}
+
+	fs.chain.chainDb.FlatState.WriteVersion(batch, prevSnapshotBlock.Height, &prevSnapshotBlock.Hash)
+	return &prevSnapshotBlock.Hash
+}
+
+func (fs *flatState) invalidate(batch *database.Batch) *types.Hash {
+	fs.chain.chainDb.FlatState.DeleteVersion(batch)
+	return nil
+}
+
+// Commit writes batch and sets the version. The version is cleared while batch is written, so the readers fall back
+// to the tries rather than see the new storage with the old version, and they aren't blocked by the write.
+func (fs *flatState) Commit(batch *database.Batch, version *types.Hash) error {
+	fs.lock.Lock()
+	prevVersion := fs.version
+	fs.version = nil
+	fs.lock.Unlock()
+
+	err := fs.chain.chainDb.Commit(batch)
+
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if err != nil {
+		fs.version = prevVersion
+		return err
+	}
+	fs.version = version
+	return nil
+}
+
+// newView returns the flat state of the read view, the entries and the version are read from the store of view, so
+// they're consistent with the blocks of the view.
+func (fs *flatState) newView(view *chain) *flatState {
+	viewFlatState := newFlatState(view)
+	if err := viewFlatState.loadVersion(); err != nil {
+		fs.log.Error("loadVersion failed, error is "+err.Error(), "method", "newView")
+		return nil
+	}
+	return viewFlatState
+}
+
+// Rebuild rewrites the flat state from the state trie of the latest snapshot block if the version doesn't match.
+func (fs *flatState) Rebuild() error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	latestSnapshotBlock := fs.chain.GetLatestSnapshotBlock()
+	if fs.version != nil && *fs.version == latestSnapshotBlock.Hash {
+		return nil
+	}
+	fs.version = nil
+
+	db := fs.chain.chainDb.Db()
+	flatStateDb := fs.chain.chainDb.FlatState
+
+	// clear
+	batch := new(database.Batch)
+	flatStateDb.DeleteVersion(batch)
+	for _, prefix := range []byte{database.DBKP_FLAT_STATE, database.DBKP_FLAT_STATE_HASH} {
+		iter := db.NewIterator(database.BytesPrefix([]byte{prefix}))
+		for iter.Next() {
+			key := make([]byte, len(iter.Key()))
+			copy(key, iter.Key())
+			batch.Delete(key)
+
+			if batch.Len() >= flatStateRebuildBatchSize {
+				if err := fs.chain.chainDb.Commit(batch); err != nil {
+					iter.Release()
+					return err
+				}
+				batch = new(database.Batch)
+			}
+		}
+		err := iter.Error()
+		iter.Release()
+		if err != nil && err != database.ErrNotFound {
+			return err
+		}
+	}
+
+	// write all accounts
+	stateTrieIter := fs.chain.GetStateTrie(&latestSnapshotBlock.StateHash).NewIterator(nil)
+	for {
+		addrBytes, stateHashBytes, ok := stateTrieIter.Next()
+		if !ok {
+			break
+		}
+
+		addr, err := types.BytesToAddress(addrBytes)
+		if err != nil {
+			return err
+		}
+		stateHash, err := types.BytesToHash(stateHashBytes)
+		if err != nil {
+			return err
+		}
+
+		storage, err := fs.accountStorage(&stateHash)
+		if err != nil {
+			return err
+		}
+		if err := flatStateDb.WriteAccountState(batch, &addr, &stateHash, storage); err != nil {
+			return err
+		}
+
+		if batch.Len() >= flatStateRebuildBatchSize {
+			if err := fs.chain.chainDb.Commit(batch); err != nil {
+				return err
+			}
+			batch = new(database.Batch)
+		}
+	}
+
+	// the version is written last, a rebuild which is interrupted is restarted on the next start
+	flatStateDb.WriteVersion(batch, latestSnapshotBlock.Height, &latestSnapshotBlock.Hash)
+	if err := fs.chain.chainDb.Commit(batch); err != nil {
+		return err
+	}
+	fs.version = &latestSnapshotBlock.Hash
+	return nil
+}
+
+func (fs *flatState) GetStorage(snapshotHash *types.Hash, addr *types.Address, key []byte) ([]byte, bool) {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+
+	if fs.version == nil || *fs.version != *snapshotHash {
+		return nil, false
+	}
+
+	value, err := fs.chain.chainDb.FlatState.GetValue(addr, key)
+	if err != nil {
+		fs.log.Error("GetValue failed, error is "+err.Error(), "method", "GetStorage")
+		return nil, false
+	}
+	return value, true
+}
+
+func (fs *flatState) NewStorageIterator(snapshotHash *types.Hash, addr *types.Address, prefix []byte) (vmctxt_interface.StorageIterator, bool) {
+	fs.lock.RLock()
+	defer fs.lock.RUnlock()
+
+	if fs.version == nil || *fs.version != *snapshotHash {
+		return nil, false
+	}
+
+	iter := &flatStorageIterator{}
+	err := fs.chain.chainDb.FlatState.Iterate(addr, prefix, func(key, value []byte) bool {
+		iter.keys = append(iter.keys, append([]byte{}, key...))
+		iter.values = append(iter.values, append([]byte{}, value...))
+		return true
+	})
+	if err != nil {
+		fs.log.Error("Iterate failed, error is "+err.Error(), "method", "NewStorageIterator")
+		return nil, false
+	}
+	return iter, true
+}
+
+// flatStorageIterator holds a copy of the entries, the flat state may be changed after the iterator is created.
+type flatStorageIterator struct {
+	keys   [][]byte
+	values [][]byte
+}
+
+func (iter *flatStorageIterator) Next() (key, value []byte, ok bool) {
+	if len(iter.keys) <= 0 {
+		return nil, nil, false
+	}
+
+	key, value = iter.keys[0], iter.values[0]
+	iter.keys, iter.values = iter.keys[1:], iter.values[1:]
+	return key, value, true
+}
+
+func (c *chain) commit(batch *database.Batch, flatStateVersion *types.Hash) error {
+	if c.flatState != nil {
+		return c.flatState.Commit(batch, flatStateVersion)
+	}
+	return c.chainDb.Commit(batch)
+}
+
+func (c *chain) GetFlatStorage(snapshotHash *types.Hash, addr *types.Address, key []byte) ([]byte, bool) {
+	if c.flatState == nil {
+		return nil, false
+	}
+	return c.flatState.GetStorage(snapshotHash, addr, key)
+}
+
+func (c *chain) NewFlatStorageIterator(snapshotHash *types.Hash, addr *types.Address, prefix []byte) (vmctxt_interface.StorageIterator, bool) {
+	if c.flatState == nil {
+		return nil, false
+	}
+	return c.flatState.NewStorageIterator(snapshotHash, addr, prefix)
+}
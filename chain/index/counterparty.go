@@ -0,0 +1,109 @@
+package chain_index
+
+import (
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const CounterpartyIndexName = "counterparty"
+
+const (
+	DBKP_COUNTERPARTY_SEND = byte(1)
+
+	DBKP_COUNTERPARTY_SEND_KEY = byte(2)
+)
+
+var counterpartySchema = &Schema{
+	Name:    CounterpartyIndexName,
+	Id:      2,
+	Version: 1,
+	Keys: []KeySchema{
+		{Prefix: DBKP_COUNTERPARTY_SEND, Name: "send", Fields: []string{"toAddress", "fromAddress", "height"}, Value: "sendBlockHash"},
+		{Prefix: DBKP_COUNTERPARTY_SEND_KEY, Name: "sendKey", Fields: []string{"sendBlockHash"}, Value: "toAddress+fromAddress+height"},
+	},
+}
+
+// CounterpartyIndex indexes the send blocks by the receiver and the sender.
+type CounterpartyIndex struct {
+	store *Store
+}
+
+func NewCounterpartyIndex() *CounterpartyIndex {
+	return &CounterpartyIndex{}
+}
+
+func (cpi *CounterpartyIndex) Schema() *Schema {
+	return counterpartySchema
+}
+
+func (cpi *CounterpartyIndex) Open(store *Store) {
+	cpi.store = store
+}
+
+func (cpi *CounterpartyIndex) AddAccountBlocks(batch *database.Batch, blocks []*ledger.AccountBlock) error {
+	for _, block := range blocks {
+		if !block.IsSendBlock() {
+			continue
+		}
+
+		key := cpi.store.EncodeKey(DBKP_COUNTERPARTY_SEND, block.ToAddress.Bytes(), block.AccountAddress.Bytes(), block.Height)
+		batch.Put(key, block.Hash.Bytes())
+
+		sendKey := cpi.store.EncodeKey(DBKP_COUNTERPARTY_SEND_KEY, block.Hash.Bytes())
+		batch.Put(sendKey, key[KeyHeaderLen:])
+	}
+	return nil
+}
+
+func (cpi *CounterpartyIndex) DeleteAccountBlocks(batch *database.Batch, hashList []types.Hash) error {
+	for _, hash := range hashList {
+		sendKey := cpi.store.EncodeKey(DBKP_COUNTERPARTY_SEND_KEY, hash.Bytes())
+		value, err := cpi.store.Get(sendKey)
+		if err != nil {
+			if err == database.ErrNotFound {
+				// not a send block
+				continue
+			}
+			return err
+		}
+
+		batch.Delete(cpi.store.EncodeKey(DBKP_COUNTERPARTY_SEND, value))
+		batch.Delete(sendKey)
+	}
+	return nil
+}
+
+// GetSendBlockHashList returns the hashes of the send blocks to toAddr, sent by fromAddr if fromAddr isn't nil. The
+// hashes are ordered by the sender address descending, then by the height descending, at most count hashes are
+// returned after skipping offset ones.
+func (cpi *CounterpartyIndex) GetSendBlockHashList(toAddr types.Address, fromAddr *types.Address, offset int, count int) ([]types.Hash, error) {
+	var prefix []byte
+	if fromAddr != nil {
+		prefix = cpi.store.EncodeKey(DBKP_COUNTERPARTY_SEND, toAddr.Bytes(), fromAddr.Bytes())
+	} else {
+		prefix = cpi.store.EncodeKey(DBKP_COUNTERPARTY_SEND, toAddr.Bytes())
+	}
+
+	iter := cpi.store.NewIterator(database.BytesPrefix(prefix))
+	defer iter.Release()
+
+	var hashList []types.Hash
+	skipped := 0
+	for ok := iter.Last(); ok && len(hashList) < count; ok = iter.Prev() {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		hash, err := types.BytesToHash(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		hashList = append(hashList, hash)
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return hashList, nil
+}
@@ -0,0 +1,76 @@
+package chain_index
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func TestCounterpartyIndex(t *testing.T) {
+	c := newTestChain()
+	m, err := NewManagerWithStore(database.NewMemStore(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpi := NewCounterpartyIndex()
+	if err := m.Register(cpi); err != nil {
+		t.Fatal(err)
+	}
+
+	addrA := types.Address{1}
+	addrB := types.Address{2}
+	addrC := types.Address{3}
+
+	newSend := func(from, to types.Address, height uint64) *ledger.AccountBlock {
+		return &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeSendCall,
+			Hash:           types.Hash{from[0], to[0], byte(height)},
+			Height:         height,
+			AccountAddress: from,
+			ToAddress:      to,
+		}
+	}
+	a1 := newSend(addrA, addrB, 1)
+	a2 := newSend(addrA, addrB, 2)
+	c1 := newSend(addrC, addrB, 1)
+	a3 := newSend(addrA, addrC, 3)
+	receive := &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeReceive,
+		Hash:           types.Hash{9},
+		Height:         1,
+		AccountAddress: addrB,
+		FromBlockHash:  a1.Hash,
+	}
+	for _, block := range []*ledger.AccountBlock{a1, a2, c1, a3, receive} {
+		c.addAccountBlock(block)
+	}
+	m.Build()
+
+	expect := func(fromAddr *types.Address, offset, count int, expected ...*ledger.AccountBlock) {
+		hashList, err := cpi.GetSendBlockHashList(addrB, fromAddr, offset, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hashList) != len(expected) {
+			t.Fatalf("expect %d hashes, got %d", len(expected), len(hashList))
+		}
+		for i, block := range expected {
+			if hashList[i] != block.Hash {
+				t.Fatalf("hash %d, expect %s, got %s", i, block.Hash, hashList[i])
+			}
+		}
+	}
+
+	expect(&addrA, 0, 10, a2, a1)
+	expect(&addrA, 1, 10, a1)
+	expect(nil, 0, 10, c1, a2, a1)
+	expect(nil, 1, 1, a2)
+
+	c.deleteAccountBlock(a2.Hash)
+	c.deleteAccountBlock(receive.Hash)
+	m.Build()
+	expect(&addrA, 0, 10, a1)
+	expect(nil, 0, 10, c1, a1)
+}
@@ -0,0 +1,111 @@
+package chain_index
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+// KeySchema describes one kind of key an index writes, Fields lists the parts of the key after the prefix.
+type KeySchema struct {
+	Prefix byte     `json:"prefix"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+	Value  string   `json:"value"`
+}
+
+// Schema is registered by an index. Id is the namespace of the index in the index db and must never change, bump
+// Version when the key layout changes and the index is rebuilt from scratch on the next start.
+type Schema struct {
+	Name    string      `json:"name"`
+	Id      byte        `json:"id"`
+	Version uint32      `json:"version"`
+	Keys    []KeySchema `json:"keys"`
+}
+
+// Index is a secondary index built by consuming the block event log. The handlers write into batch, the batch is
+// committed together with the consume id of the index. The blocks of an add event which are deleted before the
+// event is consumed are skipped, so are their delete events.
+type Index interface {
+	Schema() *Schema
+
+	// Open is called once when the index is registered, store is the namespace of the index.
+	Open(store *Store)
+
+	AddAccountBlocks(batch *database.Batch, blocks []*ledger.AccountBlock) error
+	// DeleteAccountBlocks is called after the blocks are deleted from the chain, so only the hashes are available.
+	DeleteAccountBlocks(batch *database.Batch, hashList []types.Hash) error
+}
+
+// SnapshotIndex is implemented by the indexes which consume the snapshot block events as well.
+type SnapshotIndex interface {
+	Index
+
+	AddSnapshotBlocks(batch *database.Batch, blocks []*ledger.SnapshotBlock) error
+	DeleteSnapshotBlocks(batch *database.Batch, hashList []types.Hash) error
+}
+
+// KeyHeaderLen is the length of the namespace and the prefix at the beginning of every key of an index.
+const KeyHeaderLen = 2
+
+// Store is the namespace of an index in the index db, all keys start with the id of the index.
+type Store struct {
+	id byte
+	db database.Store
+}
+
+// EncodeKey encodes the key like database.EncodeKey, prefixed by the id of the index.
+func (s *Store) EncodeKey(prefix byte, partList ...interface{}) []byte {
+	key, err := database.EncodeKey(s.id, append([]interface{}{[]byte{prefix}}, partList...)...)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (s *Store) Get(key []byte) ([]byte, error) {
+	return s.db.Get(key)
+}
+
+func (s *Store) Has(key []byte) (bool, error) {
+	return s.db.Has(key)
+}
+
+func (s *Store) NewIterator(slice *database.Range) database.Iterator {
+	return s.db.NewIterator(slice)
+}
+
+// getConfirmedBlocks walks back from the block in the snapshot content to the block confirmed by a previous snapshot
+// block, only the block in the snapshot content records the snapshot height which confirmed it. The blocks are ordered
+// by the height descending.
+func getConfirmedBlocks(chainInstance Chain, hash *types.Hash) ([]*ledger.AccountBlock, error) {
+	var blocks []*ledger.AccountBlock
+
+	currentHash := hash
+	for {
+		block, err := chainInstance.GetAccountBlockByHash(currentHash)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, errors.New(fmt.Sprintf("block %s is not existed", currentHash))
+		}
+		blocks = append(blocks, block)
+
+		if block.Height <= 1 {
+			return blocks, nil
+		}
+
+		prevMeta, err := chainInstance.GetAccountBlockMetaByHash(&block.PrevHash)
+		if err != nil {
+			return nil, err
+		}
+		if prevMeta == nil || prevMeta.SnapshotHeight > 0 {
+			return blocks, nil
+		}
+		currentHash = &block.PrevHash
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/vitelabs/go-vite/chain_db"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
 )
 
 type Chain interface {
@@ -11,6 +12,11 @@ type Chain interface {
 
 	GetEvent(eventId uint64) (byte, []types.Hash, error)
 	GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
+	GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error)
+	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
+	GetStateTrie(stateHash *types.Hash) *trie.Trie
+	GetSnapshotBlockByHash(hash *types.Hash) (*ledger.SnapshotBlock, error)
+	IsStatePruned(snapshotBlock *ledger.SnapshotBlock) (bool, error)
 
 	GetAccount(address *types.Address) (*ledger.Account, error)
 	IsAccountBlockExisted(hash types.Hash) (bool, error)
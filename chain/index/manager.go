@@ -0,0 +1,612 @@
+package chain_index
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain_db/access"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+)
+
+const (
+	STOP  = 1
+	START = 2
+)
+
+// The keys of the manager are under the namespace 0, the indexes use the namespaces from 1.
+const (
+	metaNamespace = byte(0)
+
+	// the layout version of the index db, the db written before the index framework has no format key
+	formatVersion = uint32(1)
+)
+
+var (
+	formatKey = []byte{metaNamespace}
+)
+
+// Status is the progress of an index.
+type Status struct {
+	Schema *Schema `json:"schema"`
+
+	ConsumedEventId uint64     `json:"consumedEventId"`
+	LatestEventId   uint64     `json:"latestEventId"`
+	Synced          bool       `json:"synced"`
+	LastBuildTime   *time.Time `json:"lastBuildTime,omitempty"`
+	LastError       string     `json:"lastError,omitempty"`
+}
+
+// consumeState is persisted for every index, the digest of the consumed event detects a chain db which is
+// recreated under the index db.
+type consumeState struct {
+	version     uint32
+	eventId     uint64
+	eventDigest types.Hash
+}
+
+type registeredIndex struct {
+	index Index
+	store *Store
+
+	buildResultLock sync.Mutex
+	lastBuildTime   *time.Time
+	lastError       error
+}
+
+// Manager builds the registered indexes from the block event log in the background, it polls the event log
+// every 3 seconds. Every index has its own consume id, so an index added later catches up from the first event.
+type Manager struct {
+	db      database.Store
+	dataDir string
+
+	log              log15.Logger
+	chainInstance    Chain
+	EventNumPerBatch uint64
+
+	indexes    []*registeredIndex
+	indexMap   map[string]*registeredIndex
+	indexLock  sync.RWMutex
+	status     int
+	statusLock sync.Mutex
+	ticker     *time.Ticker
+	terminal   chan struct{}
+	wg         sync.WaitGroup
+
+	buildLock sync.Mutex
+}
+
+func NewManager(dataDir string, chainInstance Chain) (*Manager, error) {
+	m := newManager(chainInstance)
+	m.dataDir = dataDir
+
+	if err := m.initDb(); err != nil {
+		err := errors.New("initDb failed, error is " + err.Error())
+		m.log.Error(err.Error(), "method", "NewManager")
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewManagerWithStore creates a Manager on store, unit tests use it with database.NewMemStore.
+func NewManagerWithStore(store database.Store, chainInstance Chain) (*Manager, error) {
+	m := newManager(chainInstance)
+	m.db = store
+
+	if err := m.checkFormat(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func newManager(chainInstance Chain) *Manager {
+	return &Manager{
+		log:              log15.New("module", "chain_index"),
+		chainInstance:    chainInstance,
+		EventNumPerBatch: 1000,
+
+		indexMap: make(map[string]*registeredIndex),
+		status:   STOP,
+	}
+}
+
+func (m *Manager) initDb() error {
+	db, err := database.NewLevelDb(m.dataDir)
+	if err != nil {
+		if database.IsCorrupted(err) {
+			return m.clearAndInitDb()
+		}
+		m.log.Error("NewLevelDb failed, error is "+err.Error(), "method", "initDb")
+		return err
+	}
+
+	if db == nil {
+		err := errors.New("NewManager failed, db is nil")
+		m.log.Error(err.Error(), "method", "initDb")
+		return err
+	}
+	m.db = db
+
+	if isNewFormat, err := m.isNewFormat(); err != nil {
+		return err
+	} else if !isNewFormat {
+		m.log.Info("The index db is written by an old version, it will be rebuilt", "method", "initDb")
+		return m.clearAndInitDb()
+	}
+
+	return m.checkFormat()
+}
+
+func (m *Manager) clearAndInitDb() error {
+	if m.db != nil {
+		if closeErr := m.db.Close(); closeErr != nil {
+			return errors.New("Close db failed, error is " + closeErr.Error())
+		}
+	}
+
+	if err := os.RemoveAll(m.dataDir); err != nil && err != os.ErrNotExist {
+		return errors.New("Remove " + m.dataDir + " failed, error is " + err.Error())
+	}
+
+	m.db = nil
+	return m.initDb()
+}
+
+// isNewFormat returns false when the db isn't empty and has no format key.
+func (m *Manager) isNewFormat() (bool, error) {
+	if ok, err := m.db.Has(formatKey); err != nil || ok {
+		return ok, err
+	}
+
+	iter := m.db.NewIterator(nil)
+	defer iter.Release()
+	if iter.Next() {
+		return false, nil
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *Manager) checkFormat() error {
+	value, err := m.db.Get(formatKey)
+	if err != nil {
+		if err != database.ErrNotFound {
+			return err
+		}
+		value = make([]byte, 4)
+		binary.BigEndian.PutUint32(value, formatVersion)
+		return m.db.Put(formatKey, value)
+	}
+
+	if len(value) != 4 || binary.BigEndian.Uint32(value) != formatVersion {
+		return errors.New(fmt.Sprintf("the format of the index db is not supported, format is %x", value))
+	}
+	return nil
+}
+
+// Register adds an index, it must be called before Start.
+func (m *Manager) Register(index Index) error {
+	m.indexLock.Lock()
+	defer m.indexLock.Unlock()
+
+	schema := index.Schema()
+	if schema.Id == metaNamespace {
+		return errors.New(fmt.Sprintf("the id of index %s is %d, it is reserved", schema.Name, metaNamespace))
+	}
+	if _, ok := m.indexMap[schema.Name]; ok {
+		return errors.New(fmt.Sprintf("index %s is registered", schema.Name))
+	}
+	for _, ri := range m.indexes {
+		if ri.index.Schema().Id == schema.Id {
+			return errors.New(fmt.Sprintf("the id of index %s is used by index %s", schema.Name, ri.index.Schema().Name))
+		}
+	}
+
+	ri := &registeredIndex{
+		index: index,
+		store: &Store{
+			id: schema.Id,
+			db: m.db,
+		},
+	}
+	index.Open(ri.store)
+
+	m.indexes = append(m.indexes, ri)
+	m.indexMap[schema.Name] = ri
+	return nil
+}
+
+// Index returns the registered index by name, nil means the index isn't registered.
+func (m *Manager) Index(name string) Index {
+	m.indexLock.RLock()
+	defer m.indexLock.RUnlock()
+
+	if ri, ok := m.indexMap[name]; ok {
+		return ri.index
+	}
+	return nil
+}
+
+func (m *Manager) Start() {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+	if m.status == START {
+		return
+	}
+
+	for _, ri := range m.indexes {
+		if err := m.checkAndInitData(ri); err != nil {
+			m.log.Crit("Index "+ri.index.Schema().Name+" start failed, error is "+err.Error(), "method", "Start")
+		}
+	}
+	m.Build()
+
+	m.ticker = time.NewTicker(time.Second * 3)
+	m.terminal = make(chan struct{})
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			select {
+			case <-m.ticker.C:
+				m.Build()
+			case <-m.terminal:
+				return
+			}
+		}
+	}()
+
+	m.status = START
+}
+
+func (m *Manager) Stop() {
+	m.statusLock.Lock()
+	defer m.statusLock.Unlock()
+
+	if m.status == STOP {
+		return
+	}
+
+	m.ticker.Stop()
+	close(m.terminal)
+	m.wg.Wait()
+	m.status = STOP
+}
+
+func (m *Manager) Close() error {
+	return m.db.Close()
+}
+
+func stateKey(id byte) []byte {
+	return []byte{metaNamespace, id}
+}
+
+func (m *Manager) getConsumeState(ri *registeredIndex) (*consumeState, error) {
+	value, err := m.db.Get(stateKey(ri.store.id))
+	if err != nil {
+		if err == database.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(value) != 4+8+types.HashSize {
+		return nil, errors.New(fmt.Sprintf("the consume state of index %s is malformed", ri.index.Schema().Name))
+	}
+
+	state := &consumeState{
+		version: binary.BigEndian.Uint32(value[:4]),
+		eventId: binary.BigEndian.Uint64(value[4:12]),
+	}
+	copy(state.eventDigest[:], value[12:])
+	return state, nil
+}
+
+func (m *Manager) writeConsumeState(batch *database.Batch, ri *registeredIndex, state *consumeState) {
+	value := make([]byte, 4+8+types.HashSize)
+	binary.BigEndian.PutUint32(value[:4], state.version)
+	binary.BigEndian.PutUint64(value[4:12], state.eventId)
+	copy(value[12:], state.eventDigest.Bytes())
+
+	batch.Put(stateKey(ri.store.id), value)
+}
+
+func eventDigest(eventType byte, blockHashList []types.Hash) types.Hash {
+	buf := []byte{eventType}
+	for _, blockHash := range blockHashList {
+		buf = append(buf, blockHash.Bytes()...)
+	}
+
+	digest, _ := types.BytesToHash(crypto.Hash256(buf))
+	return digest
+}
+
+// checkConsistency compares the consume state of the index with the head of the event log.
+func (m *Manager) checkConsistency(ri *registeredIndex) (bool, error) {
+	state, err := m.getConsumeState(ri)
+	if err != nil {
+		return false, err
+	}
+	if state == nil {
+		return true, nil
+	}
+	if state.version != ri.index.Schema().Version {
+		return false, nil
+	}
+
+	latestBlockEventId, err := m.chainInstance.GetLatestBlockEventId()
+	if err != nil {
+		return false, err
+	}
+	if state.eventId > latestBlockEventId {
+		return false, nil
+	}
+
+	eventType, blockHashList, err := m.chainInstance.GetEvent(state.eventId)
+	if err != nil {
+		return false, err
+	}
+	return eventDigest(eventType, blockHashList) == state.eventDigest, nil
+}
+
+func (m *Manager) checkAndInitData(ri *registeredIndex) error {
+	if isConsistency, err := m.checkConsistency(ri); err != nil {
+		m.log.Error("checkConsistency failed, error is "+err.Error(), "method", "checkAndInitData")
+		return err
+	} else if !isConsistency {
+		m.log.Info("Index "+ri.index.Schema().Name+" is inconsistent with the chain, it will be rebuilt", "method", "checkAndInitData")
+		return m.clearIndex(ri)
+	}
+	return nil
+}
+
+// clearIndex deletes all keys and the consume state of the index.
+func (m *Manager) clearIndex(ri *registeredIndex) error {
+	batch := new(database.Batch)
+	batch.Delete(stateKey(ri.store.id))
+
+	iter := m.db.NewIterator(database.BytesPrefix([]byte{ri.store.id}))
+	defer iter.Release()
+
+	for iter.Next() {
+		key := make([]byte, len(iter.Key()))
+		copy(key, iter.Key())
+		batch.Delete(key)
+
+		if batch.Len() >= 10000 {
+			if err := m.db.Write(batch); err != nil {
+				return err
+			}
+			batch = new(database.Batch)
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+
+	return m.db.Write(batch)
+}
+
+// Rebuild clears the index, the index is built again from the first event.
+func (m *Manager) Rebuild(name string) error {
+	m.indexLock.RLock()
+	ri, ok := m.indexMap[name]
+	m.indexLock.RUnlock()
+	if !ok {
+		return errors.New(fmt.Sprintf("index %s is not registered", name))
+	}
+
+	m.buildLock.Lock()
+	defer m.buildLock.Unlock()
+
+	return m.clearIndex(ri)
+}
+
+func (m *Manager) Status() ([]*Status, error) {
+	latestEventId, err := m.chainInstance.GetLatestBlockEventId()
+	if err != nil {
+		return nil, err
+	}
+
+	statusList := make([]*Status, 0, len(m.indexes))
+	for _, ri := range m.indexes {
+		state, err := m.getConsumeState(ri)
+		if err != nil {
+			return nil, err
+		}
+
+		status := &Status{
+			Schema:        ri.index.Schema(),
+			LatestEventId: latestEventId,
+		}
+		if state != nil {
+			status.ConsumedEventId = state.eventId
+		}
+		status.Synced = status.ConsumedEventId >= latestEventId
+
+		ri.buildResultLock.Lock()
+		status.LastBuildTime = ri.lastBuildTime
+		if ri.lastError != nil {
+			status.LastError = ri.lastError.Error()
+		}
+		ri.buildResultLock.Unlock()
+		statusList = append(statusList, status)
+	}
+	return statusList, nil
+}
+
+// Build consumes the new events for all indexes.
+func (m *Manager) Build() {
+	m.buildLock.Lock()
+	defer m.buildLock.Unlock()
+
+	for _, ri := range m.indexes {
+		err := m.build(ri)
+		now := time.Now()
+
+		ri.buildResultLock.Lock()
+		ri.lastBuildTime = &now
+		ri.lastError = err
+		ri.buildResultLock.Unlock()
+
+		if err != nil {
+			m.log.Error("Build index "+ri.index.Schema().Name+" failed, error is "+err.Error(), "method", "Build")
+		}
+	}
+}
+
+// pendingEvents collects the consecutive add events, they are handled together.
+type pendingEvents struct {
+	accountBlocks  []*ledger.AccountBlock
+	snapshotBlocks []*ledger.SnapshotBlock
+}
+
+func (m *Manager) build(ri *registeredIndex) error {
+	state, err := m.getConsumeState(ri)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &consumeState{
+			version: ri.index.Schema().Version,
+		}
+	}
+
+	latestBeId, err := m.chainInstance.GetLatestBlockEventId()
+	if err != nil {
+		return err
+	}
+
+	snapshotIndex, isSnapshotIndex := ri.index.(SnapshotIndex)
+
+	// the blocks are deleted before the add events are consumed
+	notFoundBlocks := make(map[types.Hash]struct{})
+
+	pending := &pendingEvents{}
+	eventNum := uint64(0)
+	var prevEventType byte
+	var prevBlockHashList []types.Hash
+
+	flush := func(eventId uint64, eventType byte, blockHashList []types.Hash) error {
+		batch := new(database.Batch)
+
+		if len(pending.accountBlocks) > 0 {
+			if err := ri.index.AddAccountBlocks(batch, pending.accountBlocks); err != nil {
+				return err
+			}
+		}
+		if isSnapshotIndex && len(pending.snapshotBlocks) > 0 {
+			if err := snapshotIndex.AddSnapshotBlocks(batch, pending.snapshotBlocks); err != nil {
+				return err
+			}
+		}
+		pending = &pendingEvents{}
+		eventNum = 0
+
+		state.eventId = eventId
+		state.eventDigest = eventDigest(eventType, blockHashList)
+		m.writeConsumeState(batch, ri, state)
+		return m.db.Write(batch)
+	}
+
+	for eventId := state.eventId + 1; eventId <= latestBeId; eventId++ {
+		eventType, blockHashList, err := m.chainInstance.GetEvent(eventId)
+		if err != nil {
+			return err
+		}
+
+		switch eventType {
+		case access.AddAccountBlocksEvent:
+			for _, blockHash := range blockHashList {
+				block, err := m.chainInstance.GetAccountBlockByHash(&blockHash)
+				if err != nil {
+					return err
+				}
+
+				if block == nil {
+					notFoundBlocks[blockHash] = struct{}{}
+					continue
+				}
+				pending.accountBlocks = append(pending.accountBlocks, block)
+			}
+
+		case access.AddSnapshotBlocksEvent:
+			if !isSnapshotIndex {
+				break
+			}
+			for _, blockHash := range blockHashList {
+				block, err := m.chainInstance.GetSnapshotBlockByHash(&blockHash)
+				if err != nil {
+					return err
+				}
+
+				if block == nil {
+					notFoundBlocks[blockHash] = struct{}{}
+					continue
+				}
+				pending.snapshotBlocks = append(pending.snapshotBlocks, block)
+			}
+
+		case access.DeleteAccountBlocksEvent, access.DeleteSnapshotBlocksEvent:
+			if eventType == access.DeleteSnapshotBlocksEvent && !isSnapshotIndex {
+				break
+			}
+
+			// the add events before must be handled first
+			if eventNum > 0 {
+				if err := flush(eventId-1, prevEventType, prevBlockHashList); err != nil {
+					return err
+				}
+			}
+
+			var hashList []types.Hash
+			for _, hash := range blockHashList {
+				if _, ok := notFoundBlocks[hash]; ok {
+					delete(notFoundBlocks, hash)
+					continue
+				}
+				hashList = append(hashList, hash)
+			}
+			if len(hashList) <= 0 {
+				// nothing to delete, the event is consumed like an add event
+				break
+			}
+
+			batch := new(database.Batch)
+			if eventType == access.DeleteAccountBlocksEvent {
+				err = ri.index.DeleteAccountBlocks(batch, hashList)
+			} else {
+				err = snapshotIndex.DeleteSnapshotBlocks(batch, hashList)
+			}
+			if err != nil {
+				return err
+			}
+
+			state.eventId = eventId
+			state.eventDigest = eventDigest(eventType, blockHashList)
+			m.writeConsumeState(batch, ri, state)
+			if err := m.db.Write(batch); err != nil {
+				return err
+			}
+			continue
+		}
+
+		prevEventType, prevBlockHashList = eventType, blockHashList
+		eventNum++
+		if eventId >= latestBeId || eventNum >= m.EventNumPerBatch {
+			if err := flush(eventId, eventType, blockHashList); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package chain_index
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/access"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
+)
+
+type testEvent struct {
+	eventType     byte
+	blockHashList []types.Hash
+}
+
+type testChain struct {
+	events         []testEvent
+	accountBlocks  map[types.Hash]*ledger.AccountBlock
+	snapshotBlocks map[types.Hash]*ledger.SnapshotBlock
+	blockMetas     map[types.Hash]*ledger.AccountBlockMeta
+	vmLogLists     map[types.Hash]ledger.VmLogList
+	trieDb         database.Store
+
+	// the states of the snapshot blocks below it are pruned
+	prunedHeight uint64
+}
+
+func newTestChain() *testChain {
+	return &testChain{
+		accountBlocks:  make(map[types.Hash]*ledger.AccountBlock),
+		snapshotBlocks: make(map[types.Hash]*ledger.SnapshotBlock),
+		blockMetas:     make(map[types.Hash]*ledger.AccountBlockMeta),
+		vmLogLists:     make(map[types.Hash]ledger.VmLogList),
+		trieDb:         database.NewMemStore(),
+	}
+}
+
+func (c *testChain) addAccountBlock(block *ledger.AccountBlock) {
+	c.accountBlocks[block.Hash] = block
+	c.events = append(c.events, testEvent{access.AddAccountBlocksEvent, []types.Hash{block.Hash}})
+}
+
+func (c *testChain) deleteAccountBlock(hash types.Hash) {
+	delete(c.accountBlocks, hash)
+	c.events = append(c.events, testEvent{access.DeleteAccountBlocksEvent, []types.Hash{hash}})
+}
+
+// addSnapshotBlock confirms the account blocks in the snapshot content, like the chain only the content blocks record
+// the snapshot height.
+func (c *testChain) addSnapshotBlock(block *ledger.SnapshotBlock) {
+	c.snapshotBlocks[block.Hash] = block
+	for _, hashHeight := range block.SnapshotContent {
+		c.blockMetas[hashHeight.Hash] = &ledger.AccountBlockMeta{Height: hashHeight.Height, SnapshotHeight: block.Height}
+	}
+	c.events = append(c.events, testEvent{access.AddSnapshotBlocksEvent, []types.Hash{block.Hash}})
+}
+
+func (c *testChain) deleteSnapshotBlock(hash types.Hash) {
+	for _, hashHeight := range c.snapshotBlocks[hash].SnapshotContent {
+		delete(c.blockMetas, hashHeight.Hash)
+	}
+	delete(c.snapshotBlocks, hash)
+	c.events = append(c.events, testEvent{access.DeleteSnapshotBlocksEvent, []types.Hash{hash}})
+}
+
+func (c *testChain) GetLatestBlockEventId() (uint64, error) {
+	return uint64(len(c.events)), nil
+}
+
+func (c *testChain) GetEvent(eventId uint64) (byte, []types.Hash, error) {
+	if eventId <= 0 || eventId > uint64(len(c.events)) {
+		return 0, nil, nil
+	}
+	event := c.events[eventId-1]
+	return event.eventType, event.blockHashList, nil
+}
+
+func (c *testChain) GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error) {
+	return c.accountBlocks[*blockHash], nil
+}
+
+func (c *testChain) GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error) {
+	if meta, ok := c.blockMetas[*hash]; ok {
+		return meta, nil
+	}
+	if block, ok := c.accountBlocks[*hash]; ok {
+		return &ledger.AccountBlockMeta{Height: block.Height}, nil
+	}
+	return nil, nil
+}
+
+func (c *testChain) GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error) {
+	return c.vmLogLists[*logListHash], nil
+}
+
+func (c *testChain) GetStateTrie(stateHash *types.Hash) *trie.Trie {
+	return trie.NewTrie(c.trieDb, stateHash, nil)
+}
+
+func (c *testChain) GetSnapshotBlockByHash(hash *types.Hash) (*ledger.SnapshotBlock, error) {
+	return c.snapshotBlocks[*hash], nil
+}
+
+func (c *testChain) IsStatePruned(snapshotBlock *ledger.SnapshotBlock) (bool, error) {
+	return snapshotBlock.Height < c.prunedHeight, nil
+}
+
+func (c *testChain) GetAccount(address *types.Address) (*ledger.Account, error) {
+	return &ledger.Account{AccountAddress: *address, AccountId: 1}, nil
+}
+
+func (c *testChain) IsAccountBlockExisted(hash types.Hash) (bool, error) {
+	_, ok := c.accountBlocks[hash]
+	return ok, nil
+}
+
+func (c *testChain) ChainDb() *chain_db.ChainDb {
+	return nil
+}
+
+func (c *testChain) IsGenesisAccountBlock(block *ledger.AccountBlock) bool {
+	return false
+}
+
+// testIndex keeps one key for every indexed block.
+type testIndex struct {
+	store *Store
+}
+
+func (ti *testIndex) Schema() *Schema {
+	return &Schema{
+		Name:    "test",
+		Id:      100,
+		Version: 1,
+		Keys:    []KeySchema{{Prefix: 1, Name: "block", Fields: []string{"blockHash"}}},
+	}
+}
+
+func (ti *testIndex) Open(store *Store) {
+	ti.store = store
+}
+
+func (ti *testIndex) AddAccountBlocks(batch *database.Batch, blocks []*ledger.AccountBlock) error {
+	for _, block := range blocks {
+		batch.Put(ti.store.EncodeKey(1, block.Hash.Bytes()), nil)
+	}
+	return nil
+}
+
+func (ti *testIndex) DeleteAccountBlocks(batch *database.Batch, hashList []types.Hash) error {
+	for _, hash := range hashList {
+		batch.Delete(ti.store.EncodeKey(1, hash.Bytes()))
+	}
+	return nil
+}
+
+func (ti *testIndex) has(t *testing.T, hash types.Hash) bool {
+	ok, err := ti.store.Has(ti.store.EncodeKey(1, hash.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ok
+}
+
+func newTestManager(t *testing.T, db database.Store, c *testChain) (*Manager, *testIndex) {
+	m, err := NewManagerWithStore(db, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.EventNumPerBatch = 2
+
+	ti := &testIndex{}
+	if err := m.Register(ti); err != nil {
+		t.Fatal(err)
+	}
+	return m, ti
+}
+
+func testStatus(t *testing.T, m *Manager) *Status {
+	statusList, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statusList) != 1 {
+		t.Fatalf("expect 1 status, got %d", len(statusList))
+	}
+	if statusList[0].LastError != "" {
+		t.Fatal(statusList[0].LastError)
+	}
+	return statusList[0]
+}
+
+func TestManager(t *testing.T) {
+	db := database.NewMemStore()
+	c := newTestChain()
+	m, ti := newTestManager(t, db, c)
+
+	if err := m.Register(&testIndex{}); err == nil {
+		t.Fatal("register an index twice should fail")
+	}
+
+	blocks := make([]*ledger.AccountBlock, 5)
+	for i := range blocks {
+		blocks[i] = &ledger.AccountBlock{Hash: types.Hash{byte(i + 1)}, Height: uint64(i + 1)}
+	}
+
+	c.addAccountBlock(blocks[0])
+	c.addAccountBlock(blocks[1])
+	c.addAccountBlock(blocks[2])
+	m.Build()
+	if !ti.has(t, blocks[0].Hash) || !ti.has(t, blocks[2].Hash) {
+		t.Fatal("the added blocks should be indexed")
+	}
+	if status := testStatus(t, m); !status.Synced || status.ConsumedEventId != 3 {
+		t.Fatalf("status is %+v", status)
+	}
+
+	// blocks[3] is deleted before its add event is consumed, blocks[2] is deleted after
+	c.addAccountBlock(blocks[3])
+	c.deleteAccountBlock(blocks[3].Hash)
+	c.deleteAccountBlock(blocks[2].Hash)
+	c.addAccountBlock(blocks[4])
+	m.Build()
+	if ti.has(t, blocks[2].Hash) || ti.has(t, blocks[3].Hash) {
+		t.Fatal("the deleted blocks should not be indexed")
+	}
+	if !ti.has(t, blocks[4].Hash) {
+		t.Fatal("the added block should be indexed")
+	}
+	if status := testStatus(t, m); !status.Synced || status.ConsumedEventId != 7 {
+		t.Fatalf("status is %+v", status)
+	}
+
+	// rebuild
+	if err := m.Rebuild("test"); err != nil {
+		t.Fatal(err)
+	}
+	if ti.has(t, blocks[0].Hash) || testStatus(t, m).ConsumedEventId != 0 {
+		t.Fatal("the index should be cleared")
+	}
+	m.Build()
+	if !ti.has(t, blocks[0].Hash) || ti.has(t, blocks[2].Hash) || !ti.has(t, blocks[4].Hash) {
+		t.Fatal("the rebuilt index is wrong")
+	}
+
+	// the chain is recreated, the event 7 is different
+	c2 := newTestChain()
+	for i := 0; i < 7; i++ {
+		c2.addAccountBlock(&ledger.AccountBlock{Hash: types.Hash{byte(i + 10)}})
+	}
+	m2, ti2 := newTestManager(t, db, c2)
+	if consistent, err := m2.checkConsistency(m2.indexes[0]); err != nil || consistent {
+		t.Fatalf("the index should be inconsistent with the new chain, error is %v", err)
+	}
+	if err := m2.checkAndInitData(m2.indexes[0]); err != nil {
+		t.Fatal(err)
+	}
+	m2.Build()
+	if ti2.has(t, blocks[0].Hash) || !ti2.has(t, types.Hash{10}) {
+		t.Fatal("the index should be rebuilt from the new chain")
+	}
+}
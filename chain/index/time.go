@@ -0,0 +1,342 @@
+package chain_index
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const TimeIndexName = "time"
+
+const (
+	DBKP_TIME_SNAPSHOT = byte(1)
+
+	DBKP_TIME_SNAPSHOT_RECORD = byte(2)
+
+	DBKP_TIME_ACCOUNT_BLOCK = byte(3)
+
+	DBKP_TIME_DAILY = byte(4)
+
+	DBKP_TIME_DAILY_SENDER = byte(5)
+)
+
+var timeSchema = &Schema{
+	Name:    TimeIndexName,
+	Id:      4,
+	Version: 1,
+	Keys: []KeySchema{
+		{Prefix: DBKP_TIME_SNAPSHOT, Name: "snapshot", Fields: []string{"timestamp", "snapshotHeight"}, Value: "snapshotHash"},
+		{Prefix: DBKP_TIME_SNAPSHOT_RECORD, Name: "snapshotRecord", Fields: []string{"snapshotHash"}, Value: "timestamp+snapshotHeight+[address+height+isSend]"},
+		{Prefix: DBKP_TIME_ACCOUNT_BLOCK, Name: "accountBlock", Fields: []string{"address", "timestamp", "height"}, Value: "accountBlockHash"},
+		{Prefix: DBKP_TIME_DAILY, Name: "daily", Fields: []string{"day"}, Value: "txCount+senderCount"},
+		{Prefix: DBKP_TIME_DAILY_SENDER, Name: "dailySender", Fields: []string{"day", "address"}, Value: "sendBlockCount"},
+	},
+}
+
+const SecondsPerDay = 24 * 60 * 60
+
+// the length of address+height+isSend of a block confirmed by a snapshot block, in the snapshot record
+const timeRecordEntryLen = types.AddressSize + 8 + 1
+
+// DailyStatistics is the rollup of the account blocks confirmed by the snapshot blocks of a day in UTC. TxCount is the
+// count of the confirmed blocks, both the send and the receive blocks, SenderCount is the count of the addresses
+// which sent transactions in the day.
+type DailyStatistics struct {
+	Day         uint64
+	TxCount     uint64
+	SenderCount uint64
+}
+
+// StartTime returns the beginning of the day.
+func (ds *DailyStatistics) StartTime() time.Time {
+	return time.Unix(int64(ds.Day*SecondsPerDay), 0).UTC()
+}
+
+type dailyDelta struct {
+	txCount int64
+	senders map[types.Address]int64
+}
+
+// TimeIndex indexes the snapshot blocks by the timestamp, and the account blocks by the timestamp of the snapshot
+// blocks which confirmed them. The confirmed blocks are rolled up by day as well.
+type TimeIndex struct {
+	store         *Store
+	chainInstance Chain
+}
+
+func NewTimeIndex(chainInstance Chain) *TimeIndex {
+	return &TimeIndex{
+		chainInstance: chainInstance,
+	}
+}
+
+func (ti *TimeIndex) Schema() *Schema {
+	return timeSchema
+}
+
+func (ti *TimeIndex) Open(store *Store) {
+	ti.store = store
+}
+
+// AddAccountBlocks does nothing, the blocks are indexed when they are confirmed.
+func (ti *TimeIndex) AddAccountBlocks(batch *database.Batch, blocks []*ledger.AccountBlock) error {
+	return nil
+}
+
+// DeleteAccountBlocks does nothing, the confirmed blocks are deleted with their snapshot blocks.
+func (ti *TimeIndex) DeleteAccountBlocks(batch *database.Batch, hashList []types.Hash) error {
+	return nil
+}
+
+func unixTimestamp(t *time.Time) uint64 {
+	if t == nil || t.Unix() < 0 {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
+func (ti *TimeIndex) AddSnapshotBlocks(batch *database.Batch, blocks []*ledger.SnapshotBlock) error {
+	deltas := make(map[uint64]*dailyDelta)
+
+	for _, snapshotBlock := range blocks {
+		timestamp := unixTimestamp(snapshotBlock.Timestamp)
+		day := timestamp / SecondsPerDay
+
+		record := make([]byte, 16)
+		binary.BigEndian.PutUint64(record[:8], timestamp)
+		binary.BigEndian.PutUint64(record[8:], snapshotBlock.Height)
+
+		for _, hashHeight := range snapshotBlock.SnapshotContent {
+			confirmedBlocks, err := getConfirmedBlocks(ti.chainInstance, &hashHeight.Hash)
+			if err != nil {
+				return err
+			}
+
+			for _, block := range confirmedBlocks {
+				batch.Put(ti.store.EncodeKey(DBKP_TIME_ACCOUNT_BLOCK, block.AccountAddress.Bytes(), timestamp, block.Height), block.Hash.Bytes())
+
+				isSend := byte(0)
+				if block.IsSendBlock() {
+					isSend = 1
+				}
+				record = append(record, block.AccountAddress.Bytes()...)
+				record = append(record, uint64ToBytes(block.Height)...)
+				record = append(record, isSend)
+
+				addDailyDelta(deltas, day, block.AccountAddress, isSend == 1, 1)
+			}
+		}
+
+		batch.Put(ti.store.EncodeKey(DBKP_TIME_SNAPSHOT, timestamp, snapshotBlock.Height), snapshotBlock.Hash.Bytes())
+		batch.Put(ti.store.EncodeKey(DBKP_TIME_SNAPSHOT_RECORD, snapshotBlock.Hash.Bytes()), record)
+	}
+
+	return ti.writeDailyDeltas(batch, deltas)
+}
+
+func (ti *TimeIndex) DeleteSnapshotBlocks(batch *database.Batch, hashList []types.Hash) error {
+	deltas := make(map[uint64]*dailyDelta)
+
+	for _, hash := range hashList {
+		recordKey := ti.store.EncodeKey(DBKP_TIME_SNAPSHOT_RECORD, hash.Bytes())
+		record, err := ti.store.Get(recordKey)
+		if err != nil {
+			if err == database.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		if len(record) < 16 || (len(record)-16)%timeRecordEntryLen != 0 {
+			return errors.New("snapshot record is malformed")
+		}
+
+		timestamp := binary.BigEndian.Uint64(record[:8])
+		height := binary.BigEndian.Uint64(record[8:16])
+		day := timestamp / SecondsPerDay
+
+		for entries := record[16:]; len(entries) > 0; entries = entries[timeRecordEntryLen:] {
+			var addr types.Address
+			copy(addr[:], entries[:types.AddressSize])
+			blockHeight := binary.BigEndian.Uint64(entries[types.AddressSize : types.AddressSize+8])
+			isSend := entries[types.AddressSize+8] == 1
+
+			batch.Delete(ti.store.EncodeKey(DBKP_TIME_ACCOUNT_BLOCK, addr.Bytes(), timestamp, blockHeight))
+			addDailyDelta(deltas, day, addr, isSend, -1)
+		}
+
+		batch.Delete(ti.store.EncodeKey(DBKP_TIME_SNAPSHOT, timestamp, height))
+		batch.Delete(recordKey)
+	}
+
+	return ti.writeDailyDeltas(batch, deltas)
+}
+
+func addDailyDelta(deltas map[uint64]*dailyDelta, day uint64, addr types.Address, isSend bool, delta int64) {
+	dd := deltas[day]
+	if dd == nil {
+		dd = &dailyDelta{senders: make(map[types.Address]int64)}
+		deltas[day] = dd
+	}
+	dd.txCount += delta
+	if isSend {
+		dd.senders[addr] += delta
+	}
+}
+
+func (ti *TimeIndex) getCount(key []byte) (uint64, error) {
+	value, err := ti.store.Get(key)
+	if err != nil {
+		if err == database.ErrNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(value), nil
+}
+
+// writeDailyDeltas applies the deltas to the rollups, the deltas of all blocks of a call are accumulated first because
+// the batch isn't readable.
+func (ti *TimeIndex) writeDailyDeltas(batch *database.Batch, deltas map[uint64]*dailyDelta) error {
+	for day, dd := range deltas {
+		stat, err := ti.getDailyStatistics(day)
+		if err != nil {
+			return err
+		}
+
+		for addr, delta := range dd.senders {
+			if delta == 0 {
+				continue
+			}
+			senderKey := ti.store.EncodeKey(DBKP_TIME_DAILY_SENDER, day, addr.Bytes())
+			count, err := ti.getCount(senderKey)
+			if err != nil {
+				return err
+			}
+
+			newCount := uint64(int64(count) + delta)
+			if count == 0 {
+				stat.SenderCount++
+			} else if newCount == 0 {
+				stat.SenderCount--
+			}
+
+			if newCount > 0 {
+				batch.Put(senderKey, uint64ToBytes(newCount))
+			} else {
+				batch.Delete(senderKey)
+			}
+		}
+
+		stat.TxCount = uint64(int64(stat.TxCount) + dd.txCount)
+		dailyKey := ti.store.EncodeKey(DBKP_TIME_DAILY, day)
+		if stat.TxCount > 0 {
+			batch.Put(dailyKey, append(uint64ToBytes(stat.TxCount), uint64ToBytes(stat.SenderCount)...))
+		} else {
+			batch.Delete(dailyKey)
+		}
+	}
+	return nil
+}
+
+func parseDailyStatistics(day uint64, value []byte) (*DailyStatistics, error) {
+	if len(value) != 16 {
+		return nil, errors.New("daily statistics is malformed")
+	}
+	return &DailyStatistics{
+		Day:         day,
+		TxCount:     binary.BigEndian.Uint64(value[:8]),
+		SenderCount: binary.BigEndian.Uint64(value[8:]),
+	}, nil
+}
+
+func (ti *TimeIndex) getDailyStatistics(day uint64) (*DailyStatistics, error) {
+	value, err := ti.store.Get(ti.store.EncodeKey(DBKP_TIME_DAILY, day))
+	if err != nil {
+		if err == database.ErrNotFound {
+			return &DailyStatistics{Day: day}, nil
+		}
+		return nil, err
+	}
+	return parseDailyStatistics(day, value)
+}
+
+// timeRange returns the key range of prefix+[from, to], the timestamps are the first part of the keys after prefix.
+func (ti *TimeIndex) timeRange(prefix []byte, from uint64, to uint64) *database.Range {
+	keyRange := database.BytesPrefix(prefix)
+	keyRange.Start = append(append([]byte{}, prefix...), uint64ToBytes(from)...)
+	if to < ^uint64(0) {
+		keyRange.Limit = append(append([]byte{}, prefix...), uint64ToBytes(to+1)...)
+	}
+	return keyRange
+}
+
+// GetSnapshotBlockHashByTime returns the hash of the latest snapshot block whose timestamp isn't after timestamp,
+// returns nil if all snapshot blocks are after timestamp.
+func (ti *TimeIndex) GetSnapshotBlockHashByTime(timestamp uint64) (*types.Hash, error) {
+	iter := ti.store.NewIterator(ti.timeRange(ti.store.EncodeKey(DBKP_TIME_SNAPSHOT), 0, timestamp))
+	defer iter.Release()
+
+	if !iter.Last() {
+		if err := iter.Error(); err != nil && err != database.ErrNotFound {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	hash, err := types.BytesToHash(iter.Value())
+	if err != nil {
+		return nil, err
+	}
+	return &hash, nil
+}
+
+// GetAccountBlockHashList returns the hashes of the blocks of addr which are confirmed in [from, to], ordered by the
+// confirmed time, then by the height. At most count hashes are returned after skipping offset ones.
+func (ti *TimeIndex) GetAccountBlockHashList(addr types.Address, from uint64, to uint64, offset int, count int) ([]types.Hash, error) {
+	iter := ti.store.NewIterator(ti.timeRange(ti.store.EncodeKey(DBKP_TIME_ACCOUNT_BLOCK, addr.Bytes()), from, to))
+	defer iter.Release()
+
+	var hashList []types.Hash
+	skipped := 0
+	for iter.Next() && len(hashList) < count {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		hash, err := types.BytesToHash(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		hashList = append(hashList, hash)
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return hashList, nil
+}
+
+// GetDailyStatistics returns the rollups of the days from fromDay to toDay, the days without transactions are
+// omitted. The days are counted from the unix epoch.
+func (ti *TimeIndex) GetDailyStatistics(fromDay uint64, toDay uint64) ([]*DailyStatistics, error) {
+	prefix := ti.store.EncodeKey(DBKP_TIME_DAILY)
+	iter := ti.store.NewIterator(ti.timeRange(prefix, fromDay, toDay))
+	defer iter.Release()
+
+	var statList []*DailyStatistics
+	for iter.Next() {
+		stat, err := parseDailyStatistics(binary.BigEndian.Uint64(iter.Key()[len(prefix):]), iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		statList = append(statList, stat)
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return statList, nil
+}
@@ -0,0 +1,124 @@
+package chain_index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func TestTimeIndex(t *testing.T) {
+	c := newTestChain()
+	m, err := NewManagerWithStore(database.NewMemStore(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ti := NewTimeIndex(c)
+	if err := m.Register(ti); err != nil {
+		t.Fatal(err)
+	}
+
+	addrA := types.Address{1}
+	addrB := types.Address{2}
+
+	newBlock := func(addr types.Address, height uint64, prevHash types.Hash, blockType byte) *ledger.AccountBlock {
+		block := &ledger.AccountBlock{
+			BlockType:      blockType,
+			Hash:           types.Hash{addr[0], byte(height)},
+			Height:         height,
+			PrevHash:       prevHash,
+			AccountAddress: addr,
+		}
+		c.addAccountBlock(block)
+		return block
+	}
+	newSnapshotBlock := func(height uint64, timestamp int64, blocks ...*ledger.AccountBlock) *ledger.SnapshotBlock {
+		blockTime := time.Unix(timestamp, 0)
+		block := &ledger.SnapshotBlock{
+			Hash:            types.Hash{0x50, byte(height)},
+			Height:          height,
+			Timestamp:       &blockTime,
+			SnapshotContent: ledger.SnapshotContent{},
+		}
+		for _, accountBlock := range blocks {
+			block.SnapshotContent[accountBlock.AccountAddress] = &ledger.HashHeight{Hash: accountBlock.Hash, Height: accountBlock.Height}
+		}
+		c.addSnapshotBlock(block)
+		return block
+	}
+
+	day := int64(17000 * SecondsPerDay)
+	a1 := newBlock(addrA, 1, types.Hash{}, ledger.BlockTypeSendCall)
+	a2 := newBlock(addrA, 2, a1.Hash, ledger.BlockTypeSendCall)
+	b1 := newBlock(addrB, 1, types.Hash{}, ledger.BlockTypeReceive)
+	s1 := newSnapshotBlock(1, day+100, a2, b1)
+	b2 := newBlock(addrB, 2, b1.Hash, ledger.BlockTypeSendCall)
+	s2 := newSnapshotBlock(2, day+200, b2)
+	a3 := newBlock(addrA, 3, a2.Hash, ledger.BlockTypeSendCall)
+	s3 := newSnapshotBlock(3, day+SecondsPerDay+100, a3)
+	m.Build()
+
+	expectSnapshot := func(timestamp int64, expected *ledger.SnapshotBlock) {
+		hash, err := ti.GetSnapshotBlockHashByTime(uint64(timestamp))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected == nil {
+			if hash != nil {
+				t.Fatalf("time %d, expect no snapshot block, got %s", timestamp, hash)
+			}
+			return
+		}
+		if hash == nil || *hash != expected.Hash {
+			t.Fatalf("time %d, expect %s, got %v", timestamp, expected.Hash, hash)
+		}
+	}
+	expectSnapshot(day, nil)
+	expectSnapshot(day+100, s1)
+	expectSnapshot(day+199, s1)
+	expectSnapshot(day+SecondsPerDay*2, s3)
+
+	expectBlocks := func(addr types.Address, from, to int64, offset, count int, expected ...*ledger.AccountBlock) {
+		hashList, err := ti.GetAccountBlockHashList(addr, uint64(from), uint64(to), offset, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hashList) != len(expected) {
+			t.Fatalf("expect %d hashes, got %d", len(expected), len(hashList))
+		}
+		for i, block := range expected {
+			if hashList[i] != block.Hash {
+				t.Fatalf("hash %d, expect %s, got %s", i, block.Hash, hashList[i])
+			}
+		}
+	}
+	expectBlocks(addrA, day, day+SecondsPerDay*2, 0, 10, a1, a2, a3)
+	expectBlocks(addrA, day, day+100, 1, 10, a2)
+	expectBlocks(addrA, day+101, day+SecondsPerDay*2, 0, 10, a3)
+	expectBlocks(addrB, day+200, day+200, 0, 10, b2)
+
+	expectStatistics := func(expected ...DailyStatistics) {
+		statList, err := ti.GetDailyStatistics(0, ^uint64(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(statList) != len(expected) {
+			t.Fatalf("expect %d days, got %d", len(expected), len(statList))
+		}
+		for i, stat := range expected {
+			if *statList[i] != stat {
+				t.Fatalf("day %d, expect %+v, got %+v", i, stat, statList[i])
+			}
+		}
+	}
+	expectStatistics(DailyStatistics{17000, 4, 2}, DailyStatistics{17001, 1, 1})
+
+	c.deleteSnapshotBlock(s3.Hash)
+	c.deleteSnapshotBlock(s2.Hash)
+	m.Build()
+	expectSnapshot(day+SecondsPerDay*2, s1)
+	expectBlocks(addrB, day, day+SecondsPerDay*2, 0, 10, b1)
+	expectStatistics(DailyStatistics{17000, 3, 1})
+}
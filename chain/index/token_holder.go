@@ -0,0 +1,506 @@
+package chain_index
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/helper"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+const TokenHolderIndexName = "tokenHolder"
+
+const (
+	DBKP_TOKEN_HOLDER = byte(1)
+
+	DBKP_TOKEN_HOLDER_BALANCE = byte(2)
+
+	DBKP_TOKEN_HOLDER_STAT = byte(3)
+
+	DBKP_TOKEN_HOLDER_UNDO = byte(4)
+)
+
+var tokenHolderSchema = &Schema{
+	Name:    TokenHolderIndexName,
+	Id:      5,
+	Version: 2,
+	Keys: []KeySchema{
+		{Prefix: DBKP_TOKEN_HOLDER, Name: "holder", Fields: []string{"tokenId", "balance", "address"}},
+		{Prefix: DBKP_TOKEN_HOLDER_BALANCE, Name: "balance", Fields: []string{"address", "tokenId"}, Value: "balance"},
+		{Prefix: DBKP_TOKEN_HOLDER_STAT, Name: "stat", Fields: []string{"tokenId", "snapshotHeight"}, Value: "holderCount+supply"},
+		{Prefix: DBKP_TOKEN_HOLDER_UNDO, Name: "undo", Fields: []string{"snapshotHash"}, Value: "snapshotHeight+supplyCount+[tokenId]+[address+tokenId+balanceLen+balance]"},
+	},
+}
+
+// the balances are padded to this length in the holder keys, so the holders are ordered by the balance
+const holderBalanceLen = 32
+
+type TokenHolder struct {
+	Address types.Address
+	Balance *big.Int
+}
+
+// TokenStat is the holder count and the supply of a token after the snapshot block of SnapshotHeight. The supply is
+// the total supply recorded by the mintage contract, the minted amount minus the burned amount, so the amounts onroad
+// are counted while the sum of the holder balances misses them.
+type TokenStat struct {
+	SnapshotHeight uint64
+	HolderCount    uint64
+	Supply         *big.Int
+}
+
+// TokenHolderIndex indexes the balances of every token by the holder, the balances are read from the state of the
+// account blocks confirmed by the snapshot blocks, and the supplies are read from the state of the mintage contract.
+// The holder count and the supply of a token are recorded at every snapshot height they change.
+type TokenHolderIndex struct {
+	store         *Store
+	chainInstance Chain
+}
+
+func NewTokenHolderIndex(chainInstance Chain) *TokenHolderIndex {
+	return &TokenHolderIndex{
+		chainInstance: chainInstance,
+	}
+}
+
+func (thi *TokenHolderIndex) Schema() *Schema {
+	return tokenHolderSchema
+}
+
+func (thi *TokenHolderIndex) Open(store *Store) {
+	thi.store = store
+}
+
+// AddAccountBlocks does nothing, the balances are indexed when the blocks are confirmed.
+func (thi *TokenHolderIndex) AddAccountBlocks(batch *database.Batch, blocks []*ledger.AccountBlock) error {
+	return nil
+}
+
+// DeleteAccountBlocks does nothing, the balances are rolled back with the snapshot blocks.
+func (thi *TokenHolderIndex) DeleteAccountBlocks(batch *database.Batch, hashList []types.Hash) error {
+	return nil
+}
+
+// holderWriter caches the balances and the stats changed in a batch, the batch isn't readable.
+type holderWriter struct {
+	thi   *TokenHolderIndex
+	batch *database.Batch
+
+	balances map[types.Address]map[types.TokenTypeId]*big.Int
+	stats    map[types.TokenTypeId]*TokenStat
+}
+
+func (thi *TokenHolderIndex) newHolderWriter(batch *database.Batch) *holderWriter {
+	return &holderWriter{
+		thi:      thi,
+		batch:    batch,
+		balances: make(map[types.Address]map[types.TokenTypeId]*big.Int),
+		stats:    make(map[types.TokenTypeId]*TokenStat),
+	}
+}
+
+func (hw *holderWriter) getBalances(addr types.Address) (map[types.TokenTypeId]*big.Int, error) {
+	if balances, ok := hw.balances[addr]; ok {
+		return balances, nil
+	}
+
+	balances, err := hw.thi.getAccountBalances(addr)
+	if err != nil {
+		return nil, err
+	}
+	hw.balances[addr] = balances
+	return balances, nil
+}
+
+func (hw *holderWriter) getStat(tokenId types.TokenTypeId) (*TokenStat, error) {
+	if stat, ok := hw.stats[tokenId]; ok {
+		return stat, nil
+	}
+
+	stat, err := hw.thi.GetTokenStat(tokenId, 0)
+	if err != nil {
+		return nil, err
+	}
+	hw.stats[tokenId] = stat
+	return stat, nil
+}
+
+// setBalance sets the balance of addr, zero balance removes addr from the holders. It returns the previous balance.
+func (hw *holderWriter) setBalance(addr types.Address, tokenId types.TokenTypeId, balance *big.Int) (*big.Int, error) {
+	balances, err := hw.getBalances(addr)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := hw.getStat(tokenId)
+	if err != nil {
+		return nil, err
+	}
+
+	prevBalance := balances[tokenId]
+	if prevBalance == nil {
+		prevBalance = big.NewInt(0)
+	}
+	if prevBalance.Cmp(balance) == 0 {
+		return prevBalance, nil
+	}
+
+	thi := hw.thi
+	if prevBalance.Sign() > 0 {
+		hw.batch.Delete(thi.holderKey(tokenId, prevBalance, addr))
+		stat.HolderCount--
+	}
+	if balance.Sign() > 0 {
+		hw.batch.Put(thi.holderKey(tokenId, balance, addr), nil)
+		hw.batch.Put(thi.store.EncodeKey(DBKP_TOKEN_HOLDER_BALANCE, addr.Bytes(), tokenId.Bytes()), balance.Bytes())
+		balances[tokenId] = balance
+		stat.HolderCount++
+	} else {
+		hw.batch.Delete(thi.store.EncodeKey(DBKP_TOKEN_HOLDER_BALANCE, addr.Bytes(), tokenId.Bytes()))
+		delete(balances, tokenId)
+	}
+	return prevBalance, nil
+}
+
+// setSupply sets the supply of the token, it reports whether the supply is changed.
+func (hw *holderWriter) setSupply(tokenId types.TokenTypeId, supply *big.Int) (bool, error) {
+	stat, err := hw.getStat(tokenId)
+	if err != nil {
+		return false, err
+	}
+	if stat.Supply.Cmp(supply) == 0 {
+		return false, nil
+	}
+	stat.Supply = supply
+	return true, nil
+}
+
+func (thi *TokenHolderIndex) holderKey(tokenId types.TokenTypeId, balance *big.Int, addr types.Address) []byte {
+	return thi.store.EncodeKey(DBKP_TOKEN_HOLDER, tokenId.Bytes(), helper.LeftPadBytes(balance.Bytes(), holderBalanceLen), addr.Bytes())
+}
+
+func (thi *TokenHolderIndex) getAccountBalances(addr types.Address) (map[types.TokenTypeId]*big.Int, error) {
+	prefix := thi.store.EncodeKey(DBKP_TOKEN_HOLDER_BALANCE, addr.Bytes())
+	iter := thi.store.NewIterator(database.BytesPrefix(prefix))
+	defer iter.Release()
+
+	balances := make(map[types.TokenTypeId]*big.Int)
+	for iter.Next() {
+		tokenId, err := types.BytesToTokenTypeId(iter.Key()[len(prefix):])
+		if err != nil {
+			return nil, err
+		}
+		balances[tokenId] = new(big.Int).SetBytes(iter.Value())
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// getStateBalances reads the balances of all tokens from the state of the account block.
+func (thi *TokenHolderIndex) getStateBalances(block *ledger.AccountBlock) (map[types.TokenTypeId]*big.Int, error) {
+	balances := make(map[types.TokenTypeId]*big.Int)
+	if block.StateHash == (types.Hash{}) {
+		return balances, nil
+	}
+
+	stateTrie := thi.chainInstance.GetStateTrie(&block.StateHash)
+	if stateTrie == nil || stateTrie.Hash() == nil {
+		return nil, errors.New(fmt.Sprintf("the state of block %s is missing", block.Hash))
+	}
+
+	iter := stateTrie.NewIterator(vm_context.STORAGE_KEY_BALANCE)
+	for {
+		key, value, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		tokenId, err := types.BytesToTokenTypeId(key[len(vm_context.STORAGE_KEY_BALANCE):])
+		if err != nil {
+			return nil, err
+		}
+		balances[tokenId] = new(big.Int).SetBytes(value)
+	}
+	return balances, nil
+}
+
+// getStateSupplies reads the total supplies of all tokens from the state of the mintage contract block.
+func (thi *TokenHolderIndex) getStateSupplies(block *ledger.AccountBlock) (map[types.TokenTypeId]*big.Int, error) {
+	supplies := make(map[types.TokenTypeId]*big.Int)
+	if block.StateHash == (types.Hash{}) {
+		return supplies, nil
+	}
+
+	stateTrie := thi.chainInstance.GetStateTrie(&block.StateHash)
+	if stateTrie == nil || stateTrie.Hash() == nil {
+		return nil, errors.New(fmt.Sprintf("the state of block %s is missing", block.Hash))
+	}
+
+	// the mintage keys are the token ids left padded with zeros
+	iter := stateTrie.NewIterator(make([]byte, types.HashSize-types.TokenTypeIdSize))
+	for {
+		key, value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if !abi.IsMintageKey(key) {
+			continue
+		}
+
+		tokenInfo, err := abi.ParseTokenInfo(value)
+		if err != nil {
+			return nil, err
+		}
+		supplies[abi.GetTokenIdFromMintageKey(key)] = tokenInfo.TotalSupply
+	}
+	return supplies, nil
+}
+
+func (thi *TokenHolderIndex) AddSnapshotBlocks(batch *database.Batch, blocks []*ledger.SnapshotBlock) error {
+	hw := thi.newHolderWriter(batch)
+
+	for _, snapshotBlock := range blocks {
+		// the ledger which has been run with LedgerGc has no state of the old snapshot blocks
+		pruned, err := thi.chainInstance.IsStatePruned(snapshotBlock)
+		if err != nil {
+			return err
+		}
+		if pruned {
+			return errors.New(fmt.Sprintf("the state of snapshot block %d is pruned, the index needs a ledger which is never run with LedgerGc", snapshotBlock.Height))
+		}
+
+		changedTokens := make(map[types.TokenTypeId]struct{})
+
+		var supplyUndo []byte
+		supplyCount := uint32(0)
+		if hashHeight, ok := snapshotBlock.SnapshotContent[types.AddressMintage]; ok {
+			block, err := thi.chainInstance.GetAccountBlockByHash(&hashHeight.Hash)
+			if err != nil {
+				return err
+			}
+			if block == nil {
+				return errors.New(fmt.Sprintf("block %s is not existed", hashHeight.Hash))
+			}
+
+			supplies, err := thi.getStateSupplies(block)
+			if err != nil {
+				return err
+			}
+			for tokenId, supply := range supplies {
+				changed, err := hw.setSupply(tokenId, supply)
+				if err != nil {
+					return err
+				}
+				if !changed {
+					continue
+				}
+
+				supplyUndo = append(supplyUndo, tokenId.Bytes()...)
+				supplyCount++
+				changedTokens[tokenId] = struct{}{}
+			}
+		}
+
+		undo := uint64ToBytes(snapshotBlock.Height)
+		undo = append(undo, uint32ToBytes(supplyCount)...)
+		undo = append(undo, supplyUndo...)
+
+		for addr, hashHeight := range snapshotBlock.SnapshotContent {
+			block, err := thi.chainInstance.GetAccountBlockByHash(&hashHeight.Hash)
+			if err != nil {
+				return err
+			}
+			if block == nil {
+				return errors.New(fmt.Sprintf("block %s is not existed", hashHeight.Hash))
+			}
+
+			stateBalances, err := thi.getStateBalances(block)
+			if err != nil {
+				return err
+			}
+			balances, err := hw.getBalances(addr)
+			if err != nil {
+				return err
+			}
+			for tokenId := range balances {
+				if _, ok := stateBalances[tokenId]; !ok {
+					stateBalances[tokenId] = big.NewInt(0)
+				}
+			}
+
+			for tokenId, balance := range stateBalances {
+				prevBalance, err := hw.setBalance(addr, tokenId, balance)
+				if err != nil {
+					return err
+				}
+				if prevBalance.Cmp(balance) == 0 {
+					continue
+				}
+
+				prevBalanceBytes := prevBalance.Bytes()
+				undo = append(undo, addr.Bytes()...)
+				undo = append(undo, tokenId.Bytes()...)
+				undo = append(undo, byte(len(prevBalanceBytes)))
+				undo = append(undo, prevBalanceBytes...)
+				changedTokens[tokenId] = struct{}{}
+			}
+		}
+
+		for tokenId := range changedTokens {
+			thi.writeStat(batch, tokenId, snapshotBlock.Height, hw.stats[tokenId])
+		}
+		batch.Put(thi.store.EncodeKey(DBKP_TOKEN_HOLDER_UNDO, snapshotBlock.Hash.Bytes()), undo)
+	}
+	return nil
+}
+
+type holderUndo struct {
+	key            []byte
+	snapshotHeight uint64
+	// the ids of the tokens whose supplies are changed
+	supplyTokens []byte
+	entries      []byte
+}
+
+func (thi *TokenHolderIndex) DeleteSnapshotBlocks(batch *database.Batch, hashList []types.Hash) error {
+	var undoList []*holderUndo
+	for _, hash := range hashList {
+		key := thi.store.EncodeKey(DBKP_TOKEN_HOLDER_UNDO, hash.Bytes())
+		value, err := thi.store.Get(key)
+		if err != nil {
+			if err == database.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		if len(value) < 12 {
+			return errors.New("token holder undo is malformed")
+		}
+		supplyEnd := 12 + int(binary.BigEndian.Uint32(value[8:12]))*types.TokenTypeIdSize
+		if len(value) < supplyEnd {
+			return errors.New("token holder undo is malformed")
+		}
+		undoList = append(undoList, &holderUndo{
+			key:            key,
+			snapshotHeight: binary.BigEndian.Uint64(value[:8]),
+			supplyTokens:   value[12:supplyEnd],
+			entries:        value[supplyEnd:],
+		})
+	}
+
+	// roll back from the latest snapshot block
+	sort.Slice(undoList, func(i, j int) bool {
+		return undoList[i].snapshotHeight > undoList[j].snapshotHeight
+	})
+
+	hw := thi.newHolderWriter(batch)
+	for _, undo := range undoList {
+		changedTokens := make(map[types.TokenTypeId]struct{})
+		for supplyTokens := undo.supplyTokens; len(supplyTokens) > 0; supplyTokens = supplyTokens[types.TokenTypeIdSize:] {
+			var tokenId types.TokenTypeId
+			copy(tokenId[:], supplyTokens[:types.TokenTypeIdSize])
+			changedTokens[tokenId] = struct{}{}
+		}
+		for entries := undo.entries; len(entries) > 0; {
+			headerLen := types.AddressSize + types.TokenTypeIdSize + 1
+			if len(entries) < headerLen || len(entries) < headerLen+int(entries[headerLen-1]) {
+				return errors.New("token holder undo is malformed")
+			}
+
+			var addr types.Address
+			copy(addr[:], entries[:types.AddressSize])
+			var tokenId types.TokenTypeId
+			copy(tokenId[:], entries[types.AddressSize:types.AddressSize+types.TokenTypeIdSize])
+			balanceEnd := headerLen + int(entries[headerLen-1])
+			balance := new(big.Int).SetBytes(entries[headerLen:balanceEnd])
+			entries = entries[balanceEnd:]
+
+			if _, err := hw.setBalance(addr, tokenId, balance); err != nil {
+				return err
+			}
+			changedTokens[tokenId] = struct{}{}
+		}
+
+		for tokenId := range changedTokens {
+			batch.Delete(thi.store.EncodeKey(DBKP_TOKEN_HOLDER_STAT, tokenId.Bytes(), undo.snapshotHeight))
+		}
+		batch.Delete(undo.key)
+	}
+	return nil
+}
+
+func (thi *TokenHolderIndex) writeStat(batch *database.Batch, tokenId types.TokenTypeId, snapshotHeight uint64, stat *TokenStat) {
+	batch.Put(thi.store.EncodeKey(DBKP_TOKEN_HOLDER_STAT, tokenId.Bytes(), snapshotHeight),
+		append(uint64ToBytes(stat.HolderCount), stat.Supply.Bytes()...))
+}
+
+// GetTokenStat returns the holder count and the supply of the token at snapshotHeight, 0 snapshotHeight means the
+// latest indexed snapshot block.
+func (thi *TokenHolderIndex) GetTokenStat(tokenId types.TokenTypeId, snapshotHeight uint64) (*TokenStat, error) {
+	prefix := thi.store.EncodeKey(DBKP_TOKEN_HOLDER_STAT, tokenId.Bytes())
+	keyRange := database.BytesPrefix(prefix)
+	if snapshotHeight > 0 && snapshotHeight < ^uint64(0) {
+		keyRange.Limit = append(append([]byte{}, prefix...), uint64ToBytes(snapshotHeight+1)...)
+	}
+
+	iter := thi.store.NewIterator(keyRange)
+	defer iter.Release()
+
+	if !iter.Last() {
+		if err := iter.Error(); err != nil && err != database.ErrNotFound {
+			return nil, err
+		}
+		return &TokenStat{Supply: big.NewInt(0)}, nil
+	}
+
+	value := iter.Value()
+	if len(value) < 8 {
+		return nil, errors.New("token stat is malformed")
+	}
+	return &TokenStat{
+		SnapshotHeight: binary.BigEndian.Uint64(iter.Key()[len(prefix):]),
+		HolderCount:    binary.BigEndian.Uint64(value[:8]),
+		Supply:         new(big.Int).SetBytes(value[8:]),
+	}, nil
+}
+
+// GetTokenHolders returns the holders of the token ordered by the balance descending, then by the address
+// descending. At most count holders are returned after skipping offset ones.
+func (thi *TokenHolderIndex) GetTokenHolders(tokenId types.TokenTypeId, offset int, count int) ([]*TokenHolder, error) {
+	if offset < 0 || count <= 0 {
+		return nil, nil
+	}
+
+	prefix := thi.store.EncodeKey(DBKP_TOKEN_HOLDER, tokenId.Bytes())
+	iter := thi.store.NewIterator(database.BytesPrefix(prefix))
+	defer iter.Release()
+
+	var holders []*TokenHolder
+	skipped := 0
+	for ok := iter.Last(); ok && len(holders) < count; ok = iter.Prev() {
+		if skipped < offset {
+			skipped++
+			continue
+		}
+
+		key := iter.Key()[len(prefix):]
+		if len(key) != holderBalanceLen+types.AddressSize {
+			return nil, errors.New("token holder key is malformed")
+		}
+		holder := &TokenHolder{Balance: new(big.Int).SetBytes(key[:holderBalanceLen])}
+		copy(holder.Address[:], key[holderBalanceLen:])
+		holders = append(holders, holder)
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return holders, nil
+}
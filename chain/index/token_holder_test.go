@@ -0,0 +1,166 @@
+package chain_index
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+func TestTokenHolderIndex(t *testing.T) {
+	c := newTestChain()
+	m, err := NewManagerWithStore(database.NewMemStore(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thi := NewTokenHolderIndex(c)
+	if err := m.Register(thi); err != nil {
+		t.Fatal(err)
+	}
+
+	addrA := types.Address{1}
+	addrB := types.Address{2}
+	tokenX := types.TokenTypeId{1}
+	tokenY := types.TokenTypeId{2}
+
+	newStateBlock := func(addr types.Address, height uint64, storage map[string][]byte) *ledger.AccountBlock {
+		stateTrie := trie.NewTrie(c.trieDb, nil, nil)
+		for key, value := range storage {
+			stateTrie.SetValue([]byte(key), value)
+		}
+		batch := new(database.Batch)
+		callback, err := stateTrie.Save(batch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.trieDb.Write(batch); err != nil {
+			t.Fatal(err)
+		}
+		callback()
+
+		block := &ledger.AccountBlock{
+			Hash:           types.Hash{addr[0], byte(height)},
+			Height:         height,
+			AccountAddress: addr,
+			StateHash:      *stateTrie.Hash(),
+		}
+		c.addAccountBlock(block)
+		return block
+	}
+	newBlock := func(addr types.Address, height uint64, balances map[types.TokenTypeId]int64) *ledger.AccountBlock {
+		storage := make(map[string][]byte)
+		for tokenId, balance := range balances {
+			storage[string(vm_context.BalanceKey(&tokenId))] = big.NewInt(balance).Bytes()
+		}
+		return newStateBlock(addr, height, storage)
+	}
+	newMintageBlock := func(height uint64, supplies map[types.TokenTypeId]int64) *ledger.AccountBlock {
+		storage := make(map[string][]byte)
+		for tokenId, supply := range supplies {
+			tokenInfo, err := abi.ABIMintage.PackVariable(abi.VariableNameTokenInfo, "token", "TOKEN", big.NewInt(supply), uint8(0),
+				addrA, big.NewInt(0), uint64(0), addrA, true, big.NewInt(1000), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			storage[string(abi.GetMintageKey(tokenId))] = tokenInfo
+		}
+		storage[string(abi.GetOwnerTokenIdListKey(addrA))] = []byte{1}
+		return newStateBlock(types.AddressMintage, height, storage)
+	}
+	newSnapshotBlock := func(height uint64, blocks ...*ledger.AccountBlock) *ledger.SnapshotBlock {
+		block := &ledger.SnapshotBlock{
+			Hash:            types.Hash{0x50, byte(height)},
+			Height:          height,
+			SnapshotContent: ledger.SnapshotContent{},
+		}
+		for _, accountBlock := range blocks {
+			block.SnapshotContent[accountBlock.AccountAddress] = &ledger.HashHeight{Hash: accountBlock.Hash, Height: accountBlock.Height}
+		}
+		c.addSnapshotBlock(block)
+		return block
+	}
+
+	// the supply counts the amounts onroad, 100 of tokenX is onroad at the first snapshot block, and 50 of tokenX is
+	// burned at the third
+	newSnapshotBlock(1,
+		newBlock(addrA, 1, map[types.TokenTypeId]int64{tokenX: 100, tokenY: 5}),
+		newBlock(addrB, 1, map[types.TokenTypeId]int64{tokenX: 200}),
+		newMintageBlock(1, map[types.TokenTypeId]int64{tokenX: 400, tokenY: 5}))
+	newSnapshotBlock(2, newBlock(addrA, 2, map[types.TokenTypeId]int64{tokenX: 300}))
+	s3 := newSnapshotBlock(3,
+		newBlock(addrB, 2, map[types.TokenTypeId]int64{tokenX: 50, tokenY: 10}),
+		newMintageBlock(2, map[types.TokenTypeId]int64{tokenX: 350, tokenY: 10}))
+	m.Build()
+
+	expectHolders := func(tokenId types.TokenTypeId, offset, count int, expected ...TokenHolder) {
+		holders, err := thi.GetTokenHolders(tokenId, offset, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(holders) != len(expected) {
+			t.Fatalf("expect %d holders, got %d", len(expected), len(holders))
+		}
+		for i, holder := range expected {
+			if holders[i].Address != holder.Address || holders[i].Balance.Cmp(holder.Balance) != 0 {
+				t.Fatalf("holder %d, expect %v %v, got %v %v", i, holder.Address, holder.Balance, holders[i].Address, holders[i].Balance)
+			}
+		}
+	}
+	expectStat := func(tokenId types.TokenTypeId, snapshotHeight uint64, holderCount uint64, supply int64) {
+		stat, err := thi.GetTokenStat(tokenId, snapshotHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stat.HolderCount != holderCount || stat.Supply.Cmp(big.NewInt(supply)) != 0 {
+			t.Fatalf("token %s at %d, expect %d holders and %d supply, got %+v", tokenId, snapshotHeight, holderCount, supply, stat)
+		}
+	}
+
+	expectHolders(tokenX, 0, 10, TokenHolder{addrA, big.NewInt(300)}, TokenHolder{addrB, big.NewInt(50)})
+	expectHolders(tokenX, 1, 10, TokenHolder{addrB, big.NewInt(50)})
+	expectHolders(tokenY, 0, 10, TokenHolder{addrB, big.NewInt(10)})
+	expectStat(tokenX, 0, 2, 350)
+	expectStat(tokenX, 1, 2, 400)
+	expectStat(tokenX, 2, 2, 400)
+	expectStat(tokenY, 2, 0, 5)
+	expectStat(tokenY, 3, 1, 10)
+
+	c.deleteSnapshotBlock(s3.Hash)
+	m.Build()
+	expectHolders(tokenX, 0, 10, TokenHolder{addrA, big.NewInt(300)}, TokenHolder{addrB, big.NewInt(200)})
+	expectHolders(tokenY, 0, 10)
+	expectStat(tokenX, 0, 2, 400)
+	expectStat(tokenY, 0, 0, 5)
+}
+
+func TestTokenHolderIndex_StatePruned(t *testing.T) {
+	c := newTestChain()
+	c.prunedHeight = 2
+	m, err := NewManagerWithStore(database.NewMemStore(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register(NewTokenHolderIndex(c)); err != nil {
+		t.Fatal(err)
+	}
+
+	c.addSnapshotBlock(&ledger.SnapshotBlock{
+		Hash:            types.Hash{0x50, 1},
+		Height:          1,
+		SnapshotContent: ledger.SnapshotContent{},
+	})
+	m.Build()
+
+	statusList, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statusList) != 1 || statusList[0].Synced || statusList[0].LastError == "" {
+		t.Fatalf("the index is built on the pruned state, status is %+v", statusList[0])
+	}
+}
@@ -0,0 +1,382 @@
+package chain_index
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const VmLogIndexName = "vmLog"
+
+const (
+	DBKP_VM_LOG_BY_HEIGHT = byte(1)
+
+	DBKP_VM_LOG_BY_ADDRESS = byte(2)
+
+	DBKP_VM_LOG_BY_TOPIC = byte(3)
+
+	DBKP_VM_LOG_SNAPSHOT_HEIGHT = byte(4)
+)
+
+var vmLogSchema = &Schema{
+	Name:    VmLogIndexName,
+	Id:      3,
+	Version: 1,
+	Keys: []KeySchema{
+		{Prefix: DBKP_VM_LOG_BY_HEIGHT, Name: "logByHeight", Fields: []string{"snapshotHeight", "blockHash", "logIndex"}, Value: "address+topics"},
+		{Prefix: DBKP_VM_LOG_BY_ADDRESS, Name: "logByAddress", Fields: []string{"address", "snapshotHeight", "blockHash", "logIndex"}, Value: "address+topics"},
+		{Prefix: DBKP_VM_LOG_BY_TOPIC, Name: "logByTopic", Fields: []string{"position", "topic", "snapshotHeight", "blockHash", "logIndex"}, Value: "address+topics"},
+		{Prefix: DBKP_VM_LOG_SNAPSHOT_HEIGHT, Name: "snapshotHeight", Fields: []string{"snapshotHash"}, Value: "snapshotHeight"},
+	},
+}
+
+// the length of snapshotHeight+blockHash+logIndex at the end of the log keys
+const vmLogKeySuffixLen = 8 + types.HashSize + 8
+
+// the position of the topic keys is a byte, the topics after it aren't indexed and are only matched
+const maxVmLogTopicPosition = math.MaxUint8
+
+// the max count of the log keys scanned by a GetLogs call
+const maxVmLogScan = 100000
+
+// VmLogItem locates a log, the log is the LogIndex-th log of the VmLogList of the block.
+type VmLogItem struct {
+	SnapshotHeight uint64
+	BlockHash      types.Hash
+	LogIndex       uint64
+	Address        types.Address
+	Topics         []types.Hash
+}
+
+// VmLogFilter selects the logs confirmed by the snapshot blocks from FromHeight to ToHeight, 0 ToHeight means no
+// upper bound. Empty Addresses means any address. Topics[i] lists the alternatives of the i-th topic, empty means
+// any topic.
+type VmLogFilter struct {
+	Addresses  []types.Address
+	Topics     [][]types.Hash
+	FromHeight uint64
+	ToHeight   uint64
+}
+
+// Match reports whether the log of item is selected by filter.
+func (filter *VmLogFilter) Match(item *VmLogItem) bool {
+	if item.SnapshotHeight < filter.FromHeight || filter.ToHeight > 0 && item.SnapshotHeight > filter.ToHeight {
+		return false
+	}
+
+	if len(filter.Addresses) > 0 {
+		matched := false
+		for _, addr := range filter.Addresses {
+			if addr == item.Address {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for position, alternatives := range filter.Topics {
+		if len(alternatives) <= 0 {
+			continue
+		}
+		if position >= len(item.Topics) {
+			return false
+		}
+
+		matched := false
+		for _, topic := range alternatives {
+			if topic == item.Topics[position] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// VmLogIndex indexes the logs of the account blocks when the blocks are confirmed by a snapshot block, by the
+// contract address and by every topic.
+type VmLogIndex struct {
+	store         *Store
+	chainInstance Chain
+}
+
+func NewVmLogIndex(chainInstance Chain) *VmLogIndex {
+	return &VmLogIndex{
+		chainInstance: chainInstance,
+	}
+}
+
+func (vli *VmLogIndex) Schema() *Schema {
+	return vmLogSchema
+}
+
+func (vli *VmLogIndex) Open(store *Store) {
+	vli.store = store
+}
+
+// AddAccountBlocks does nothing, the logs are indexed when the blocks are confirmed.
+func (vli *VmLogIndex) AddAccountBlocks(batch *database.Batch, blocks []*ledger.AccountBlock) error {
+	return nil
+}
+
+// DeleteAccountBlocks does nothing, the confirmed blocks are deleted with their snapshot blocks.
+func (vli *VmLogIndex) DeleteAccountBlocks(batch *database.Batch, hashList []types.Hash) error {
+	return nil
+}
+
+func (vli *VmLogIndex) AddSnapshotBlocks(batch *database.Batch, blocks []*ledger.SnapshotBlock) error {
+	for _, snapshotBlock := range blocks {
+		batch.Put(vli.store.EncodeKey(DBKP_VM_LOG_SNAPSHOT_HEIGHT, snapshotBlock.Hash.Bytes()), uint64ToBytes(snapshotBlock.Height))
+
+		for _, hashHeight := range snapshotBlock.SnapshotContent {
+			confirmedBlocks, err := getConfirmedBlocks(vli.chainInstance, &hashHeight.Hash)
+			if err != nil {
+				return err
+			}
+
+			for _, block := range confirmedBlocks {
+				if block.LogHash == nil {
+					continue
+				}
+				logList, err := vli.chainInstance.GetVmLogList(block.LogHash)
+				if err != nil {
+					return err
+				}
+
+				for logIndex, vmLog := range logList {
+					vli.writeLog(batch, &VmLogItem{
+						SnapshotHeight: snapshotBlock.Height,
+						BlockHash:      block.Hash,
+						LogIndex:       uint64(logIndex),
+						Address:        block.AccountAddress,
+						Topics:         vmLog.Topics,
+					})
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (vli *VmLogIndex) DeleteSnapshotBlocks(batch *database.Batch, hashList []types.Hash) error {
+	for _, hash := range hashList {
+		heightKey := vli.store.EncodeKey(DBKP_VM_LOG_SNAPSHOT_HEIGHT, hash.Bytes())
+		value, err := vli.store.Get(heightKey)
+		if err != nil {
+			if err == database.ErrNotFound {
+				continue
+			}
+			return err
+		}
+		height := binary.BigEndian.Uint64(value)
+
+		iter := vli.store.NewIterator(database.BytesPrefix(vli.store.EncodeKey(DBKP_VM_LOG_BY_HEIGHT, height)))
+		for iter.Next() {
+			item, err := parseVmLogItem(iter.Key()[KeyHeaderLen:], iter.Value())
+			if err != nil {
+				iter.Release()
+				return err
+			}
+			vli.deleteLog(batch, item)
+		}
+		err = iter.Error()
+		iter.Release()
+		if err != nil && err != database.ErrNotFound {
+			return err
+		}
+
+		batch.Delete(heightKey)
+	}
+	return nil
+}
+
+func uint64ToBytes(value uint64) []byte {
+	result := make([]byte, 8)
+	binary.BigEndian.PutUint64(result, value)
+	return result
+}
+
+func uint32ToBytes(value uint32) []byte {
+	result := make([]byte, 4)
+	binary.BigEndian.PutUint32(result, value)
+	return result
+}
+
+func vmLogKeySuffix(item *VmLogItem) []byte {
+	suffix := make([]byte, 0, vmLogKeySuffixLen)
+	suffix = append(suffix, uint64ToBytes(item.SnapshotHeight)...)
+	suffix = append(suffix, item.BlockHash.Bytes()...)
+	return append(suffix, uint64ToBytes(item.LogIndex)...)
+}
+
+func vmLogValue(item *VmLogItem) []byte {
+	value := make([]byte, 0, types.AddressSize+len(item.Topics)*types.HashSize)
+	value = append(value, item.Address.Bytes()...)
+	for _, topic := range item.Topics {
+		value = append(value, topic.Bytes()...)
+	}
+	return value
+}
+
+// parseVmLogItem parses the key suffix and the value of a log key.
+func parseVmLogItem(keySuffix []byte, value []byte) (*VmLogItem, error) {
+	if len(keySuffix) != vmLogKeySuffixLen ||
+		len(value) < types.AddressSize || (len(value)-types.AddressSize)%types.HashSize != 0 {
+		return nil, errors.New("vm log key is malformed")
+	}
+
+	item := &VmLogItem{
+		SnapshotHeight: binary.BigEndian.Uint64(keySuffix[:8]),
+		LogIndex:       binary.BigEndian.Uint64(keySuffix[8+types.HashSize:]),
+	}
+	copy(item.BlockHash[:], keySuffix[8:8+types.HashSize])
+	copy(item.Address[:], value[:types.AddressSize])
+	for topics := value[types.AddressSize:]; len(topics) > 0; topics = topics[types.HashSize:] {
+		var topic types.Hash
+		copy(topic[:], topics[:types.HashSize])
+		item.Topics = append(item.Topics, topic)
+	}
+	return item, nil
+}
+
+func (vli *VmLogIndex) logKeys(item *VmLogItem) [][]byte {
+	suffix := vmLogKeySuffix(item)
+	keys := [][]byte{
+		vli.store.EncodeKey(DBKP_VM_LOG_BY_HEIGHT, suffix),
+		vli.store.EncodeKey(DBKP_VM_LOG_BY_ADDRESS, item.Address.Bytes(), suffix),
+	}
+	for position, topic := range item.Topics {
+		if position > maxVmLogTopicPosition {
+			break
+		}
+		keys = append(keys, vli.store.EncodeKey(DBKP_VM_LOG_BY_TOPIC, []byte{byte(position)}, topic.Bytes(), suffix))
+	}
+	return keys
+}
+
+func (vli *VmLogIndex) writeLog(batch *database.Batch, item *VmLogItem) {
+	value := vmLogValue(item)
+	for _, key := range vli.logKeys(item) {
+		batch.Put(key, value)
+	}
+}
+
+func (vli *VmLogIndex) deleteLog(batch *database.Batch, item *VmLogItem) {
+	for _, key := range vli.logKeys(item) {
+		batch.Delete(key)
+	}
+}
+
+// GetLogs returns the logs matched by filter, ordered by the snapshot height, then by the block hash and the log
+// index. At most count logs are returned after skipping offset ones, and the call fails if the filter is so loose
+// that more than maxVmLogScan log keys are scanned.
+func (vli *VmLogIndex) GetLogs(filter *VmLogFilter, offset int, count int) ([]*VmLogItem, error) {
+	if offset < 0 || count <= 0 {
+		return nil, nil
+	}
+	if offset > maxVmLogScan || count > maxVmLogScan-offset {
+		return nil, errors.New(fmt.Sprintf("the logs after %d logs are out of range", maxVmLogScan))
+	}
+	limit := offset + count
+
+	// scan the narrowest key kind, the other conditions are matched against the items
+	var prefixList [][]byte
+	if len(filter.Addresses) > 0 {
+		for _, addr := range filter.Addresses {
+			prefixList = append(prefixList, vli.store.EncodeKey(DBKP_VM_LOG_BY_ADDRESS, addr.Bytes()))
+		}
+	} else if position, alternatives := firstTopicCondition(filter); position >= 0 {
+		for _, topic := range alternatives {
+			prefixList = append(prefixList, vli.store.EncodeKey(DBKP_VM_LOG_BY_TOPIC, []byte{byte(position)}, topic.Bytes()))
+		}
+	} else {
+		prefixList = append(prefixList, vli.store.EncodeKey(DBKP_VM_LOG_BY_HEIGHT))
+	}
+
+	var items []*VmLogItem
+	scanned := 0
+	for _, prefix := range prefixList {
+		prefixItems, err := vli.scan(prefix, filter, limit, &scanned)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, prefixItems...)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].SnapshotHeight != items[j].SnapshotHeight {
+			return items[i].SnapshotHeight < items[j].SnapshotHeight
+		}
+		if cmp := bytes.Compare(items[i].BlockHash.Bytes(), items[j].BlockHash.Bytes()); cmp != 0 {
+			return cmp < 0
+		}
+		return items[i].LogIndex < items[j].LogIndex
+	})
+
+	if len(items) <= offset {
+		return nil, nil
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items[offset:], nil
+}
+
+func firstTopicCondition(filter *VmLogFilter) (int, []types.Hash) {
+	for position, alternatives := range filter.Topics {
+		if position > maxVmLogTopicPosition {
+			break
+		}
+		if len(alternatives) > 0 {
+			return position, alternatives
+		}
+	}
+	return -1, nil
+}
+
+// scan returns at most limit matched items of the keys with prefix, from the FromHeight of filter. scanned counts the
+// keys scanned by all the scans of a call, the scan fails if it exceeds maxVmLogScan.
+func (vli *VmLogIndex) scan(prefix []byte, filter *VmLogFilter, limit int, scanned *int) ([]*VmLogItem, error) {
+	keyRange := database.BytesPrefix(prefix)
+	keyRange.Start = append(append([]byte{}, prefix...), uint64ToBytes(filter.FromHeight)...)
+
+	iter := vli.store.NewIterator(keyRange)
+	defer iter.Release()
+
+	var items []*VmLogItem
+	for iter.Next() && len(items) < limit {
+		if *scanned >= maxVmLogScan {
+			return nil, errors.New(fmt.Sprintf("more than %d logs are scanned, narrow the filter", maxVmLogScan))
+		}
+		*scanned++
+
+		key := iter.Key()
+		item, err := parseVmLogItem(key[len(key)-vmLogKeySuffixLen:], iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		if filter.ToHeight > 0 && item.SnapshotHeight > filter.ToHeight {
+			break
+		}
+		if filter.Match(item) {
+			items = append(items, item)
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return items, nil
+}
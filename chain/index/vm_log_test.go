@@ -0,0 +1,111 @@
+package chain_index
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func TestVmLogIndex(t *testing.T) {
+	c := newTestChain()
+	m, err := NewManagerWithStore(database.NewMemStore(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vli := NewVmLogIndex(c)
+	if err := m.Register(vli); err != nil {
+		t.Fatal(err)
+	}
+
+	addrA := types.Address{1}
+	addrB := types.Address{2}
+	topicX := types.Hash{0xa}
+	topicY := types.Hash{0xb}
+	topicZ := types.Hash{0xc}
+
+	newBlock := func(addr types.Address, height uint64, prevHash types.Hash, logList ledger.VmLogList) *ledger.AccountBlock {
+		block := &ledger.AccountBlock{
+			Hash:           types.Hash{addr[0], byte(height)},
+			Height:         height,
+			PrevHash:       prevHash,
+			AccountAddress: addr,
+		}
+		if logList != nil {
+			logHash := types.Hash{0xff, addr[0], byte(height)}
+			block.LogHash = &logHash
+			c.vmLogLists[logHash] = logList
+		}
+		c.addAccountBlock(block)
+		return block
+	}
+
+	a1 := newBlock(addrA, 1, types.Hash{}, ledger.VmLogList{
+		{Topics: []types.Hash{topicX, topicY}},
+		{Topics: []types.Hash{topicZ}},
+	})
+	a2 := newBlock(addrA, 2, a1.Hash, ledger.VmLogList{{Topics: []types.Hash{topicX, topicZ}}})
+	b1 := newBlock(addrB, 1, types.Hash{}, ledger.VmLogList{{Topics: []types.Hash{topicX}}})
+	c.addSnapshotBlock(&ledger.SnapshotBlock{
+		Hash:   types.Hash{0x51},
+		Height: 2,
+		SnapshotContent: ledger.SnapshotContent{
+			addrA: {Hash: a2.Hash, Height: a2.Height},
+			addrB: {Hash: b1.Hash, Height: b1.Height},
+		},
+	})
+
+	a3 := newBlock(addrA, 3, a2.Hash, nil)
+	a4 := newBlock(addrA, 4, a3.Hash, ledger.VmLogList{{Topics: []types.Hash{topicY}}})
+	s3 := &ledger.SnapshotBlock{
+		Hash:            types.Hash{0x52},
+		Height:          3,
+		SnapshotContent: ledger.SnapshotContent{addrA: {Hash: a4.Hash, Height: a4.Height}},
+	}
+	c.addSnapshotBlock(s3)
+	m.Build()
+
+	type logId struct {
+		height   uint64
+		hash     types.Hash
+		logIndex uint64
+	}
+	expect := func(filter *VmLogFilter, offset, count int, expected ...logId) {
+		items, err := vli.GetLogs(filter, offset, count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != len(expected) {
+			t.Fatalf("filter %+v, expect %d logs, got %d", filter, len(expected), len(items))
+		}
+		for i, id := range expected {
+			if items[i].SnapshotHeight != id.height || items[i].BlockHash != id.hash || items[i].LogIndex != id.logIndex {
+				t.Fatalf("filter %+v, log %d, expect %+v, got %+v", filter, i, id, items[i])
+			}
+		}
+	}
+
+	all := []logId{{2, a1.Hash, 0}, {2, a1.Hash, 1}, {2, a2.Hash, 0}, {2, b1.Hash, 0}, {3, a4.Hash, 0}}
+	expect(&VmLogFilter{}, 0, 10, all...)
+	expect(&VmLogFilter{}, 1, 2, all[1:3]...)
+	expect(&VmLogFilter{FromHeight: 3}, 0, 10, all[4])
+	expect(&VmLogFilter{ToHeight: 2}, 0, 10, all[:4]...)
+	expect(&VmLogFilter{Addresses: []types.Address{addrB}}, 0, 10, all[3])
+	expect(&VmLogFilter{Topics: [][]types.Hash{{topicX}}}, 0, 10, all[0], all[2], all[3])
+	expect(&VmLogFilter{Topics: [][]types.Hash{nil, {topicY, topicZ}}}, 0, 10, all[0], all[2])
+	expect(&VmLogFilter{Addresses: []types.Address{addrA}, Topics: [][]types.Hash{{topicY, topicZ}}}, 0, 10, all[1], all[4])
+
+	// the positions over a byte aren't indexed, they are matched against the scanned logs
+	farTopics := make([][]types.Hash, maxVmLogTopicPosition+2)
+	farTopics[maxVmLogTopicPosition+1] = []types.Hash{topicX}
+	expect(&VmLogFilter{Topics: farTopics}, 0, 10)
+	if _, err := vli.GetLogs(&VmLogFilter{}, maxVmLogScan, 1); err == nil {
+		t.Fatal("the logs out of the scan range should be rejected")
+	}
+
+	c.deleteSnapshotBlock(s3.Hash)
+	m.Build()
+	expect(&VmLogFilter{}, 0, 10, all[:4]...)
+	expect(&VmLogFilter{Topics: [][]types.Hash{{topicY}}}, 0, 10)
+}
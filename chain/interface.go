@@ -4,12 +4,12 @@ import (
 	"math/big"
 	"time"
 
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain/cache"
 	"github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/chain/sender"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
@@ -18,9 +18,9 @@ import (
 	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 )
 
-type InsertProcessorFunc func(batch *leveldb.Batch, blocks []*vm_context.VmAccountBlock) error
+type InsertProcessorFunc func(batch *database.Batch, blocks []*vm_context.VmAccountBlock) error
 type InsertProcessorFuncSuccess func(blocks []*vm_context.VmAccountBlock)
-type DeleteProcessorFunc func(batch *leveldb.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error
+type DeleteProcessorFunc func(batch *database.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error
 type DeleteProcessorFuncSuccess func(subLedger map[types.Address][]*ledger.AccountBlock)
 
 type InsertSnapshotBlocksSuccess func([]*ledger.SnapshotBlock)
@@ -39,6 +39,7 @@ type Chain interface {
 	GetAllLatestAccountBlock() ([]*ledger.AccountBlock, error)
 	GetAccountBlockByHeight(addr *types.Address, height uint64) (*ledger.AccountBlock, error)
 	GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
+	GetArchivedAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
 	GetAccountBlocksByAddress(addr *types.Address, index int, num int, count int) ([]*ledger.AccountBlock, error)
 	GetFirstConfirmedAccountBlockBySbHeight(snapshotBlockHeight uint64, addr *types.Address) (*ledger.AccountBlock, error)
 
@@ -69,6 +70,7 @@ type Chain interface {
 	GetSnapshotBlocksByHeight(height uint64, count uint64, forward bool, containSnapshotContent bool) ([]*ledger.SnapshotBlock, error)
 
 	GetSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error)
+	GetArchivedSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error)
 	GetSnapshotBlockHeadByHeight(height uint64) (*ledger.SnapshotBlock, error)
 
 	GetSnapshotBlockByHash(hash *types.Hash) (*ledger.SnapshotBlock, error)
@@ -77,6 +79,9 @@ type Chain interface {
 	GetLatestSnapshotBlock() *ledger.SnapshotBlock
 	GetGenesisSnapshotBlock() *ledger.SnapshotBlock
 
+	// NewReadView pins the ledger for a group of queries, the view must be released after use
+	NewReadView() (ReadView, error)
+
 	NewGenesisSnapshotBlock() ledger.SnapshotBlock
 	NewSecondSnapshotBlock() ledger.SnapshotBlock
 	NewGenesisMintageBlock() (ledger.AccountBlock, vmctxt_interface.VmDatabase)
@@ -93,7 +98,7 @@ type Chain interface {
 	GetContractGid(addr *types.Address) (*types.Gid, error)
 	GetRegisterList(snapshotHash types.Hash, gid types.Gid) ([]*types.Registration, error)
 	GetVoteMap(snapshotHash types.Hash, gid types.Gid) ([]*types.VoteInfo, error)
-	KafkaSender() *sender.KafkaSender
+	Sender() *sender.Sender
 
 	// Pledge amount
 	GetPledgeAmount(snapshotHash types.Hash, beneficial types.Address) (*big.Int, error)
@@ -113,7 +118,7 @@ type Chain interface {
 	GetConfirmSubLedger(fromHeight uint64, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error)
 	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
 	UnRegister(listenerId uint64)
-	TrieDb() *leveldb.DB
+	TrieDb() database.Store
 	CleanTrieNodePool()
 	RegisterInsertAccountBlocks(processor InsertProcessorFunc) uint64
 	RegisterInsertAccountBlocksSuccess(processor InsertProcessorFuncSuccess) uint64
@@ -125,9 +130,14 @@ type Chain interface {
 
 	GetStateTrie(stateHash *types.Hash) *trie.Trie
 	ShallowCheckStateTrie(stateHash *types.Hash) (bool, error)
+	IsStatePruned(snapshotBlock *ledger.SnapshotBlock) (bool, error)
 	GenStateTrieFromDb(prevStateHash types.Hash, snapshotContent ledger.SnapshotContent) (*trie.Trie, error)
 	NewStateTrie() *trie.Trie
 
+	// flat state
+	GetFlatStorage(snapshotHash *types.Hash, addr *types.Address, key []byte) ([]byte, bool)
+	NewFlatStorageIterator(snapshotHash *types.Hash, addr *types.Address, prefix []byte) (vmctxt_interface.StorageIterator, bool)
+
 	IsGenesisSnapshotBlock(block *ledger.SnapshotBlock) bool
 	IsGenesisAccountBlock(block *ledger.AccountBlock) bool
 
@@ -146,6 +156,7 @@ type Chain interface {
 	// get receive block heights
 	GetReceiveBlockHeights(hash *types.Hash) ([]uint64, error)
 	Fti() *chain_index.FilterTokenIndex
+	IndexManager() *chain_index.Manager
 
 	// get on road blocks in a snapshot
 	GetOnRoadBlocksBySendAccount(sendAccountAddress *types.Address, snapshotBlockHeight uint64) ([]*ledger.AccountBlock, error)
@@ -49,7 +49,7 @@ func TestSend(t *testing.T) {
 	go func() {
 		for {
 			time.Sleep(time.Second)
-			for index, p := range chainInstance.KafkaSender().RunProducers() {
+			for index, p := range chainInstance.Sender().RunProducers() {
 				lastId, _ := chainInstance.GetLatestBlockEventId()
 				fmt.Printf("%d %d %d\n", index, p.HasSend(), lastId)
 			}
@@ -0,0 +1,116 @@
+package chain
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain/index"
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
+)
+
+// ReadView is the query part of Chain pinned at the moment it is created. Blocks inserted or deleted after that
+// are invisible to the view, so a caller querying several times sees one consistent ledger.
+// The view must be released after use.
+type ReadView interface {
+	GetAccountBlocksByHash(addr types.Address, origin *types.Hash, count uint64, forward bool) ([]*ledger.AccountBlock, error)
+	GetAccountBlocksByHeight(addr types.Address, start uint64, count uint64, forward bool) ([]*ledger.AccountBlock, error)
+	GetLatestAccountBlock(addr *types.Address) (*ledger.AccountBlock, error)
+	GetAccountBalance(addr *types.Address) (map[types.TokenTypeId]*big.Int, error)
+	GetAccountBalanceByTokenId(addr *types.Address, tokenId *types.TokenTypeId) (*big.Int, error)
+	GetAccountBlockHashByHeight(addr *types.Address, height uint64) (*types.Hash, error)
+	GetAllLatestAccountBlock() ([]*ledger.AccountBlock, error)
+	GetAccountBlockByHeight(addr *types.Address, height uint64) (*ledger.AccountBlock, error)
+	GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
+	GetArchivedAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
+	GetAccountBlocksByAddress(addr *types.Address, index int, num int, count int) ([]*ledger.AccountBlock, error)
+	GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error)
+	GetUnConfirmAccountBlocks(addr *types.Address) []*ledger.AccountBlock
+
+	GetSnapshotBlocksByHash(originBlockHash *types.Hash, count uint64, forward bool, containSnapshotContent bool) ([]*ledger.SnapshotBlock, error)
+	GetSnapshotBlocksByHeight(height uint64, count uint64, forward bool, containSnapshotContent bool) ([]*ledger.SnapshotBlock, error)
+	GetSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error)
+	GetArchivedSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error)
+	GetSnapshotBlockHeadByHeight(height uint64) (*ledger.SnapshotBlock, error)
+	GetSnapshotBlockByHash(hash *types.Hash) (*ledger.SnapshotBlock, error)
+	GetSnapshotBlockHeadByHash(hash *types.Hash) (*ledger.SnapshotBlock, error)
+	GetLatestSnapshotBlock() *ledger.SnapshotBlock
+	GetGenesisSnapshotBlock() *ledger.SnapshotBlock
+	GetConfirmBlock(accountBlockHash *types.Hash) (*ledger.SnapshotBlock, error)
+	GetConfirmTimes(accountBlockHash *types.Hash) (uint64, error)
+	GetSnapshotBlockBeforeTime(blockCreatedTime *time.Time) (*ledger.SnapshotBlock, error)
+	GetConfirmAccountBlock(snapshotHeight uint64, address *types.Address) (*ledger.AccountBlock, error)
+
+	GetContractGid(addr *types.Address) (*types.Gid, error)
+	GetPledgeAmount(snapshotHash types.Hash, beneficial types.Address) (*big.Int, error)
+	GetPledgeQuota(snapshotHash types.Hash, beneficial types.Address) (uint64, error)
+	GetTokenInfoById(tokenId *types.TokenTypeId) (*types.TokenInfo, error)
+	AccountType(address *types.Address) (uint64, error)
+	GetAccount(address *types.Address) (*ledger.Account, error)
+	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
+	GetStateTrie(stateHash *types.Hash) *trie.Trie
+
+	// Fti is shared with the chain, the index is built in the background, so the hashes it lists may be newer than
+	// the view or rolled back, they are resolved by GetAccountBlockByHash of the view.
+	Fti() *chain_index.FilterTokenIndex
+
+	Release()
+}
+
+// readView is a chain which reads from a snapshot of the store. Only the query methods are reachable through
+// ReadView, the writes would fail anyway because the snapshot store is read only.
+type readView struct {
+	*chain
+}
+
+func (c *chain) NewReadView() (ReadView, error) {
+	snapshot, err := c.chainDb.Db().GetSnapshot()
+	if err != nil {
+		c.log.Error("GetSnapshot failed, error is "+err.Error(), "method", "NewReadView")
+		return nil, err
+	}
+
+	viewChainDb := chain_db.NewChainDbWithStore(database.NewSnapshotStore(snapshot))
+
+	// the latest snapshot block in memory may be newer or older than the snapshot, read it from the snapshot
+	latestSnapshotBlock, err := viewChainDb.Sc.GetLatestBlock()
+	if err != nil {
+		snapshot.Release()
+		c.log.Error("GetLatestBlock failed, error is "+err.Error(), "method", "NewReadView")
+		return nil, err
+	}
+	if latestSnapshotBlock == nil {
+		snapshot.Release()
+		return nil, errors.New("the latest snapshot block of read view is nil")
+	}
+
+	// needSnapshotCache isn't shared, it holds the live unconfirmed blocks and no query of the view reads it
+	viewChain := &chain{
+		log:                  c.log.New("view", latestSnapshotBlock.Height),
+		chainDb:              viewChainDb,
+		compressor:           c.compressor,
+		trieNodePool:         c.trieNodePool,
+		genesisSnapshotBlock: c.genesisSnapshotBlock,
+		latestSnapshotBlock:  latestSnapshotBlock,
+		dataDir:              c.dataDir,
+		ledgerDirName:        c.ledgerDirName,
+		cfg:                  c.cfg,
+		globalCfg:            c.globalCfg,
+		fti:                  c.fti,
+	}
+	// the state tries cached by the chain may be newer than the snapshot
+	viewChain.stateTriePool = NewStateTriePool(viewChain)
+	if c.flatState != nil {
+		viewChain.flatState = c.flatState.newView(viewChain)
+	}
+
+	return &readView{viewChain}, nil
+}
+
+func (view *readView) Release() {
+	view.chainDb.Db().Close()
+}
@@ -1,7 +1,7 @@
 package chain
 
 import (
-	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/vm_context"
@@ -70,7 +70,7 @@ func newEventManager() *eventManager {
 	}
 }
 
-func (em *eventManager) triggerInsertAccountBlocks(batch *leveldb.Batch, blocks []*vm_context.VmAccountBlock) error {
+func (em *eventManager) triggerInsertAccountBlocks(batch *database.Batch, blocks []*vm_context.VmAccountBlock) error {
 	for _, listener := range em.iabsEventListener {
 		if err := listener.processor(batch, blocks); err != nil {
 			return err
@@ -84,7 +84,7 @@ func (em *eventManager) triggerInsertAccountBlocksSuccess(blocks []*vm_context.V
 	}
 }
 
-func (em *eventManager) triggerDeleteAccountBlocks(batch *leveldb.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error {
+func (em *eventManager) triggerDeleteAccountBlocks(batch *database.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error {
 	for _, listener := range em.dabsEventListener {
 		if err := listener.processor(batch, subLedger); err != nil {
 			return err
@@ -2,7 +2,7 @@ package rocket
 
 import (
 	"encoding/binary"
-	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/crypto/ed25519"
 	"github.com/vitelabs/go-vite/ledger"
@@ -16,7 +16,7 @@ func (c *chain) InsertAccountBlocks(vmAccountBlocks []*vm_context.VmAccountBlock
 	blockIdBytes := make([]byte, 8)
 	binary.BigEndian.PutUint64(blockIdBytes, blockId)
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 	for _, vmBlock := range vmAccountBlocks {
 		accountBlock := vmBlock.AccountBlock
 		blockBytes, err := vmBlock.AccountBlock.DbSerialize()
@@ -41,7 +41,7 @@ func (c *chain) InsertAccountBlocks(vmAccountBlocks []*vm_context.VmAccountBlock
 			return saveBlockMetaErr
 		}
 	}
-	if err := c.chainDb.Db().Write(batch, nil); err != nil {
+	if err := c.chainDb.Db().Write(batch); err != nil {
 		return err
 	}
 	return nil
@@ -55,7 +55,7 @@ func (c *chain) newAccountId() (uint64, error) {
 	return lastAccountId + 1, nil
 }
 
-func (c *chain) createAccount(batch *leveldb.Batch, accountId uint64, address *types.Address, publicKey ed25519.PublicKey) (*ledger.Account, error) {
+func (c *chain) createAccount(batch *database.Batch, accountId uint64, address *types.Address, publicKey ed25519.PublicKey) (*ledger.Account, error) {
 	account := &ledger.Account{
 		AccountAddress: *address,
 		AccountId:      accountId,
@@ -69,7 +69,7 @@ func (c *chain) createAccount(batch *leveldb.Batch, accountId uint64, address *t
 	return account, nil
 }
 
-func (c *chain) createAccountIfNotExists(batch *leveldb.Batch, addr *types.Address, pbkey ed25519.PublicKey) error {
+func (c *chain) createAccountIfNotExists(batch *database.Batch, addr *types.Address, pbkey ed25519.PublicKey) error {
 	var getErr error
 	isExisted := false
 	if isExisted, getErr = c.chainDb.Account.IsAccountExisted(addr); getErr != nil {
@@ -0,0 +1,63 @@
+package sender
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// fileSink appends the messages to a file, one json per line. A message may be appended twice if the node exits
+// after the append and before the cursor is saved.
+type fileSink struct {
+	cfg  *SinkConfig
+	file *os.File
+}
+
+func newFileSink(cfg *SinkConfig) *fileSink {
+	return &fileSink{
+		cfg: cfg,
+	}
+}
+
+func (sink *fileSink) Config() *SinkConfig {
+	return sink.cfg
+}
+
+func (sink *fileSink) Open() error {
+	if err := os.MkdirAll(filepath.Dir(sink.cfg.Path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(sink.cfg.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	sink.file = file
+	return nil
+}
+
+func (sink *fileSink) Send(msgList []*Message) error {
+	writer := bufio.NewWriter(sink.file)
+	for _, msg := range msgList {
+		buf, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(append(buf, '\n')); err != nil {
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return sink.file.Sync()
+}
+
+func (sink *fileSink) Close() error {
+	if err := sink.file.Close(); err != nil {
+		return err
+	}
+	sink.file = nil
+	return nil
+}
@@ -0,0 +1,86 @@
+package sender
+
+import (
+	"encoding/json"
+
+	"github.com/Shopify/sarama"
+	"github.com/golang/protobuf/proto"
+	"github.com/vitelabs/go-vite/log15"
+)
+
+// the key of all the messages, the messages of the same key are in one partition, so they're consumed in order
+const kafkaMessageKey = "vite"
+
+type kafkaSink struct {
+	cfg *SinkConfig
+
+	kafkaProducer sarama.SyncProducer
+
+	log log15.Logger
+}
+
+func newKafkaSink(cfg *SinkConfig) *kafkaSink {
+	return &kafkaSink{
+		cfg: cfg,
+		log: log15.New("module", "sender/kafka_sink"),
+	}
+}
+
+func (sink *kafkaSink) Config() *SinkConfig {
+	return sink.cfg
+}
+
+func (sink *kafkaSink) Open() error {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	// one request in flight, so the retried messages aren't reordered
+	config.Net.MaxOpenRequests = 1
+
+	kafkaProducer, err := sarama.NewSyncProducer(sink.cfg.BrokerList, config)
+	if err != nil {
+		return err
+	}
+
+	sink.kafkaProducer = kafkaProducer
+	return nil
+}
+
+// Send produces the messages in order and returns when all of them are acknowledged, the error of any message fails
+// the whole list and it's sent again by the sender.
+func (sink *kafkaSink) Send(msgList []*Message) error {
+	sMsgList := make([]*sarama.ProducerMessage, 0, len(msgList))
+	for _, msg := range msgList {
+		buf, err := sink.encode(msg)
+		if err != nil {
+			return err
+		}
+		sMsgList = append(sMsgList, &sarama.ProducerMessage{
+			Topic: sink.cfg.Topic,
+			Key:   sarama.StringEncoder(kafkaMessageKey),
+			Value: sarama.ByteEncoder(buf),
+		})
+	}
+
+	if err := sink.kafkaProducer.SendMessages(sMsgList); err != nil {
+		sink.log.Error("kafka send failed, error is "+err.Error(), "method", "Send")
+		return err
+	}
+	return nil
+}
+
+func (sink *kafkaSink) encode(msg *Message) ([]byte, error) {
+	if sink.cfg.Format == FormatProto {
+		return proto.Marshal(msg.Proto())
+	}
+	return json.Marshal(msg)
+}
+
+func (sink *kafkaSink) Close() error {
+	if err := sink.kafkaProducer.Close(); err != nil {
+		return err
+	}
+	sink.kafkaProducer = nil
+	return nil
+}
@@ -0,0 +1,317 @@
+package sender
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/vitepb"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+const (
+	// MessageVersion is the version of the message schema, it's increased if the meaning of a field is changed.
+	MessageVersion = uint32(2)
+
+	ActionAdd    = "add"
+	ActionRevert = "revert"
+)
+
+// Message is the envelope of a block event. The sinks deliver the messages at least once, not exactly once: a message
+// is sent again if the sink fails before the progress is saved. EventId is increasing but not always continuous, a
+// consumer detects the lost events by PrevEventHash, which is the EventHash of the event before, and gets exactly-once
+// processing only by skipping the EventId it has handled.
+//
+// The blocks of an add event are in Data, the blocks deleted after the event are not included. A revert event lists
+// the hashes of the deleted blocks in BlockHashList, the consumers revert them with the blocks of the add events.
+type Message struct {
+	Version       uint32       `json:"version"`
+	EventId       uint64       `json:"eventId"`
+	MsgType       string       `json:"type"`
+	Action        string       `json:"action"`
+	PrevEventHash types.Hash   `json:"prevEventHash"`
+	EventHash     types.Hash   `json:"eventHash"`
+	BlockHashList []types.Hash `json:"blockHashList"`
+	Data          string       `json:"data"`
+
+	eventType      byte
+	accountBlocks  []*MqAccountBlock
+	snapshotBlocks []*MqSnapshotBlock
+}
+
+// Proto converts the message to the protobuf envelope.
+func (m *Message) Proto() *vitepb.ChainEvent {
+	pb := &vitepb.ChainEvent{
+		Version:       m.Version,
+		EventId:       m.EventId,
+		EventType:     vitepb.ChainEvent_EventType(m.eventType),
+		PrevEventHash: m.PrevEventHash.Bytes(),
+		EventHash:     m.EventHash.Bytes(),
+	}
+	for _, blockHash := range m.BlockHashList {
+		pb.BlockHashList = append(pb.BlockHashList, blockHash.Bytes())
+	}
+	for _, block := range m.accountBlocks {
+		pb.AccountBlocks = append(pb.AccountBlocks, block.Proto())
+	}
+	for _, block := range m.snapshotBlocks {
+		pb.SnapshotBlocks = append(pb.SnapshotBlocks, block.SnapshotBlock.Proto())
+	}
+	return pb
+}
+
+// eventHash is the hash of the id, the type and the block hashes of an event.
+func eventHash(eventId uint64, eventType byte, blockHashList []types.Hash) types.Hash {
+	source := make([]byte, 9, 9+len(blockHashList)*types.HashSize)
+	binary.BigEndian.PutUint64(source, eventId)
+	source[8] = eventType
+	for _, blockHash := range blockHashList {
+		source = append(source, blockHash.Bytes()...)
+	}
+	return types.DataHash(source)
+}
+
+// getPrevEventHash returns the hash of the last event before or at eventId, the ids of the events failed to write
+// are skipped.
+func getPrevEventHash(chain Chain, eventId uint64) (types.Hash, error) {
+	for ; eventId > 0; eventId-- {
+		eventType, blockHashList, err := chain.GetEvent(eventId)
+		if err != nil {
+			return types.Hash{}, err
+		}
+		if eventType != byte(0) {
+			return eventHash(eventId, eventType, blockHashList), nil
+		}
+	}
+	return types.Hash{}, nil
+}
+
+type MqSnapshotContentItem struct {
+	Start *ledger.HashHeight `json:"start"`
+	End   *ledger.HashHeight `json:"end"`
+}
+
+type MqSnapshotContent map[types.Address]*MqSnapshotContentItem
+
+type MqSnapshotBlock struct {
+	*ledger.SnapshotBlock
+	MqSnapshotContent MqSnapshotContent `json:"snapshotContent"`
+	Producer          types.Address     `json:"producer"`
+	Timestamp         int64             `json:"timestamp"`
+}
+
+type MqAccountBlock struct {
+	ledger.AccountBlock
+
+	Balance     *big.Int      `json:"balance"`
+	FromAddress types.Address `json:"fromAddress"`
+	Timestamp   int64         `json:"timestamp"`
+
+	ParsedData string `json:"parsedData"`
+	SendData   []byte `json:"sendData"`
+}
+
+// Proto converts the block to the protobuf account block of the event with the same enrichment as the json one.
+func (b *MqAccountBlock) Proto() *vitepb.EventAccountBlock {
+	pb := &vitepb.EventAccountBlock{
+		Block:       b.AccountBlock.Proto(),
+		FromAddress: b.FromAddress.Bytes(),
+		ParsedData:  b.ParsedData,
+		SendData:    b.SendData,
+	}
+	if b.Balance != nil {
+		pb.Balance = b.Balance.Bytes()
+	}
+	if b.Amount != nil {
+		pb.Amount = b.Amount.Bytes()
+	}
+	pb.TokenId = b.TokenId.Bytes()
+	return pb
+}
+
+// newMessage reads the event of eventId and builds its message, prevEventHash is the hash of the event before. It
+// returns nil if the event doesn't exist, the id of an event may be skipped if the event failed to write.
+func newMessage(chain Chain, eventId uint64, prevEventHash types.Hash) (*Message, error) {
+	eventType, blockHashList, err := chain.GetEvent(eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Message{
+		Version:       MessageVersion,
+		EventId:       eventId,
+		PrevEventHash: prevEventHash,
+		EventHash:     eventHash(eventId, eventType, blockHashList),
+		BlockHashList: blockHashList,
+
+		eventType: eventType,
+	}
+
+	var data interface{}
+	switch eventType {
+	// AddAccountBlocksEvent     = byte(1)
+	case byte(1):
+		m.MsgType = "InsertAccountBlocks"
+		m.Action = ActionAdd
+		m.accountBlocks, err = newMqAccountBlocks(chain, blockHashList)
+		if err != nil {
+			return nil, err
+		}
+		data = m.accountBlocks
+
+	// DeleteAccountBlocksEvent  = byte(2)
+	case byte(2):
+		m.MsgType = "DeleteAccountBlocks"
+		m.Action = ActionRevert
+		data = blockHashList
+
+	// AddSnapshotBlocksEvent    = byte(3)
+	case byte(3):
+		m.MsgType = "InsertSnapshotBlocks"
+		m.Action = ActionAdd
+		m.snapshotBlocks, err = newMqSnapshotBlocks(chain, blockHashList)
+		if err != nil {
+			return nil, err
+		}
+		data = m.snapshotBlocks
+
+	// DeleteSnapshotBlocksEvent = byte(4)
+	case byte(4):
+		m.MsgType = "DeleteSnapshotBlocks"
+		m.Action = ActionRevert
+		data = blockHashList
+
+	// No event
+	default:
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	m.Data = string(buf)
+	return m, nil
+}
+
+func newMqAccountBlocks(chain Chain, blockHashList []types.Hash) ([]*MqAccountBlock, error) {
+	blocks := make([]*MqAccountBlock, 0, len(blockHashList))
+	for _, blockHash := range blockHashList {
+		block, err := chain.GetAccountBlockByHash(&blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+
+		// Wrap block
+		mqAccountBlock := &MqAccountBlock{}
+		mqAccountBlock.AccountBlock = *block
+
+		var tokenTypeId *types.TokenTypeId
+		if block.IsReceiveBlock() {
+			sendBlock, err := chain.GetAccountBlockByHash(&block.FromBlockHash)
+			if err != nil {
+				return nil, err
+			}
+
+			if sendBlock != nil {
+				tokenTypeId = &sendBlock.TokenId
+				// set token id
+				mqAccountBlock.Amount = sendBlock.Amount
+				mqAccountBlock.TokenId = sendBlock.TokenId
+				mqAccountBlock.FromAddress = sendBlock.AccountAddress
+				mqAccountBlock.ToAddress = mqAccountBlock.AccountAddress
+				mqAccountBlock.SendData = sendBlock.Data
+			}
+		} else {
+			tokenTypeId = &block.TokenId
+			mqAccountBlock.FromAddress = mqAccountBlock.AccountAddress
+
+			var err error
+			mqAccountBlock.ParsedData, err = getParsedData(block)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		balance := big.NewInt(0)
+		if tokenTypeId != nil {
+			vc, err := vm_context.NewVmContext(chain, nil, &block.Hash, &block.AccountAddress)
+			if err != nil {
+				return nil, err
+			}
+			balance = vc.GetBalance(nil, tokenTypeId)
+		}
+
+		mqAccountBlock.Balance = balance
+		mqAccountBlock.Timestamp = block.Timestamp.Unix()
+		blocks = append(blocks, mqAccountBlock)
+	}
+	return blocks, nil
+}
+
+func newMqSnapshotBlocks(chain Chain, blockHashList []types.Hash) ([]*MqSnapshotBlock, error) {
+	blocks := make([]*MqSnapshotBlock, 0, len(blockHashList))
+	for _, blockHash := range blockHashList {
+		block, err := chain.GetSnapshotBlockByHash(&blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			continue
+		}
+
+		mqSnapshotBlock := &MqSnapshotBlock{}
+		mqSnapshotBlock.SnapshotBlock = block
+		subLedger, err := chain.GetConfirmSubLedgerBySnapshotBlocks([]*ledger.SnapshotBlock{block})
+		if err != nil {
+			return nil, err
+		}
+
+		mqSnapshotBlock.MqSnapshotContent = make(MqSnapshotContent)
+		for addr, blocks := range subLedger {
+			mqSnapshotBlock.MqSnapshotContent[addr] = &MqSnapshotContentItem{
+				Start: &ledger.HashHeight{
+					Hash:   blocks[0].Hash,
+					Height: blocks[0].Height,
+				},
+				End: &ledger.HashHeight{
+					Hash:   blocks[len(blocks)-1].Hash,
+					Height: blocks[len(blocks)-1].Height,
+				},
+			}
+		}
+
+		mqSnapshotBlock.Producer = mqSnapshotBlock.SnapshotBlock.Producer()
+		mqSnapshotBlock.Timestamp = block.Timestamp.Unix()
+		blocks = append(blocks, mqSnapshotBlock)
+	}
+	return blocks, nil
+}
+
+func getParsedData(block *ledger.AccountBlock) (string, error) {
+	if len(block.Data) <= 0 {
+		return "", nil
+	}
+
+	switch block.ToAddress.String() {
+	case types.AddressMintage.String():
+		tokenInfo := new(types.TokenInfo)
+		if m, err := abi.ABIMintage.MethodById(block.Data); err != nil || m.Name != abi.MethodNameMintage {
+			return "", nil
+		}
+		err := abi.ABIMintage.UnpackVariable(tokenInfo, abi.MethodNameMintage, block.Data)
+		if err != nil {
+			return "", err
+		}
+		tokenBytes, err := json.Marshal(tokenInfo)
+		return string(tokenBytes), err
+	}
+
+	return "", nil
+}
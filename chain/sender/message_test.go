@@ -0,0 +1,120 @@
+package sender
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/vitepb"
+)
+
+type testEvent struct {
+	eventType     byte
+	blockHashList []types.Hash
+}
+
+// testChain only serves the events, the other methods of Chain panic.
+type testChain struct {
+	Chain
+	events map[uint64]*testEvent
+}
+
+func (c *testChain) GetEvent(eventId uint64) (byte, []types.Hash, error) {
+	event := c.events[eventId]
+	if event == nil {
+		return byte(0), nil, nil
+	}
+	return event.eventType, event.blockHashList, nil
+}
+
+func TestRevertMessage(t *testing.T) {
+	hash1 := types.DataHash([]byte{1})
+	hash2 := types.DataHash([]byte{2})
+	chain := &testChain{
+		events: map[uint64]*testEvent{
+			1: {eventType: byte(2), blockHashList: []types.Hash{hash1}},
+			// the event 2 failed to write
+			3: {eventType: byte(4), blockHashList: []types.Hash{hash1, hash2}},
+		},
+	}
+
+	prevEventHash, err := getPrevEventHash(chain, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := newMessage(chain, 1, types.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prevEventHash != first.EventHash {
+		t.Fatal("the missing event should be skipped")
+	}
+	if m, err := newMessage(chain, 2, prevEventHash); err != nil || m != nil {
+		t.Fatal("there is no message for the missing event")
+	}
+
+	m, err := newMessage(chain, 3, prevEventHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != MessageVersion || m.Action != ActionRevert || m.MsgType != "DeleteSnapshotBlocks" ||
+		m.PrevEventHash != first.EventHash || len(m.BlockHashList) != 2 {
+		t.Fatalf("wrong message %+v", m)
+	}
+	if m.EventHash == first.EventHash || m.EventHash != eventHash(3, byte(4), []types.Hash{hash1, hash2}) {
+		t.Fatal("wrong event hash")
+	}
+
+	buf, err := proto.Marshal(m.Proto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb := &vitepb.ChainEvent{}
+	if err := proto.Unmarshal(buf, pb); err != nil {
+		t.Fatal(err)
+	}
+	if pb.EventId != 3 || pb.EventType != vitepb.ChainEvent_REVERT_SNAPSHOT_BLOCKS ||
+		len(pb.BlockHashList) != 2 || !bytes.Equal(pb.PrevEventHash, first.EventHash.Bytes()) {
+		t.Fatalf("wrong proto %v", pb)
+	}
+}
+
+func TestSinkFormat(t *testing.T) {
+	if _, err := NewSink(&SinkConfig{Kind: SinkFile, Format: FormatProto, Path: "events.jsonl"}); err == nil {
+		t.Fatal("only the kafka sinks send proto messages")
+	}
+	cfg := &SinkConfig{Kind: SinkKafka, BrokerList: []string{"a"}, Topic: "t"}
+	if !cfg.IsSame(&SinkConfig{Kind: SinkKafka, Format: FormatJson, BrokerList: []string{"a"}, Topic: "t"}) {
+		t.Fatal("the default format is json")
+	}
+	if cfg.IsSame(&SinkConfig{Kind: SinkKafka, Format: FormatProto, BrokerList: []string{"a"}, Topic: "t"}) {
+		t.Fatal("the formats are different")
+	}
+}
+
+func TestAccountBlockProto(t *testing.T) {
+	from, _, _ := types.CreateAddress()
+	block := &MqAccountBlock{
+		Balance:     big.NewInt(100),
+		FromAddress: from,
+		ParsedData:  "{}",
+		SendData:    []byte{1},
+	}
+	// the receive block carries the amount and the token of its send block
+	now := time.Now()
+	block.BlockType = ledger.BlockTypeReceive
+	block.AccountBlock.Timestamp = &now
+	block.Amount = big.NewInt(10)
+	block.TokenId = ledger.ViteTokenId
+
+	pb := block.Proto()
+	if pb.Block == nil || !bytes.Equal(pb.Amount, block.Amount.Bytes()) || !bytes.Equal(pb.TokenId, ledger.ViteTokenId.Bytes()) ||
+		!bytes.Equal(pb.Balance, block.Balance.Bytes()) ||
+		!bytes.Equal(pb.FromAddress, from.Bytes()) || pb.ParsedData != "{}" || !bytes.Equal(pb.SendData, []byte{1}) {
+		t.Fatalf("the enrichment is lost %v", pb)
+	}
+}
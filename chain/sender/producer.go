@@ -3,20 +3,14 @@ package sender
 import (
 	"encoding/binary"
 	"encoding/json"
-	"github.com/vitelabs/go-vite/vm/contracts/abi"
-	"math/big"
 	"sync"
 	"time"
 
-	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
-	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common"
-	"github.com/vitelabs/go-vite/common/types"
-	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/vitepb"
-	"github.com/vitelabs/go-vite/vm_context"
 )
 
 const (
@@ -24,48 +18,17 @@ const (
 	RUNNING
 )
 
-type message struct {
-	MsgType string `json:"type"`
-	Data    string `json:"data"`
-	EventId uint64 `json:"eventId"`
-}
-
-type MqSnapshotContentItem struct {
-	Start *ledger.HashHeight `json:"start"`
-	End   *ledger.HashHeight `json:"end"`
-}
-
-type MqSnapshotContent map[types.Address]*MqSnapshotContentItem
-
-type MqSnapshotBlock struct {
-	*ledger.SnapshotBlock
-	MqSnapshotContent MqSnapshotContent `json:"snapshotContent"`
-	Producer          types.Address     `json:"producer"`
-	Timestamp         int64             `json:"timestamp"`
-}
-
-type MqAccountBlock struct {
-	ledger.AccountBlock
-
-	Balance     *big.Int      `json:"balance"`
-	FromAddress types.Address `json:"fromAddress"`
-	Timestamp   int64         `json:"timestamp"`
-
-	ParsedData string `json:"parsedData"`
-	SendData   []byte `json:"sendData"`
-}
-
+// Producer replays the block events to a sink from its cursor. hasSend is the id of the last event delivered to the
+// sink, it's saved after every delivered batch, so only the last batch may be sent again if the node exits before the
+// cursor is saved. The consumers drop the duplicated messages by EventId.
 type Producer struct {
 	producerId uint8
-	db         *leveldb.DB
+	db         database.Store
 
-	brokerList []string
-	topic      string
+	sink Sink
 
-	hasSendLock      sync.RWMutex
-	hasSend          uint64
-	dbHasSend        uint64
-	dbRecordInterval uint64
+	hasSendLock sync.RWMutex
+	hasSend     uint64
 
 	termination chan int
 
@@ -75,30 +38,27 @@ type Producer struct {
 
 	wg sync.WaitGroup
 
-	kafkaProducer sarama.AsyncProducer
-	chain         Chain
-	concurrency   uint64
-
-	sendWg sync.WaitGroup
+	chain       Chain
+	concurrency uint64
 }
 
-func NewProducerFromDb(producerId uint8, buf []byte, chain Chain, db *leveldb.DB) (*Producer, error) {
-	producer := &Producer{}
-	if dsErr := producer.Deserialize(buf); dsErr != nil {
+func NewProducerFromDb(producerId uint8, buf []byte, chain Chain, db database.Store) (*Producer, error) {
+	cfg, dsErr := deserializeKafkaConfig(buf)
+	if dsErr != nil {
 		return nil, dsErr
 	}
 
-	if err := producer.init(producerId, chain, db); err != nil {
+	return NewProducer(producerId, cfg, chain, db)
+}
+
+func NewProducer(producerId uint8, cfg *SinkConfig, chain Chain, db database.Store) (*Producer, error) {
+	sink, err := NewSink(cfg)
+	if err != nil {
 		return nil, err
 	}
-	producer.log = log15.New("module", "sender/producer")
-	return producer, nil
-}
 
-func NewProducer(producerId uint8, brokerList []string, topic string, chain Chain, db *leveldb.DB) (*Producer, error) {
 	producer := &Producer{
-		brokerList: brokerList,
-		topic:      topic,
+		sink: sink,
 	}
 
 	if err := producer.init(producerId, chain, db); err != nil {
@@ -108,13 +68,12 @@ func NewProducer(producerId uint8, brokerList []string, topic string, chain Chai
 	return producer, nil
 }
 
-func (producer *Producer) init(producerId uint8, chain Chain, db *leveldb.DB) error {
+func (producer *Producer) init(producerId uint8, chain Chain, db database.Store) error {
 	producer.producerId = producerId
 	producer.concurrency = 100
 
 	producer.chain = chain
 	producer.db = db
-	producer.dbRecordInterval = 100
 
 	hasSend, err := producer.getHasSend()
 	if err != nil {
@@ -124,7 +83,6 @@ func (producer *Producer) init(producerId uint8, chain Chain, db *leveldb.DB) er
 	producer.hasSendLock.Lock()
 
 	producer.hasSend = hasSend
-	producer.dbHasSend = hasSend
 
 	producer.hasSendLock.Unlock()
 
@@ -143,12 +101,8 @@ func (producer *Producer) ProducerId() uint8 {
 	return producer.producerId
 }
 
-func (producer *Producer) BrokerList() []string {
-	return producer.brokerList
-}
-
-func (producer *Producer) Topic() string {
-	return producer.topic
+func (producer *Producer) SinkConfig() *SinkConfig {
+	return producer.sink.Config()
 }
 
 func (producer *Producer) HasSend() uint64 {
@@ -159,49 +113,36 @@ func (producer *Producer) Status() int {
 	return producer.status
 }
 
-func (producer *Producer) IsSame(brokerList []string, topic string) bool {
-	if producer.topic != topic ||
-		len(brokerList) != len(producer.brokerList) {
-		return false
-	}
-
-	tmpBrokerList := brokerList[:]
-	for _, aBroker := range producer.brokerList {
-		hasExist := false
-		tmpBrokerListLen := len(tmpBrokerList)
-
-		for i := 0; i < tmpBrokerListLen; i++ {
-			if aBroker == tmpBrokerList[i] {
-				tmpBrokerList = append(tmpBrokerList[:i], tmpBrokerList[i+1:]...)
-				hasExist = true
-				break
-			}
-		}
+func (producer *Producer) IsSame(cfg *SinkConfig) bool {
+	return producer.sink.Config().IsSame(cfg)
+}
 
-		if !hasExist {
-			return false
-		}
+// Serialize encodes the config of the sink, the kafka sinks are encoded in the format of the old kafka producers.
+func (producer *Producer) Serialize() ([]byte, error) {
+	cfg := producer.sink.Config()
+	if cfg.Kind == SinkKafka {
+		pb := &vitepb.Producer{}
+		pb.BrokerList = cfg.BrokerList
+		pb.Topic = cfg.Topic
+		pb.Format = cfg.Format
+
+		return proto.Marshal(pb)
 	}
-	return true
+	return json.Marshal(cfg)
 }
 
-func (producer *Producer) Deserialize(buffer []byte) error {
+func deserializeKafkaConfig(buffer []byte) (*SinkConfig, error) {
 	pb := &vitepb.Producer{}
 	if err := proto.Unmarshal(buffer, pb); err != nil {
-		return err
+		return nil, err
 	}
 
-	producer.topic = pb.Topic
-	producer.brokerList = pb.BrokerList
-	return nil
-}
-
-func (producer *Producer) Serialize() ([]byte, error) {
-	pb := &vitepb.Producer{}
-	pb.BrokerList = producer.brokerList
-	pb.Topic = producer.topic
-
-	return proto.Marshal(pb)
+	return &SinkConfig{
+		Kind:       SinkKafka,
+		BrokerList: pb.BrokerList,
+		Topic:      pb.Topic,
+		Format:     pb.Format,
+	}, nil
 }
 
 func (producer *Producer) Start() error {
@@ -211,16 +152,10 @@ func (producer *Producer) Start() error {
 		return nil
 	}
 
-	config := sarama.NewConfig()
-	config.Producer.Return.Successes = true
-	config.Producer.Return.Errors = true
-
-	kafkaProducer, err := sarama.NewAsyncProducer(producer.brokerList, config)
-	if err != nil {
+	if err := producer.sink.Open(); err != nil {
 		return err
 	}
 
-	producer.kafkaProducer = kafkaProducer
 	producer.status = RUNNING
 	producer.termination = make(chan int)
 
@@ -234,18 +169,17 @@ func (producer *Producer) Start() error {
 				closeCount := 0
 
 				for ; closeCount < tryCloseCount; closeCount++ {
-					closeErr := producer.kafkaProducer.Close()
+					closeErr := producer.sink.Close()
 
 					if closeErr != nil {
-						producer.log.Error("kafkaProducer close failed, error is "+closeErr.Error(), "method", "Start")
+						producer.log.Error("sink close failed, error is "+closeErr.Error(), "method", "Start")
 					} else {
-						producer.kafkaProducer = nil
 						return
 					}
 				}
 
 				if closeCount == tryCloseCount {
-					producer.log.Crit("kafkaProducer close failed", "method", "Start")
+					producer.log.Crit("sink close failed", "method", "Start")
 				}
 			default:
 				producer.send()
@@ -269,28 +203,6 @@ func (producer *Producer) Stop() {
 	producer.status = STOPPED
 }
 
-func (producer *Producer) getParsedData(block *ledger.AccountBlock) (string, error) {
-	if len(block.Data) <= 0 {
-		return "", nil
-	}
-
-	switch block.ToAddress.String() {
-	case types.AddressMintage.String():
-		tokenInfo := new(types.TokenInfo)
-		if m, err := abi.ABIMintage.MethodById(block.Data); err != nil || m.Name != abi.MethodNameMintage {
-			return "", nil
-		}
-		err := abi.ABIMintage.UnpackVariable(tokenInfo, abi.MethodNameMintage, block.Data)
-		if err != nil {
-			return "", err
-		}
-		tokenBytes, err := json.Marshal(tokenInfo)
-		return string(tokenBytes), err
-	}
-
-	return "", nil
-}
-
 func (producer *Producer) send() {
 	producer.hasSendLock.Lock()
 	defer producer.hasSendLock.Unlock()
@@ -301,208 +213,46 @@ func (producer *Producer) send() {
 		producer.log.Error("GetLatestBlockEventId failed, error is "+err.Error(), "method", "send")
 		return
 	}
+	if start >= end {
+		return
+	}
 
-	defer func() {
-		if producer.hasSend > producer.dbHasSend {
-			if err := producer.saveHasSend(); err != nil {
-				producer.log.Error("saveHasSend failed, error is "+err.Error(), "method", "send")
-			}
-		}
-	}()
+	prevEventHash, err := getPrevEventHash(producer.chain, start)
+	if err != nil {
+		producer.log.Error("getPrevEventHash failed, error is "+err.Error(), "method", "send")
+		return
+	}
 
 	for i := start; i < end; {
-		if producer.hasSend > producer.dbHasSend &&
-			producer.hasSend-producer.dbHasSend >= producer.dbRecordInterval {
-			if err := producer.saveHasSend(); err != nil {
-				producer.log.Error("saveHasSend failed, error is "+err.Error(), "method", "send")
-			}
-
-		}
-
-		var msgList []*message
+		var msgList []*Message
 
 		j := i + 1
 		for ; j-i <= producer.concurrency && j <= end; j++ {
-			eventType, blockHashList, err := producer.chain.GetEvent(j)
+			m, err := newMessage(producer.chain, j, prevEventHash)
 			if err != nil {
-				producer.log.Error("Get event failed, error is "+err.Error(), "method", "send")
+				producer.log.Error("newMessage failed, error is "+err.Error(), "method", "send")
 				return
 			}
-
-			m := &message{
-				EventId: j,
-			}
-
-			switch eventType {
-			// AddAccountBlocksEvent     = byte(1)
-			case byte(1):
-				m.MsgType = "InsertAccountBlocks"
-				var blocks []*MqAccountBlock
-				for _, blockHash := range blockHashList {
-					block, err := producer.chain.GetAccountBlockByHash(&blockHash)
-					if err != nil {
-						producer.log.Error("GetAccountBlockByHash failed, error is "+err.Error(), "method", "send")
-						return
-					}
-					if block != nil {
-						// Wrap block
-						mqAccountBlock := &MqAccountBlock{}
-						mqAccountBlock.AccountBlock = *block
-
-						var sendBlock *ledger.AccountBlock
-
-						var tokenTypeId *types.TokenTypeId
-						if block.IsReceiveBlock() {
-							var err error
-							sendBlock, err = producer.chain.GetAccountBlockByHash(&block.FromBlockHash)
-
-							if err != nil {
-								producer.log.Error("Get send account block failed, error is "+err.Error(), "method", "send")
-								return
-							}
-
-							if sendBlock != nil {
-								tokenTypeId = &sendBlock.TokenId
-								// set token id
-								mqAccountBlock.Amount = sendBlock.Amount
-								mqAccountBlock.TokenId = sendBlock.TokenId
-								mqAccountBlock.FromAddress = sendBlock.AccountAddress
-								mqAccountBlock.ToAddress = mqAccountBlock.AccountAddress
-								mqAccountBlock.SendData = sendBlock.Data
-							}
-						} else {
-							tokenTypeId = &block.TokenId
-							mqAccountBlock.FromAddress = mqAccountBlock.AccountAddress
-
-							var err error
-							mqAccountBlock.ParsedData, err = producer.getParsedData(block)
-							if err != nil {
-								producer.log.Error("GetParsedData failed, error is "+err.Error(), "method", "send")
-								return
-							}
-
-						}
-
-						balance := big.NewInt(0)
-
-						if tokenTypeId != nil {
-							vc, newVcErr := vm_context.NewVmContext(producer.chain, nil, &block.Hash, &block.AccountAddress)
-							if newVcErr != nil {
-								producer.log.Error("NewVmContext failed, error is "+newVcErr.Error(), "method", "send")
-								return
-							}
-							balance = vc.GetBalance(nil, tokenTypeId)
-						}
-
-						mqAccountBlock.Balance = balance
-						mqAccountBlock.Timestamp = block.Timestamp.Unix()
-						blocks = append(blocks, mqAccountBlock)
-					}
-				}
-
-				if len(blocks) <= 0 {
-					producer.hasSend = j
-					continue
-				}
-
-				buf, jsonErr := json.Marshal(blocks)
-				if jsonErr != nil {
-					producer.log.Error("[InsertAccountBlocks] json.Marshal failed, error is "+jsonErr.Error(), "method", "send")
-					return
-				}
-				m.Data += string(buf)
-
-				// DeleteAccountBlocksEvent  = byte(2)
-			case byte(2):
-				m.MsgType = "DeleteAccountBlocks"
-
-				buf, jsonErr := json.Marshal(blockHashList)
-				if jsonErr != nil {
-					producer.log.Error("[DeleteAccountBlocks] json.Marshal failed, error is "+jsonErr.Error(), "method", "send")
-					return
-				}
-				m.Data = string(buf)
-
-				// AddSnapshotBlocksEvent    = byte(3)
-			case byte(3):
-				m.MsgType = "InsertSnapshotBlocks"
-				var blocks []*MqSnapshotBlock
-				for _, blockHash := range blockHashList {
-					block, err := producer.chain.GetSnapshotBlockByHash(&blockHash)
-					if err != nil {
-						producer.log.Error("GetSnapshotBlockByHash failed, error is "+err.Error(), "method", "send")
-						return
-					}
-					if block != nil {
-						mqSnapshotBlock := &MqSnapshotBlock{}
-						mqSnapshotBlock.SnapshotBlock = block
-						subLedger, err := producer.chain.GetConfirmSubLedgerBySnapshotBlocks([]*ledger.SnapshotBlock{block})
-						if err != nil {
-							producer.log.Error("GetConfirmSubLedgerBySnapshotBlocks failed, error is "+err.Error(), "method", "send")
-							return
-						}
-
-						mqSnapshotBlock.MqSnapshotContent = make(MqSnapshotContent)
-						for addr, blocks := range subLedger {
-							mqSnapshotBlock.MqSnapshotContent[addr] = &MqSnapshotContentItem{
-								Start: &ledger.HashHeight{
-									Hash:   blocks[0].Hash,
-									Height: blocks[0].Height,
-								},
-								End: &ledger.HashHeight{
-									Hash:   blocks[len(blocks)-1].Hash,
-									Height: blocks[len(blocks)-1].Height,
-								},
-							}
-						}
-
-						mqSnapshotBlock.Producer = mqSnapshotBlock.SnapshotBlock.Producer()
-						mqSnapshotBlock.Timestamp = block.Timestamp.Unix()
-						blocks = append(blocks, mqSnapshotBlock)
-					}
-				}
-
-				if len(blocks) <= 0 {
-					producer.hasSend = j
-					continue
-				}
-
-				buf, jsonErr := json.Marshal(blocks)
-				if jsonErr != nil {
-					producer.log.Error("[InsertSnapshotBlocks] json.Marshal failed, error is "+jsonErr.Error(), "method", "send")
-					return
-				}
-				m.Data += string(buf)
-
-				// DeleteSnapshotBlocksEvent = byte(4)
-			case byte(4):
-				m.MsgType = "DeleteSnapshotBlocks"
-				buf, jsonErr := json.Marshal(blockHashList)
-				if jsonErr != nil {
-					producer.log.Error("[DeleteSnapshotBlocks] json.Marshal failed, error is "+jsonErr.Error(), "method", "send")
-					return
-				}
-				m.Data = string(buf)
-
-				// No event
-			default:
-				producer.hasSend = j
-				continue
+			if m != nil {
+				msgList = append(msgList, m)
+				prevEventHash = m.EventHash
 			}
-
-			msgList = append(msgList, m)
-
 		}
 
-		sendErr := producer.sendMessage(msgList)
-		if sendErr != nil {
-			producer.log.Error("sendMessage failed, error is "+sendErr.Error(), "method", "send")
-			return
+		if len(msgList) > 0 {
+			sendErr := producer.sink.Send(msgList)
+			if sendErr != nil {
+				producer.log.Error("Send failed, error is "+sendErr.Error(), "method", "send")
+				return
+			}
 		}
 
+		// the cursor is moved only after all messages before it are delivered
 		i = j - 1
 		producer.hasSend = i
-
+		if err := producer.saveHasSend(); err != nil {
+			producer.log.Error("saveHasSend failed, error is "+err.Error(), "method", "send")
+		}
 	}
 }
 
@@ -512,20 +262,15 @@ func (producer *Producer) saveHasSend() error {
 	buf := make([]byte, 8)
 	binary.BigEndian.PutUint64(buf, producer.hasSend)
 
-	if err := producer.db.Put(key, buf, nil); err != nil {
-		return err
-	}
-
-	producer.dbHasSend = producer.hasSend
-	return nil
+	return producer.db.Put(key, buf)
 }
 
 func (producer *Producer) getHasSend() (uint64, error) {
 	key := append([]byte{DBKP_PRODUCER_HAS_SEND}, producer.producerId)
 
-	value, err := producer.db.Get(key, nil)
+	value, err := producer.db.Get(key)
 	if err != nil {
-		if err != leveldb.ErrNotFound {
+		if err != database.ErrNotFound {
 			return 0, err
 		}
 		return 0, nil
@@ -533,33 +278,3 @@ func (producer *Producer) getHasSend() (uint64, error) {
 
 	return binary.BigEndian.Uint64(value), nil
 }
-
-func (producer *Producer) sendMessage(msgList []*message) (err error) {
-	//var err error
-	for i := 0; i < len(msgList); i++ {
-		buf, jsonErr := json.Marshal(msgList[i])
-		if jsonErr != nil {
-			return jsonErr
-		}
-		sMsg := &sarama.ProducerMessage{Topic: producer.topic, Value: sarama.StringEncoder(buf)}
-
-		producer.sendWg.Add(1)
-		// Simple implementation, may be fix
-		go func() {
-			defer producer.sendWg.Done()
-			producer.kafkaProducer.Input() <- sMsg
-			select {
-			// success
-			case <-producer.kafkaProducer.Successes():
-				break
-
-			// error
-			case sendError := <-producer.kafkaProducer.Errors():
-				producer.log.Error("kafka send failed, error is "+sendError.Error(), "method", "sendMessage")
-				err = sendError
-			}
-		}()
-	}
-	producer.sendWg.Wait()
-	return
-}
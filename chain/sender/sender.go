@@ -1,42 +1,47 @@
 package sender
 
 import (
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
+	"encoding/json"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/log15"
 	"os"
 	"sync"
 )
 
 const (
+	// the producers of the kafka sinks, in the format before the other sinks are added
 	DBKP_PRODUCER          = byte(1)
 	DBKP_PRODUCER_HAS_SEND = byte(2)
+	// the producers of the other sinks
+	DBKP_PRODUCER_SINK = byte(3)
 )
 
-type KafkaSender struct {
+// Sender runs the producers, every producer sends the block events to a sink and keeps its own cursor.
+type Sender struct {
 	producers    []*Producer
 	runProducers []*Producer
 
 	chain Chain
-	db    *leveldb.DB
+	db    database.Store
 
 	lock sync.Mutex
 	log  log15.Logger
 }
 
-func NewKafkaSender(chain Chain, dirName string) (*KafkaSender, error) {
+func NewSender(chain Chain, dirName string) (*Sender, error) {
 	// create directory
 	if _, err := os.Stat(dirName); os.IsNotExist(err) {
 		os.Mkdir(dirName, 0755)
 	}
 
-	sender := &KafkaSender{
+	sender := &Sender{
 		//producer: producer,
 		chain: chain,
 		log:   log15.New("module", "chain/sender"),
 	}
 
-	db, openDbErr := leveldb.OpenFile(dirName, nil)
+	db, openDbErr := database.NewLevelDb(dirName)
 
 	if openDbErr != nil {
 		return nil, openDbErr
@@ -54,17 +59,17 @@ func NewKafkaSender(chain Chain, dirName string) (*KafkaSender, error) {
 	return sender, nil
 }
 
-func (sender *KafkaSender) Start(brokerList []string, topic string) error {
+func (sender *Sender) Start(cfg *SinkConfig) error {
 	sender.lock.Lock()
 	defer sender.lock.Unlock()
 
-	producer, err := sender.getProducer(brokerList, topic)
+	producer, err := sender.getProducer(cfg)
 	if err != nil {
 		return err
 	}
 
 	for _, runProducer := range sender.runProducers {
-		if runProducer.IsSame(brokerList, topic) {
+		if runProducer.IsSame(cfg) {
 			// has run
 			return nil
 		}
@@ -79,7 +84,7 @@ func (sender *KafkaSender) Start(brokerList []string, topic string) error {
 	return nil
 }
 
-func (sender *KafkaSender) StopById(producerId uint8) {
+func (sender *Sender) StopById(producerId uint8) {
 	sender.lock.Lock()
 	defer sender.lock.Unlock()
 
@@ -92,12 +97,12 @@ func (sender *KafkaSender) StopById(producerId uint8) {
 	}
 }
 
-func (sender *KafkaSender) Stop(brokerList []string, topic string) {
+func (sender *Sender) Stop(cfg *SinkConfig) {
 	sender.lock.Lock()
 	defer sender.lock.Unlock()
 
 	for index, runProducer := range sender.runProducers {
-		if runProducer.IsSame(brokerList, topic) {
+		if runProducer.IsSame(cfg) {
 			// has run
 			runProducer.Stop()
 			sender.runProducers = append(sender.runProducers[:index], sender.runProducers[index+1:]...)
@@ -105,7 +110,7 @@ func (sender *KafkaSender) Stop(brokerList []string, topic string) {
 	}
 }
 
-func (sender *KafkaSender) StopAll() {
+func (sender *Sender) StopAll() {
 	sender.lock.Lock()
 	defer sender.lock.Unlock()
 
@@ -114,7 +119,7 @@ func (sender *KafkaSender) StopAll() {
 	}
 }
 
-func (sender *KafkaSender) SetHasSend(producerId uint8, hasSend uint64) {
+func (sender *Sender) SetHasSend(producerId uint8, hasSend uint64) {
 	for _, producer := range sender.producers {
 		if producer.producerId == producerId {
 			producer.SetHasSend(hasSend)
@@ -123,22 +128,22 @@ func (sender *KafkaSender) SetHasSend(producerId uint8, hasSend uint64) {
 	}
 }
 
-func (sender *KafkaSender) Producers() []*Producer {
+func (sender *Sender) Producers() []*Producer {
 	return sender.producers
 }
 
-func (sender *KafkaSender) RunProducers() []*Producer {
+func (sender *Sender) RunProducers() []*Producer {
 	return sender.runProducers
 }
 
-func (sender *KafkaSender) getProducer(brokerList []string, topic string) (*Producer, error) {
+func (sender *Sender) getProducer(cfg *SinkConfig) (*Producer, error) {
 	for _, producer := range sender.producers {
-		if producer.IsSame(brokerList, topic) {
+		if producer.IsSame(cfg) {
 			return producer, nil
 		}
 	}
 
-	newProducer, newErr := NewProducer(byte(len(sender.producers)+1), brokerList, topic, sender.chain, sender.db)
+	newProducer, newErr := NewProducer(byte(len(sender.producers)+1), cfg, sender.chain, sender.db)
 	if newErr != nil {
 		return nil, newErr
 	}
@@ -151,27 +156,59 @@ func (sender *KafkaSender) getProducer(brokerList []string, topic string) (*Prod
 	return newProducer, nil
 }
 
-func (sender *KafkaSender) writeProducerToDb(producer *Producer) error {
+func (sender *Sender) writeProducerToDb(producer *Producer) error {
 	key := append([]byte{DBKP_PRODUCER}, producer.producerId)
+	if producer.SinkConfig().Kind != SinkKafka {
+		key = append([]byte{DBKP_PRODUCER_SINK}, producer.producerId)
+	}
 	buf, sErr := producer.Serialize()
 
 	if sErr != nil {
 		return sErr
 	}
 
-	wErr := sender.db.Put(key, buf, nil)
+	wErr := sender.db.Put(key, buf)
 	return wErr
 }
 
-func (sender *KafkaSender) readProducersFromDb() ([]*Producer, error) {
-	iter := sender.db.NewIterator(util.BytesPrefix([]byte{byte(DBKP_PRODUCER)}), nil)
+func (sender *Sender) readProducersFromDb() ([]*Producer, error) {
+	producers, err := sender.readKafkaProducersFromDb()
+	if err != nil {
+		return nil, err
+	}
+
+	iter := sender.db.NewIterator(database.BytesPrefix([]byte{DBKP_PRODUCER_SINK}))
+	defer iter.Release()
+
+	for iter.Next() {
+		producerId := uint8(iter.Key()[1])
+
+		cfg := &SinkConfig{}
+		if err := json.Unmarshal(iter.Value(), cfg); err != nil {
+			return nil, err
+		}
+		producer, err := NewProducer(producerId, cfg, sender.chain, sender.db)
+		if err != nil {
+			return nil, err
+		}
+		producers = append(producers, producer)
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+
+	return producers, nil
+}
+
+func (sender *Sender) readKafkaProducersFromDb() ([]*Producer, error) {
+	iter := sender.db.NewIterator(database.BytesPrefix([]byte{byte(DBKP_PRODUCER)}))
 	defer iter.Release()
 
 	var producers []*Producer
 	for {
 		iterOk := iter.Next()
 		if !iterOk {
-			if iterErr := iter.Error(); iterErr != nil && iterErr != leveldb.ErrNotFound {
+			if iterErr := iter.Error(); iterErr != nil && iterErr != database.ErrNotFound {
 				return nil, iterErr
 			}
 			break
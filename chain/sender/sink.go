@@ -0,0 +1,116 @@
+package sender
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	SinkKafka   = "kafka"
+	SinkFile    = "file"
+	SinkWebhook = "webhook"
+
+	// the messages are encoded in json by default, the kafka sinks can send them as vitepb.ChainEvent
+	FormatJson  = "json"
+	FormatProto = "proto"
+)
+
+// SinkConfig identifies a sink, only the fields of Kind are used. The cursor of a producer is bound to its SinkConfig.
+type SinkConfig struct {
+	Kind   string `json:"kind"`
+	Format string `json:"format,omitempty"`
+
+	// kafka
+	BrokerList []string `json:"brokerList,omitempty"`
+	Topic      string   `json:"topic,omitempty"`
+
+	// file
+	Path string `json:"path,omitempty"`
+
+	// webhook
+	Url string `json:"url,omitempty"`
+}
+
+func (cfg *SinkConfig) IsSame(other *SinkConfig) bool {
+	if cfg.Kind != other.Kind ||
+		cfg.format() != other.format() ||
+		cfg.Topic != other.Topic ||
+		cfg.Path != other.Path ||
+		cfg.Url != other.Url ||
+		len(cfg.BrokerList) != len(other.BrokerList) {
+		return false
+	}
+
+	brokerSet := make(map[string]int)
+	for _, broker := range cfg.BrokerList {
+		brokerSet[broker]++
+	}
+	for _, broker := range other.BrokerList {
+		if brokerSet[broker] <= 0 {
+			return false
+		}
+		brokerSet[broker]--
+	}
+	return true
+}
+
+func (cfg *SinkConfig) format() string {
+	if cfg.Format == "" {
+		return FormatJson
+	}
+	return cfg.Format
+}
+
+func (cfg *SinkConfig) String() string {
+	switch cfg.Kind {
+	case SinkKafka:
+		return fmt.Sprintf("kafka %v %s %s", cfg.BrokerList, cfg.Topic, cfg.format())
+	case SinkFile:
+		return "file " + cfg.Path
+	case SinkWebhook:
+		return "webhook " + cfg.Url
+	}
+	return cfg.Kind
+}
+
+// Sink is the transport of the messages. The producer calls Open when it starts and Close when it stops, Send is
+// called from one goroutine and returns nil only if all messages are delivered, the cursor of the producer isn't
+// moved otherwise and the messages are sent again later.
+type Sink interface {
+	Config() *SinkConfig
+
+	Open() error
+	Send(msgList []*Message) error
+	Close() error
+}
+
+func NewSink(cfg *SinkConfig) (Sink, error) {
+	switch cfg.format() {
+	case FormatJson:
+	case FormatProto:
+		if cfg.Kind != SinkKafka {
+			return nil, errors.New(fmt.Sprintf("%s sink can't send proto messages", cfg.Kind))
+		}
+	default:
+		return nil, errors.New(fmt.Sprintf("unknown message format %s", cfg.Format))
+	}
+
+	switch cfg.Kind {
+	case SinkKafka:
+		if len(cfg.BrokerList) <= 0 || cfg.Topic == "" {
+			return nil, errors.New("kafka sink needs brokerList and topic")
+		}
+		return newKafkaSink(cfg), nil
+	case SinkFile:
+		if cfg.Path == "" {
+			return nil, errors.New("file sink needs path")
+		}
+		return newFileSink(cfg), nil
+	case SinkWebhook:
+		if cfg.Url == "" {
+			return nil, errors.New("webhook sink needs url")
+		}
+		return newWebhookSink(cfg), nil
+	}
+	return nil, errors.New(fmt.Sprintf("unknown sink kind %s", cfg.Kind))
+}
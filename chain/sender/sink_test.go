@@ -0,0 +1,102 @@
+package sender
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testSinkSend(t *testing.T, cfg *SinkConfig, msgList ...*Message) error {
+	sink, err := NewSink(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	return sink.Send(msgList)
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file_sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := &SinkConfig{Kind: SinkFile, Path: filepath.Join(dir, "events", "events.jsonl")}
+	if err := testSinkSend(t, cfg, &Message{MsgType: "DeleteAccountBlocks", EventId: 1}, &Message{EventId: 2}); err != nil {
+		t.Fatal(err)
+	}
+	// the file is appended after reopened
+	if err := testSinkSend(t, cfg, &Message{EventId: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(cfg.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var eventIdList []uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		msg := &Message{}
+		if err := json.Unmarshal(scanner.Bytes(), msg); err != nil {
+			t.Fatal(err)
+		}
+		eventIdList = append(eventIdList, msg.EventId)
+	}
+	if len(eventIdList) != 3 || eventIdList[0] != 1 || eventIdList[2] != 3 {
+		t.Fatalf("the lines are %v", eventIdList)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received []*Message
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msgList []*Message
+		if err := json.NewDecoder(r.Body).Decode(&msgList); err != nil {
+			t.Error(err)
+		}
+		if status == http.StatusOK {
+			received = append(received, msgList...)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := &SinkConfig{Kind: SinkWebhook, Url: server.URL}
+	if err := testSinkSend(t, cfg, &Message{EventId: 1}, &Message{EventId: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 2 || received[1].EventId != 2 {
+		t.Fatalf("received %d messages", len(received))
+	}
+
+	status = http.StatusInternalServerError
+	if err := testSinkSend(t, cfg, &Message{EventId: 3}); err == nil {
+		t.Fatal("the send should fail if the webhook fails")
+	}
+}
+
+func TestSinkConfig(t *testing.T) {
+	cfg := &SinkConfig{Kind: SinkKafka, BrokerList: []string{"a", "b"}, Topic: "t"}
+	if !cfg.IsSame(&SinkConfig{Kind: SinkKafka, BrokerList: []string{"b", "a"}, Topic: "t"}) {
+		t.Fatal("the order of the brokers should be ignored")
+	}
+	if cfg.IsSame(&SinkConfig{Kind: SinkKafka, BrokerList: []string{"a", "a"}, Topic: "t"}) {
+		t.Fatal("the brokers are different")
+	}
+	if _, err := NewSink(&SinkConfig{Kind: "nats"}); err == nil {
+		t.Fatal("unknown kind should fail")
+	}
+}
@@ -0,0 +1,60 @@
+package sender
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const webhookTimeout = 30 * time.Second
+
+// webhookSink posts the messages to an url as a json array, any status other than 2xx fails the whole list.
+type webhookSink struct {
+	cfg    *SinkConfig
+	client *http.Client
+}
+
+func newWebhookSink(cfg *SinkConfig) *webhookSink {
+	return &webhookSink{
+		cfg: cfg,
+	}
+}
+
+func (sink *webhookSink) Config() *SinkConfig {
+	return sink.cfg
+}
+
+func (sink *webhookSink) Open() error {
+	sink.client = &http.Client{Timeout: webhookTimeout}
+	return nil
+}
+
+func (sink *webhookSink) Send(msgList []*Message) error {
+	buf, err := json.Marshal(msgList)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sink.client.Post(sink.cfg.Url, "application/json", bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.New(fmt.Sprintf("webhook responded %s", resp.Status))
+	}
+	return nil
+}
+
+func (sink *webhookSink) Close() error {
+	sink.client = nil
+	return nil
+}
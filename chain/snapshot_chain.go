@@ -5,7 +5,7 @@ import (
 
 	"fmt"
 	"github.com/pkg/errors"
-	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/monitor"
@@ -86,7 +86,7 @@ func (c *chain) InsertSnapshotBlock(snapshotBlock *ledger.SnapshotBlock) error {
 	monitorTags := []string{"chain", "InsertSnapshotBlock"}
 	defer monitor.LogTimerConsuming(monitorTags, time.Now())
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 
 	// Check and create account
 	address := types.PubkeyToAddress(snapshotBlock.PublicKey)
@@ -149,6 +149,12 @@ func (c *chain) InsertSnapshotBlock(snapshotBlock *ledger.SnapshotBlock) error {
 		return saveTrieErr
 	}
 
+	// Save flat state
+	var flatStateVersion *types.Hash
+	if c.flatState != nil {
+		flatStateVersion = c.flatState.WriteSnapshotBlock(batch, snapshotBlock)
+	}
+
 	// Add snapshot block event
 	c.chainDb.Be.AddSnapshotBlocks(batch, []types.Hash{snapshotBlock.Hash})
 
@@ -164,7 +170,7 @@ func (c *chain) InsertSnapshotBlock(snapshotBlock *ledger.SnapshotBlock) error {
 	}
 
 	// Write db
-	if err := c.chainDb.Commit(batch); err != nil {
+	if err := c.commit(batch, flatStateVersion); err != nil {
 		c.log.Crit("c.chainDb.Commit(batch) failed, error is "+err.Error(), "method", "InsertSnapshotBlock")
 		return err
 	}
@@ -263,6 +269,30 @@ func (c *chain) GetSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock,
 	return block, nil
 }
 
+// GetArchivedSnapshotBlockByHeight reads the block from the db, then from the compressed ledger files if
+// ArchiveFallback is set, it's for the historical queries of rpc only. The blocks above the latest snapshot block
+// are rolled back, the files may still have them, so nil is returned.
+func (c *chain) GetArchivedSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error) {
+	if height > c.GetLatestSnapshotBlock().Height {
+		return nil, nil
+	}
+
+	block, err := c.GetSnapshotBlockByHeight(height)
+	if err != nil || block != nil || !c.cfg.ArchiveFallback {
+		return block, err
+	}
+
+	blocks, err := c.compressor.GetSnapshotBlocks(height, 1)
+	if err != nil {
+		c.log.Error("GetSnapshotBlocks failed, error is "+err.Error(), "method", "GetArchivedSnapshotBlockByHeight")
+		return nil, err
+	}
+	if len(blocks) > 0 {
+		return blocks[0], nil
+	}
+	return nil, nil
+}
+
 func (c *chain) GetSnapshotBlockByHash(hash *types.Hash) (*ledger.SnapshotBlock, error) {
 	monitorTags := []string{"chain", "GetSnapshotBlockByHash"}
 	defer monitor.LogTimerConsuming(monitorTags, time.Now())
@@ -526,7 +556,7 @@ func (c *chain) DeleteSnapshotBlocksToHeight(toHeight uint64) ([]*ledger.Snapsho
 		return nil, nil, nil
 	}
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 	snapshotBlocks, accountBlocksMap, err := c.deleteSnapshotBlocksByHeight(batch, toHeight)
 	if err != nil {
 		c.log.Error("deleteSnapshotBlocksByHeight failed, error is "+err.Error(), "method", "DeleteSnapshotBlocksToHeight")
@@ -611,6 +641,12 @@ func (c *chain) DeleteSnapshotBlocksToHeight(toHeight uint64) ([]*ledger.Snapsho
 	c.chainDb.Be.DeleteSnapshotBlocks(batch, deleteSbHashList)
 	c.chainDb.Be.DeleteAccountBlocks(batch, deleteAbHashList)
 
+	// Reset flat state
+	var flatStateVersion *types.Hash
+	if c.flatState != nil {
+		flatStateVersion = c.flatState.WriteDeleteSnapshotBlocks(batch, snapshotBlocks, prevSnapshotBlock)
+	}
+
 	// Set needSnapshotCache, first remove
 	c.needSnapshotCache.NotSnapshot(needNotSnapshot)
 
@@ -626,12 +662,15 @@ func (c *chain) DeleteSnapshotBlocksToHeight(toHeight uint64) ([]*ledger.Snapsho
 	}
 
 	// write db
-	writeErr := c.chainDb.Commit(batch)
+	writeErr := c.commit(batch, flatStateVersion)
 
 	if writeErr != nil {
 		c.log.Crit("Write db failed, error is "+writeErr.Error(), "method", "DeleteSnapshotBlocksByHeight")
 	}
 
+	// the files of the deleted blocks are deleted with them, so the archive never serves a rolled back block
+	c.compressor.DeleteFrom(toHeight)
+
 	// Delete cache
 	c.stateTriePool.Delete(needRemoveAddrList)
 
@@ -672,7 +711,7 @@ func (c *chain) CheckNeedSnapshotCache(content ledger.SnapshotContent) bool {
 	return true
 }
 
-func (c *chain) deleteSnapshotBlocksByHeight(batch *leveldb.Batch, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error) {
+func (c *chain) deleteSnapshotBlocksByHeight(batch *database.Batch, toHeight uint64) ([]*ledger.SnapshotBlock, map[types.Address][]*ledger.AccountBlock, error) {
 	maxAccountId, err := c.chainDb.Account.GetLastAccountId()
 	if err != nil {
 		c.log.Error("GetLastAccountId failed, error is "+err.Error(), "method", "DeleteSnapshotBlocksByHeight")
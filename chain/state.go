@@ -2,6 +2,7 @@ package chain
 
 import (
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/trie"
 )
 
@@ -39,6 +40,20 @@ func (c *chain) ShallowCheckStateTrie(stateHash *types.Hash) (bool, error) {
 	return trie.ShallowCheck(c.TrieDb(), stateHash)
 }
 
+// IsStatePruned reports whether the state of snapshotBlock has been removed by the trie gc, only the states of the
+// latest LedgerGcRetain snapshot blocks are kept when LedgerGc is open.
+func (c *chain) IsStatePruned(snapshotBlock *ledger.SnapshotBlock) (bool, error) {
+	if c.cfg.LedgerGc && snapshotBlock.Height < c.TrieGc().RetainMinHeight() {
+		return true, nil
+	}
+
+	ok, err := c.ShallowCheckStateTrie(&snapshotBlock.StateHash)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
+
 func (c *chain) GetStateTrie(stateHash *types.Hash) *trie.Trie {
 	return trie.NewTrie(c.TrieDb(), stateHash, c.trieNodePool)
 }
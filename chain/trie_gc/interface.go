@@ -1,8 +1,8 @@
 package trie_gc
 
 import (
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/trie"
@@ -35,7 +35,7 @@ type Chain interface {
 	GetEvent(eventId uint64) (byte, []types.Hash, error)
 	ChainDb() *chain_db.ChainDb
 
-	TrieDb() *leveldb.DB
+	TrieDb() database.Store
 	CleanTrieNodePool()
 	GenStateTrieFromDb(prevStateHash types.Hash, snapshotContent ledger.SnapshotContent) (*trie.Trie, error)
 	ShallowCheckStateTrie(stateHash *types.Hash) (bool, error)
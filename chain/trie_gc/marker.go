@@ -3,8 +3,6 @@ package trie_gc
 import (
 	"errors"
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain_db/access"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
@@ -165,11 +163,11 @@ func (m *Marker) clean(hashSet map[types.Hash]struct{}, refHashSet map[types.Has
 	m.chain.StopSaveTrie()
 	defer m.chain.StartSaveTrie()
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 
 	// clear trie node
 	dbkey, _ := database.EncodeKey(database.DBKP_TRIE_NODE)
-	iter := m.chain.TrieDb().NewIterator(util.BytesPrefix(dbkey), nil)
+	iter := m.chain.TrieDb().NewIterator(database.BytesPrefix(dbkey))
 	defer iter.Release()
 
 	for iter.Next() {
@@ -183,7 +181,7 @@ func (m *Marker) clean(hashSet map[types.Hash]struct{}, refHashSet map[types.Has
 
 	// clear ref value
 	refDbKey, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE)
-	refIter := m.chain.TrieDb().NewIterator(util.BytesPrefix(refDbKey), nil)
+	refIter := m.chain.TrieDb().NewIterator(database.BytesPrefix(refDbKey))
 	defer refIter.Release()
 	for refIter.Next() {
 		key := refIter.Key()
@@ -201,7 +199,7 @@ func (m *Marker) clean(hashSet map[types.Hash]struct{}, refHashSet map[types.Has
 	// clear cache
 	m.chain.CleanTrieNodePool()
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return err
 	}
 
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/generator"
@@ -14,7 +13,7 @@ import (
 func (gc *collector) recoverGenesis() error {
 
 	// recover genesis trie
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 
 	genesisSnapshotBlock := gc.chain.NewGenesisSnapshotBlock()
 	trieSaveCallback1, err := genesisSnapshotBlock.StateTrie.Save(batch)
@@ -67,7 +66,7 @@ func (gc *collector) recoverGenesis() error {
 }
 
 func (gc *collector) saveTrie(t *trie.Trie) error {
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 
 	trieSaveCallback, saveTrieErr := t.Save(batch)
 	if saveTrieErr != nil {
@@ -90,14 +89,14 @@ func isNodeExist(chainInstance Chain, node *trie.TrieNode) (bool, error) {
 
 	nodeHash := node.Hash()
 	dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, nodeHash.Bytes())
-	ok, err := db.Has(dbKey, nil)
+	ok, err := db.Has(dbKey)
 	if !ok || err != nil {
 		return ok, err
 	}
 
 	if node.NodeType() == trie.TRIE_HASH_NODE {
 		dbKey2, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE, node.Value())
-		ok2, err := db.Has(dbKey2, nil)
+		ok2, err := db.Has(dbKey2)
 		if !ok || err != nil {
 			return ok2, err
 		}
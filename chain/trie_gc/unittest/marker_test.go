@@ -2,7 +2,6 @@ package trie_gc_unittest
 
 import (
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/chain_db/access"
 	"github.com/vitelabs/go-vite/chain_db/database"
@@ -149,7 +148,7 @@ LOOP:
 func loadAllHashSet(chainInstance chain.Chain) map[types.Hash]struct{} {
 	allHashSet := make(map[types.Hash]struct{})
 	key, _ := database.EncodeKey(database.DBKP_TRIE_NODE)
-	iter := chainInstance.ChainDb().Db().NewIterator(util.BytesPrefix(key), nil)
+	iter := chainInstance.ChainDb().Db().NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 	for iter.Next() {
 		key, _ := types.BytesToHash(iter.Key()[1:])
@@ -161,7 +160,7 @@ func loadAllHashSet(chainInstance chain.Chain) map[types.Hash]struct{} {
 func loadAllRefHashSet(chainInstance chain.Chain) map[types.Hash]struct{} {
 	allHashSet := make(map[types.Hash]struct{})
 	key, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE)
-	iter := chainInstance.ChainDb().Db().NewIterator(util.BytesPrefix(key), nil)
+	iter := chainInstance.ChainDb().Db().NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 	for iter.Next() {
 		key, _ := types.BytesToHash(iter.Key()[1:])
@@ -8,8 +8,6 @@ import (
 	_ "net/http/pprof"
 	"testing"
 
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/chain_db/database"
@@ -19,30 +17,30 @@ import (
 )
 
 func deleteAllTrie(chainInstance chain.Chain) error {
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 
 	// clear trie node
 	dbkey, _ := database.EncodeKey(database.DBKP_TRIE_NODE)
-	iter := chainInstance.TrieDb().NewIterator(util.BytesPrefix(dbkey), nil)
+	iter := chainInstance.TrieDb().NewIterator(database.BytesPrefix(dbkey))
 	defer iter.Release()
 
 	for iter.Next() {
 		batch.Delete(iter.Key())
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return err
 	}
 
 	// clear ref value
 	refDbKey, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE)
-	refIter := chainInstance.TrieDb().NewIterator(util.BytesPrefix(refDbKey), nil)
+	refIter := chainInstance.TrieDb().NewIterator(database.BytesPrefix(refDbKey))
 	defer refIter.Release()
 	for refIter.Next() {
 		batch.Delete(refIter.Key())
 
 	}
-	if err := refIter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := refIter.Error(); err != nil && err != database.ErrNotFound {
 		return err
 	}
 
@@ -62,14 +60,14 @@ func isNodeExist(chainInstance chain.Chain, node *trie.TrieNode) (bool, error) {
 
 	nodeHash := node.Hash()
 	dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, nodeHash.Bytes())
-	ok, err := db.Has(dbKey, nil)
+	ok, err := db.Has(dbKey)
 	if !ok || err != nil {
 		return ok, err
 	}
 
 	if node.NodeType() == trie.TRIE_HASH_NODE {
 		dbKey2, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE, node.Value())
-		ok2, err := db.Has(dbKey2, nil)
+		ok2, err := db.Has(dbKey2)
 		if !ok || err != nil {
 			return ok2, err
 		}
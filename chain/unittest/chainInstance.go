@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/fork"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/config"
@@ -157,3 +158,17 @@ func NewChainInstanceFromAbsPath(dataDir string, clearDataDir bool) chain.Chain
 
 	return chainInstance
 }
+
+// NewMemChainInstance creates a chain with the ledger in memory, the ledger files and the indexes are still written
+// into dataDir.
+func NewMemChainInstance(dataDir string) chain.Chain {
+	chainInstance := chain.NewChainWithStore(&config.Config{
+		DataDir: dataDir,
+
+		Genesis: MakeChainConfig(""),
+	}, database.NewMemStore())
+
+	chainInstance.Init()
+
+	return chainInstance
+}
@@ -2,29 +2,27 @@ package access
 
 import (
 	"encoding/binary"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 )
 
 type Account struct {
-	db *leveldb.DB
+	db database.Store
 }
 
-func NewAccount(db *leveldb.DB) *Account {
+func NewAccount(db database.Store) *Account {
 	return &Account{
 		db: db,
 	}
 }
 
-func (accountAccess *Account) WriteAccountIndex(batch *leveldb.Batch, accountId uint64, accountAddress *types.Address) {
+func (accountAccess *Account) WriteAccountIndex(batch *database.Batch, accountId uint64, accountAddress *types.Address) {
 	accountIndexKey, _ := database.EncodeKey(database.DBKP_ACCOUNTID_INDEX, accountId)
 	batch.Put(accountIndexKey, accountAddress.Bytes())
 }
 
-func (accountAccess *Account) WriteAccount(batch *leveldb.Batch, account *ledger.Account) error {
+func (accountAccess *Account) WriteAccount(batch *database.Batch, account *ledger.Account) error {
 	accountKey, _ := database.EncodeKey(database.DBKP_ACCOUNT, account.AccountAddress.Bytes())
 	data, err := account.Serialize()
 	if err != nil {
@@ -37,10 +35,10 @@ func (accountAccess *Account) WriteAccount(batch *leveldb.Batch, account *ledger
 
 func (accountAccess *Account) GetLastAccountId() (uint64, error) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTID_INDEX)
-	iter := accountAccess.db.NewIterator(util.BytesPrefix(key), nil)
+	iter := accountAccess.db.NewIterator(database.BytesPrefix(key))
 
 	if !iter.Last() {
-		if err := iter.Error(); err != leveldb.ErrNotFound {
+		if err := iter.Error(); err != database.ErrNotFound {
 			return 0, err
 		}
 		return 0, nil
@@ -51,7 +49,7 @@ func (accountAccess *Account) GetLastAccountId() (uint64, error) {
 
 func (accountAccess *Account) GetAddressById(accountId uint64) (*types.Address, error) {
 	keyAccountAddress, _ := database.EncodeKey(database.DBKP_ACCOUNTID_INDEX, accountId)
-	data, dgErr := accountAccess.db.Get(keyAccountAddress, nil)
+	data, dgErr := accountAccess.db.Get(keyAccountAddress)
 
 	if dgErr != nil {
 		return nil, dgErr
@@ -66,15 +64,15 @@ func (accountAccess *Account) GetAddressById(accountId uint64) (*types.Address,
 
 func (accountAccess *Account) IsAccountExisted(address *types.Address) (bool, error) {
 	keyAccountMeta, _ := database.EncodeKey(database.DBKP_ACCOUNT, address.Bytes())
-	return accountAccess.db.Has(keyAccountMeta, nil)
+	return accountAccess.db.Has(keyAccountMeta)
 }
 
 func (accountAccess *Account) GetAccountByAddress(address *types.Address) (*ledger.Account, error) {
 	keyAccountMeta, _ := database.EncodeKey(database.DBKP_ACCOUNT, address.Bytes())
 
-	data, dgErr := accountAccess.db.Get(keyAccountMeta, nil)
+	data, dgErr := accountAccess.db.Get(keyAccountMeta)
 	if dgErr != nil {
-		if dgErr != leveldb.ErrNotFound {
+		if dgErr != database.ErrNotFound {
 			return nil, dgErr
 		}
 
@@ -4,9 +4,6 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/iterator"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/helper"
 	"github.com/vitelabs/go-vite/common/types"
@@ -26,28 +23,28 @@ func getAccountBlockHeight(dbKey []byte) uint64 {
 }
 
 type AccountChain struct {
-	db *leveldb.DB
+	db database.Store
 }
 
-func NewAccountChain(db *leveldb.DB) *AccountChain {
+func NewAccountChain(db database.Store) *AccountChain {
 	return &AccountChain{
 		db: db,
 	}
 }
 
-func (ac *AccountChain) DeleteBlock(batch *leveldb.Batch, accountId uint64, height uint64, hash *types.Hash) {
+func (ac *AccountChain) DeleteBlock(batch *database.Batch, accountId uint64, height uint64, hash *types.Hash) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, height, hash.Bytes())
 	batch.Delete(key)
 }
 
-func (ac *AccountChain) DeleteBlockMeta(batch *leveldb.Batch, hash *types.Hash) {
+func (ac *AccountChain) DeleteBlockMeta(batch *database.Batch, hash *types.Hash) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCKMETA, hash.Bytes())
 	batch.Delete(key)
 	// Delete be snapshot
 	ac.DeleteBeSnapshot(batch, hash)
 }
 
-func (ac *AccountChain) WriteBlock(batch *leveldb.Batch, accountId uint64, block *ledger.AccountBlock) error {
+func (ac *AccountChain) WriteBlock(batch *database.Batch, accountId uint64, block *ledger.AccountBlock) error {
 	buf, err := block.DbSerialize()
 	if err != nil {
 		return err
@@ -59,7 +56,7 @@ func (ac *AccountChain) WriteBlock(batch *leveldb.Batch, accountId uint64, block
 	return nil
 }
 
-func (ac *AccountChain) WriteBlockMeta(batch *leveldb.Batch, blockHash *types.Hash, blockMeta *ledger.AccountBlockMeta) error {
+func (ac *AccountChain) WriteBlockMeta(batch *database.Batch, blockHash *types.Hash, blockMeta *ledger.AccountBlockMeta) error {
 	buf, err := blockMeta.Serialize()
 	if err != nil {
 		return err
@@ -71,7 +68,7 @@ func (ac *AccountChain) WriteBlockMeta(batch *leveldb.Batch, blockHash *types.Ha
 	return nil
 }
 
-func (ac *AccountChain) WriteBeSnapshot(batch *leveldb.Batch, blockHash *types.Hash, snapshotBlockHeight uint64) error {
+func (ac *AccountChain) WriteBeSnapshot(batch *database.Batch, blockHash *types.Hash, snapshotBlockHeight uint64) error {
 	key, _ := database.EncodeKey(database.DBKP_BE_SNAPSHOT, blockHash.Bytes())
 
 	heightBytes := make([]byte, 8)
@@ -81,7 +78,7 @@ func (ac *AccountChain) WriteBeSnapshot(batch *leveldb.Batch, blockHash *types.H
 	return nil
 }
 
-func (ac *AccountChain) DeleteBeSnapshot(batch *leveldb.Batch, blockHash *types.Hash) {
+func (ac *AccountChain) DeleteBeSnapshot(batch *database.Batch, blockHash *types.Hash) {
 	key, _ := database.EncodeKey(database.DBKP_BE_SNAPSHOT, blockHash.Bytes())
 	batch.Delete(key)
 }
@@ -89,9 +86,9 @@ func (ac *AccountChain) DeleteBeSnapshot(batch *leveldb.Batch, blockHash *types.
 func (ac *AccountChain) GetBeSnapshot(blockHash *types.Hash) (uint64, error) {
 	key, _ := database.EncodeKey(database.DBKP_BE_SNAPSHOT, blockHash.Bytes())
 
-	value, err := ac.db.Get(key, nil)
+	value, err := ac.db.Get(key)
 	if err != nil {
-		if err == leveldb.ErrNotFound {
+		if err == database.ErrNotFound {
 			return 0, nil
 		}
 		return 0, err
@@ -104,11 +101,11 @@ func (ac *AccountChain) GetBeSnapshot(blockHash *types.Hash) (uint64, error) {
 
 func (ac *AccountChain) GetHashByHeight(accountId uint64, height uint64) (*types.Hash, error) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, height)
-	iter := ac.db.NewIterator(util.BytesPrefix(key), nil)
+	iter := ac.db.NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 
 	if !iter.Last() {
-		if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		if err := iter.Error(); err != nil && err != database.ErrNotFound {
 			return nil, err
 		}
 
@@ -121,7 +118,7 @@ func (ac *AccountChain) GetHashByHeight(accountId uint64, height uint64) (*types
 
 func (ac *AccountChain) IsBlockExisted(hash types.Hash) (bool, error) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCKMETA, hash.Bytes())
-	return ac.db.Has(key, nil)
+	return ac.db.Has(key)
 }
 
 func (ac *AccountChain) GetLatestBlock(accountId uint64) (*ledger.AccountBlock, error) {
@@ -130,11 +127,11 @@ func (ac *AccountChain) GetLatestBlock(accountId uint64) (*ledger.AccountBlock,
 		return nil, err
 	}
 
-	iter := ac.db.NewIterator(util.BytesPrefix(key), nil)
+	iter := ac.db.NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 
 	if !iter.Last() {
-		if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		if err := iter.Error(); err != nil && err != database.ErrNotFound {
 			return nil, err
 		}
 		return nil, nil
@@ -152,7 +149,7 @@ func (ac *AccountChain) GetBlockListByAccountId(accountId, startHeight, endHeigh
 	startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, startHeight)
 	limitKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, endHeight+1)
 
-	iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: limitKey}, nil)
+	iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: limitKey})
 	defer iter.Release()
 
 	// cap
@@ -182,7 +179,7 @@ func (ac *AccountChain) GetBlockListByAccountId(accountId, startHeight, endHeigh
 		}
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 
@@ -208,10 +205,10 @@ func (ac *AccountChain) GetBlock(blockHash *types.Hash) (*ledger.AccountBlock, e
 
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, blockMeta.AccountId, blockMeta.Height, blockHash.Bytes())
 
-	data, err := ac.db.Get(key, nil)
+	data, err := ac.db.Get(key)
 
 	if err != nil {
-		if err != leveldb.ErrNotFound {
+		if err != database.ErrNotFound {
 			return nil, err
 		}
 		return nil, nil
@@ -232,9 +229,9 @@ func (ac *AccountChain) GetBlockMeta(blockHash *types.Hash) (*ledger.AccountBloc
 	if err != nil {
 		return nil, err
 	}
-	blockMetaBytes, err := ac.db.Get(key, nil)
+	blockMetaBytes, err := ac.db.Get(key)
 	if err != nil {
-		if err == leveldb.ErrNotFound {
+		if err == database.ErrNotFound {
 			return nil, nil
 		}
 		return nil, err
@@ -257,9 +254,9 @@ func (ac *AccountChain) GetBlockMeta(blockHash *types.Hash) (*ledger.AccountBloc
 
 func (ac *AccountChain) GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error) {
 	key, _ := database.EncodeKey(database.DBKP_LOG_LIST, logListHash.Bytes())
-	data, err := ac.db.Get(key, nil)
+	data, err := ac.db.Get(key)
 	if err != nil {
-		if err != leveldb.ErrNotFound {
+		if err != database.ErrNotFound {
 			return nil, err
 		}
 		return nil, nil
@@ -302,7 +299,7 @@ func (ac *AccountChain) GetConfirmHeight(accountBlockHash *types.Hash) (uint64,
 	startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountBlockMeta.AccountId, accountBlockMeta.Height+1)
 	endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountBlockMeta.AccountId, helper.MaxUint64)
 
-	iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+	iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 	defer iter.Release()
 
 	for iter.Next() {
@@ -317,14 +314,14 @@ func (ac *AccountChain) GetConfirmHeight(accountBlockHash *types.Hash) (uint64,
 		}
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return 0, err
 	}
 
 	return 0, nil
 }
 
-func (ac *AccountChain) WriteVmLogList(batch *leveldb.Batch, logList ledger.VmLogList) error {
+func (ac *AccountChain) WriteVmLogList(batch *database.Batch, logList ledger.VmLogList) error {
 	key, _ := database.EncodeKey(database.DBKP_LOG_LIST, logList.Hash().Bytes())
 
 	buf, err := logList.Serialize()
@@ -337,7 +334,7 @@ func (ac *AccountChain) WriteVmLogList(batch *leveldb.Batch, logList ledger.VmLo
 	return nil
 }
 
-func (ac *AccountChain) DeleteVmLogList(batch *leveldb.Batch, logListHash *types.Hash) {
+func (ac *AccountChain) DeleteVmLogList(batch *database.Batch, logListHash *types.Hash) {
 	key, _ := database.EncodeKey(database.DBKP_LOG_LIST, logListHash.Bytes())
 	batch.Delete(key)
 }
@@ -345,7 +342,7 @@ func (ac *AccountChain) DeleteVmLogList(batch *leveldb.Batch, logListHash *types
 func (ac *AccountChain) GetBlockByHeight(accountId uint64, height uint64) (*ledger.AccountBlock, error) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, height)
 
-	iter := ac.db.NewIterator(util.BytesPrefix(key), nil)
+	iter := ac.db.NewIterator(database.BytesPrefix(key))
 	if !iter.Last() {
 		if err := iter.Error(); err != nil {
 			return nil, err
@@ -396,7 +393,7 @@ func (ac *AccountChain) GetContractGidFromSendCreateBlock(fromBlock *ledger.Acco
 	return &gid, nil
 }
 
-func (ac *AccountChain) ReopenSendBlocks(batch *leveldb.Batch, reopenList []*ledger.HashHeight, deletedMap map[uint64]uint64) error {
+func (ac *AccountChain) ReopenSendBlocks(batch *database.Batch, reopenList []*ledger.HashHeight, deletedMap map[uint64]uint64) error {
 	for _, reopenItem := range reopenList {
 		blockMeta, err := ac.GetBlockMeta(&reopenItem.Hash)
 		if err != nil {
@@ -428,13 +425,13 @@ func (ac *AccountChain) ReopenSendBlocks(batch *leveldb.Batch, reopenList []*led
 	return nil
 }
 
-func (ac *AccountChain) deleteChain(batch *leveldb.Batch, accountId uint64, toHeight uint64) ([]*ledger.AccountBlock, error) {
+func (ac *AccountChain) deleteChain(batch *database.Batch, accountId uint64, toHeight uint64) ([]*ledger.AccountBlock, error) {
 	deletedChain := make([]*ledger.AccountBlock, 0)
 
 	startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, toHeight)
 	endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, helper.MaxUint64)
 
-	iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+	iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 	defer iter.Release()
 
 	for iter.Next() {
@@ -460,14 +457,14 @@ func (ac *AccountChain) deleteChain(batch *leveldb.Batch, accountId uint64, toHe
 		deletedChain = append(deletedChain, deleteBlock)
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 
 	return deletedChain, nil
 }
 
-func (ac *AccountChain) Delete(batch *leveldb.Batch, deleteMap map[uint64]uint64) (map[uint64][]*ledger.AccountBlock, error) {
+func (ac *AccountChain) Delete(batch *database.Batch, deleteMap map[uint64]uint64) (map[uint64][]*ledger.AccountBlock, error) {
 	deleted := make(map[uint64][]*ledger.AccountBlock)
 	for accountId, deleteHeight := range deleteMap {
 		deletedChain, err := ac.deleteChain(batch, accountId, deleteHeight)
@@ -509,7 +506,7 @@ func (ac *AccountChain) GetDeleteMapAndReopenList(planToDelete map[uint64]uint64
 			startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, needDeleteHeight)
 			endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, endHeight)
 
-			iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+			iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 
 			for iter.Next() {
 				accountBlock := &ledger.AccountBlock{}
@@ -559,7 +556,7 @@ func (ac *AccountChain) GetDeleteMapAndReopenList(planToDelete map[uint64]uint64
 				}
 			}
 
-			if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+			if err := iter.Error(); err != nil && err != database.ErrNotFound {
 				iter.Release()
 				return nil, nil, err
 			}
@@ -579,7 +576,7 @@ func (ac *AccountChain) GetPlanToDelete(maxAccountId uint64, snapshotBlockHeight
 	for i := uint64(1); i <= maxAccountId; i++ {
 		blockKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, i)
 
-		iter := ac.db.NewIterator(util.BytesPrefix(blockKey), nil)
+		iter := ac.db.NewIterator(database.BytesPrefix(blockKey))
 		iterOk := iter.Last()
 
 		for iterOk {
@@ -602,7 +599,7 @@ func (ac *AccountChain) GetPlanToDelete(maxAccountId uint64, snapshotBlockHeight
 			iterOk = iter.Prev()
 		}
 
-		if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+		if err := iter.Error(); err != nil && err != database.ErrNotFound {
 			iter.Release()
 			return nil, err
 		}
@@ -693,7 +690,7 @@ func (ac *AccountChain) getUnconfirmedBlocks(accountId uint64) ([]*ledger.Accoun
 func (ac *AccountChain) GetConfirmAccountBlock(snapshotHeight uint64, accountId uint64) (*ledger.AccountBlock, error) {
 	key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId)
 
-	iter := ac.db.NewIterator(util.BytesPrefix(key), nil)
+	iter := ac.db.NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 
 	iterOk := iter.Last()
@@ -719,7 +716,7 @@ func (ac *AccountChain) GetConfirmAccountBlock(snapshotHeight uint64, accountId
 		}
 		iterOk = iter.Prev()
 	}
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 
@@ -730,7 +727,7 @@ func (ac *AccountChain) GetFirstConfirmedBlockBeforeOrAtAbHeight(accountId, acco
 	startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, 1)
 	endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, accountBlockHeight+1)
 
-	iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+	iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 	defer iter.Release()
 
 	iterOk := iter.Last()
@@ -758,7 +755,7 @@ func (ac *AccountChain) GetFirstConfirmedBlockBeforeOrAtAbHeight(accountId, acco
 		accountBlock = tmpAccountBlock
 		iterOk = iter.Prev()
 	}
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 
@@ -768,14 +765,14 @@ func (ac *AccountChain) GetFirstConfirmedBlockBeforeOrAtAbHeight(accountId, acco
 func (ac *AccountChain) GetUnConfirmAccountBlocks(accountId uint64, beforeHeight uint64) ([]*ledger.AccountBlock, error) {
 	accountBlocks := make([]*ledger.AccountBlock, 0)
 
-	var iter iterator.Iterator
+	var iter database.Iterator
 	if beforeHeight > 0 {
 		startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, uint64(1))
 		endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, beforeHeight)
-		iter = ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+		iter = ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 	} else {
 		key, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId)
-		iter = ac.db.NewIterator(util.BytesPrefix(key), nil)
+		iter = ac.db.NewIterator(database.BytesPrefix(key))
 	}
 
 	defer iter.Release()
@@ -807,7 +804,7 @@ func (ac *AccountChain) GetUnConfirmAccountBlocks(accountId uint64, beforeHeight
 		iterOk = iter.Prev()
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 
@@ -820,7 +817,7 @@ func (ac *AccountChain) GetSendAndReceiveBlocks(accountId uint64, snapshotBlockH
 
 	startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, uint64(1))
 	endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, helper.MaxUint64)
-	iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+	iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 	defer iter.Release()
 
 	lastSnapshotBlockHeight := uint64(0)
@@ -863,7 +860,7 @@ func (ac *AccountChain) GetSendAndReceiveBlocks(accountId uint64, snapshotBlockH
 		}
 	}
 
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, nil, err
 	}
 
@@ -874,7 +871,7 @@ func (ac *AccountChain) GetSendBlocks(accountId uint64, snapshotBlockHeight uint
 	blocks := make([]*ledger.AccountBlock, 0)
 	startKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, 1)
 	endKey, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK, accountId, helper.MaxUint64)
-	iter := ac.db.NewIterator(&util.Range{Start: startKey, Limit: endKey}, nil)
+	iter := ac.db.NewIterator(&database.Range{Start: startKey, Limit: endKey})
 	defer iter.Release()
 
 	lastSnapshotBlockHeight := uint64(0)
@@ -909,7 +906,7 @@ func (ac *AccountChain) GetSendBlocks(accountId uint64, snapshotBlockHeight uint
 			lastSnapshotBlockHeight = accountBlockMeta.SnapshotHeight
 		}
 	}
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 
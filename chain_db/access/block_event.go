@@ -2,8 +2,6 @@ package access
 
 import (
 	"encoding/binary"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/log15"
@@ -11,7 +9,7 @@ import (
 )
 
 type BlockEvent struct {
-	db *leveldb.DB
+	db database.Store
 
 	log         log15.Logger
 	eventIdLock sync.RWMutex
@@ -19,7 +17,7 @@ type BlockEvent struct {
 	latestEventId uint64
 }
 
-func NewBlockEvent(db *leveldb.DB) *BlockEvent {
+func NewBlockEvent(db database.Store) *BlockEvent {
 	blockEvent := &BlockEvent{
 		db:  db,
 		log: log15.New("module", "chain_db/block_event"),
@@ -48,7 +46,7 @@ func (be *BlockEvent) newEventId() uint64 {
 	return be.latestEventId
 }
 
-func (be *BlockEvent) writeEvent(batch *leveldb.Batch, eventPrefix byte, blockHashList []types.Hash) {
+func (be *BlockEvent) writeEvent(batch *database.Batch, eventPrefix byte, blockHashList []types.Hash) {
 	if len(blockHashList) <= 0 {
 		return
 	}
@@ -64,10 +62,10 @@ func (be *BlockEvent) writeEvent(batch *leveldb.Batch, eventPrefix byte, blockHa
 }
 
 func (be *BlockEvent) getLatestEventId() (uint64, error) {
-	iter := be.db.NewIterator(util.BytesPrefix([]byte{database.DBKP_BLOCK_EVENT}), nil)
+	iter := be.db.NewIterator(database.BytesPrefix([]byte{database.DBKP_BLOCK_EVENT}))
 	iterOk := iter.Last()
 	if !iterOk {
-		if iterErr := iter.Error(); iterErr != nil && iterErr != leveldb.ErrNotFound {
+		if iterErr := iter.Error(); iterErr != nil && iterErr != database.ErrNotFound {
 			return 0, iterErr
 		}
 		return 0, nil
@@ -82,9 +80,9 @@ func (be *BlockEvent) LatestEventId() (uint64, error) {
 
 func (be *BlockEvent) GetEvent(eventId uint64) (byte, []types.Hash, error) {
 	key, _ := database.EncodeKey(database.DBKP_BLOCK_EVENT, eventId)
-	value, err := be.db.Get(key, nil)
+	value, err := be.db.Get(key)
 	if err != nil {
-		if err != leveldb.ErrNotFound {
+		if err != database.ErrNotFound {
 			return byte(0), nil, err
 		}
 		return byte(0), nil, nil
@@ -103,18 +101,18 @@ func (be *BlockEvent) GetEvent(eventId uint64) (byte, []types.Hash, error) {
 	return eventType, blockHashList, nil
 }
 
-func (be *BlockEvent) AddAccountBlocks(batch *leveldb.Batch, blockHashList []types.Hash) {
+func (be *BlockEvent) AddAccountBlocks(batch *database.Batch, blockHashList []types.Hash) {
 	be.writeEvent(batch, AddAccountBlocksEvent, blockHashList)
 }
 
-func (be *BlockEvent) DeleteAccountBlocks(batch *leveldb.Batch, blockHashList []types.Hash) {
+func (be *BlockEvent) DeleteAccountBlocks(batch *database.Batch, blockHashList []types.Hash) {
 	be.writeEvent(batch, DeleteAccountBlocksEvent, blockHashList)
 }
 
-func (be *BlockEvent) AddSnapshotBlocks(batch *leveldb.Batch, blockHashList []types.Hash) {
+func (be *BlockEvent) AddSnapshotBlocks(batch *database.Batch, blockHashList []types.Hash) {
 	be.writeEvent(batch, AddSnapshotBlocksEvent, blockHashList)
 }
 
-func (be *BlockEvent) DeleteSnapshotBlocks(batch *leveldb.Batch, blockHashList []types.Hash) {
+func (be *BlockEvent) DeleteSnapshotBlocks(batch *database.Batch, blockHashList []types.Hash) {
 	be.writeEvent(batch, DeleteSnapshotBlocksEvent, blockHashList)
 }
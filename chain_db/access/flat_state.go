@@ -0,0 +1,157 @@
+package access
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+// FlatState stores the storage of every account as plain key/value pairs. The entries of an account reflect the
+// account state hash saved under DBKP_FLAT_STATE_HASH, the whole flat state reflects the snapshot block saved
+// under DBKP_FLAT_STATE_VERSION.
+type FlatState struct {
+	db database.Store
+}
+
+func NewFlatState(db database.Store) *FlatState {
+	return &FlatState{
+		db: db,
+	}
+}
+
+func (fs *FlatState) GetValue(addr *types.Address, key []byte) ([]byte, error) {
+	dbKey, _ := database.EncodeKey(database.DBKP_FLAT_STATE, addr.Bytes(), key)
+	value, err := fs.db.Get(dbKey)
+	if err != nil {
+		if err != database.ErrNotFound {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return value, nil
+}
+
+// Iterate calls f with every key/value of the account which has the prefix, it stops when f returns false.
+func (fs *FlatState) Iterate(addr *types.Address, prefix []byte, f func(key, value []byte) bool) error {
+	dbPrefix, _ := database.EncodeKey(database.DBKP_FLAT_STATE, addr.Bytes(), prefix)
+	iter := fs.db.NewIterator(database.BytesPrefix(dbPrefix))
+	defer iter.Release()
+
+	keyOffset := 1 + types.AddressSize
+	for iter.Next() {
+		if !f(iter.Key()[keyOffset:], iter.Value()) {
+			break
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (fs *FlatState) GetStateHash(addr *types.Address) (*types.Hash, error) {
+	key, _ := database.EncodeKey(database.DBKP_FLAT_STATE_HASH, addr.Bytes())
+	value, err := fs.db.Get(key)
+	if err != nil {
+		if err != database.ErrNotFound {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	stateHash, err := types.BytesToHash(value)
+	if err != nil {
+		return nil, err
+	}
+	return &stateHash, nil
+}
+
+// WriteAccountState replaces the entries of the account with storage, only the changed keys are written into
+// the batch. The account is removed from the flat state when stateHash is nil.
+func (fs *FlatState) WriteAccountState(batch *database.Batch, addr *types.Address, stateHash *types.Hash, storage map[string][]byte) error {
+	unchanged := make(map[string]struct{})
+	err := fs.Iterate(addr, nil, func(key, value []byte) bool {
+		newValue, ok := storage[string(key)]
+		if !ok {
+			dbKey, _ := database.EncodeKey(database.DBKP_FLAT_STATE, addr.Bytes(), key)
+			batch.Delete(dbKey)
+			return true
+		}
+
+		if bytes.Equal(newValue, value) {
+			unchanged[string(key)] = struct{}{}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for key, value := range storage {
+		if _, ok := unchanged[key]; ok {
+			continue
+		}
+		dbKey, _ := database.EncodeKey(database.DBKP_FLAT_STATE, addr.Bytes(), []byte(key))
+		batch.Put(dbKey, value)
+	}
+
+	stateHashKey, _ := database.EncodeKey(database.DBKP_FLAT_STATE_HASH, addr.Bytes())
+	if stateHash == nil {
+		batch.Delete(stateHashKey)
+	} else {
+		batch.Put(stateHashKey, stateHash.Bytes())
+	}
+	return nil
+}
+
+// WriteValue writes the value of the key of the account into the batch, nil value deletes the key.
+func (fs *FlatState) WriteValue(batch *database.Batch, addr *types.Address, key []byte, value []byte) {
+	dbKey, _ := database.EncodeKey(database.DBKP_FLAT_STATE, addr.Bytes(), key)
+	if value == nil {
+		batch.Delete(dbKey)
+	} else {
+		batch.Put(dbKey, value)
+	}
+}
+
+// WriteStateHash writes the account state hash which the entries of the account reflect into the batch.
+func (fs *FlatState) WriteStateHash(batch *database.Batch, addr *types.Address, stateHash *types.Hash) {
+	stateHashKey, _ := database.EncodeKey(database.DBKP_FLAT_STATE_HASH, addr.Bytes())
+	batch.Put(stateHashKey, stateHash.Bytes())
+}
+
+// GetVersion returns the snapshot block which the flat state reflects, nil hash means the flat state is empty
+// or out of sync.
+func (fs *FlatState) GetVersion() (uint64, *types.Hash, error) {
+	value, err := fs.db.Get([]byte{database.DBKP_FLAT_STATE_VERSION})
+	if err != nil {
+		if err != database.ErrNotFound {
+			return 0, nil, err
+		}
+		return 0, nil, nil
+	}
+
+	if len(value) != 8+types.HashSize {
+		return 0, nil, errors.New("flat state version is malformed")
+	}
+	hash, err := types.BytesToHash(value[8:])
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.BigEndian.Uint64(value[:8]), &hash, nil
+}
+
+func (fs *FlatState) WriteVersion(batch *database.Batch, height uint64, hash *types.Hash) {
+	value := make([]byte, 8+types.HashSize)
+	binary.BigEndian.PutUint64(value[:8], height)
+	copy(value[8:], hash.Bytes())
+
+	batch.Put([]byte{database.DBKP_FLAT_STATE_VERSION}, value)
+}
+
+func (fs *FlatState) DeleteVersion(batch *database.Batch) {
+	batch.Delete([]byte{database.DBKP_FLAT_STATE_VERSION})
+}
@@ -0,0 +1,73 @@
+package access
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+func TestFlatState(t *testing.T) {
+	db := database.NewMemStore()
+	fs := NewFlatState(db)
+	addr, _ := types.BytesToAddress(bytes.Repeat([]byte{1}, types.AddressSize))
+
+	write := func(stateHash *types.Hash, storage map[string][]byte) {
+		batch := new(database.Batch)
+		if err := fs.WriteAccountState(batch, &addr, stateHash, storage); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Write(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect := func(storage map[string][]byte) {
+		count := 0
+		if err := fs.Iterate(&addr, nil, func(key, value []byte) bool {
+			count++
+			if !bytes.Equal(storage[string(key)], value) {
+				t.Fatalf("key %s, expect %x, got %x", key, storage[string(key)], value)
+			}
+			return true
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if count != len(storage) {
+			t.Fatalf("expect %d keys, got %d", len(storage), count)
+		}
+	}
+
+	hash1 := types.Hash{1}
+	storage1 := map[string][]byte{"a": {1}, "b": {2}, "c": {3}}
+	write(&hash1, storage1)
+	expect(storage1)
+
+	hash2 := types.Hash{2}
+	storage2 := map[string][]byte{"a": {1}, "b": {4}, "d": {5}}
+	write(&hash2, storage2)
+	expect(storage2)
+
+	if value, err := fs.GetValue(&addr, []byte("b")); err != nil || !bytes.Equal(value, []byte{4}) {
+		t.Fatalf("GetValue returns %x, %v", value, err)
+	}
+	if value, err := fs.GetValue(&addr, []byte("c")); err != nil || value != nil {
+		t.Fatalf("the deleted key returns %x, %v", value, err)
+	}
+	if stateHash, err := fs.GetStateHash(&addr); err != nil || *stateHash != hash2 {
+		t.Fatalf("GetStateHash returns %v, %v", stateHash, err)
+	}
+
+	write(nil, nil)
+	expect(nil)
+	if stateHash, err := fs.GetStateHash(&addr); err != nil || stateHash != nil {
+		t.Fatalf("GetStateHash of the removed account returns %v, %v", stateHash, err)
+	}
+
+	batch := new(database.Batch)
+	fs.WriteVersion(batch, 10, &hash1)
+	db.Write(batch)
+	if height, hash, err := fs.GetVersion(); err != nil || height != 10 || *hash != hash1 {
+		t.Fatalf("GetVersion returns %d, %v, %v", height, hash, err)
+	}
+}
@@ -2,8 +2,6 @@ package chain_db
 
 import (
 	"errors"
-	"github.com/syndtr/goleveldb/leveldb"
-	errors2 "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/vitelabs/go-vite/chain_db/access"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/log15"
@@ -12,7 +10,7 @@ import (
 
 type ChainDb struct {
 	dbDir string
-	db    *leveldb.DB
+	db    database.Store
 
 	Ac      *access.AccountChain
 	Sc      *access.SnapshotChain
@@ -20,6 +18,8 @@ type ChainDb struct {
 	Be      *access.BlockEvent
 	OnRoad  *access.OnRoad
 
+	FlatState *access.FlatState
+
 	log log15.Logger
 }
 
@@ -39,16 +39,24 @@ func NewChainDb(dbDir string) *ChainDb {
 	return cDb
 }
 
+// NewChainDbWithStore creates a ChainDb on store, the directory isn't used. Unit tests use it with database.NewMemStore.
+func NewChainDbWithStore(store database.Store) *ChainDb {
+	cDb := &ChainDb{
+		log: log15.New("module", "chainDb"),
+	}
+	cDb.setStore(store)
+
+	return cDb
+}
+
 func (chainDb *ChainDb) initDb() error {
 	db, err := database.NewLevelDb(chainDb.dbDir)
 	if err != nil {
-		switch err.(type) {
-		case *errors2.ErrCorrupted:
+		if database.IsCorrupted(err) {
 			return chainDb.ClearData()
-		default:
-			chainDb.log.Error("NewLevelDb failed, error is "+err.Error(), "method", "initDb")
-			return err
 		}
+		chainDb.log.Error("NewLevelDb failed, error is "+err.Error(), "method", "initDb")
+		return err
 	}
 
 	if db == nil {
@@ -56,17 +64,27 @@ func (chainDb *ChainDb) initDb() error {
 		chainDb.log.Error(err.Error(), "method", "initDb")
 		return err
 	}
+	chainDb.setStore(db)
+
+	return nil
+}
+
+func (chainDb *ChainDb) setStore(db database.Store) {
 	chainDb.db = db
 	chainDb.Ac = access.NewAccountChain(db)
 	chainDb.Sc = access.NewSnapshotChain(db)
 	chainDb.Account = access.NewAccount(db)
 	chainDb.Be = access.NewBlockEvent(db)
 	chainDb.OnRoad = access.NewOnRoad(db)
-
-	return nil
+	chainDb.FlatState = access.NewFlatState(db)
 }
 
 func (chainDb *ChainDb) ClearData() error {
+	if len(chainDb.dbDir) <= 0 {
+		// the store is not opened by ChainDb, delete all keys instead of removing the directory
+		return chainDb.clearStore()
+	}
+
 	if chainDb.db != nil {
 		if closeErr := chainDb.db.Close(); closeErr != nil {
 			return errors.New("Close db failed, error is " + closeErr.Error())
@@ -81,10 +99,25 @@ func (chainDb *ChainDb) ClearData() error {
 	return chainDb.initDb()
 }
 
-func (chainDb *ChainDb) Db() *leveldb.DB {
+func (chainDb *ChainDb) clearStore() error {
+	batch := new(database.Batch)
+
+	iter := chainDb.db.NewIterator(nil)
+	for iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	iter.Release()
+
+	if err := iter.Error(); err != nil {
+		return errors.New("Iterate store failed, error is " + err.Error())
+	}
+	return chainDb.db.Write(batch)
+}
+
+func (chainDb *ChainDb) Db() database.Store {
 	return chainDb.db
 }
 
-func (chainDb *ChainDb) Commit(batch *leveldb.Batch) error {
-	return chainDb.db.Write(batch, nil)
+func (chainDb *ChainDb) Commit(batch *database.Batch) error {
+	return chainDb.db.Write(batch)
 }
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// BatchReplay receives the operations of a Batch in order.
+type BatchReplay interface {
+	Put(key, value []byte)
+	Delete(key []byte)
+}
+
+// Batch collects write operations which are applied atomically by Store.Write. The zero value is an empty batch,
+// key and value are copied into the records so the caller may reuse them, the leveldb store writes the records
+// as they are.
+type Batch struct {
+	batch leveldb.Batch
+}
+
+func (b *Batch) Put(key, value []byte) {
+	b.batch.Put(key, value)
+}
+
+func (b *Batch) Delete(key []byte) {
+	b.batch.Delete(key)
+}
+
+// Len returns the count of operations in the batch.
+func (b *Batch) Len() int {
+	return b.batch.Len()
+}
+
+func (b *Batch) Reset() {
+	b.batch.Reset()
+}
+
+// Replay passes the operations to r, key and value refer to the records of the batch and are valid until it's reset.
+func (b *Batch) Replay(r BatchReplay) {
+	b.batch.Replay(r)
+}
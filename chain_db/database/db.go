@@ -2,10 +2,17 @@ package database
 
 import (
 	"github.com/syndtr/goleveldb/leveldb"
+	lerrors "github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
-func NewLevelDb(dbDir string) (*leveldb.DB, error) {
+type levelDbStore struct {
+	db *leveldb.DB
+}
+
+func NewLevelDb(dbDir string) (Store, error) {
 	db, err := leveldb.OpenFile(dbDir, &opt.Options{
 		BlockCacheCapacity: 128 * opt.MiB,
 	})
@@ -13,5 +20,100 @@ func NewLevelDb(dbDir string) (*leveldb.DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	return db, nil
+	return &levelDbStore{db: db}, nil
+}
+
+// IsCorrupted reports whether err is returned because the files of a LevelDB store are corrupted.
+func IsCorrupted(err error) bool {
+	return lerrors.IsCorrupted(err)
+}
+
+func toLevelDbRange(slice *Range) *util.Range {
+	if slice == nil {
+		return nil
+	}
+	return &util.Range{
+		Start: slice.Start,
+		Limit: slice.Limit,
+	}
+}
+
+func convertLevelDbErr(err error) error {
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (store *levelDbStore) Get(key []byte) ([]byte, error) {
+	value, err := store.db.Get(key, nil)
+	return value, convertLevelDbErr(err)
+}
+
+func (store *levelDbStore) Has(key []byte) (bool, error) {
+	return store.db.Has(key, nil)
+}
+
+func (store *levelDbStore) NewIterator(slice *Range) Iterator {
+	return &levelDbIterator{store.db.NewIterator(toLevelDbRange(slice), nil)}
+}
+
+func (store *levelDbStore) Put(key []byte, value []byte) error {
+	return store.db.Put(key, value, nil)
+}
+
+func (store *levelDbStore) Delete(key []byte) error {
+	return store.db.Delete(key, nil)
+}
+
+func (store *levelDbStore) Write(batch *Batch) error {
+	return store.db.Write(&batch.batch, nil)
+}
+
+func (store *levelDbStore) GetSnapshot() (Snapshot, error) {
+	snapshot, err := store.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelDbSnapshot{snapshot}, nil
+}
+
+func (store *levelDbStore) CompactRange(r Range) error {
+	return store.db.CompactRange(util.Range{
+		Start: r.Start,
+		Limit: r.Limit,
+	})
+}
+
+func (store *levelDbStore) Close() error {
+	return store.db.Close()
+}
+
+type levelDbSnapshot struct {
+	snapshot *leveldb.Snapshot
+}
+
+func (s *levelDbSnapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snapshot.Get(key, nil)
+	return value, convertLevelDbErr(err)
+}
+
+func (s *levelDbSnapshot) Has(key []byte) (bool, error) {
+	return s.snapshot.Has(key, nil)
+}
+
+func (s *levelDbSnapshot) NewIterator(slice *Range) Iterator {
+	return &levelDbIterator{s.snapshot.NewIterator(toLevelDbRange(slice), nil)}
+}
+
+func (s *levelDbSnapshot) Release() {
+	s.snapshot.Release()
+}
+
+type levelDbIterator struct {
+	iterator.Iterator
+}
+
+func (iter *levelDbIterator) Error() error {
+	return convertLevelDbErr(iter.Iterator.Error())
 }
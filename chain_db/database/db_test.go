@@ -4,24 +4,15 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/vitelabs/go-vite/common"
-	"os"
-	"path/filepath"
 	"sync"
 	"testing"
 )
 
 func TestSameBatch(t *testing.T) {
-	dbDir := filepath.Join(common.GoViteTestDataDir(), "db_test")
-	os.RemoveAll(dbDir)
-	db, err := NewLevelDb(dbDir)
-	if err != nil {
-		t.Fatal(err)
-	}
+	db := NewMemStore()
 
 	for i := 0; i < 100000; i++ {
-		batch := new(leveldb.Batch)
+		batch := new(Batch)
 
 		key := []byte("hahaKey")
 		value := []byte("hahaValue")
@@ -29,16 +20,15 @@ func TestSameBatch(t *testing.T) {
 
 		value2 := []byte("hahaValueValue")
 		batch.Put(key, value2)
-		db.Write(batch, nil)
+		db.Write(batch)
 
-		v, _ := db.Get(key, nil)
+		v, _ := db.Get(key)
 		if !bytes.Equal(v, value2) {
 			t.Error(fmt.Sprintf("%s", v))
 		}
 
 	}
 
-	os.RemoveAll(dbDir)
 }
 
 type testAbc struct {
@@ -46,12 +36,7 @@ type testAbc struct {
 }
 
 func TestWriteMeta(t *testing.T) {
-	dbDir := filepath.Join(common.GoViteTestDataDir(), "db_test")
-	os.RemoveAll(dbDir)
-	db, err := NewLevelDb(dbDir)
-	if err != nil {
-		t.Fatal(err)
-	}
+	db := NewMemStore()
 
 	wg := sync.WaitGroup{}
 
@@ -64,7 +49,7 @@ func TestWriteMeta(t *testing.T) {
 		defer wg.Done()
 		for count < 1000000 {
 			lock.Lock()
-			valueByte, _ := db.Get(key1, nil)
+			valueByte, _ := db.Get(key1)
 
 			// read tmpCount
 			var tmpCount uint64
@@ -81,11 +66,11 @@ func TestWriteMeta(t *testing.T) {
 			writeByte := make([]byte, 8)
 			binary.BigEndian.PutUint64(writeByte, tmpCount+1)
 
-			batch := new(leveldb.Batch)
+			batch := new(Batch)
 			batch.Put(key1, writeByte)
 			batch.Put(key1, writeByte)
 			batch.Put(key1, writeByte)
-			db.Write(batch, nil)
+			db.Write(batch)
 			count++
 			lock.Unlock()
 		}
@@ -101,7 +86,7 @@ func TestWriteMeta(t *testing.T) {
 		for count < 1000000 {
 			lock.Lock()
 
-			valueByte, _ := db.Get(key1, nil)
+			valueByte, _ := db.Get(key1)
 			var tmpCount uint64
 			if len(valueByte) > 0 {
 				tmpCount = binary.BigEndian.Uint64(valueByte)
@@ -114,12 +99,12 @@ func TestWriteMeta(t *testing.T) {
 			writeByte := make([]byte, 8)
 			binary.BigEndian.PutUint64(writeByte, tmpCount+1)
 
-			batch := new(leveldb.Batch)
+			batch := new(Batch)
 			batch.Put(key1, writeByte)
 			batch.Put(key1, writeByte)
 			batch.Put(key1, writeByte)
 			batch.Put(key1, writeByte)
-			db.Write(batch, nil)
+			db.Write(batch)
 			count++
 			lock.Unlock()
 		}
@@ -131,9 +116,8 @@ func TestWriteMeta(t *testing.T) {
 	//	key2 := []byte("k" + strconv.FormatUint(2, 10))
 	//	value2 := []byte("v" + strconv.FormatUint(2, 10))
 	//
-	//	db.Put(key2, value2, nil)
+	//	db.Put(key2, value2)
 	//}()
 
 	wg.Wait()
-	os.RemoveAll(dbDir)
 }
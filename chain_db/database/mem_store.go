@@ -0,0 +1,139 @@
+package database
+
+import (
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb/comparer"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// memStore keeps all key/value pairs in a sorted skip list, nothing is written to disk. It is used by unit tests.
+type memStore struct {
+	// guards the atomicity of Write and GetSnapshot, memdb guards itself for single operations
+	lock sync.RWMutex
+	db   *memdb.DB
+}
+
+func NewMemStore() Store {
+	return &memStore{
+		db: memdb.New(comparer.DefaultComparer, 0),
+	}
+}
+
+func memGet(db *memdb.DB, key []byte) ([]byte, error) {
+	value, err := db.Get(key)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	// the value refers to the buffer of memdb
+	return append([]byte(nil), value...), nil
+}
+
+func memNewIterator(db *memdb.DB, slice *Range) Iterator {
+	var r *util.Range
+	if slice != nil {
+		r = &util.Range{
+			Start: slice.Start,
+			Limit: slice.Limit,
+		}
+	}
+	return db.NewIterator(r)
+}
+
+func (store *memStore) Get(key []byte) ([]byte, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return memGet(store.db, key)
+}
+
+func (store *memStore) Has(key []byte) (bool, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return store.db.Contains(key), nil
+}
+
+func (store *memStore) NewIterator(slice *Range) Iterator {
+	return memNewIterator(store.db, slice)
+}
+
+func (store *memStore) Put(key []byte, value []byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.db.Put(key, value)
+}
+
+func (store *memStore) Delete(key []byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.db.Delete(key)
+	return nil
+}
+
+func (store *memStore) Write(batch *Batch) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	batch.Replay(memBatchReplay{store.db})
+	return nil
+}
+
+// GetSnapshot copies all key/value pairs, the cost is acceptable for the small stores of unit tests.
+func (store *memStore) GetSnapshot() (Snapshot, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	snapshotDb := memdb.New(comparer.DefaultComparer, store.db.Size())
+
+	iter := store.db.NewIterator(nil)
+	defer iter.Release()
+	for iter.Next() {
+		snapshotDb.Put(iter.Key(), iter.Value())
+	}
+
+	return &memSnapshot{db: snapshotDb}, nil
+}
+
+func (store *memStore) CompactRange(r Range) error {
+	return nil
+}
+
+func (store *memStore) Close() error {
+	return nil
+}
+
+type memBatchReplay struct {
+	db *memdb.DB
+}
+
+func (r memBatchReplay) Put(key, value []byte) {
+	r.db.Put(key, value)
+}
+
+func (r memBatchReplay) Delete(key []byte) {
+	r.db.Delete(key)
+}
+
+type memSnapshot struct {
+	db *memdb.DB
+}
+
+func (s *memSnapshot) Get(key []byte) ([]byte, error) {
+	return memGet(s.db, key)
+}
+
+func (s *memSnapshot) Has(key []byte) (bool, error) {
+	return s.db.Contains(key), nil
+}
+
+func (s *memSnapshot) NewIterator(slice *Range) Iterator {
+	return memNewIterator(s.db, slice)
+}
+
+func (s *memSnapshot) Release() {
+	s.db.Reset()
+}
@@ -36,4 +36,10 @@ const (
 	DBKP_BE_SNAPSHOT = byte(17)
 
 	DBKP_ADDITIONAL_LIST = byte(18)
+
+	DBKP_FLAT_STATE = byte(19)
+
+	DBKP_FLAT_STATE_HASH = byte(20)
+
+	DBKP_FLAT_STATE_VERSION = byte(21)
 )
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"errors"
+)
+
+// ErrReadOnly is returned by the write operations of a read-only store.
+var ErrReadOnly = errors.New("database: read only")
+
+// snapshotStore serves reads from a Snapshot and rejects writes, so a read-only view can be passed to code that
+// expects a Store.
+type snapshotStore struct {
+	Snapshot
+}
+
+// NewSnapshotStore returns a read-only Store backed by snapshot, Close releases the snapshot.
+func NewSnapshotStore(snapshot Snapshot) Store {
+	return &snapshotStore{snapshot}
+}
+
+func (store *snapshotStore) Put(key []byte, value []byte) error {
+	return ErrReadOnly
+}
+
+func (store *snapshotStore) Delete(key []byte) error {
+	return ErrReadOnly
+}
+
+func (store *snapshotStore) Write(batch *Batch) error {
+	return ErrReadOnly
+}
+
+// GetSnapshot returns a snapshot which shares the underlying snapshot, releasing it has no effect.
+func (store *snapshotStore) GetSnapshot() (Snapshot, error) {
+	return nopReleaseSnapshot{store.Snapshot}, nil
+}
+
+func (store *snapshotStore) CompactRange(r Range) error {
+	return nil
+}
+
+func (store *snapshotStore) Close() error {
+	store.Snapshot.Release()
+	return nil
+}
+
+type nopReleaseSnapshot struct {
+	Snapshot
+}
+
+func (nopReleaseSnapshot) Release() {}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"errors"
+)
+
+// ErrNotFound is returned by Get when the key doesn't exist, every Store implementation returns this error.
+var ErrNotFound = errors.New("database: not found")
+
+// Range is a key range [Start, Limit), nil Start means the first key and nil Limit means after the last key.
+type Range struct {
+	Start []byte
+	Limit []byte
+}
+
+// BytesPrefix returns the key range which contains all keys with the prefix.
+func BytesPrefix(prefix []byte) *Range {
+	var limit []byte
+	for i := len(prefix) - 1; i >= 0; i-- {
+		c := prefix[i]
+		if c < 0xff {
+			limit = make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			break
+		}
+	}
+	return &Range{
+		Start: prefix,
+		Limit: limit,
+	}
+}
+
+// Iterator iterates over the key/value pairs of a Range in key order, it must be released after use.
+type Iterator interface {
+	First() bool
+	Last() bool
+	Seek(key []byte) bool
+	Next() bool
+	Prev() bool
+
+	Key() []byte
+	Value() []byte
+
+	Error() error
+	Release()
+}
+
+// Reader is the read operations shared by Store and Snapshot.
+type Reader interface {
+	Get(key []byte) ([]byte, error)
+	Has(key []byte) (bool, error)
+
+	// NewIterator returns an iterator over slice, nil slice means the whole store.
+	NewIterator(slice *Range) Iterator
+}
+
+// Snapshot is a frozen view of a Store, it must be released after use.
+type Snapshot interface {
+	Reader
+
+	Release()
+}
+
+// Store is a sorted key/value store, the chain, the trie and the secondary indexes are persisted into it.
+type Store interface {
+	Reader
+
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+
+	// Write applies all operations of batch atomically.
+	Write(batch *Batch) error
+
+	GetSnapshot() (Snapshot, error)
+
+	// CompactRange compacts the underlying storage of the key range, nil Start and Limit mean the whole store.
+	CompactRange(r Range) error
+
+	Close() error
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func testStore(t *testing.T, store Store) {
+	batch := new(Batch)
+	batch.Put([]byte("a1"), []byte("v1"))
+	batch.Put([]byte("a2"), []byte("v2"))
+	batch.Put([]byte("a3"), []byte("v3"))
+	batch.Put([]byte("b1"), []byte("v4"))
+	batch.Delete([]byte("a3"))
+	if err := store.Write(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := store.Get([]byte("a2")); err != nil || !bytes.Equal(value, []byte("v2")) {
+		t.Fatalf("get a2 failed, value is %s, error is %v", value, err)
+	}
+	if _, err := store.Get([]byte("a3")); err != ErrNotFound {
+		t.Fatalf("a3 should be deleted, error is %v", err)
+	}
+
+	snapshot, err := store.GetSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snapshot.Release()
+
+	if err := store.Put([]byte("a0"), []byte("v0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete([]byte("a1")); err != nil {
+		t.Fatal(err)
+	}
+
+	checkKeys := func(reader Reader, expected []string) {
+		iter := reader.NewIterator(BytesPrefix([]byte("a")))
+		defer iter.Release()
+
+		var keys []string
+		for iter.Next() {
+			keys = append(keys, string(iter.Key()))
+		}
+		if err := iter.Error(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(keys) != len(expected) {
+			t.Fatalf("keys are %v, expected %v", keys, expected)
+		}
+		for i := range keys {
+			if keys[i] != expected[i] {
+				t.Fatalf("keys are %v, expected %v", keys, expected)
+			}
+		}
+	}
+
+	checkKeys(store, []string{"a0", "a2"})
+	checkKeys(snapshot, []string{"a1", "a2"})
+
+	iter := store.NewIterator(nil)
+	if !iter.Last() || string(iter.Key()) != "b1" {
+		t.Fatalf("the last key should be b1")
+	}
+	if !iter.Prev() || string(iter.Key()) != "a2" {
+		t.Fatalf("the previous key of b1 should be a2")
+	}
+	iter.Release()
+
+	if err := store.CompactRange(Range{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	testStore(t, NewMemStore())
+}
+
+func TestLevelDbStore(t *testing.T) {
+	dbDir, err := ioutil.TempDir("", "leveldb_store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dbDir)
+
+	store, err := NewLevelDb(dbDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	testStore(t, store)
+}
+
+func TestBytesPrefix(t *testing.T) {
+	r := BytesPrefix([]byte{1, 0xff})
+	if !bytes.Equal(r.Limit, []byte{2}) {
+		t.Fatalf("limit is %v", r.Limit)
+	}
+
+	r = BytesPrefix([]byte{0xff})
+	if r.Limit != nil {
+		t.Fatalf("limit is %v", r.Limit)
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	store := NewMemStore()
+	store.Put([]byte("k1"), []byte("v1"))
+
+	snapshot, err := store.GetSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	readOnlyStore := NewSnapshotStore(snapshot)
+	defer readOnlyStore.Close()
+
+	store.Put([]byte("k1"), []byte("v2"))
+
+	if value, err := readOnlyStore.Get([]byte("k1")); err != nil || !bytes.Equal(value, []byte("v1")) {
+		t.Fatalf("value is %s, error is %v", value, err)
+	}
+	if err := readOnlyStore.Put([]byte("k2"), []byte("v2")); err != ErrReadOnly {
+		t.Fatalf("error is %v", err)
+	}
+	if err := readOnlyStore.Write(new(Batch)); err != ErrReadOnly {
+		t.Fatalf("error is %v", err)
+	}
+}
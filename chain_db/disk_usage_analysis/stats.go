@@ -0,0 +1,430 @@
+package disk_usage_analysis
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
+)
+
+var prefixNames = map[byte]string{
+	database.DBKP_ACCOUNTID_INDEX:      "DBKP_ACCOUNTID_INDEX",
+	database.DBKP_ACCOUNT:              "DBKP_ACCOUNT",
+	database.DBKP_ACCOUNTBLOCKMETA:     "DBKP_ACCOUNTBLOCKMETA",
+	database.DBKP_ACCOUNTBLOCK:         "DBKP_ACCOUNTBLOCK",
+	database.DBKP_SNAPSHOTBLOCKHASH:    "DBKP_SNAPSHOTBLOCKHASH",
+	database.DBKP_SNAPSHOTBLOCK:        "DBKP_SNAPSHOTBLOCK",
+	database.DBKP_SNAPSHOTCONTENT:      "DBKP_SNAPSHOTCONTENT",
+	database.DBKP_ONROADMETA:           "DBKP_ONROADMETA",
+	database.DBKP_ONROADRECEIVEERR:     "DBKP_ONROADRECEIVEERR",
+	database.DBKP_ACCOUNTBLOCK_COUNTER: "DBKP_ACCOUNTBLOCK_COUNTER",
+	database.DBKP_TRIE_NODE:            "DBKP_TRIE_NODE",
+	database.DBKP_TRIE_REF_VALUE:       "DBKP_TRIE_REF_VALUE",
+	database.DBKP_LOG_LIST:             "DBKP_LOG_LIST",
+	database.DBKP_ADDR_GID:             "DBKP_ADDR_GID",
+	database.DBKP_GID_ADDR:             "DBKP_GID_ADDR",
+	database.DBKP_BLOCK_EVENT:          "DBKP_BLOCK_EVENT",
+	database.DBKP_BE_SNAPSHOT:          "DBKP_BE_SNAPSHOT",
+	database.DBKP_ADDITIONAL_LIST:      "DBKP_ADDITIONAL_LIST",
+	database.DBKP_FLAT_STATE:           "DBKP_FLAT_STATE",
+	database.DBKP_FLAT_STATE_HASH:      "DBKP_FLAT_STATE_HASH",
+	database.DBKP_FLAT_STATE_VERSION:   "DBKP_FLAT_STATE_VERSION",
+}
+
+func prefixName(prefix byte) string {
+	if name, ok := prefixNames[prefix]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN_%d", prefix)
+}
+
+type PrefixStats struct {
+	Prefix    byte   `json:"prefix"`
+	Name      string `json:"name"`
+	Count     uint64 `json:"count"`
+	KeySize   uint64 `json:"keySize"`
+	ValueSize uint64 `json:"valueSize"`
+}
+
+// TrieStats counts the trie nodes reachable from the state tries of the snapshot blocks in
+// [RootFromHeight, RootToHeight] and of the latest account blocks, the other nodes are unreachable.
+type TrieStats struct {
+	RootFromHeight uint64 `json:"rootFromHeight"`
+	RootToHeight   uint64 `json:"rootToHeight"`
+
+	ReachableCount   uint64 `json:"reachableCount"`
+	ReachableSize    uint64 `json:"reachableSize"`
+	UnreachableCount uint64 `json:"unreachableCount"`
+	UnreachableSize  uint64 `json:"unreachableSize"`
+}
+
+// AccountStats is the storage consumed by an account, StorageSize is the size of the latest state trie of the account.
+type AccountStats struct {
+	Address          types.Address `json:"address"`
+	BlockCount       uint64        `json:"blockCount"`
+	BlockSize        uint64        `json:"blockSize"`
+	StorageNodeCount uint64        `json:"storageNodeCount"`
+	StorageSize      uint64        `json:"storageSize"`
+}
+
+func (stats *AccountStats) TotalSize() uint64 {
+	return stats.BlockSize + stats.StorageSize
+}
+
+type Stats struct {
+	TotalCount  uint64          `json:"totalCount"`
+	TotalSize   uint64          `json:"totalSize"`
+	Prefixes    []*PrefixStats  `json:"prefixes"`
+	Trie        *TrieStats      `json:"trie"`
+	TopAccounts []*AccountStats `json:"topAccounts"`
+}
+
+type analyzer struct {
+	chainDb  *chain_db.ChainDb
+	triePool *trie.TrieNodePool
+
+	// the hashes of the reachable trie nodes, about 100 bytes each in memory
+	reachableSet map[types.Hash]struct{}
+
+	// the accounts of the most block bytes, bounded by topN
+	topN int
+	top  accountHeap
+}
+
+// accountHeap is a min heap of the accounts by the block size, the smallest is replaced when it's full.
+type accountHeap []*accountItem
+
+type accountItem struct {
+	accountId uint64
+	stats     *AccountStats
+}
+
+func (h accountHeap) Len() int            { return len(h) }
+func (h accountHeap) Less(i, j int) bool  { return h[i].stats.BlockSize < h[j].stats.BlockSize }
+func (h accountHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *accountHeap) Push(x interface{}) { *h = append(*h, x.(*accountItem)) }
+func (h *accountHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// Analyze walks the whole chain db and reports the usage of every prefix, the trie nodes and the top n accounts by
+// the block size. The state tries of the snapshot blocks from trieRootFromHeight to the latest are the roots of the
+// reachable trie nodes, the latest snapshot block is the only root if trieRootFromHeight is 0. It reads from a snapshot
+// of the db. The keys are streamed and the top n accounts are bounded, but the hashes of all the reachable trie nodes
+// are kept in memory, about 100 bytes per node, which grows with the state and with the range of trieRootFromHeight.
+func Analyze(chainDb *chain_db.ChainDb, trieRootFromHeight uint64, topN int) (*Stats, error) {
+	snapshot, err := chainDb.Db().GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	viewChainDb := chain_db.NewChainDbWithStore(database.NewSnapshotStore(snapshot))
+	defer viewChainDb.Db().Close()
+
+	a := &analyzer{
+		chainDb:      viewChainDb,
+		triePool:     trie.NewTrieNodePool(),
+		reachableSet: make(map[types.Hash]struct{}),
+		topN:         topN,
+	}
+
+	stats := &Stats{}
+	if stats.Prefixes, err = a.countPrefixes(); err != nil {
+		return nil, err
+	}
+	for _, prefixStats := range stats.Prefixes {
+		stats.TotalCount += prefixStats.Count
+		stats.TotalSize += prefixStats.KeySize + prefixStats.ValueSize
+	}
+
+	if stats.Trie, err = a.countTrieNodes(trieRootFromHeight); err != nil {
+		return nil, err
+	}
+
+	if stats.TopAccounts, err = a.topAccounts(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// pushAccount keeps the account if it's among the top n accounts by the block size.
+func (a *analyzer) pushAccount(accountId uint64, accountStats *AccountStats) {
+	if a.topN <= 0 {
+		return
+	}
+	if len(a.top) < a.topN {
+		heap.Push(&a.top, &accountItem{accountId: accountId, stats: accountStats})
+	} else if a.top[0].stats.BlockSize < accountStats.BlockSize {
+		a.top[0] = &accountItem{accountId: accountId, stats: accountStats}
+		heap.Fix(&a.top, 0)
+	}
+}
+
+func (a *analyzer) countPrefixes() ([]*PrefixStats, error) {
+	iter := a.chainDb.Db().NewIterator(nil)
+	defer iter.Release()
+
+	var prefixes []*PrefixStats
+	var current *PrefixStats
+
+	// the account blocks are keyed by the account id first, so the blocks of an account are adjacent
+	var currentAccountId uint64
+	var currentAccount *AccountStats
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) <= 0 {
+			continue
+		}
+		keySize := uint64(len(key))
+		valueSize := uint64(len(iter.Value()))
+
+		if current == nil || current.Prefix != key[0] {
+			current = &PrefixStats{
+				Prefix: key[0],
+				Name:   prefixName(key[0]),
+			}
+			prefixes = append(prefixes, current)
+		}
+		current.Count++
+		current.KeySize += keySize
+		current.ValueSize += valueSize
+
+		if key[0] == database.DBKP_ACCOUNTBLOCK && len(key) >= 9 {
+			accountId := binary.BigEndian.Uint64(key[1:9])
+			if currentAccount == nil || accountId != currentAccountId {
+				if currentAccount != nil {
+					a.pushAccount(currentAccountId, currentAccount)
+				}
+				currentAccountId = accountId
+				currentAccount = &AccountStats{}
+			}
+			currentAccount.BlockCount++
+			currentAccount.BlockSize += keySize + valueSize
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	if currentAccount != nil {
+		a.pushAccount(currentAccountId, currentAccount)
+	}
+	return prefixes, nil
+}
+
+// markTrie adds the nodes of the trie to set and returns the number and the size of the new nodes, the size is only
+// measured if measure is set, which reads every node again. The values of the snapshot state trie are the state
+// hashes of accounts, set followAccounts to mark the account tries too.
+func (a *analyzer) markTrie(stateHash types.Hash, set map[types.Hash]struct{}, followAccounts bool, measure bool) (uint64, uint64, error) {
+	stateTrie := trie.NewTrie(a.chainDb.Db(), &stateHash, a.triePool)
+	if stateTrie.Root == nil {
+		return 0, 0, nil
+	}
+
+	var count, size uint64
+	var accountStateHashes []types.Hash
+	ni := stateTrie.NewNodeIterator()
+	for ni.Next(func(node *trie.TrieNode) bool {
+		if node == nil {
+			return false
+		}
+		_, ok := set[*node.Hash()]
+		return !ok
+	}) {
+		// the child is nil if it's missing in the db
+		node := ni.Node()
+		if node == nil {
+			continue
+		}
+		nodeHash := *node.Hash()
+		if _, ok := set[nodeHash]; ok {
+			continue
+		}
+		set[nodeHash] = struct{}{}
+		count++
+
+		if measure {
+			key, _ := database.EncodeKey(database.DBKP_TRIE_NODE, nodeHash.Bytes())
+			value, err := a.chainDb.Db().Get(key)
+			if err != nil && err != database.ErrNotFound {
+				return 0, 0, err
+			}
+			if err == nil {
+				size += uint64(len(key) + len(value))
+			}
+		}
+
+		if followAccounts && node.NodeType() == trie.TRIE_VALUE_NODE && len(node.Value()) == types.HashSize {
+			accountStateHash, _ := types.BytesToHash(node.Value())
+			accountStateHashes = append(accountStateHashes, accountStateHash)
+		}
+	}
+
+	for _, accountStateHash := range accountStateHashes {
+		if _, _, err := a.markTrie(accountStateHash, set, false, false); err != nil {
+			return 0, 0, err
+		}
+	}
+	return count, size, nil
+}
+
+// markLatestAccountTries marks the state tries of the latest account blocks, the unconfirmed account blocks refer to
+// state tries which aren't in the snapshot state tries. The latest block of an account is the last of its keys.
+func (a *analyzer) markLatestAccountTries() error {
+	prefix, _ := database.EncodeKey(database.DBKP_ACCOUNTBLOCK)
+	iter := a.chainDb.Db().NewIterator(database.BytesPrefix(prefix))
+	defer iter.Release()
+
+	markLatest := func(value []byte) error {
+		block := &ledger.AccountBlock{}
+		if err := block.DbDeserialize(value); err != nil {
+			return err
+		}
+		_, _, err := a.markTrie(block.StateHash, a.reachableSet, false, false)
+		return err
+	}
+
+	var latestKey, latestValue []byte
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) < 9 {
+			continue
+		}
+		if latestKey != nil && !bytes.Equal(key[1:9], latestKey[1:9]) {
+			if err := markLatest(latestValue); err != nil {
+				return err
+			}
+		}
+		latestKey = append(latestKey[:0], key...)
+		latestValue = append(latestValue[:0], iter.Value()...)
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return err
+	}
+	if latestKey != nil {
+		return markLatest(latestValue)
+	}
+	return nil
+}
+
+func (a *analyzer) countTrieNodes(rootFromHeight uint64) (*TrieStats, error) {
+	latestBlock, err := a.chainDb.Sc.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	trieStats := &TrieStats{}
+	if latestBlock != nil {
+		trieStats.RootToHeight = latestBlock.Height
+		trieStats.RootFromHeight = latestBlock.Height
+		if rootFromHeight > 0 && rootFromHeight < latestBlock.Height {
+			trieStats.RootFromHeight = rootFromHeight
+		}
+
+		for height := trieStats.RootFromHeight; height <= trieStats.RootToHeight; height++ {
+			block, err := a.chainDb.Sc.GetSnapshotBlock(height, false)
+			if err != nil {
+				return nil, err
+			}
+			if block != nil {
+				if _, _, err := a.markTrie(block.StateHash, a.reachableSet, true, false); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := a.markLatestAccountTries(); err != nil {
+		return nil, err
+	}
+
+	prefix, _ := database.EncodeKey(database.DBKP_TRIE_NODE)
+	iter := a.chainDb.Db().NewIterator(database.BytesPrefix(prefix))
+	defer iter.Release()
+	for iter.Next() {
+		key := iter.Key()
+		size := uint64(len(key) + len(iter.Value()))
+		hash, err := types.BytesToHash(key[1:])
+		if err != nil {
+			continue
+		}
+		if _, ok := a.reachableSet[hash]; ok {
+			trieStats.ReachableCount++
+			trieStats.ReachableSize += size
+		} else {
+			trieStats.UnreachableCount++
+			trieStats.UnreachableSize += size
+		}
+	}
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
+		return nil, err
+	}
+	return trieStats, nil
+}
+
+// topAccounts measures the storage of the top n accounts by the block size, only their tries are walked.
+func (a *analyzer) topAccounts() ([]*AccountStats, error) {
+	accountList := make([]*AccountStats, 0, len(a.top))
+	for _, item := range a.top {
+		accountStats := item.stats
+		addr, err := a.chainDb.Account.GetAddressById(item.accountId)
+		if err != nil {
+			if err != database.ErrNotFound {
+				return nil, err
+			}
+		} else {
+			accountStats.Address = *addr
+		}
+
+		block, err := a.chainDb.Ac.GetLatestBlock(item.accountId)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			if accountStats.StorageNodeCount, accountStats.StorageSize, err = a.markTrie(block.StateHash, make(map[types.Hash]struct{}), false, true); err != nil {
+				return nil, err
+			}
+		}
+		accountList = append(accountList, accountStats)
+	}
+
+	sort.Slice(accountList, func(i, j int) bool {
+		return accountList[i].TotalSize() > accountList[j].TotalSize()
+	})
+	return accountList, nil
+}
+
+// WriteTable writes the stats as aligned tables, the sizes are in bytes.
+func (stats *Stats) WriteTable(writer io.Writer) error {
+	w := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "PREFIX\tNAME\tCOUNT\tKEY SIZE\tVALUE SIZE\tTOTAL SIZE")
+	for _, prefixStats := range stats.Prefixes {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\n", prefixStats.Prefix, prefixStats.Name, prefixStats.Count,
+			prefixStats.KeySize, prefixStats.ValueSize, prefixStats.KeySize+prefixStats.ValueSize)
+	}
+	fmt.Fprintf(w, "\tTOTAL\t%d\t\t\t%d\n\n", stats.TotalCount, stats.TotalSize)
+
+	if stats.Trie != nil {
+		fmt.Fprintf(w, "TRIE NODES (roots from snapshot height %d to %d)\tCOUNT\tSIZE\n", stats.Trie.RootFromHeight, stats.Trie.RootToHeight)
+		fmt.Fprintf(w, "reachable\t%d\t%d\n", stats.Trie.ReachableCount, stats.Trie.ReachableSize)
+		fmt.Fprintf(w, "unreachable\t%d\t%d\n\n", stats.Trie.UnreachableCount, stats.Trie.UnreachableSize)
+	}
+
+	fmt.Fprintln(w, "ADDRESS\tBLOCK COUNT\tBLOCK SIZE\tSTORAGE NODE COUNT\tSTORAGE SIZE\tTOTAL SIZE")
+	for _, accountStats := range stats.TopAccounts {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", accountStats.Address, accountStats.BlockCount, accountStats.BlockSize,
+			accountStats.StorageNodeCount, accountStats.StorageSize, accountStats.TotalSize())
+	}
+	return w.Flush()
+}
@@ -0,0 +1,91 @@
+package disk_usage_analysis
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain_db"
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/trie"
+)
+
+func TestAnalyze(t *testing.T) {
+	chainDb := chain_db.NewChainDbWithStore(database.NewMemStore())
+	batch := new(database.Batch)
+
+	// the orphan trie isn't referred by any block
+	orphanTrie := trie.NewTrie(chainDb.Db(), nil, nil)
+	orphanTrie.SetValue([]byte("orphan"), []byte("value"))
+	if _, err := orphanTrie.Save(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	stateTrie := trie.NewTrie(chainDb.Db(), nil, nil)
+	stateTrie.SetValue([]byte("key1"), []byte("value1"))
+	stateTrie.SetValue([]byte("key2"), []byte("value2"))
+	if _, err := stateTrie.Save(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	snapshotBlock := &ledger.SnapshotBlock{
+		Height:    1,
+		Timestamp: &now,
+		StateHash: *stateTrie.Hash(),
+	}
+	if err := chainDb.Sc.WriteSnapshotBlock(batch, snapshotBlock); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := types.Address{1}
+	chainDb.Account.WriteAccountIndex(batch, 1, &addr)
+	for height := uint64(1); height <= 3; height++ {
+		block := &ledger.AccountBlock{
+			BlockType:      ledger.BlockTypeReceive,
+			Hash:           types.Hash{byte(height)},
+			Height:         height,
+			AccountAddress: addr,
+			Timestamp:      &now,
+		}
+		if err := chainDb.Ac.WriteBlock(batch, 1, block); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := chainDb.Commit(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Analyze(chainDb, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefixCounts := make(map[byte]uint64)
+	for _, prefixStats := range stats.Prefixes {
+		prefixCounts[prefixStats.Prefix] = prefixStats.Count
+	}
+	if prefixCounts[database.DBKP_ACCOUNTBLOCK] != 3 || prefixCounts[database.DBKP_SNAPSHOTBLOCK] != 1 {
+		t.Fatalf("prefix counts are %v", prefixCounts)
+	}
+
+	if stats.Trie.ReachableCount <= 0 || stats.Trie.UnreachableCount <= 0 ||
+		stats.Trie.ReachableCount+stats.Trie.UnreachableCount != prefixCounts[database.DBKP_TRIE_NODE] {
+		t.Fatalf("trie stats are %+v, trie node count is %d", stats.Trie, prefixCounts[database.DBKP_TRIE_NODE])
+	}
+
+	if len(stats.TopAccounts) != 1 || stats.TopAccounts[0].Address != addr || stats.TopAccounts[0].BlockCount != 3 {
+		t.Fatalf("top accounts are %+v", stats.TopAccounts)
+	}
+
+	var buf bytes.Buffer
+	if err := stats.WriteTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("DBKP_ACCOUNTBLOCK")) {
+		t.Fatalf("table is %s", buf.String())
+	}
+}
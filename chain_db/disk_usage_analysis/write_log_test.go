@@ -1,34 +1,63 @@
-package disk_usage_analysis
+package disk_usage_analysis_test
 
 import (
 	"encoding/binary"
 	"fmt"
+	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/journal"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
-	"github.com/vitelabs/go-vite/node"
 	"io"
 	"io/ioutil"
 	"os"
-	"path"
+	"path/filepath"
 	"testing"
 )
 
 func Test_write_log(t *testing.T) {
-	//chainDb := newChainDb("testdata")
+	dir, err := ioutil.TempDir("", "write_log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
 
-	journalFileReader, err := os.Open(path.Join(node.DefaultDataDir(), "ledger_test/testdata/ledger/005876.log"))
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := ledger.SnapshotContent{
+		types.AddressConsensusGroup: &ledger.HashHeight{Height: 1, Hash: types.Hash{1}},
+	}
+	value, err := sc.Serialize()
 	if err != nil {
 		t.Fatal(err)
 	}
+	key, _ := database.EncodeKey(database.DBKP_SNAPSHOTCONTENT, uint64(1))
+	if err := db.Put(key, value, nil); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
 
-	_, err = read(journalFileReader)
+	logFiles, err := filepath.Glob(filepath.Join(dir, "*.log"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	if len(logFiles) <= 0 {
+		t.Fatal("the journal is missing")
+	}
+
+	journalFileReader, err := os.Open(logFiles[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journalFileReader.Close()
 
+	_, err = read(journalFileReader)
+	if err != nil {
+		t.Fatal(err)
+	}
 }
 
 type keyType uint
@@ -0,0 +1,46 @@
+package gvite_plugins
+
+import (
+	"fmt"
+	"github.com/vitelabs/go-vite/cmd/nodemanager"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+)
+
+var (
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "Inspect the database",
+		Category: "DATABASE COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(dbStatsAction),
+				Name:      "stats",
+				Usage:     "stats --dbStatsFormat=table --dbStatsTopN=20",
+				ArgsUsage: "[--dbStatsFormat=table|json] [--dbStatsTopN=20] [--dbStatsTrieFromHeight=1]",
+				Flags:     append(dbStatsFlags, configFlags...),
+				Description: `
+Report the key count and the byte size of every key prefix, the reachable and unreachable trie nodes,
+and the accounts which consume the most storage. The hashes of the reachable trie nodes are kept in
+memory, about 100 bytes per node, an earlier --dbStatsTrieFromHeight needs more memory.
+`,
+			},
+		},
+	}
+)
+
+func dbStatsAction(ctx *cli.Context) error {
+	nodeManager, err := nodemanager.NewDbStatsNodeManager(ctx, nodemanager.FullNodeMaker{})
+	if err != nil {
+		log.Error(fmt.Sprintf("new Node error, %+v", err))
+		return err
+	}
+	if err := nodeManager.Start(); err != nil {
+		log.Error(err.Error())
+		fmt.Println(err.Error())
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
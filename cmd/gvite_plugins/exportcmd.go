@@ -17,6 +17,14 @@ var (
 		Category: "EXPORT COMMANDS",
 		Description: `
 Export ledger.
+
+Without --exportDir, the balances of all accounts at --sbHeight are printed.
+
+With --exportDir, the snapshot blocks from --fromSbHeight to --sbHeight (the latest if it is not set), the account
+blocks and the vm logs confirmed by them are written into the directory as --exportFormat. The balances of all
+accounts are written only at the boundaries of the range, at --fromSbHeight - 1 (if it is above 0) and at --sbHeight,
+not after every snapshot block. The state tries of both heights must be kept, the export fails if they are removed by
+the ledger gc.
 `,
 	}
 )
@@ -0,0 +1,74 @@
+package gvite_plugins
+
+import (
+	"fmt"
+	"github.com/vitelabs/go-vite/cmd/nodemanager"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+	"os"
+)
+
+var (
+	ledgerCommand = cli.Command{
+		Name:     "ledger",
+		Usage:    "Manage the ledger",
+		Category: "LEDGER COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(importLedgerAction),
+				Name:      "import",
+				Usage:     "import --importDir=~/ledger_files",
+				ArgsUsage: "--importDir=~/ledger_files",
+				Flags:     append(importFlags, configFlags...),
+				Description: `
+Import the compressed ledger files which are produced by the compressor, the blocks are verified and inserted into the ledger.
+The import resumes from the latest snapshot block of the ledger.
+`,
+			},
+			{
+				Action:    utils.MigrateFlags(verifyLedgerAction),
+				Name:      "verify",
+				Usage:     "verify --verifyReport=~/verify_report.json",
+				ArgsUsage: "[--verifyReport=~/verify_report.json]",
+				Flags:     append(verifyFlags, configFlags...),
+				Description: `
+Walk the ledger and cross-check the account chains, the snapshot chain, the state tries, the onroad metas and the
+block events. The violations are reported as JSON, the command fails if any violation is found.
+`,
+			},
+		},
+	}
+)
+
+func importLedgerAction(ctx *cli.Context) error {
+	// Create and start the node based on the CLI flags
+	nodeManager, err := nodemanager.NewImportNodeManager(ctx, nodemanager.FullNodeMaker{})
+	if err != nil {
+		log.Error(fmt.Sprintf("new Node error, %+v", err))
+		return err
+	}
+	// the node and the chain are stopped before return, so the imported blocks are flushed
+	defer nodeManager.Stop()
+
+	if err := nodeManager.Start(); err != nil {
+		log.Error(err.Error())
+		fmt.Println(err.Error())
+		return err
+	}
+	return nil
+}
+
+func verifyLedgerAction(ctx *cli.Context) error {
+	nodeManager, err := nodemanager.NewVerifyNodeManager(ctx, nodemanager.FullNodeMaker{})
+	if err != nil {
+		log.Error(fmt.Sprintf("new Node error, %+v", err))
+		return err
+	}
+	if err := nodeManager.Start(); err != nil {
+		log.Error(err.Error())
+		fmt.Println(err.Error())
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
@@ -60,6 +60,7 @@ var (
 		utils.RPCEnabledFlag,
 		utils.RPCListenAddrFlag,
 		utils.RPCPortFlag,
+		utils.RPCDiscoverFileFlag,
 	}
 
 	//WS
@@ -69,6 +70,20 @@ var (
 		utils.WSPortFlag,
 	}
 
+	//gRPC
+	grpcFlags = []cli.Flag{
+		utils.GRPCEnabledFlag,
+		utils.GRPCListenAddrFlag,
+		utils.GRPCPortFlag,
+	}
+
+	//REST
+	restFlags = []cli.Flag{
+		utils.RESTEnabledFlag,
+		utils.RESTListenAddrFlag,
+		utils.RESTPortFlag,
+	}
+
 	//Console
 	consoleFlags = []cli.Flag{
 		utils.JSPathFlag,
@@ -126,6 +141,27 @@ var (
 	// Export
 	exportFlags = []cli.Flag{
 		utils.ExportSbHeightFlags,
+		utils.ExportFromSbHeightFlag,
+		utils.ExportDirFlag,
+		utils.ExportFormatFlag,
+	}
+
+	// Import
+	importFlags = []cli.Flag{
+		utils.ImportDirFlag,
+	}
+
+	// Verify
+	verifyFlags = []cli.Flag{
+		utils.VerifyReportFlag,
+		utils.VerifyMaxViolationsFlag,
+	}
+
+	// Database stats
+	dbStatsFlags = []cli.Flag{
+		utils.DbStatsFormatFlag,
+		utils.DbStatsTopNFlag,
+		utils.DbStatsTrieFromHeightFlag,
 	}
 )
 
@@ -153,13 +189,15 @@ func init() {
 		attachCommand,
 		ledgerRecoverCommand,
 		exportCommand,
+		ledgerCommand,
+		dbCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	//Import: Please add the New Flags here
 	app.Flags = utils.MergeFlags(configFlags, generalFlags, p2pFlags,
-		ipcFlags, httpFlags, wsFlags, consoleFlags, producerFlags, logFlags,
-		vmFlags, netFlags, statFlags, metricsFlags, ledgerFlags, exportFlags)
+		ipcFlags, httpFlags, wsFlags, grpcFlags, restFlags, consoleFlags, producerFlags, logFlags,
+		vmFlags, netFlags, statFlags, metricsFlags, ledgerFlags, exportFlags, importFlags, verifyFlags, dbStatsFlags)
 
 	app.Before = beforeAction
 	app.Action = action
@@ -0,0 +1,83 @@
+package nodemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/chain_db/disk_usage_analysis"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"github.com/vitelabs/go-vite/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type DbStatsNodeManager struct {
+	ctx  *cli.Context
+	node *node.Node
+}
+
+func NewDbStatsNodeManager(ctx *cli.Context, maker NodeMaker) (*DbStatsNodeManager, error) {
+	node, err := maker.MakeNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// single mode
+	node.Config().Single = true
+	node.ViteConfig().Net.Single = true
+
+	// no miner
+	node.Config().MinerEnabled = false
+	node.ViteConfig().Producer.Producer = false
+
+	// no ledger gc
+	ledgerGc := false
+	node.Config().LedgerGc = &ledgerGc
+	node.ViteConfig().Chain.LedgerGc = ledgerGc
+
+	return &DbStatsNodeManager{
+		ctx:  ctx,
+		node: node,
+	}, nil
+}
+
+func (nodeManager *DbStatsNodeManager) Start() error {
+	format := nodeManager.ctx.GlobalString(utils.DbStatsFormatFlag.Name)
+	if format != "table" && format != "json" {
+		return errors.New(fmt.Sprintf("Unknown format %s, it should be table or json", format))
+	}
+
+	// Start up the node
+	node := nodeManager.node
+	if err := StartNode(nodeManager.node); err != nil {
+		return err
+	}
+
+	stats, err := disk_usage_analysis.Analyze(node.Vite().Chain().ChainDb(),
+		nodeManager.ctx.GlobalUint64(utils.DbStatsTrieFromHeightFlag.Name),
+		nodeManager.ctx.GlobalInt(utils.DbStatsTopNFlag.Name))
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		statsJson, err := json.MarshalIndent(stats, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(statsJson))
+		return nil
+	}
+	return stats.WriteTable(os.Stdout)
+}
+
+func (nodeManager *DbStatsNodeManager) Stop() error {
+	StopNode(nodeManager.node)
+
+	return nil
+}
+
+func (nodeManager *DbStatsNodeManager) Node() *node.Node {
+	return nodeManager.node
+}
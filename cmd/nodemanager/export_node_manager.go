@@ -56,7 +56,22 @@ func (nodeManager *ExportNodeManager) getSbHeight() uint64 {
 	return sbHeight
 }
 
+func (nodeManager *ExportNodeManager) getFromSbHeight() uint64 {
+	return nodeManager.ctx.GlobalUint64(utils.ExportFromSbHeightFlag.Name)
+}
+
+func (nodeManager *ExportNodeManager) getExportDir() string {
+	return nodeManager.ctx.GlobalString(utils.ExportDirFlag.Name)
+}
+
+func (nodeManager *ExportNodeManager) getExportFormat() string {
+	return nodeManager.ctx.GlobalString(utils.ExportFormatFlag.Name)
+}
+
 func (nodeManager *ExportNodeManager) Start() error {
+	if len(nodeManager.getExportDir()) > 0 {
+		return nodeManager.exportLedger()
+	}
 
 	allAddress := make(map[types.Address]struct{})
 	generalAddressMap := make(map[types.Address]struct{})
@@ -299,6 +314,49 @@ func (nodeManager *ExportNodeManager) Start() error {
 	return nil
 }
 
+// exportLedger writes snapshot blocks, account blocks, vm logs from `--fromSbHeight` to `--sbHeight`
+// and the balances at `--fromSbHeight` - 1 and at `--sbHeight` into `--exportDir`.
+func (nodeManager *ExportNodeManager) exportLedger() error {
+	format := nodeManager.getExportFormat()
+	if format != ExportFormatJsonLines && format != ExportFormatCsv {
+		return errors.New(fmt.Sprintf("`--exportFormat` must be %s or %s", ExportFormatJsonLines, ExportFormatCsv))
+	}
+
+	// Start up the node
+	if err := StartNode(nodeManager.node); err != nil {
+		return err
+	}
+	chainInstance := nodeManager.node.Vite().Chain()
+
+	toHeight := nodeManager.getSbHeight()
+	if toHeight <= 0 {
+		toHeight = chainInstance.GetLatestSnapshotBlock().Height
+	}
+	fromHeight := nodeManager.getFromSbHeight()
+	if fromHeight <= 0 {
+		fromHeight = 1
+	}
+	if fromHeight > toHeight {
+		return errors.New(fmt.Sprintf("`--fromSbHeight` %d is greater than `--sbHeight` %d", fromHeight, toHeight))
+	}
+
+	exporter, err := newLedgerExporter(chainInstance, nodeManager.getExportDir(), format)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Start export the ledger from %d to %d into %s\n", fromHeight, toHeight, nodeManager.getExportDir())
+	if err := exporter.export(fromHeight, toHeight); err != nil {
+		exporter.close()
+		return err
+	}
+	if err := exporter.close(); err != nil {
+		return err
+	}
+	fmt.Printf("Complete exporting the ledger from %d to %d\n", fromHeight, toHeight)
+	return nil
+}
+
 func (nodeManager *ExportNodeManager) calculateSumBalanceMap(balanceMapList ...map[types.Address]*big.Int) map[types.Address]*big.Int {
 	sumBalanceMap := make(map[types.Address]*big.Int)
 	for _, balanceMap := range balanceMapList {
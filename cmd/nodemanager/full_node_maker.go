@@ -121,6 +121,10 @@ func mappingNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.HttpPort = ctx.GlobalInt(utils.RPCPortFlag.Name)
 	}
 
+	if discoverFile := ctx.GlobalString(utils.RPCDiscoverFileFlag.Name); len(discoverFile) > 0 {
+		cfg.RPCDiscoverFile = discoverFile
+	}
+
 	//WS Config
 	if ctx.GlobalIsSet(utils.WSEnabledFlag.Name) {
 		cfg.WSEnabled = ctx.GlobalBool(utils.WSEnabledFlag.Name)
@@ -134,6 +138,32 @@ func mappingNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.WSPort = ctx.GlobalInt(utils.WSPortFlag.Name)
 	}
 
+	//gRPC Config
+	if ctx.GlobalIsSet(utils.GRPCEnabledFlag.Name) {
+		cfg.GRPCEnabled = ctx.GlobalBool(utils.GRPCEnabledFlag.Name)
+	}
+
+	if grpcListenAddr := ctx.GlobalString(utils.GRPCListenAddrFlag.Name); len(grpcListenAddr) > 0 {
+		cfg.GRPCHost = grpcListenAddr
+	}
+
+	if ctx.GlobalIsSet(utils.GRPCPortFlag.Name) {
+		cfg.GRPCPort = ctx.GlobalInt(utils.GRPCPortFlag.Name)
+	}
+
+	//REST Config
+	if ctx.GlobalIsSet(utils.RESTEnabledFlag.Name) {
+		cfg.RESTEnabled = ctx.GlobalBool(utils.RESTEnabledFlag.Name)
+	}
+
+	if restListenAddr := ctx.GlobalString(utils.RESTListenAddrFlag.Name); len(restListenAddr) > 0 {
+		cfg.RESTHost = restListenAddr
+	}
+
+	if ctx.GlobalIsSet(utils.RESTPortFlag.Name) {
+		cfg.RESTPort = ctx.GlobalInt(utils.RESTPortFlag.Name)
+	}
+
 	//Producer Config
 	if coinBase := ctx.GlobalString(utils.CoinBaseFlag.Name); len(coinBase) > 0 {
 		cfg.CoinBase = coinBase
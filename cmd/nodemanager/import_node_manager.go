@@ -0,0 +1,416 @@
+package nodemanager
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/node"
+	"github.com/vitelabs/go-vite/verifier"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type ImportNodeManager struct {
+	ctx  *cli.Context
+	node *node.Node
+}
+
+func NewImportNodeManager(ctx *cli.Context, maker NodeMaker) (*ImportNodeManager, error) {
+	node, err := maker.MakeNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// single mode
+	node.Config().Single = true
+	node.ViteConfig().Net.Single = true
+
+	// no miner
+	node.Config().MinerEnabled = false
+	node.ViteConfig().Producer.Producer = false
+
+	// no ledger gc
+	ledgerGc := false
+	node.Config().LedgerGc = &ledgerGc
+	node.ViteConfig().Chain.LedgerGc = ledgerGc
+
+	return &ImportNodeManager{
+		ctx:  ctx,
+		node: node,
+	}, nil
+}
+
+func (nodeManager *ImportNodeManager) getImportDir() string {
+	return nodeManager.ctx.GlobalString(utils.ImportDirFlag.Name)
+}
+
+func (nodeManager *ImportNodeManager) Start() error {
+	importDir := nodeManager.getImportDir()
+	if len(importDir) <= 0 {
+		return errors.New("`--importDir` must be set")
+	}
+
+	files, err := listLedgerFiles(importDir)
+	if err != nil {
+		return err
+	}
+	if len(files) <= 0 {
+		return errors.New(fmt.Sprintf("There is no ledger file in %s", importDir))
+	}
+
+	// Start up the node
+	node := nodeManager.node
+	if err := StartNode(nodeManager.node); err != nil {
+		return err
+	}
+
+	v := node.Vite()
+	im := newLedgerImporter(v.Chain(), verifier.NewAccountVerifier(v.Chain(), v.Consensus()), verifier.NewSnapshotVerifier(v.Chain(), v.Consensus()))
+
+	// the ledger is the checkpoint, files below the latest snapshot block have been imported
+	latestHeight := v.Chain().GetLatestSnapshotBlock().Height
+	fmt.Printf("Latest snapshot block height is %d\n", latestHeight)
+
+	for _, file := range files {
+		if file.endHeight <= latestHeight {
+			continue
+		}
+
+		fmt.Printf("Importing %s...\n", file.filename)
+		if err := im.importFile(importDir, file); err != nil {
+			fmt.Printf("Import %s failed, error is %s\n", file.filename, err.Error())
+			fmt.Printf("Latest snapshot block height is %d, run the command again to resume\n", v.Chain().GetLatestSnapshotBlock().Height)
+			return err
+		}
+
+		latestHeight = v.Chain().GetLatestSnapshotBlock().Height
+		fmt.Printf("Import %s successed! Latest snapshot block height is %d\n", file.filename, latestHeight)
+	}
+
+	return nil
+}
+
+func (nodeManager *ImportNodeManager) Stop() error {
+	StopNode(nodeManager.node)
+
+	return nil
+}
+
+func (nodeManager *ImportNodeManager) Node() *node.Node {
+	return nodeManager.node
+}
+
+type ledgerFile struct {
+	filename    string
+	startHeight uint64
+	endHeight   uint64
+
+	// meta in the index file of the dir, nil if the file isn't indexed
+	meta *ledger.CompressedFileMeta
+}
+
+// listLedgerFiles returns the files named subgraph_{startHeight}_{endHeight} in dir, sorted by height.
+func listLedgerFiles(dir string) ([]*ledgerFile, error) {
+	fileInfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make(map[string]*ledger.CompressedFileMeta)
+	items, err := compress.ReadIndex(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, item := range items {
+		metas[item.Filename] = item
+	}
+
+	var files []*ledgerFile
+	for _, fileInfo := range fileInfos {
+		segs := strings.Split(fileInfo.Name(), "_")
+		if fileInfo.IsDir() || len(segs) != 3 || segs[0] != "subgraph" {
+			continue
+		}
+
+		startHeight, err := strconv.ParseUint(segs[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		endHeight, err := strconv.ParseUint(segs[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		files = append(files, &ledgerFile{
+			filename:    fileInfo.Name(),
+			startHeight: startHeight,
+			endHeight:   endHeight,
+			meta:        metas[fileInfo.Name()],
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].startHeight < files[j].startHeight
+	})
+
+	for i := 1; i < len(files); i++ {
+		if files[i].startHeight != files[i-1].endHeight+1 {
+			return nil, errors.New(fmt.Sprintf("%s doesn't follow %s", files[i].filename, files[i-1].filename))
+		}
+	}
+	return files, nil
+}
+
+const verifyBatchSize = 1000
+
+type ledgerImporter struct {
+	chain            chain.Chain
+	accountVerifier  *verifier.AccountVerifier
+	snapshotVerifier *verifier.SnapshotVerifier
+
+	// account blocks which haven't been inserted, sorted by height
+	pendingAccountBlocks map[types.Address][]*ledger.AccountBlock
+}
+
+func newLedgerImporter(chain chain.Chain, accountVerifier *verifier.AccountVerifier, snapshotVerifier *verifier.SnapshotVerifier) *ledgerImporter {
+	return &ledgerImporter{
+		chain:                chain,
+		accountVerifier:      accountVerifier,
+		snapshotVerifier:     snapshotVerifier,
+		pendingAccountBlocks: make(map[types.Address][]*ledger.AccountBlock),
+	}
+}
+
+func (im *ledgerImporter) importFile(dir string, lf *ledgerFile) error {
+	file, err := os.Open(filepath.Join(dir, lf.filename))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if lf.meta != nil && !lf.meta.Hash.IsZero() {
+		fileHash, err := compress.FileHash(file)
+		if err != nil {
+			return err
+		}
+		if fileHash != lf.meta.Hash {
+			return errors.New(fmt.Sprintf("hash is %s, expected %s", fileHash, lf.meta.Hash))
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	var snapshotBlocks []*ledger.SnapshotBlock
+	var accountBlocks []*ledger.AccountBlock
+	var parseErr error
+	streamErr := compress.BlockParser(file, 0, func(block ledger.Block, err error) {
+		if err != nil {
+			parseErr = err
+			return
+		}
+
+		switch block.(type) {
+		case *ledger.SnapshotBlock:
+			snapshotBlocks = append(snapshotBlocks, block.(*ledger.SnapshotBlock))
+		case *ledger.AccountBlock:
+			accountBlock := block.(*ledger.AccountBlock)
+			accountBlocks = append(accountBlocks, accountBlock)
+			im.pendingAccountBlocks[accountBlock.AccountAddress] = append(im.pendingAccountBlocks[accountBlock.AccountAddress], accountBlock)
+		}
+	})
+	if parseErr == nil {
+		parseErr = streamErr
+	}
+	if parseErr != nil {
+		return parseErr
+	}
+
+	for _, blocks := range im.pendingAccountBlocks {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Height < blocks[j].Height
+		})
+	}
+	sort.Slice(snapshotBlocks, func(i, j int) bool {
+		return snapshotBlocks[i].Height < snapshotBlocks[j].Height
+	})
+
+	if uint64(len(snapshotBlocks)) != lf.endHeight-lf.startHeight+1 {
+		return errors.New(fmt.Sprintf("has %d snapshot blocks, expected %d", len(snapshotBlocks), lf.endHeight-lf.startHeight+1))
+	}
+	for i, snapshotBlock := range snapshotBlocks {
+		if snapshotBlock.Height != lf.startHeight+uint64(i) {
+			return errors.New(fmt.Sprintf("snapshot block %d is missing", lf.startHeight+uint64(i)))
+		}
+	}
+
+	if err := im.verifyNetBlocks(snapshotBlocks, accountBlocks); err != nil {
+		return err
+	}
+
+	for _, snapshotBlock := range snapshotBlocks {
+		if err := im.importSnapshotBlock(snapshotBlock); err != nil {
+			return errors.New(fmt.Sprintf("import snapshot block failed, height is %d, hash is %s, error is %s",
+				snapshotBlock.Height, snapshotBlock.Hash, err.Error()))
+		}
+	}
+	return nil
+}
+
+// verifyNetBlocks checks the hashes, the signatures and the nonces of the blocks in batches of verifyBatchSize on all
+// cpus. They don't depend on the ledger, unlike the vm which runs block by block on the state of the previous block.
+func (im *ledgerImporter) verifyNetBlocks(snapshotBlocks []*ledger.SnapshotBlock, accountBlocks []*ledger.AccountBlock) error {
+	total := len(snapshotBlocks) + len(accountBlocks)
+	verify := func(i int) error {
+		if i < len(snapshotBlocks) {
+			if err := im.snapshotVerifier.VerifyNetSb(snapshotBlocks[i]); err != nil {
+				return errors.New(fmt.Sprintf("verify snapshot block failed, height is %d, hash is %s, error is %s",
+					snapshotBlocks[i].Height, snapshotBlocks[i].Hash, err.Error()))
+			}
+			return nil
+		}
+
+		block := accountBlocks[i-len(snapshotBlocks)]
+		if err := im.accountVerifier.VerifyNetAb(block); err != nil {
+			return errors.New(fmt.Sprintf("verify account block failed, addr is %s, height is %d, hash is %s, error is %s",
+				block.AccountAddress, block.Height, block.Hash, err.Error()))
+		}
+		return nil
+	}
+
+	workers := runtime.NumCPU()
+	for start := 0; start < total; start += verifyBatchSize {
+		end := start + verifyBatchSize
+		if end > total {
+			end = total
+		}
+
+		errs := make([]error, workers)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := start + w; i < end && errs[w] == nil; i += workers {
+					errs[w] = verify(i)
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (im *ledgerImporter) importSnapshotBlock(snapshotBlock *ledger.SnapshotBlock) error {
+	latestSb := im.chain.GetLatestSnapshotBlock()
+	if snapshotBlock.Height <= latestSb.Height {
+		existedSb, err := im.chain.GetSnapshotBlockByHeight(snapshotBlock.Height)
+		if err != nil {
+			return err
+		}
+		if existedSb == nil || existedSb.Hash != snapshotBlock.Hash {
+			return errors.New("the ledger forks from the file")
+		}
+		return nil
+	}
+
+	if err := im.importConfirmedAccountBlocks(snapshotBlock); err != nil {
+		return err
+	}
+
+	if stat := im.snapshotVerifier.VerifyReferred(snapshotBlock); stat.VerifyResult() != verifier.SUCCESS {
+		return errors.New("verify failed, " + stat.ErrMsg())
+	}
+
+	return im.chain.InsertSnapshotBlock(snapshotBlock)
+}
+
+// importConfirmedAccountBlocks inserts the account blocks confirmed by snapshotBlock. A receive block waits until
+// its send block is inserted, so accounts are visited repeatedly until all blocks are inserted or no progress is made.
+func (im *ledgerImporter) importConfirmedAccountBlocks(snapshotBlock *ledger.SnapshotBlock) error {
+	for {
+		hasProgress := false
+		isFinished := true
+
+		for addr, hashHeight := range snapshotBlock.SnapshotContent {
+			blocks := im.pendingAccountBlocks[addr]
+
+			for len(blocks) > 0 && blocks[0].Height <= hashHeight.Height {
+				inserted, err := im.importAccountBlock(blocks[0])
+				if err != nil {
+					return errors.New(fmt.Sprintf("import account block failed, addr is %s, height is %d, hash is %s, error is %s",
+						addr, blocks[0].Height, blocks[0].Hash, err.Error()))
+				}
+				if !inserted {
+					break
+				}
+
+				blocks = blocks[1:]
+				hasProgress = true
+			}
+
+			if len(blocks) > 0 {
+				im.pendingAccountBlocks[addr] = blocks
+				if blocks[0].Height <= hashHeight.Height {
+					isFinished = false
+				}
+			} else {
+				delete(im.pendingAccountBlocks, addr)
+			}
+		}
+
+		if isFinished {
+			return nil
+		}
+		if !hasProgress {
+			return errors.New("the referred blocks of account blocks are missing")
+		}
+	}
+}
+
+// importAccountBlock returns false if the referred blocks of block haven't been inserted.
+func (im *ledgerImporter) importAccountBlock(block *ledger.AccountBlock) (bool, error) {
+	// the send blocks of contract are inserted with the receive block, or the block is imported before resume
+	if existedBlock, err := im.chain.GetAccountBlockByHash(&block.Hash); err != nil {
+		return false, err
+	} else if existedBlock != nil {
+		return true, nil
+	}
+
+	result, stat := im.accountVerifier.VerifyReferred(block)
+	switch result {
+	case verifier.PENDING:
+		return false, nil
+	case verifier.FAIL:
+		return false, errors.New("verify failed, " + stat.ErrMsg())
+	}
+
+	vmAccountBlocks, err := im.accountVerifier.VerifyforVM(block)
+	if err != nil {
+		return false, err
+	}
+
+	if err := im.chain.InsertAccountBlocks(vmAccountBlocks); err != nil {
+		return false, err
+	}
+	return true, nil
+}
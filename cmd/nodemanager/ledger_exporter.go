@@ -0,0 +1,483 @@
+package nodemanager
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	vmabi "github.com/vitelabs/go-vite/vm/abi"
+	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+const (
+	ExportFormatJsonLines = "jsonl"
+	ExportFormatCsv       = "csv"
+
+	// count of snapshot blocks queried at a time
+	exportBatchSize = 100
+)
+
+var builtinContractAbiMap = map[types.Address]vmabi.ABIContract{
+	types.AddressRegister:       abi.ABIRegister,
+	types.AddressVote:           abi.ABIVote,
+	types.AddressPledge:         abi.ABIPledge,
+	types.AddressConsensusGroup: abi.ABIConsensusGroup,
+	types.AddressMintage:        abi.ABIMintage,
+}
+
+// The field order of every record is the column order of csv, append new fields to the end to keep the schema stable.
+type snapshotBlockRecord struct {
+	Height       uint64 `json:"height"`
+	Hash         string `json:"hash"`
+	PrevHash     string `json:"prevHash"`
+	Timestamp    int64  `json:"timestamp"`
+	Producer     string `json:"producer"`
+	StateHash    string `json:"stateHash"`
+	AccountCount int    `json:"accountCount"`
+}
+
+type accountBlockRecord struct {
+	SnapshotHeight uint64          `json:"snapshotHeight"`
+	Hash           string          `json:"hash"`
+	Height         uint64          `json:"height"`
+	PrevHash       string          `json:"prevHash"`
+	BlockType      byte            `json:"blockType"`
+	AccountAddress string          `json:"accountAddress"`
+	ToAddress      string          `json:"toAddress"`
+	FromBlockHash  string          `json:"fromBlockHash"`
+	TokenId        string          `json:"tokenId"`
+	Amount         string          `json:"amount"`
+	Fee            string          `json:"fee"`
+	Quota          uint64          `json:"quota"`
+	Timestamp      int64           `json:"timestamp"`
+	LogHash        string          `json:"logHash"`
+	Data           string          `json:"data"`
+	Method         string          `json:"method"`
+	Params         json.RawMessage `json:"params"`
+}
+
+type vmLogRecord struct {
+	SnapshotHeight uint64 `json:"snapshotHeight"`
+	BlockHash      string `json:"blockHash"`
+	AccountAddress string `json:"accountAddress"`
+	Index          int    `json:"index"`
+	Topics         string `json:"topics"`
+	Data           string `json:"data"`
+}
+
+type balanceRecord struct {
+	SnapshotHeight uint64 `json:"snapshotHeight"`
+	Address        string `json:"address"`
+	TokenId        string `json:"tokenId"`
+	Balance        string `json:"balance"`
+}
+
+// recordWriter writes records of one type into a json lines file or a csv file.
+type recordWriter struct {
+	file      *os.File
+	format    string
+	csvWriter *csv.Writer
+	encoder   *json.Encoder
+}
+
+func newRecordWriter(dir string, name string, format string, header []string) (*recordWriter, error) {
+	file, err := os.Create(filepath.Join(dir, name+"."+format))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &recordWriter{
+		file:   file,
+		format: format,
+	}
+
+	switch format {
+	case ExportFormatJsonLines:
+		w.encoder = json.NewEncoder(file)
+	case ExportFormatCsv:
+		w.csvWriter = csv.NewWriter(file)
+		if err := w.csvWriter.Write(header); err != nil {
+			file.Close()
+			return nil, err
+		}
+	default:
+		file.Close()
+		return nil, errors.New("unknown format " + format)
+	}
+	return w, nil
+}
+
+func (w *recordWriter) write(record interface{}, row []string) error {
+	if w.encoder != nil {
+		return w.encoder.Encode(record)
+	}
+	return w.csvWriter.Write(row)
+}
+
+func (w *recordWriter) close() error {
+	if w.csvWriter != nil {
+		w.csvWriter.Flush()
+		if err := w.csvWriter.Error(); err != nil {
+			w.file.Close()
+			return err
+		}
+	}
+	return w.file.Close()
+}
+
+type ledgerExporter struct {
+	chain chain.Chain
+
+	snapshotBlockWriter *recordWriter
+	accountBlockWriter  *recordWriter
+	vmLogWriter         *recordWriter
+	balanceWriter       *recordWriter
+}
+
+func newLedgerExporter(chain chain.Chain, dir string, format string) (*ledgerExporter, error) {
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return nil, err
+	}
+
+	e := &ledgerExporter{
+		chain: chain,
+	}
+
+	var err error
+	if e.snapshotBlockWriter, err = newRecordWriter(dir, "snapshot_blocks", format,
+		[]string{"height", "hash", "prevHash", "timestamp", "producer", "stateHash", "accountCount"}); err != nil {
+		return nil, err
+	}
+	if e.accountBlockWriter, err = newRecordWriter(dir, "account_blocks", format,
+		[]string{"snapshotHeight", "hash", "height", "prevHash", "blockType", "accountAddress", "toAddress", "fromBlockHash",
+			"tokenId", "amount", "fee", "quota", "timestamp", "logHash", "data", "method", "params"}); err != nil {
+		e.close()
+		return nil, err
+	}
+	if e.vmLogWriter, err = newRecordWriter(dir, "vm_logs", format,
+		[]string{"snapshotHeight", "blockHash", "accountAddress", "index", "topics", "data"}); err != nil {
+		e.close()
+		return nil, err
+	}
+	if e.balanceWriter, err = newRecordWriter(dir, "balances", format,
+		[]string{"snapshotHeight", "address", "tokenId", "balance"}); err != nil {
+		e.close()
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *ledgerExporter) close() error {
+	var closeErr error
+	for _, w := range []*recordWriter{e.snapshotBlockWriter, e.accountBlockWriter, e.vmLogWriter, e.balanceWriter} {
+		if w == nil {
+			continue
+		}
+		if err := w.close(); err != nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
+// export writes snapshot blocks from fromHeight to toHeight and the account blocks and vm logs confirmed by them,
+// and the balances of all accounts at both boundaries of the range: at fromHeight-1 before the range and at toHeight,
+// so the blocks of the range can be reconciled against the balances.
+func (e *ledgerExporter) export(fromHeight uint64, toHeight uint64) error {
+	if fromHeight > 1 {
+		if err := e.exportBalancesAt(fromHeight - 1); err != nil {
+			return err
+		}
+	}
+
+	for batchStart := fromHeight; batchStart <= toHeight; batchStart += exportBatchSize {
+		batchEnd := batchStart + exportBatchSize - 1
+		if batchEnd > toHeight {
+			batchEnd = toHeight
+		}
+
+		snapshotBlocks, subLedger, err := e.chain.GetConfirmSubLedger(batchStart, batchEnd)
+		if err != nil {
+			return errors.New(fmt.Sprintf("GetConfirmSubLedger failed, from %d to %d, error is %s", batchStart, batchEnd, err.Error()))
+		}
+
+		if err := e.exportBlocks(snapshotBlocks, subLedger); err != nil {
+			return err
+		}
+		fmt.Printf("Export snapshot blocks from %d to %d successed!\n", batchStart, batchEnd)
+	}
+
+	return e.exportBalancesAt(toHeight)
+}
+
+func (e *ledgerExporter) exportBalancesAt(height uint64) error {
+	sb, err := e.chain.GetSnapshotBlockByHeight(height)
+	if err != nil {
+		return err
+	}
+	if sb == nil {
+		return errors.New(fmt.Sprintf("Snapshot block is nil, height is %d", height))
+	}
+
+	if err := e.exportBalances(sb); err != nil {
+		return err
+	}
+	fmt.Printf("Export balances at %d successed!\n", height)
+	return nil
+}
+
+func (e *ledgerExporter) exportBlocks(snapshotBlocks []*ledger.SnapshotBlock, subLedger map[types.Address][]*ledger.AccountBlock) error {
+	sort.Slice(snapshotBlocks, func(i, j int) bool {
+		return snapshotBlocks[i].Height < snapshotBlocks[j].Height
+	})
+	for _, blocks := range subLedger {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Height < blocks[j].Height
+		})
+	}
+
+	for _, sb := range snapshotBlocks {
+		if err := e.exportSnapshotBlock(sb); err != nil {
+			return err
+		}
+
+		// account blocks confirmed by sb, in address order to make output stable
+		addrList := make([]types.Address, 0, len(sb.SnapshotContent))
+		for addr := range sb.SnapshotContent {
+			addrList = append(addrList, addr)
+		}
+		sort.Slice(addrList, func(i, j int) bool {
+			return bytes.Compare(addrList[i].Bytes(), addrList[j].Bytes()) < 0
+		})
+
+		for _, addr := range addrList {
+			blocks := subLedger[addr]
+			confirmedHeight := sb.SnapshotContent[addr].Height
+
+			for len(blocks) > 0 && blocks[0].Height <= confirmedHeight {
+				if err := e.exportAccountBlock(sb.Height, blocks[0]); err != nil {
+					return err
+				}
+				blocks = blocks[1:]
+			}
+			subLedger[addr] = blocks
+		}
+	}
+	return nil
+}
+
+func (e *ledgerExporter) exportSnapshotBlock(sb *ledger.SnapshotBlock) error {
+	record := &snapshotBlockRecord{
+		Height:       sb.Height,
+		Hash:         sb.Hash.String(),
+		PrevHash:     sb.PrevHash.String(),
+		Producer:     sb.Producer().String(),
+		StateHash:    sb.StateHash.String(),
+		AccountCount: len(sb.SnapshotContent),
+	}
+	if sb.Timestamp != nil {
+		record.Timestamp = sb.Timestamp.Unix()
+	}
+
+	return e.snapshotBlockWriter.write(record, []string{
+		strconv.FormatUint(record.Height, 10),
+		record.Hash,
+		record.PrevHash,
+		strconv.FormatInt(record.Timestamp, 10),
+		record.Producer,
+		record.StateHash,
+		strconv.Itoa(record.AccountCount),
+	})
+}
+
+func (e *ledgerExporter) exportAccountBlock(snapshotHeight uint64, block *ledger.AccountBlock) error {
+	record := &accountBlockRecord{
+		SnapshotHeight: snapshotHeight,
+		Hash:           block.Hash.String(),
+		Height:         block.Height,
+		PrevHash:       block.PrevHash.String(),
+		BlockType:      block.BlockType,
+		AccountAddress: block.AccountAddress.String(),
+		ToAddress:      block.ToAddress.String(),
+		FromBlockHash:  block.FromBlockHash.String(),
+		TokenId:        block.TokenId.String(),
+		Amount:         "0",
+		Fee:            "0",
+		Quota:          block.Quota,
+		Data:           hex.EncodeToString(block.Data),
+		Params:         json.RawMessage("null"),
+	}
+	if block.Amount != nil {
+		record.Amount = block.Amount.String()
+	}
+	if block.Fee != nil {
+		record.Fee = block.Fee.String()
+	}
+	if block.Timestamp != nil {
+		record.Timestamp = block.Timestamp.Unix()
+	}
+	if block.LogHash != nil {
+		record.LogHash = block.LogHash.String()
+	}
+
+	if block.IsSendBlock() {
+		record.Method, record.Params = decodeBuiltinContractData(block.ToAddress, block.Data)
+	}
+
+	if err := e.accountBlockWriter.write(record, []string{
+		strconv.FormatUint(record.SnapshotHeight, 10),
+		record.Hash,
+		strconv.FormatUint(record.Height, 10),
+		record.PrevHash,
+		strconv.Itoa(int(record.BlockType)),
+		record.AccountAddress,
+		record.ToAddress,
+		record.FromBlockHash,
+		record.TokenId,
+		record.Amount,
+		record.Fee,
+		strconv.FormatUint(record.Quota, 10),
+		strconv.FormatInt(record.Timestamp, 10),
+		record.LogHash,
+		record.Data,
+		record.Method,
+		string(record.Params),
+	}); err != nil {
+		return err
+	}
+
+	if block.LogHash == nil {
+		return nil
+	}
+
+	vmLogList, err := e.chain.GetVmLogList(block.LogHash)
+	if err != nil {
+		return errors.New(fmt.Sprintf("GetVmLogList failed, block hash is %s, error is %s", block.Hash, err.Error()))
+	}
+	for index, vmLog := range vmLogList {
+		topics := make([]string, len(vmLog.Topics))
+		for i, topic := range vmLog.Topics {
+			topics[i] = topic.String()
+		}
+
+		logRecord := &vmLogRecord{
+			SnapshotHeight: snapshotHeight,
+			BlockHash:      block.Hash.String(),
+			AccountAddress: block.AccountAddress.String(),
+			Index:          index,
+			Topics:         strings.Join(topics, ";"),
+			Data:           hex.EncodeToString(vmLog.Data),
+		}
+		if err := e.vmLogWriter.write(logRecord, []string{
+			strconv.FormatUint(logRecord.SnapshotHeight, 10),
+			logRecord.BlockHash,
+			logRecord.AccountAddress,
+			strconv.Itoa(logRecord.Index),
+			logRecord.Topics,
+			logRecord.Data,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBuiltinContractData returns the method name and the json of params if toAddress is a builtin contract.
+func decodeBuiltinContractData(toAddress types.Address, data []byte) (string, json.RawMessage) {
+	contractAbi, ok := builtinContractAbiMap[toAddress]
+	if !ok || len(data) < 4 {
+		return "", json.RawMessage("null")
+	}
+
+	method, err := contractAbi.MethodById(data)
+	if err != nil {
+		return "", json.RawMessage("null")
+	}
+
+	values, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return method.Name, json.RawMessage("null")
+	}
+
+	params := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		params[method.Inputs[i].Name] = value
+	}
+
+	paramsJson, err := json.Marshal(params)
+	if err != nil {
+		return method.Name, json.RawMessage("null")
+	}
+	return method.Name, paramsJson
+}
+
+func (e *ledgerExporter) exportBalances(sb *ledger.SnapshotBlock) error {
+	sbStateTrie := e.chain.GetStateTrie(&sb.StateHash)
+	if sbStateTrie == nil || sbStateTrie.Root == nil {
+		return errors.New(fmt.Sprintf("The state trie of snapshot block is nil, it may be removed by the ledger gc, height is %d", sb.Height))
+	}
+
+	iter := sbStateTrie.NewIterator(nil)
+	for {
+		key, value, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		addr, err := types.BytesToAddress(key)
+		if err != nil {
+			return errors.New("Convert key to address failed, error is " + err.Error())
+		}
+		accountStateHash, err := types.BytesToHash(value)
+		if err != nil {
+			return errors.New("Convert value to accountStateHash failed, error is " + err.Error())
+		}
+
+		accountStateTrie := e.chain.GetStateTrie(&accountStateHash)
+		if accountStateTrie == nil {
+			return errors.New(fmt.Sprintf("The state trie of account is nil, addr is %s", addr))
+		}
+
+		balanceIter := accountStateTrie.NewIterator(vm_context.STORAGE_KEY_BALANCE)
+		for {
+			balanceKey, balanceBytes, ok := balanceIter.Next()
+			if !ok {
+				break
+			}
+
+			tokenId, err := types.BytesToTokenTypeId(balanceKey[len(vm_context.STORAGE_KEY_BALANCE):])
+			if err != nil {
+				continue
+			}
+
+			record := &balanceRecord{
+				SnapshotHeight: sb.Height,
+				Address:        addr.String(),
+				TokenId:        tokenId.String(),
+				Balance:        new(big.Int).SetBytes(balanceBytes).String(),
+			}
+			if err := e.balanceWriter.write(record, []string{
+				strconv.FormatUint(record.SnapshotHeight, 10),
+				record.Address,
+				record.TokenId,
+				record.Balance,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
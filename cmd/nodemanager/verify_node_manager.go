@@ -0,0 +1,90 @@
+package nodemanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/vitelabs/go-vite/chain/consistency"
+	"github.com/vitelabs/go-vite/cmd/utils"
+	"github.com/vitelabs/go-vite/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type VerifyNodeManager struct {
+	ctx  *cli.Context
+	node *node.Node
+}
+
+func NewVerifyNodeManager(ctx *cli.Context, maker NodeMaker) (*VerifyNodeManager, error) {
+	node, err := maker.MakeNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// single mode
+	node.Config().Single = true
+	node.ViteConfig().Net.Single = true
+
+	// no miner
+	node.Config().MinerEnabled = false
+	node.ViteConfig().Producer.Producer = false
+
+	// no ledger gc
+	ledgerGc := false
+	node.Config().LedgerGc = &ledgerGc
+	node.ViteConfig().Chain.LedgerGc = ledgerGc
+
+	return &VerifyNodeManager{
+		ctx:  ctx,
+		node: node,
+	}, nil
+}
+
+func (nodeManager *VerifyNodeManager) Start() error {
+	// Start up the node
+	node := nodeManager.node
+	if err := StartNode(nodeManager.node); err != nil {
+		return err
+	}
+
+	c := node.Vite().Chain()
+	checker := consistency.NewChecker(c, c.TrieGc().RetainMinHeight())
+	checker.SetMaxViolations(nodeManager.ctx.GlobalInt(utils.VerifyMaxViolationsFlag.Name))
+
+	fmt.Printf("Verifying the ledger, latest snapshot block height is %d...\n", c.GetLatestSnapshotBlock().Height)
+	report, err := checker.Check()
+	if err != nil {
+		return err
+	}
+
+	reportJson, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	if reportFile := nodeManager.ctx.GlobalString(utils.VerifyReportFlag.Name); len(reportFile) > 0 {
+		if err := ioutil.WriteFile(reportFile, reportJson, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("The report is written into %s\n", reportFile)
+	} else {
+		fmt.Println(string(reportJson))
+	}
+
+	if !report.IsConsistent() {
+		return errors.New(fmt.Sprintf("The ledger is inconsistent, %d violations are found", report.ViolationCount))
+	}
+	fmt.Println("The ledger is consistent")
+	return nil
+}
+
+func (nodeManager *VerifyNodeManager) Stop() error {
+	StopNode(nodeManager.node)
+
+	return nil
+}
+
+func (nodeManager *VerifyNodeManager) Node() *node.Node {
+	return nodeManager.node
+}
@@ -96,6 +96,10 @@ var (
 		Name:  "rpcport",
 		Usage: "HTTP-RPC server listening port",
 	}
+	RPCDiscoverFileFlag = cli.StringFlag{
+		Name:  "rpcdiscover",
+		Usage: "Write the rpc_discover document of all the RPC methods to the file on startup",
+	}
 
 	//WS Settings
 	WSEnabledFlag = cli.BoolFlag{
@@ -111,6 +115,34 @@ var (
 		Usage: "WS-RPC server listening port",
 	}
 
+	//gRPC Settings
+	GRPCEnabledFlag = cli.BoolFlag{
+		Name:  "grpc",
+		Usage: "Enable the gRPC server",
+	}
+	GRPCListenAddrFlag = cli.StringFlag{
+		Name:  "grpcaddr",
+		Usage: "gRPC server listening interface",
+	}
+	GRPCPortFlag = cli.IntFlag{
+		Name:  "grpcport",
+		Usage: "gRPC server listening port",
+	}
+
+	//REST Settings
+	RESTEnabledFlag = cli.BoolFlag{
+		Name:  "rest",
+		Usage: "Enable the REST gateway of the read-only ledger queries",
+	}
+	RESTListenAddrFlag = cli.StringFlag{
+		Name:  "restaddr",
+		Usage: "REST gateway listening interface",
+	}
+	RESTPortFlag = cli.IntFlag{
+		Name:  "restport",
+		Usage: "REST gateway listening port",
+	}
+
 	//Console Settings
 	JSPathFlag = cli.StringFlag{
 		Name:  "jspath",
@@ -179,6 +211,53 @@ var (
 		Name:  "sbHeight",
 		Usage: "The snapshot block height",
 	}
+	ExportFromSbHeightFlag = cli.Uint64Flag{
+		Name:  "fromSbHeight",
+		Usage: "The snapshot block height to start the full ledger export from",
+		Value: 1,
+	}
+	ExportDirFlag = DirectoryFlag{
+		Name:  "exportDir",
+		Usage: "Export the full ledger into the directory instead of printing balances",
+	}
+	ExportFormatFlag = cli.StringFlag{
+		Name:  "exportFormat",
+		Usage: "The format of the full ledger export, \"jsonl\" or \"csv\"",
+		Value: "jsonl",
+	}
+
+	// Import ledger files
+	ImportDirFlag = DirectoryFlag{
+		Name:  "importDir",
+		Usage: "The directory of compressed ledger files to import",
+	}
+
+	// Verify ledger
+	VerifyReportFlag = cli.StringFlag{
+		Name:  "verifyReport",
+		Usage: "Write the verify report into the file as JSON instead of printing it",
+	}
+	VerifyMaxViolationsFlag = cli.IntFlag{
+		Name:  "verifyMaxViolations",
+		Usage: "The max number of violations kept in the verify report",
+		Value: 1000,
+	}
+
+	// Database stats
+	DbStatsFormatFlag = cli.StringFlag{
+		Name:  "dbStatsFormat",
+		Usage: "The output format of database stats, \"table\" or \"json\"",
+		Value: "table",
+	}
+	DbStatsTopNFlag = cli.IntFlag{
+		Name:  "dbStatsTopN",
+		Usage: "The number of the accounts which consume the most storage in database stats",
+		Value: 20,
+	}
+	DbStatsTrieFromHeightFlag = cli.Uint64Flag{
+		Name:  "dbStatsTrieFromHeight",
+		Usage: "The trie nodes are reachable from the snapshot blocks since the height, default is the latest snapshot block",
+	}
 
 	//Net
 	SingleFlag = cli.BoolFlag{
@@ -13,6 +13,10 @@ const (
 	DefaultHTTPPort = 48132       // Default TCP port for the HTTP RPC server
 	DefaultWSHost   = "localhost" // Default host interface for the websocket RPC server
 	DefaultWSPort   = 31420       // Default TCP port for the websocket RPC server
+	DefaultGRPCHost = "localhost" // Default host interface for the gRPC server
+	DefaultGRPCPort = 48133       // Default TCP port for the gRPC server
+	DefaultRESTHost = "localhost" // Default host interface for the REST gateway
+	DefaultRESTPort = 48134       // Default TCP port for the REST gateway
 	DefaultP2PPort  = 8483
 )
 
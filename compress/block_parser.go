@@ -19,7 +19,6 @@ type blockParserCache struct {
 
 	reader        io.Reader
 	hasReadBlocks uint64
-	processor     blockProcessor
 }
 
 func (blockParser *blockParserCache) RefreshCache() {
@@ -33,11 +32,31 @@ var blockParserLog = log15.New("module", "compress/block_parser")
 
 var readNum = 1024 * 1024 * 10 // 10M
 
-// If blockNum is zero, finish when stream encounter io.EOF
-func BlockParser(reader io.Reader, blockNum uint64, processor blockProcessor) {
+// If blockNum is zero, finish when stream encounter io.EOF.
+// The codec is detected from the file header, legacy files without header are parsed as uncompressed.
+// The error of reading the stream is returned, the errors of the blocks are passed to processor.
+func BlockParser(reader io.Reader, blockNum uint64, processor blockProcessor) error {
+	reader, codec, cErr := NewCodecReader(reader)
+	if cErr != nil {
+		blockParserLog.Error("Read file header failed, error is " + cErr.Error())
+		return cErr
+	}
+	blockParserLog.Debug("Parse blocks, codec is " + codec.String())
+
+	return parseBlockStream(reader, blockNum, func(block ledger.Block, offset uint64, err error) bool {
+		processor(block, err)
+		return true
+	})
+}
+
+// streamProcessor receives every block and its offset in the decoded block stream, return false to stop parsing.
+type streamProcessor func(block ledger.Block, offset uint64, err error) bool
+
+// parseBlockStream parses a decoded block stream, the stream has no file header. io.ErrUnexpectedEOF is returned if the
+// stream ends in the middle of a block.
+func parseBlockStream(reader io.Reader, blockNum uint64, processor streamProcessor) error {
 	blockParser := &blockParserCache{
 		reader:        reader,
-		processor:     processor,
 		hasReadBlocks: 0,
 	}
 
@@ -45,22 +64,30 @@ func BlockParser(reader io.Reader, blockNum uint64, processor blockProcessor) {
 
 	readBytes := make([]byte, readNum)
 
+	// offset of bytes has been consumed, and offset of the block being parsed
+	hasReadBytes := uint64(0)
+	currentBlockOffset := uint64(0)
+
 	for {
 		readN, rErr := reader.Read(readBytes)
 
 		if rErr != nil && rErr != io.EOF {
 			blockParserLog.Error("Read failed, error is " + rErr.Error())
-			return
+			return rErr
 		}
 
 		buffer := bytes.NewBuffer(readBytes[:readN])
 
 		for buffer.Len() > 0 {
 			if blockParser.currentBlockSize == 0 {
+				if len(blockParser.currentBlockSizeBuffer) == 0 {
+					currentBlockOffset = hasReadBytes
+				}
 
 				readNum := 4 - len(blockParser.currentBlockSizeBuffer)
 
 				sizeBytes := buffer.Next(readNum)
+				hasReadBytes += uint64(len(sizeBytes))
 				blockParser.currentBlockSizeBuffer = append(blockParser.currentBlockSizeBuffer, sizeBytes...)
 
 				if len(blockParser.currentBlockSizeBuffer) >= 4 {
@@ -68,11 +95,13 @@ func BlockParser(reader io.Reader, blockNum uint64, processor blockProcessor) {
 				}
 			} else if blockParser.currentBlockSize != 0 && blockParser.currentBlockType == 0 {
 				blockParser.currentBlockType = buffer.Next(1)[0]
+				hasReadBytes++
 
 			} else {
 				readNum := blockParser.currentBlockSize - uint32(len(blockParser.currentBlockBuffer))
 
 				blockBytes := buffer.Next(int(readNum))
+				hasReadBytes += uint64(len(blockBytes))
 
 				blockParser.currentBlockBuffer = append(blockParser.currentBlockBuffer, blockBytes...)
 
@@ -85,20 +114,33 @@ func BlockParser(reader io.Reader, blockNum uint64, processor blockProcessor) {
 						block = &ledger.SnapshotBlock{}
 
 					}
+
+					var goOn bool
 					if block != nil {
 						blockParser.hasReadBlocks++
-						processor(block, block.Deserialize(blockParser.currentBlockBuffer))
+						goOn = processor(block, currentBlockOffset, block.Deserialize(blockParser.currentBlockBuffer))
 					} else {
-						processor(nil, errors.New("Unknown block type"))
+						goOn = processor(nil, currentBlockOffset, errors.New("Unknown block type"))
 					}
 
 					blockParser.RefreshCache()
+
+					if !goOn {
+						return nil
+					}
 				}
 			}
 		}
 
-		if rErr == io.EOF || (blockNum > 0 && blockParser.hasReadBlocks >= blockNum) {
-			return
+		if blockNum > 0 && blockParser.hasReadBlocks >= blockNum {
+			return nil
+		}
+		if rErr == io.EOF {
+			if len(blockParser.currentBlockSizeBuffer) > 0 {
+				blockParserLog.Error("Read failed, the last block is truncated")
+				return io.ErrUnexpectedEOF
+			}
+			return nil
 		}
 	}
 }
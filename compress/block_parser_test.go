@@ -1,8 +1,11 @@
 package compress
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/vitelabs/go-vite/ledger"
+	"io"
+	"io/ioutil"
 	"os"
 	"testing"
 )
@@ -24,3 +27,26 @@ func Test_BlockParser(t *testing.T) {
 
 	})
 }
+
+func Test_BlockParser_Truncated(t *testing.T) {
+	data, err := ioutil.ReadFile("./subgraph_1_3600")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cut := range []int{1, 3, 100} {
+		blockNumbers := 0
+		parseErr := BlockParser(bytes.NewReader(data[:len(data)-cut]), 0, func(block ledger.Block, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			blockNumbers++
+		})
+		if parseErr != io.ErrUnexpectedEOF {
+			t.Fatalf("cut %d bytes, error is %v, expected %v", cut, parseErr, io.ErrUnexpectedEOF)
+		}
+		if blockNumbers <= 0 {
+			t.Fatalf("cut %d bytes, no block is parsed", cut)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package compress
+
+import (
+	"path/filepath"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+// GetSnapshotBlocks reads snapshot blocks from startHeight to startHeight + count - 1 from the ledger files,
+// only the snapshot blocks which have been compressed are returned.
+func (c *Compressor) GetSnapshotBlocks(startHeight uint64, count uint64) ([]*ledger.SnapshotBlock, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+	endHeight := startHeight + count - 1
+
+	var snapshotBlocks []*ledger.SnapshotBlock
+	for _, item := range c.indexer.Get(startHeight, endHeight) {
+		blocks, err := c.readSnapshotBlocks(item, startHeight, endHeight)
+		if err != nil {
+			c.log.Error("readSnapshotBlocks failed, error is "+err.Error(), "method", "GetSnapshotBlocks")
+			return nil, err
+		}
+		snapshotBlocks = append(snapshotBlocks, blocks...)
+	}
+	return snapshotBlocks, nil
+}
+
+func (c *Compressor) readSnapshotBlocks(item *ledger.CompressedFileMeta, startHeight uint64, endHeight uint64) ([]*ledger.SnapshotBlock, error) {
+	oi, err := c.indexer.OffsetIndex(item)
+	if err != nil {
+		return nil, err
+	}
+
+	position, ok := oi.SnapshotBlockOffset(startHeight)
+	if !ok {
+		return nil, nil
+	}
+
+	reader, closer, err := openBlockStreamAt(filepath.Join(c.dir, item.Filename), position)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	var snapshotBlocks []*ledger.SnapshotBlock
+	var parseErr error
+	streamErr := parseBlockStream(reader, 0, func(block ledger.Block, offset uint64, err error) bool {
+		if err != nil {
+			parseErr = err
+			return false
+		}
+
+		snapshotBlock, ok := block.(*ledger.SnapshotBlock)
+		if !ok {
+			return true
+		}
+
+		if snapshotBlock.Height > endHeight {
+			return false
+		}
+		if snapshotBlock.Height >= startHeight {
+			snapshotBlocks = append(snapshotBlocks, snapshotBlock)
+		}
+		return true
+	})
+	if parseErr == nil {
+		parseErr = streamErr
+	}
+
+	return snapshotBlocks, parseErr
+}
+
+// GetAccountBlockByHash searches the offset index of ledger files from the latest one, return nil if the account block isn't found.
+// The offset indexes are cached by the indexer, so only the evicted ones are loaded from the disk again. Every file is
+// searched on a miss, so it's for the historical queries of rpc only.
+func (c *Compressor) GetAccountBlockByHash(hash *types.Hash) (*ledger.AccountBlock, error) {
+	latestHeight := c.indexer.LatestHeight()
+	if latestHeight <= 0 {
+		return nil, nil
+	}
+
+	items := c.indexer.Get(1, latestHeight)
+	for i := len(items) - 1; i >= 0; i-- {
+		item := items[i]
+
+		oi, err := c.indexer.OffsetIndex(item)
+		if err != nil {
+			c.log.Error("OffsetIndex failed, error is "+err.Error(), "method", "GetAccountBlockByHash")
+			return nil, err
+		}
+
+		position, ok := oi.AccountBlockOffset(hash)
+		if !ok {
+			continue
+		}
+
+		reader, closer, err := openBlockStreamAt(filepath.Join(c.dir, item.Filename), position)
+		if err != nil {
+			return nil, err
+		}
+
+		var accountBlock *ledger.AccountBlock
+		var parseErr error
+		streamErr := parseBlockStream(reader, 1, func(block ledger.Block, offset uint64, err error) bool {
+			if err != nil {
+				parseErr = err
+			} else {
+				accountBlock, _ = block.(*ledger.AccountBlock)
+			}
+			return false
+		})
+		closer.Close()
+
+		if parseErr == nil {
+			parseErr = streamErr
+		}
+
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if accountBlock == nil || accountBlock.Hash != *hash {
+			return nil, ErrOffsetIndexCorrupted
+		}
+		return accountBlock, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,148 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/vitelabs/go-vite/common/types"
+	"golang.org/x/crypto/blake2b"
+)
+
+type Codec uint8
+
+const (
+	CodecNone   Codec = 0
+	CodecSnappy Codec = 1
+	CodecGzip   Codec = 2
+)
+
+const DefaultCodec = CodecSnappy
+
+// SupportedCodecs is the bit set of the codecs this node decodes, a peer advertises it in the handshake.
+// Peers without it only decode legacy files.
+const SupportedCodecs uint32 = 1<<CodecNone | 1<<CodecSnappy | 1<<CodecGzip
+
+const (
+	FileVersion = 1
+
+	// magic(3 bytes) + version(1 byte) + codec(1 byte)
+	fileHeaderSize = 5
+)
+
+// The first byte of a legacy file is the high byte of a big endian block size, it is never 'V',
+// so files without the magic are treated as uncompressed legacy files. Files of CodecNone are written without
+// the header, so they are the same as legacy files and can be served to peers which don't know the header.
+var fileMagic = []byte("VLF")
+
+var ErrUnknownCodec = errors.New("unknown codec")
+
+func (c Codec) String() string {
+	switch c {
+	case CodecNone:
+		return "none"
+	case CodecSnappy:
+		return "snappy"
+	case CodecGzip:
+		return "gzip"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(c))
+}
+
+func (c Codec) IsValid() bool {
+	return c == CodecNone || c == CodecSnappy || c == CodecGzip
+}
+
+// SupportedBy reports whether a peer advertising the bit set codecs decodes files of c, every peer decodes CodecNone.
+func (c Codec) SupportedBy(codecs uint32) bool {
+	return c == CodecNone || (c.IsValid() && codecs&(1<<c) != 0)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func fileHeader(codec Codec) []byte {
+	header := make([]byte, 0, fileHeaderSize)
+	header = append(header, fileMagic...)
+	header = append(header, FileVersion, byte(codec))
+	return header
+}
+
+// NewCodecWriter writes the file header into writer and returns a writer which encodes the block stream with codec,
+// CodecNone has no header. Close flushes the encoder but doesn't close the underlying writer.
+func NewCodecWriter(writer io.Writer, codec Codec) (io.WriteCloser, error) {
+	if !codec.IsValid() {
+		return nil, ErrUnknownCodec
+	}
+	if codec == CodecNone {
+		return nopWriteCloser{writer}, nil
+	}
+
+	if _, err := writer.Write(fileHeader(codec)); err != nil {
+		return nil, err
+	}
+
+	switch codec {
+	case CodecSnappy:
+		return snappy.NewBufferedWriter(writer), nil
+	case CodecGzip:
+		return gzip.NewWriter(writer), nil
+	}
+	return nil, ErrUnknownCodec
+}
+
+// NewCodecReader detects the file header of reader and returns a reader which decodes the block stream.
+// Legacy files without header are returned as CodecNone.
+func NewCodecReader(reader io.Reader) (io.Reader, Codec, error) {
+	bufReader := bufio.NewReader(reader)
+
+	header, err := bufReader.Peek(fileHeaderSize)
+	if err != nil && err != io.EOF {
+		return nil, CodecNone, err
+	}
+
+	if len(header) < fileHeaderSize || !bytes.Equal(header[:len(fileMagic)], fileMagic) {
+		return bufReader, CodecNone, nil
+	}
+
+	if version := header[len(fileMagic)]; version > FileVersion {
+		return nil, CodecNone, fmt.Errorf("unsupported file version %d", version)
+	}
+
+	codec := Codec(header[len(fileMagic)+1])
+	bufReader.Discard(fileHeaderSize)
+
+	switch codec {
+	case CodecNone:
+		return bufReader, codec, nil
+	case CodecSnappy:
+		return snappy.NewReader(bufReader), codec, nil
+	case CodecGzip:
+		gzipReader, err := gzip.NewReader(bufReader)
+		if err != nil {
+			return nil, codec, err
+		}
+		// A file is a single gzip member, don't wait for the next member on a network stream
+		gzipReader.Multistream(false)
+		return gzipReader, codec, nil
+	}
+	return nil, codec, ErrUnknownCodec
+}
+
+// FileHash is the blake2b-256 hash of the whole file content, including the file header.
+func FileHash(reader io.Reader) (types.Hash, error) {
+	hasher, _ := blake2b.New256(nil)
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return types.Hash{}, err
+	}
+	return types.BytesToHash(hasher.Sum(nil))
+}
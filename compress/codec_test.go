@@ -0,0 +1,115 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const legacyFile = "./subgraph_1_3600"
+
+func readLegacyBlocks(t *testing.T) []ledger.Block {
+	file, err := os.Open(legacyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var blocks []ledger.Block
+	BlockParser(file, 0, func(block ledger.Block, err error) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+	})
+	return blocks
+}
+
+func blockHash(block ledger.Block) types.Hash {
+	switch block.(type) {
+	case *ledger.AccountBlock:
+		return block.(*ledger.AccountBlock).Hash
+	case *ledger.SnapshotBlock:
+		return block.(*ledger.SnapshotBlock).Hash
+	}
+	return types.Hash{}
+}
+
+func TestCodec_SupportedBy(t *testing.T) {
+	for _, codec := range []Codec{CodecNone, CodecSnappy, CodecGzip} {
+		if !codec.SupportedBy(SupportedCodecs) {
+			t.Errorf("%s should be supported", codec)
+		}
+		// peers before the codec decode legacy files only
+		if codec.SupportedBy(0) != (codec == CodecNone) {
+			t.Errorf("%s: unexpected support of legacy peers", codec)
+		}
+	}
+	if Codec(3).SupportedBy(^uint32(0)) {
+		t.Error("unknown codec should not be supported")
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	blocks := readLegacyBlocks(t)
+	if len(blocks) <= 0 {
+		t.Fatal("no blocks in legacy file")
+	}
+	legacyInfo, err := os.Stat(legacyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, codec := range []Codec{CodecNone, CodecSnappy, CodecGzip} {
+		buffer := new(bytes.Buffer)
+		writer, err := NewCodecWriter(buffer, codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		written := false
+		if err := BlockFormatter(writer, func(uint64, uint64) ([]ledger.Block, error) {
+			if written {
+				return nil, io.EOF
+			}
+			written = true
+			return blocks, nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		writer.Close()
+
+		if codec == CodecNone && bytes.HasPrefix(buffer.Bytes(), fileMagic) {
+			t.Errorf("%s: the file has a header, legacy peers can't parse it", codec)
+		}
+		if codec != CodecNone && int64(buffer.Len()) >= legacyInfo.Size() {
+			t.Errorf("%s: encoded size %d is not smaller than legacy file", codec, buffer.Len())
+		}
+
+		index := 0
+		parseErr := BlockParser(buffer, 0, func(block ledger.Block, err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if index >= len(blocks) {
+				t.Fatalf("%s: parsed more blocks than written", codec)
+			}
+
+			if blockHash(block) != blockHash(blocks[index]) {
+				t.Fatalf("%s: block %d is not equal", codec, index)
+			}
+			index++
+		})
+		if parseErr != nil {
+			t.Fatal(parseErr)
+		}
+
+		if index != len(blocks) {
+			t.Errorf("%s: parsed %d blocks, expected %d", codec, index, len(blocks))
+		}
+	}
+}
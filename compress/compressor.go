@@ -29,7 +29,9 @@ type Compressor struct {
 	status     int // 0 is stop, 1 is start
 	statusLock sync.Mutex
 
-	dir string
+	dir            string
+	codec          Codec
+	verifyFileHash bool
 
 	chain Chain
 
@@ -46,6 +48,7 @@ func NewCompressor(chain Chain, dataDir string) *Compressor {
 		status:     STOPPED,
 		chain:      chain,
 		dir:        filepath.Join(dataDir, "ledger_files"),
+		codec:      DefaultCodec,
 
 		tickerDuration: time.Minute * 10,
 		log:            log15.New("module", "compressor"),
@@ -95,12 +98,51 @@ func (c *Compressor) Indexer() *Indexer {
 	return c.indexer
 }
 
+// SetCodec changes the codec of files created by subsequent tasks, existing files are kept as they are.
+func (c *Compressor) SetCodec(codec Codec) error {
+	if !codec.IsValid() {
+		return ErrUnknownCodec
+	}
+
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+
+	c.codec = codec
+	return nil
+}
+
+func (c *Compressor) Codec() Codec {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+
+	return c.codec
+}
+
+// DeleteFrom deletes the files which have the snapshot blocks from height, it's called when the blocks are deleted
+// from the ledger.
+func (c *Compressor) DeleteFrom(height uint64) {
+	c.indexer.Delete(height)
+}
+
+// SetVerifyFileHash makes the compressor verify the hash of every file when it starts, not only the last one.
+func (c *Compressor) SetVerifyFileHash(verify bool) {
+	c.statusLock.Lock()
+	defer c.statusLock.Unlock()
+
+	c.verifyFileHash = verify
+}
+
+// FileReader opens the file to serve it to peers, the file is verified against its hash first so a corrupted file
+// isn't served.
 func (c *Compressor) FileReader(filename string) (io.ReadCloser, error) {
+	if err := c.indexer.VerifyFile(filename); err != nil {
+		return nil, err
+	}
 	return NewFileReader(path.Join(c.dir, filename))
 }
 
-func (c *Compressor) BlockParser(reader io.Reader, blockNum uint64, processFunc func(block ledger.Block, err error)) {
-	BlockParser(reader, blockNum, processFunc)
+func (c *Compressor) BlockParser(reader io.Reader, blockNum uint64, processFunc func(block ledger.Block, err error)) error {
+	return BlockParser(reader, blockNum, processFunc)
 }
 
 func (c *Compressor) Start() bool {
@@ -119,7 +161,7 @@ func (c *Compressor) Start() bool {
 	c.wg.Add(1)
 
 	// repair indexer
-	c.indexer.CheckAndRepair()
+	c.indexer.CheckAndRepair(c.verifyFileHash)
 
 	common.Go(func() {
 		defer c.wg.Done()
@@ -152,9 +194,9 @@ func (c *Compressor) RunTask() {
 	c.indexer.checkAndRepairRollback()
 
 	tmpFileName := filepath.Join(c.dir, "subgraph_tmp")
-	task := NewCompressorTask(c.chain, tmpFileName, c.indexer.LatestHeight())
+	task := NewCompressorTask(c.chain, tmpFileName, c.indexer.LatestHeight(), c.codec)
 	if result := task.Run(); result.IsSuccess {
-		c.indexer.Add(result.Ti, tmpFileName, result.BlockNumbers)
+		c.indexer.Add(result.Ti, tmpFileName, result.BlockNumbers, result.Codec)
 	}
 	task.Clear()
 
@@ -8,7 +8,24 @@ import (
 
 var fileWriterLog = log15.New("module", "file_writer")
 
-func NewFileWriter(filename string) io.WriteCloser {
+type fileWriter struct {
+	file    *os.File
+	encoder io.WriteCloser
+}
+
+func (w *fileWriter) Write(p []byte) (int, error) {
+	return w.encoder.Write(p)
+}
+
+func (w *fileWriter) Close() error {
+	encoderErr := w.encoder.Close()
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return encoderErr
+}
+
+func NewFileWriter(filename string, codec Codec) io.WriteCloser {
 	file, err := os.Create(filename)
 
 	if err != nil {
@@ -16,5 +33,15 @@ func NewFileWriter(filename string) io.WriteCloser {
 		return nil
 	}
 
-	return file
+	encoder, err := NewCodecWriter(file, codec)
+	if err != nil {
+		fileWriterLog.Error("Create codec writer failed, error is " + err.Error())
+		file.Close()
+		return nil
+	}
+
+	return &fileWriter{
+		file:    file,
+		encoder: encoder,
+	}
 }
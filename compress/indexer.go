@@ -2,7 +2,10 @@ package compress
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/golang-lru"
+	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"io"
@@ -16,6 +19,9 @@ import (
 
 const INDEX_SEP = ",,,"
 
+// the count of the offset indexes kept in memory, an offset index of a file is loaded again after it's evicted
+const offsetIndexCacheSize = 32
+
 type Indexer struct {
 	file          *os.File
 	chainInstance Chain
@@ -23,6 +29,9 @@ type Indexer struct {
 	log           log15.Logger
 	lock          sync.RWMutex
 
+	// filename => *OffsetIndex
+	offsetIndexes *lru.Cache
+
 	dir string
 }
 
@@ -31,7 +40,9 @@ func NewIndexer(dir string, chainInstance Chain) *Indexer {
 	var file *os.File
 	var oErr error
 
+	offsetIndexes, _ := lru.New(offsetIndexCacheSize)
 	indexer := &Indexer{
+		offsetIndexes: offsetIndexes,
 		log:           log15.New("module", "compressor/indexer"),
 		chainInstance: chainInstance,
 		dir:           dir,
@@ -59,12 +70,42 @@ func NewIndexer(dir string, chainInstance Chain) *Indexer {
 	return indexer
 }
 
+// ReadIndex reads the metas of the files from the index file in dir without checking the files, os.IsNotExist(err)
+// is true if dir has no index file.
+func ReadIndex(dir string) ([]*ledger.CompressedFileMeta, error) {
+	file, err := os.Open(path.Join(dir, "index"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	indexer := &Indexer{
+		log: log15.New("module", "compressor/indexer"),
+		dir: dir,
+	}
+
+	var items []*ledger.CompressedFileMeta
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) <= 0 {
+			break
+		}
+		item, err := indexer.parseLine(scanner.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, scanner.Err()
+}
+
 func (indexer *Indexer) Clear() {
 	indexer.lock.Lock()
 	defer indexer.lock.Unlock()
 
 	indexer.file.Close()
 	indexer.file = nil
+	indexer.offsetIndexes.Purge()
 }
 
 func (indexer *Indexer) getFileSize(filename string) (int64, error) {
@@ -91,12 +132,25 @@ func (indexer *Indexer) getFileSize(filename string) (int64, error) {
 	return fileSize, nil
 }
 
+func (indexer *Indexer) getFileHash(filename string) (types.Hash, error) {
+	file, openErr := os.Open(filename)
+	if openErr != nil {
+		indexer.log.Error("Open file error, error is "+openErr.Error(), "method", "getFileHash")
+		return types.Hash{}, openErr
+	}
+	defer file.Close()
+
+	return FileHash(file)
+}
+
 func (indexer *Indexer) newFileName(startHeight uint64, endHeight uint64) string {
 	return "subgraph_" + strconv.FormatUint(startHeight, 10) + "_" + strconv.FormatUint(endHeight, 10)
 }
 
-func (indexer *Indexer) CheckAndRepair() {
+// CheckAndRepair deletes the broken files and the files after them, verifyAll verifies the hash of every file.
+func (indexer *Indexer) CheckAndRepair(verifyAll bool) {
 	indexer.checkAndRepairIndexErr()
+	indexer.checkAndRepairFileHash(verifyAll)
 	indexer.checkAndRepairRollback()
 	indexer.checkAndDeleteDataFile()
 }
@@ -135,6 +189,61 @@ func (indexer *Indexer) checkAndRepairIndexErr() {
 	}
 }
 
+// checkAndRepairFileHash deletes the first corrupted file and all files after it. The files indexed before hash was
+// introduced are hashed once and their hashes are persisted. If verifyAll isn't set, the other files are checked by
+// size except the last one, which is the only file a crash can leave half written, so it's verified by hash.
+func (indexer *Indexer) checkAndRepairFileHash(verifyAll bool) {
+	indexer.lock.RLock()
+	indexList := make([]*ledger.CompressedFileMeta, len(indexer.indexList))
+	copy(indexList, indexer.indexList)
+	indexer.lock.RUnlock()
+
+	needDeleteToHeight := uint64(0)
+	filledHashes := make(map[*ledger.CompressedFileMeta]types.Hash)
+
+	for i, item := range indexList {
+		if !verifyAll && !item.Hash.IsZero() && i < len(indexList)-1 {
+			if ok, err := indexer.check(item); err != nil || !ok {
+				indexer.log.Error(fmt.Sprintf("%s is corrupted, size is not %d", item.Filename, item.FileSize), "method", "checkAndRepairFileHash")
+				needDeleteToHeight = item.StartHeight
+				break
+			}
+			continue
+		}
+
+		fileHash, err := indexer.getFileHash(filepath.Join(indexer.dir, item.Filename))
+		if err != nil {
+			indexer.log.Error("getFileHash failed, error is "+err.Error(), "method", "checkAndRepairFileHash")
+			needDeleteToHeight = item.StartHeight
+			break
+		}
+
+		if item.Hash.IsZero() {
+			filledHashes[item] = fileHash
+		} else if item.Hash != fileHash {
+			indexer.log.Error(fmt.Sprintf("%s is corrupted, hash is %s, expected %s", item.Filename, fileHash, item.Hash), "method", "checkAndRepairFileHash")
+			needDeleteToHeight = item.StartHeight
+			break
+		}
+	}
+
+	if len(filledHashes) > 0 {
+		indexer.lock.Lock()
+		for item, fileHash := range filledHashes {
+			item.Hash = fileHash
+		}
+		if needDeleteToHeight <= 0 {
+			indexer.flushToFile()
+		}
+		indexer.lock.Unlock()
+	}
+
+	if needDeleteToHeight > 0 {
+		// Delete flushes the index file
+		indexer.Delete(needDeleteToHeight)
+	}
+}
+
 func (indexer *Indexer) checkAndRepairRollback() {
 	indexListLength := len(indexer.indexList)
 	if indexListLength <= 0 {
@@ -159,7 +268,8 @@ func (indexer *Indexer) checkAndDeleteDataFile() {
 	allFileNames := indexer.getAllFileNames()
 	for _, fileName := range allFileNames {
 		if strings.HasPrefix(fileName, "subgraph") {
-			if value := indexListMap[fileName]; value == 0 {
+			// offset index is kept with its data file
+			if value := indexListMap[strings.TrimSuffix(fileName, OffsetIndexSuffix)]; value == 0 {
 				indexer.delete(filepath.Join(indexer.dir, fileName))
 			}
 		}
@@ -258,6 +368,9 @@ func (indexer *Indexer) loadFromFile() (int64, error) {
 
 func (indexer *Indexer) parseLine(line []byte) (*ledger.CompressedFileMeta, error) {
 	segs := strings.Split(string(line), INDEX_SEP)
+	if len(segs) < 5 {
+		return nil, fmt.Errorf("line has %d segments, need at least 5", len(segs))
+	}
 
 	startHeight, err1 := strconv.ParseUint(segs[0], 10, 64)
 	if err1 != nil {
@@ -281,12 +394,32 @@ func (indexer *Indexer) parseLine(line []byte) (*ledger.CompressedFileMeta, erro
 		return nil, err4
 	}
 
+	// Lines written before codec was introduced have no codec segment, the file is uncompressed.
+	codec := CodecNone
+	if len(segs) > 5 {
+		codecNum, err5 := strconv.ParseUint(segs[5], 10, 8)
+		if err5 != nil {
+			return nil, err5
+		}
+		codec = Codec(codecNum)
+	}
+
+	var fileHash types.Hash
+	if len(segs) > 6 {
+		var err6 error
+		if fileHash, err6 = types.HexToHash(segs[6]); err6 != nil {
+			return nil, err6
+		}
+	}
+
 	item := &ledger.CompressedFileMeta{
 		StartHeight:  startHeight,
 		EndHeight:    endHeight,
 		Filename:     filename,
 		FileSize:     fileSize,
 		BlockNumbers: blockNumbers,
+		Codec:        uint8(codec),
+		Hash:         fileHash,
 	}
 	return item, nil
 }
@@ -297,7 +430,9 @@ func (indexer *Indexer) formatToLine(item *ledger.CompressedFileMeta) string {
 	lineString += strconv.FormatUint(item.EndHeight, 10) + INDEX_SEP
 	lineString += item.Filename + INDEX_SEP
 	lineString += strconv.FormatInt(item.FileSize, 10) + INDEX_SEP
-	lineString += strconv.FormatUint(item.BlockNumbers, 10)
+	lineString += strconv.FormatUint(item.BlockNumbers, 10) + INDEX_SEP
+	lineString += strconv.FormatUint(uint64(item.Codec), 10) + INDEX_SEP
+	lineString += item.Hash.String()
 	return lineString
 }
 
@@ -312,6 +447,42 @@ func (indexer *Indexer) LatestHeight() uint64 {
 	return 0
 }
 
+// VerifyFile checks the file against the hash in its meta, the files without hash are checked by size.
+func (indexer *Indexer) VerifyFile(filename string) error {
+	indexer.lock.RLock()
+	var item *ledger.CompressedFileMeta
+	for _, meta := range indexer.indexList {
+		if meta.Filename == filename {
+			item = meta
+			break
+		}
+	}
+	indexer.lock.RUnlock()
+
+	if item == nil {
+		return errors.New(fmt.Sprintf("%s is not indexed", filename))
+	}
+
+	if item.Hash.IsZero() {
+		if ok, err := indexer.check(item); err != nil {
+			return err
+		} else if !ok {
+			return errors.New(fmt.Sprintf("%s is corrupted, size is not %d", filename, item.FileSize))
+		}
+		return nil
+	}
+
+	fileHash, err := indexer.getFileHash(filepath.Join(indexer.dir, filename))
+	if err != nil {
+		return err
+	}
+	if fileHash != item.Hash {
+		indexer.log.Error(fmt.Sprintf("%s is corrupted, hash is %s, expected %s", filename, fileHash, item.Hash), "method", "VerifyFile")
+		return errors.New(fmt.Sprintf("%s is corrupted, hash is %s, expected %s", filename, fileHash, item.Hash))
+	}
+	return nil
+}
+
 func (indexer *Indexer) Get(startBlockHeight uint64, endBlockHeight uint64) []*ledger.CompressedFileMeta {
 	indexer.log.Info(fmt.Sprintf("!!!%d - %d", startBlockHeight, endBlockHeight), "method", "indexer.GET")
 	indexer.lock.RLock()
@@ -387,13 +558,16 @@ func (indexer *Indexer) Delete(toHeight uint64) {
 		return
 	}
 
+	for _, item := range indexer.indexList[needDeleteIndex:] {
+		indexer.offsetIndexes.Remove(item.Filename)
+	}
 	indexer.indexList = indexer.indexList[:needDeleteIndex]
 	indexer.flushToFile()
 	indexer.checkAndDeleteDataFile()
 	return
 }
 
-func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) error {
+func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64, codec Codec) error {
 	indexer.lock.Lock()
 	defer indexer.lock.Unlock()
 
@@ -405,6 +579,13 @@ func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) e
 		}
 	}
 
+	// the blocks may be deleted while the task runs
+	if latestSb := indexer.chainInstance.GetLatestSnapshotBlock(); latestSb == nil || ti.targetHeight > latestSb.Height {
+		indexer.log.Error("ti.targetHeight is higher than the latest snapshot block", "method", "Add")
+		os.Remove(tmpFile)
+		return nil
+	}
+
 	newFileName := indexer.newFileName(ti.beginHeight, ti.targetHeight)
 	newAbsoluteFileName := filepath.Join(indexer.dir, newFileName)
 
@@ -424,6 +605,12 @@ func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) e
 		return fileSizeErr
 	}
 
+	fileHash, fileHashErr := indexer.getFileHash(newAbsoluteFileName)
+	if fileHashErr != nil {
+		indexer.log.Error("getFileHash failed, error is "+fileHashErr.Error(), "method", "Add")
+		return fileHashErr
+	}
+
 	newItem := &ledger.CompressedFileMeta{
 		StartHeight: ti.beginHeight,
 		EndHeight:   ti.targetHeight,
@@ -432,6 +619,13 @@ func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) e
 
 		FileSize:     fileSize,
 		BlockNumbers: blockNumbers,
+		Codec:        uint8(codec),
+		Hash:         fileHash,
+	}
+
+	if _, err := indexer.buildOffsetIndex(newItem); err != nil {
+		// offset index will be rebuilt when it is used
+		indexer.log.Error("buildOffsetIndex failed, error is "+err.Error(), "method", "Add")
 	}
 
 	_, writeErr := indexer.file.WriteString(indexer.formatToLine(newItem) + "\n")
@@ -445,3 +639,63 @@ func (indexer *Indexer) Add(ti *taskInfo, tmpFile string, blockNumbers uint64) e
 
 	return nil
 }
+
+func (indexer *Indexer) buildOffsetIndex(item *ledger.CompressedFileMeta) (*OffsetIndex, error) {
+	file, openErr := os.Open(filepath.Join(indexer.dir, item.Filename))
+	if openErr != nil {
+		return nil, openErr
+	}
+	defer file.Close()
+
+	oi, buildErr := BuildOffsetIndex(file)
+	if buildErr != nil {
+		return nil, buildErr
+	}
+
+	offsetIndexFileName := filepath.Join(indexer.dir, item.Filename+OffsetIndexSuffix)
+	tmpFileName := offsetIndexFileName + "_tmp"
+
+	tmpFile, createErr := os.Create(tmpFileName)
+	if createErr != nil {
+		return nil, createErr
+	}
+
+	_, writeErr := oi.WriteTo(tmpFile)
+	tmpFile.Close()
+
+	if writeErr != nil {
+		os.Remove(tmpFileName)
+		return nil, writeErr
+	}
+
+	if renameErr := os.Rename(tmpFileName, offsetIndexFileName); renameErr != nil {
+		os.Remove(tmpFileName)
+		return nil, renameErr
+	}
+	indexer.offsetIndexes.Add(item.Filename, oi)
+	return oi, nil
+}
+
+// OffsetIndex returns the offset index of item from the cache or loads it, the offset index is rebuilt if it is
+// missing or corrupted.
+func (indexer *Indexer) OffsetIndex(item *ledger.CompressedFileMeta) (*OffsetIndex, error) {
+	if oi, ok := indexer.offsetIndexes.Get(item.Filename); ok {
+		return oi.(*OffsetIndex), nil
+	}
+
+	file, openErr := os.Open(filepath.Join(indexer.dir, item.Filename+OffsetIndexSuffix))
+	if openErr == nil {
+		oi, readErr := ReadOffsetIndex(file)
+		file.Close()
+
+		if readErr == nil {
+			indexer.offsetIndexes.Add(item.Filename, oi)
+			return oi, nil
+		}
+		indexer.log.Error("ReadOffsetIndex failed, error is "+readErr.Error(), "method", "OffsetIndex")
+	} else if !os.IsNotExist(openErr) {
+		return nil, openErr
+	}
+
+	return indexer.buildOffsetIndex(item)
+}
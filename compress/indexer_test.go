@@ -1,8 +1,16 @@
 package compress
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
 )
 
 func TestNewIndexer(t *testing.T) {
@@ -10,3 +18,78 @@ func TestNewIndexer(t *testing.T) {
 	fmt.Println(a[:3])
 	fmt.Println(a[3:])
 }
+
+func TestIndexer_ParseLine(t *testing.T) {
+	indexer := &Indexer{}
+
+	item := &ledger.CompressedFileMeta{
+		StartHeight:  1,
+		EndHeight:    3600,
+		Filename:     "subgraph_1_3600",
+		FileSize:     889589,
+		BlockNumbers: 3601,
+		Codec:        uint8(CodecSnappy),
+		Hash:         types.DataHash([]byte("subgraph_1_3600")),
+	}
+
+	parsedItem, err := indexer.parseLine([]byte(indexer.formatToLine(item)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsedItem != *item {
+		t.Fatalf("parsed item is %+v, expected %+v", parsedItem, item)
+	}
+
+	// line written before codec and hash
+	legacyItem, err := indexer.parseLine([]byte("1,,,3600,,,subgraph_1_3600,,,889589,,,3601"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legacyItem.Codec != uint8(CodecNone) || !legacyItem.Hash.IsZero() {
+		t.Fatalf("legacy item is %+v", legacyItem)
+	}
+}
+
+func TestIndexer_VerifyFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "indexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := []byte("subgraph_1_3600")
+	if err := ioutil.WriteFile(filepath.Join(dir, "subgraph_1_3600"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := FileHash(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	indexer := &Indexer{
+		dir: dir,
+		log: log15.New("module", "compressor/indexer"),
+		indexList: []*ledger.CompressedFileMeta{{
+			StartHeight: 1,
+			EndHeight:   3600,
+			Filename:    "subgraph_1_3600",
+			FileSize:    int64(len(data)),
+			Hash:        hash,
+		}},
+	}
+	if err := indexer.VerifyFile("subgraph_1_3600"); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.VerifyFile("subgraph_3601_7200"); err == nil {
+		t.Fatal("the file not indexed should be rejected")
+	}
+
+	// a flipped bit keeps the size
+	data[0] ^= 1
+	if err := ioutil.WriteFile(filepath.Join(dir, "subgraph_1_3600"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := indexer.VerifyFile("subgraph_1_3600"); err == nil {
+		t.Fatal("the corrupted file should be rejected")
+	}
+}
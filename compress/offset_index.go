@@ -0,0 +1,387 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/golang/snappy"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+const (
+	// the sidecar file of subgraph_1_3600 is subgraph_1_3600.idx
+	OffsetIndexSuffix = ".idx"
+
+	OffsetIndexVersion = 2
+
+	offsetTypeSnapshotBlock = 1
+	offsetTypeAccountBlock  = 2
+
+	// magic(3 bytes) + version(1 byte)
+	offsetIndexHeaderSize = 4
+	// record count(8 bytes) + crc32 of the preceding bytes(4 bytes)
+	offsetIndexFooterSize = 12
+)
+
+var offsetIndexMagic = []byte("VOI")
+
+// the stream identifier chunk starts every snappy stream, it's prepended when a stream is read from a chunk
+var snappyStreamIdentifier = []byte("\xff\x06\x00\x00sNaPpY")
+
+const (
+	snappyChunkCompressed   = 0x00
+	snappyChunkUncompressed = 0x01
+	snappyChunkIdentifier   = 0xff
+	// the chunks from 0x80 to 0xfe are skippable
+	snappyChunkSkippable = 0x80
+)
+
+var ErrOffsetIndexCorrupted = errors.New("offset index is corrupted")
+
+// BlockPosition locates a block in a file. Frame is the file offset of the snappy chunk the block starts in, and
+// Offset is the offset of the block in the decoded stream from the chunk on. The other codecs have no chunks, Frame
+// is 0 and Offset is the offset in the whole decoded stream.
+type BlockPosition struct {
+	Frame  uint64
+	Offset uint64
+}
+
+type snapshotOffset struct {
+	height   uint64
+	position BlockPosition
+}
+
+// OffsetIndex maps snapshot height and account block hash to the position of the block in a file.
+type OffsetIndex struct {
+	snapshotOffsets []*snapshotOffset
+	accountOffsets  map[types.Hash]BlockPosition
+}
+
+func newOffsetIndex() *OffsetIndex {
+	return &OffsetIndex{
+		accountOffsets: make(map[types.Hash]BlockPosition),
+	}
+}
+
+// snappyFrame is a data chunk of a snappy stream, fileOffset is the offset of the chunk in the file and
+// decodedOffset is the offset of its data in the decoded stream.
+type snappyFrame struct {
+	fileOffset    uint64
+	decodedOffset uint64
+}
+
+// scanSnappyFrames returns the data chunks of the snappy stream of reader, which starts at fileOffset of the file.
+// Only the chunk headers and the decoded lengths are read, the data isn't decoded.
+func scanSnappyFrames(reader io.Reader, fileOffset uint64) ([]*snappyFrame, error) {
+	bufReader := bufio.NewReader(reader)
+
+	var frames []*snappyFrame
+	decodedOffset := uint64(0)
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(bufReader, header); err != nil {
+			if err == io.EOF {
+				return frames, nil
+			}
+			return nil, err
+		}
+		chunkType := header[0]
+		chunkLen := int(header[1]) | int(header[2])<<8 | int(header[3])<<16
+
+		switch {
+		case chunkType == snappyChunkCompressed || chunkType == snappyChunkUncompressed:
+			// crc32(4 bytes) + data
+			if chunkLen < 4 {
+				return nil, snappy.ErrCorrupt
+			}
+			body := make([]byte, chunkLen)
+			if _, err := io.ReadFull(bufReader, body); err != nil {
+				return nil, err
+			}
+
+			decodedLen := chunkLen - 4
+			if chunkType == snappyChunkCompressed {
+				var err error
+				if decodedLen, err = snappy.DecodedLen(body[4:]); err != nil {
+					return nil, err
+				}
+			}
+			frames = append(frames, &snappyFrame{fileOffset: fileOffset, decodedOffset: decodedOffset})
+			decodedOffset += uint64(decodedLen)
+
+		case chunkType == snappyChunkIdentifier || chunkType >= snappyChunkSkippable:
+			if _, err := bufReader.Discard(chunkLen); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, snappy.ErrUnsupported
+		}
+		fileOffset += uint64(4 + chunkLen)
+	}
+}
+
+// BuildOffsetIndex parses the whole file of reader, reader is a raw file with file header.
+func BuildOffsetIndex(reader io.ReadSeeker) (*OffsetIndex, error) {
+	decoder, codec, err := NewCodecReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var decodedOffsets []uint64
+	var blocks []ledger.Block
+	var parseErr error
+	streamErr := parseBlockStream(decoder, 0, func(block ledger.Block, offset uint64, err error) bool {
+		if err != nil {
+			parseErr = err
+			return false
+		}
+
+		blocks = append(blocks, block)
+		decodedOffsets = append(decodedOffsets, offset)
+		return true
+	})
+	if parseErr == nil {
+		parseErr = streamErr
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	var frames []*snappyFrame
+	if codec == CodecSnappy {
+		if _, err := reader.Seek(fileHeaderSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if frames, err = scanSnappyFrames(reader, fileHeaderSize); err != nil {
+			return nil, err
+		}
+	}
+
+	oi := newOffsetIndex()
+	for i, block := range blocks {
+		position := BlockPosition{Offset: decodedOffsets[i]}
+		if len(frames) > 0 {
+			index := sort.Search(len(frames), func(j int) bool {
+				return frames[j].decodedOffset > position.Offset
+			}) - 1
+			if index < 0 {
+				return nil, ErrOffsetIndexCorrupted
+			}
+			position = BlockPosition{
+				Frame:  frames[index].fileOffset,
+				Offset: position.Offset - frames[index].decodedOffset,
+			}
+		}
+
+		switch block.(type) {
+		case *ledger.SnapshotBlock:
+			oi.snapshotOffsets = append(oi.snapshotOffsets, &snapshotOffset{
+				height:   block.(*ledger.SnapshotBlock).Height,
+				position: position,
+			})
+		case *ledger.AccountBlock:
+			oi.accountOffsets[block.(*ledger.AccountBlock).Hash] = position
+		}
+	}
+
+	sort.Slice(oi.snapshotOffsets, func(i, j int) bool {
+		return oi.snapshotOffsets[i].height < oi.snapshotOffsets[j].height
+	})
+
+	return oi, nil
+}
+
+// SnapshotBlockOffset returns the position of the first snapshot block whose height is not less than height.
+func (oi *OffsetIndex) SnapshotBlockOffset(height uint64) (BlockPosition, bool) {
+	index := sort.Search(len(oi.snapshotOffsets), func(i int) bool {
+		return oi.snapshotOffsets[i].height >= height
+	})
+
+	if index >= len(oi.snapshotOffsets) {
+		return BlockPosition{}, false
+	}
+	return oi.snapshotOffsets[index].position, true
+}
+
+func (oi *OffsetIndex) AccountBlockOffset(hash *types.Hash) (BlockPosition, bool) {
+	position, ok := oi.accountOffsets[*hash]
+	return position, ok
+}
+
+// File format: magic(3 bytes) + version(1 byte) + records + record count(8 bytes) + crc32 of the preceding bytes(4 bytes)
+// Record format: type(1 byte) + snapshot height(8 bytes) or account block hash(32 bytes) + frame(8 bytes) + offset(8 bytes)
+func (oi *OffsetIndex) WriteTo(writer io.Writer) (int64, error) {
+	bufWriter := bufio.NewWriter(writer)
+	checksum := crc32.NewIEEE()
+	multiWriter := io.MultiWriter(bufWriter, checksum)
+	var written int64
+
+	write := func(data []byte) error {
+		n, err := multiWriter.Write(data)
+		written += int64(n)
+		return err
+	}
+
+	if err := write(append(append([]byte{}, offsetIndexMagic...), OffsetIndexVersion)); err != nil {
+		return written, err
+	}
+
+	record := make([]byte, 1+types.HashSize+16)
+	for _, item := range oi.snapshotOffsets {
+		record[0] = offsetTypeSnapshotBlock
+		binary.BigEndian.PutUint64(record[1:9], item.height)
+		binary.BigEndian.PutUint64(record[9:17], item.position.Frame)
+		binary.BigEndian.PutUint64(record[17:25], item.position.Offset)
+
+		if err := write(record[:25]); err != nil {
+			return written, err
+		}
+	}
+
+	for hash, position := range oi.accountOffsets {
+		record[0] = offsetTypeAccountBlock
+		copy(record[1:1+types.HashSize], hash.Bytes())
+		binary.BigEndian.PutUint64(record[1+types.HashSize:9+types.HashSize], position.Frame)
+		binary.BigEndian.PutUint64(record[9+types.HashSize:], position.Offset)
+
+		if err := write(record); err != nil {
+			return written, err
+		}
+	}
+
+	if err := write(uint64ToBytes(uint64(len(oi.snapshotOffsets) + len(oi.accountOffsets)))); err != nil {
+		return written, err
+	}
+	n, err := bufWriter.Write(checksum.Sum(nil))
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	return written, bufWriter.Flush()
+}
+
+// ReadOffsetIndex reads the offset index written by WriteTo, a truncated or damaged index, or an index of the former
+// versions, is reported as ErrOffsetIndexCorrupted.
+func ReadOffsetIndex(reader io.Reader) (*OffsetIndex, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < offsetIndexHeaderSize+offsetIndexFooterSize ||
+		!bytes.Equal(data[:len(offsetIndexMagic)], offsetIndexMagic) || data[len(offsetIndexMagic)] != OffsetIndexVersion {
+		return nil, ErrOffsetIndexCorrupted
+	}
+	checksumStart := len(data) - 4
+	if crc32.ChecksumIEEE(data[:checksumStart]) != binary.BigEndian.Uint32(data[checksumStart:]) {
+		return nil, ErrOffsetIndexCorrupted
+	}
+	recordCount := binary.BigEndian.Uint64(data[checksumStart-8 : checksumStart])
+
+	oi := newOffsetIndex()
+	records := data[offsetIndexHeaderSize : checksumStart-8]
+	for count := uint64(0); len(records) > 0; count++ {
+		if count >= recordCount {
+			return nil, ErrOffsetIndexCorrupted
+		}
+
+		switch records[0] {
+		case offsetTypeSnapshotBlock:
+			if len(records) < 25 {
+				return nil, ErrOffsetIndexCorrupted
+			}
+			oi.snapshotOffsets = append(oi.snapshotOffsets, &snapshotOffset{
+				height: binary.BigEndian.Uint64(records[1:9]),
+				position: BlockPosition{
+					Frame:  binary.BigEndian.Uint64(records[9:17]),
+					Offset: binary.BigEndian.Uint64(records[17:25]),
+				},
+			})
+			records = records[25:]
+
+		case offsetTypeAccountBlock:
+			if len(records) < 1+types.HashSize+16 {
+				return nil, ErrOffsetIndexCorrupted
+			}
+			hash, _ := types.BytesToHash(records[1 : 1+types.HashSize])
+			oi.accountOffsets[hash] = BlockPosition{
+				Frame:  binary.BigEndian.Uint64(records[1+types.HashSize : 9+types.HashSize]),
+				Offset: binary.BigEndian.Uint64(records[9+types.HashSize : 17+types.HashSize]),
+			}
+			records = records[1+types.HashSize+16:]
+
+		default:
+			return nil, ErrOffsetIndexCorrupted
+		}
+	}
+
+	if uint64(len(oi.snapshotOffsets)+len(oi.accountOffsets)) != recordCount {
+		return nil, ErrOffsetIndexCorrupted
+	}
+	return oi, nil
+}
+
+func uint64ToBytes(value uint64) []byte {
+	result := make([]byte, 8)
+	binary.BigEndian.PutUint64(result, value)
+	return result
+}
+
+// openBlockStreamAt returns the decoded block stream of filename which starts at position. The uncompressed files
+// are seeked to the block, the snappy files are decoded from the chunk of the block, and the gzip files, which have
+// no chunks, are decoded from the beginning.
+func openBlockStreamAt(filename string, position BlockPosition) (io.Reader, io.Closer, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoder, codec, err := NewCodecReader(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	switch codec {
+	case CodecNone:
+		// uncompressed stream can seek directly, legacy files have no header
+		headerSize := int64(0)
+		header := make([]byte, fileHeaderSize)
+		if _, err := file.ReadAt(header, 0); err == nil && bytes.Equal(header[:len(fileMagic)], fileMagic) {
+			headerSize = fileHeaderSize
+		}
+
+		if _, err := file.Seek(headerSize+int64(position.Offset), io.SeekStart); err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return file, file, nil
+
+	case CodecSnappy:
+		if position.Frame < fileHeaderSize {
+			file.Close()
+			return nil, nil, ErrOffsetIndexCorrupted
+		}
+		if _, err := file.Seek(int64(position.Frame), io.SeekStart); err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		decoder = snappy.NewReader(io.MultiReader(bytes.NewReader(snappyStreamIdentifier), bufio.NewReader(file)))
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, decoder, int64(position.Offset)); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return decoder, file, nil
+}
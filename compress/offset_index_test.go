@@ -0,0 +1,108 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+func writeTestFile(t *testing.T, filename string, codec Codec, blocks []ledger.Block) {
+	writer := NewFileWriter(filename, codec)
+	if writer == nil {
+		t.Fatal("NewFileWriter failed")
+	}
+	defer writer.Close()
+
+	written := false
+	if err := BlockFormatter(writer, func(uint64, uint64) ([]ledger.Block, error) {
+		if written {
+			return nil, io.EOF
+		}
+		written = true
+		return blocks, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOffsetIndex(t *testing.T) {
+	blocks := readLegacyBlocks(t)
+
+	dir, err := ioutil.TempDir("", "offset_index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, codec := range []Codec{CodecNone, CodecSnappy, CodecGzip} {
+		filename := filepath.Join(dir, "subgraph_"+codec.String())
+		writeTestFile(t, filename, codec, blocks)
+
+		file, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oi, err := BuildOffsetIndex(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := new(bytes.Buffer)
+		if _, err := oi.WriteTo(buffer); err != nil {
+			t.Fatal(err)
+		}
+		data := buffer.Bytes()
+		// an index truncated on a record boundary is detected by the footer
+		recordLen := 1 + types.HashSize + 16
+		if _, err := ReadOffsetIndex(bytes.NewReader(data[:len(data)-offsetIndexFooterSize-recordLen])); err != ErrOffsetIndexCorrupted {
+			t.Fatalf("%s: the truncated index should be corrupted, error is %v", codec, err)
+		}
+		if oi, err = ReadOffsetIndex(bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, block := range blocks {
+			// gzip file is decoded from beginning, which is slow, check a part of blocks
+			if codec == CodecGzip && i%500 != 0 || i%50 != 0 {
+				continue
+			}
+
+			var position BlockPosition
+			var ok bool
+			switch block.(type) {
+			case *ledger.SnapshotBlock:
+				position, ok = oi.SnapshotBlockOffset(block.(*ledger.SnapshotBlock).Height)
+			case *ledger.AccountBlock:
+				position, ok = oi.AccountBlockOffset(&block.(*ledger.AccountBlock).Hash)
+			}
+			if !ok {
+				t.Fatalf("%s: position of %s not found", codec, blockHash(block))
+			}
+			if codec == CodecSnappy && position.Offset >= 1<<16 {
+				t.Fatalf("%s: block %s is %d bytes after its chunk", codec, blockHash(block), position.Offset)
+			}
+
+			reader, closer, err := openBlockStreamAt(filename, position)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parseBlockStream(reader, 1, func(parsedBlock ledger.Block, offset uint64, err error) bool {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if blockHash(parsedBlock) != blockHash(block) {
+					t.Fatalf("%s: block at offset %d is %s, expected %s", codec, offset, blockHash(parsedBlock), blockHash(block))
+				}
+				return false
+			})
+			closer.Close()
+		}
+	}
+}
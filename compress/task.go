@@ -11,6 +11,10 @@ var compressorTaskLog = log15.New("module", "compressorTask")
 
 const writeMax = 1024 * 1024 * 200 // 200M
 
+// MaxFileSize is the upper bound of a compressed file, a file stops growing once writeMax is reached, so only the last
+// blocks written may exceed writeMax.
+const MaxFileSize = 2 * writeMax
+
 type taskInfo struct {
 	beginHeight  uint64
 	targetHeight uint64
@@ -45,14 +49,16 @@ type CompressorTask struct {
 	indexerHeight  uint64
 	startHeightGap uint64
 	taskGap        uint64
+	codec          Codec
 	log            log15.Logger
 }
 
-func NewCompressorTask(chain Chain, tmpFile string, indexerHeight uint64) *CompressorTask {
+func NewCompressorTask(chain Chain, tmpFile string, indexerHeight uint64, codec Codec) *CompressorTask {
 	compressorTask := &CompressorTask{
 		splitSize: 10,
 		chain:     chain,
 		tmpFile:   tmpFile,
+		codec:     codec,
 		log:       log15.New("module", "compressor/task"),
 
 		indexerHeight:  indexerHeight,
@@ -67,6 +73,7 @@ type TaskRunResult struct {
 	Ti           *taskInfo
 	IsSuccess    bool
 	BlockNumbers uint64
+	Codec        Codec
 }
 
 func (task *CompressorTask) Run() *TaskRunResult {
@@ -85,7 +92,14 @@ func (task *CompressorTask) Run() *TaskRunResult {
 	taskLen := len(taskInfoList)
 	currentTaskIndex := 0
 
-	tmpFileWriter := NewFileWriter(task.tmpFile)
+	tmpFileWriter := NewFileWriter(task.tmpFile, task.codec)
+	if tmpFileWriter == nil {
+		return &TaskRunResult{
+			Ti:           ti,
+			IsSuccess:    false,
+			BlockNumbers: 0,
+		}
+	}
 	var blockNumbers = uint64(0)
 
 	// Limit write length
@@ -119,6 +133,7 @@ func (task *CompressorTask) Run() *TaskRunResult {
 		Ti:           ti,
 		IsSuccess:    true,
 		BlockNumbers: blockNumbers,
+		Codec:        task.codec,
 	}
 }
 
@@ -1,15 +1,38 @@
 package config
 
+// KafkaProducer sends the block events to a kafka topic, Format is "json" or "proto", the default is "json".
 type KafkaProducer struct {
 	BrokerList []string
 	Topic      string
+	Format     string
+}
+
+// EventSink is a file or a webhook the block events are sent to, Kind is "file" or "webhook".
+type EventSink struct {
+	Kind string
+	Path string
+	Url  string
 }
 
 type Chain struct {
-	KafkaProducers       []*KafkaProducer
-	OpenBlackBlock       bool
-	LedgerGcRetain       uint64
-	GenesisFile          string
-	LedgerGc             bool
-	OpenFilterTokenIndex bool
+	KafkaProducers        []*KafkaProducer
+	EventSinks            []*EventSink
+	OpenBlackBlock        bool
+	LedgerGcRetain        uint64
+	GenesisFile           string
+	LedgerGc              bool
+	OpenFilterTokenIndex  bool
+	OpenFlatState         bool
+	OpenCounterpartyIndex bool
+	OpenVmLogIndex        bool
+	OpenTimeIndex         bool
+	OpenTokenHolderIndex  bool
+
+	// ArchiveFallback reads the blocks missing from the database from the compressed ledger files, for the nodes
+	// whose database doesn't keep every block. The blocks read from the files have no meta.
+	ArchiveFallback bool
+
+	// VerifyLedgerFiles verifies the hash of every compressed ledger file when the compressor starts, otherwise
+	// only the last file is verified by hash and the others by size.
+	VerifyLedgerFiles bool
 }
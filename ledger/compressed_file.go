@@ -2,6 +2,7 @@ package ledger
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/vitepb"
 )
 
@@ -13,6 +14,12 @@ type CompressedFileMeta struct {
 	FileSize int64
 
 	BlockNumbers uint64
+
+	// Codec of the block stream, 0 means uncompressed
+	Codec uint8
+
+	// Hash of the whole file content, zero hash means unknown
+	Hash types.Hash
 }
 
 func (f *CompressedFileMeta) Serialize() ([]byte, error) {
@@ -32,13 +39,18 @@ func (f *CompressedFileMeta) Deserialize(buf []byte) error {
 }
 
 func (f *CompressedFileMeta) Proto() *vitepb.CompressedFileMeta {
-	return &vitepb.CompressedFileMeta{
+	pb := &vitepb.CompressedFileMeta{
 		StartHeight:  f.StartHeight,
 		EndHeight:    f.EndHeight,
 		Filename:     f.Filename,
 		FileSize:     f.FileSize,
 		BlockNumbers: f.BlockNumbers,
+		Codec:        uint32(f.Codec),
+	}
+	if !f.Hash.IsZero() {
+		pb.Hash = f.Hash.Bytes()
 	}
+	return pb
 }
 
 func (f *CompressedFileMeta) Deproto(pb *vitepb.CompressedFileMeta) {
@@ -47,4 +59,6 @@ func (f *CompressedFileMeta) Deproto(pb *vitepb.CompressedFileMeta) {
 	f.Filename = pb.Filename
 	f.FileSize = pb.FileSize
 	f.BlockNumbers = pb.BlockNumbers
+	f.Codec = uint8(pb.Codec)
+	f.Hash, _ = types.BytesToHash(pb.Hash)
 }
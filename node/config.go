@@ -24,6 +24,7 @@ import (
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/p2p"
 	"github.com/vitelabs/go-vite/p2p/network"
+	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/wallet"
 )
 
@@ -32,14 +33,23 @@ type Config struct {
 
 	KeyStoreDir string `json:"KeyStoreDir"`
 
-	// template：["broker1,broker2,...|topic",""]
+	// template：["broker1,broker2,...|topic","broker1,broker2,...|topic|proto"], the messages are json by default
 	KafkaProducers []string `json:"KafkaProducers"`
+	// template：["file|path","webhook|url"]
+	EventSinks []string `json:"EventSinks"`
 
 	// chain
-	OpenBlackBlock       bool   `json:"OpenBlackBlock"`
-	LedgerGcRetain       uint64 `json:"LedgerGcRetain"`
-	LedgerGc             *bool  `json:"LedgerGc"`
-	OpenFilterTokenIndex *bool  `json:"OpenFilterTokenIndex"`
+	OpenBlackBlock        bool   `json:"OpenBlackBlock"`
+	LedgerGcRetain        uint64 `json:"LedgerGcRetain"`
+	LedgerGc              *bool  `json:"LedgerGc"`
+	OpenFilterTokenIndex  *bool  `json:"OpenFilterTokenIndex"`
+	OpenFlatState         *bool  `json:"OpenFlatState"`
+	OpenCounterpartyIndex *bool  `json:"OpenCounterpartyIndex"`
+	OpenVmLogIndex        *bool  `json:"OpenVmLogIndex"`
+	OpenTimeIndex         *bool  `json:"OpenTimeIndex"`
+	OpenTokenHolderIndex  *bool  `json:"OpenTokenHolderIndex"`
+	ArchiveFallback       bool   `json:"ArchiveFallback"`
+	VerifyLedgerFiles     bool   `json:"VerifyLedgerFiles"`
 
 	// genesis
 	GenesisFile string `json:"GenesisFile"`
@@ -76,6 +86,16 @@ type Config struct {
 	WSHost           string   `json:"WSHost"`
 	WSPort           int      `json:"WSPort"`
 
+	// the gRPC endpoint serving the services of vitepb/api.proto
+	GRPCEnabled bool   `json:"GRPCEnabled"`
+	GRPCHost    string `json:"GRPCHost"`
+	GRPCPort    int    `json:"GRPCPort"`
+
+	// the REST gateway of the read-only ledger queries, it shares HTTPCors and HttpVirtualHosts with the HTTP endpoint
+	RESTEnabled bool   `json:"RESTEnabled"`
+	RESTHost    string `json:"RESTHost"`
+	RESTPort    int    `json:"RESTPort"`
+
 	HTTPCors            []string `json:"HTTPCors"`
 	WSOrigins           []string `json:"WSOrigins"`
 	PublicModules       []string `json:"PublicModules"`
@@ -84,6 +104,13 @@ type Config struct {
 	TestTokenHexPrivKey string   `json:"TestTokenHexPrivKey"`
 	TestTokenTti        string   `json:"TestTokenTti"`
 
+	// the authentication of the HTTP and WS endpoints, disabled if nil
+	RPCAuth *rpc.AuthConfig `json:"RPCAuth"`
+	// the rate limits of the HTTP and WS endpoints, disabled if nil
+	RPCRateLimit *rpc.RateLimitConfig `json:"RPCRateLimit"`
+	// the file which the rpc_discover document of all the apis is written to on startup, not written if empty
+	RPCDiscoverFile string `json:"RPCDiscoverFile"`
+
 	PowServerUrl string `json:"PowServerUrl”`
 
 	//Log level
@@ -350,7 +377,7 @@ func (c *Config) makeChainConfig() *config.Chain {
 	if len(c.KafkaProducers) > 0 {
 		for i, kafkaProducer := range c.KafkaProducers {
 			splitKafkaProducer := strings.Split(kafkaProducer, "|")
-			if len(splitKafkaProducer) != 2 {
+			if len(splitKafkaProducer) != 2 && len(splitKafkaProducer) != 3 {
 				log.Warn(fmt.Sprintf("KafkaProducers is setting error，The program will skip here and continue processing"))
 				break
 			}
@@ -365,6 +392,28 @@ func (c *Config) makeChainConfig() *config.Chain {
 				BrokerList: splitKafkaBroker,
 				Topic:      splitKafkaProducer[1],
 			}
+			if len(splitKafkaProducer) == 3 {
+				kafkaProducers[i].Format = splitKafkaProducer[2]
+			}
+		}
+	}
+
+	// init eventSinks
+	var eventSinks []*config.EventSink
+	for _, eventSink := range c.EventSinks {
+		splitEventSink := strings.SplitN(eventSink, "|", 2)
+		if len(splitEventSink) != 2 || splitEventSink[1] == "" {
+			log.Warn(fmt.Sprintf("EventSinks %s is setting error，The program will skip here and continue processing", eventSink))
+			continue
+		}
+
+		switch splitEventSink[0] {
+		case "file":
+			eventSinks = append(eventSinks, &config.EventSink{Kind: "file", Path: splitEventSink[1]})
+		case "webhook":
+			eventSinks = append(eventSinks, &config.EventSink{Kind: "webhook", Url: splitEventSink[1]})
+		default:
+			log.Warn(fmt.Sprintf("EventSinks %s is setting error，The program will skip here and continue processing", eventSink))
 		}
 	}
 
@@ -376,13 +425,41 @@ func (c *Config) makeChainConfig() *config.Chain {
 	if c.OpenFilterTokenIndex != nil {
 		openFilterTokenIndex = *c.OpenFilterTokenIndex
 	}
+	openFlatState := false
+	if c.OpenFlatState != nil {
+		openFlatState = *c.OpenFlatState
+	}
+	openCounterpartyIndex := false
+	if c.OpenCounterpartyIndex != nil {
+		openCounterpartyIndex = *c.OpenCounterpartyIndex
+	}
+	openVmLogIndex := false
+	if c.OpenVmLogIndex != nil {
+		openVmLogIndex = *c.OpenVmLogIndex
+	}
+	openTimeIndex := false
+	if c.OpenTimeIndex != nil {
+		openTimeIndex = *c.OpenTimeIndex
+	}
+	openTokenHolderIndex := false
+	if c.OpenTokenHolderIndex != nil {
+		openTokenHolderIndex = *c.OpenTokenHolderIndex
+	}
 
 	return &config.Chain{
-		KafkaProducers:       kafkaProducers,
-		OpenBlackBlock:       c.OpenBlackBlock,
-		LedgerGcRetain:       c.LedgerGcRetain,
-		LedgerGc:             ledgerGc,
-		OpenFilterTokenIndex: openFilterTokenIndex,
+		KafkaProducers:        kafkaProducers,
+		EventSinks:            eventSinks,
+		OpenBlackBlock:        c.OpenBlackBlock,
+		LedgerGcRetain:        c.LedgerGcRetain,
+		LedgerGc:              ledgerGc,
+		OpenFilterTokenIndex:  openFilterTokenIndex,
+		OpenFlatState:         openFlatState,
+		OpenCounterpartyIndex: openCounterpartyIndex,
+		OpenVmLogIndex:        openVmLogIndex,
+		OpenTimeIndex:         openTimeIndex,
+		OpenTokenHolderIndex:  openTokenHolderIndex,
+		ArchiveFallback:       c.ArchiveFallback,
+		VerifyLedgerFiles:     c.VerifyLedgerFiles,
 	}
 }
 
@@ -400,6 +477,20 @@ func (c *Config) WSEndpoint() string {
 	return fmt.Sprintf("%s:%d", c.WSHost, c.WSPort)
 }
 
+func (c *Config) GRPCEndpoint() string {
+	if c.GRPCHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.GRPCHost, c.GRPCPort)
+}
+
+func (c *Config) RESTEndpoint() string {
+	if c.RESTHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.RESTHost, c.RESTPort)
+}
+
 func (c *Config) SetPrivateKey(privateKey string) {
 	c.PrivateKey = privateKey
 }
@@ -16,6 +16,8 @@ var DefaultNodeConfig = Config{
 	KeyStoreDir:          DefaultDataDir(),
 	HttpPort:             common.DefaultHTTPPort,
 	WSPort:               common.DefaultWSPort,
+	GRPCPort:             common.DefaultGRPCPort,
+	RESTPort:             common.DefaultRESTPort,
 	PrivateKey:           "",
 	MaxPeers:             0,
 	MaxPassivePeersRatio: 0,
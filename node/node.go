@@ -27,6 +27,7 @@ import (
 	"github.com/vitelabs/go-vite/rpcapi"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/wallet"
+	"google.golang.org/grpc"
 )
 
 var (
@@ -70,6 +71,12 @@ type Node struct {
 	wsListener net.Listener
 	wsHandler  *rpc.Server
 
+	grpcEndpoint string
+	grpcServer   *grpc.Server
+
+	restEndpoint string
+	restListener net.Listener
+
 	wsCli *rpc.WebSocketCli
 
 	// Channel to wait for termination notifications
@@ -88,6 +95,8 @@ func New(conf *Config) (*Node, error) {
 		ipcEndpoint:   conf.IPCEndpoint(),
 		httpEndpoint:  conf.HTTPEndpoint(),
 		wsEndpoint:    conf.WSEndpoint(),
+		grpcEndpoint:  conf.GRPCEndpoint(),
+		restEndpoint:  conf.RESTEndpoint(),
 		stop:          make(chan struct{}),
 	}, nil
 }
@@ -206,6 +215,13 @@ func (node *Node) Start() error {
 func (node *Node) Stop() error {
 	node.lock.Lock()
 	defer node.lock.Unlock()
+
+	// the node is stopped already, e.g. by the interrupt and then by the command
+	select {
+	case <-node.stop:
+		return nil
+	default:
+	}
 	// unblock n.Wait
 	defer close(node.stop)
 
@@ -360,6 +376,12 @@ func (node *Node) startRPC() error {
 	// Init rpc log
 	rpcapi.Init(node.config.DataDir, node.config.LogLevel, node.config.TestTokenHexPrivKey, node.config.TestTokenTti, node.config.NetID)
 
+	if node.config.RPCDiscoverFile != "" {
+		if err := node.writeDiscovery(node.config.RPCDiscoverFile); err != nil {
+			return err
+		}
+	}
+
 	// Start the various API endpoints, terminating all in case of errors
 	if err := node.startInProcess(node.GetInProcessApis()); err != nil {
 		return err
@@ -373,12 +395,21 @@ func (node *Node) startRPC() error {
 		}
 	}
 
+	// the HTTP, WS, gRPC and REST endpoints share the quotas of the clients
+	var limiter *rpc.RateLimiter
+	if node.config.RPCRateLimit != nil {
+		limiter = rpc.NewRateLimiter(node.config.RPCRateLimit)
+	}
+
 	if node.config.RPCEnabled {
-		apis := rpcapi.GetPublicApis(node.viteServer)
-		if len(node.config.PublicModules) != 0 {
-			apis = rpcapi.GetApis(node.viteServer, node.config.PublicModules...)
+		apis, auth, err := node.getEndpointApis()
+		if err != nil {
+			node.stopInProcess()
+			node.stopIPC()
+			return err
 		}
-		if err := node.startHTTP(node.httpEndpoint, apis, nil, node.config.HTTPCors, node.config.HttpVirtualHosts, rpc.HTTPTimeouts{}, node.config.HttpExposeAll); err != nil {
+		// the private apis are registered and guarded by the authentication
+		if err := node.startHTTP(node.httpEndpoint, apis, nil, node.config.HTTPCors, node.config.HttpVirtualHosts, rpc.HTTPTimeouts{}, node.config.HttpExposeAll || auth != nil, auth, limiter); err != nil {
 			node.stopInProcess()
 			node.stopIPC()
 			return err
@@ -386,17 +417,59 @@ func (node *Node) startRPC() error {
 	}
 
 	if node.config.WSEnabled {
-		apis := rpcapi.GetPublicApis(node.viteServer)
-		if len(node.config.PublicModules) != 0 {
-			apis = rpcapi.GetApis(node.viteServer, node.config.PublicModules...)
+		apis, auth, err := node.getEndpointApis()
+		if err != nil {
+			node.stopInProcess()
+			node.stopIPC()
+			node.stopHTTP()
+			return err
 		}
-		if err := node.startWS(node.wsEndpoint, apis, nil, node.config.WSOrigins, node.config.WSExposeAll); err != nil {
+		if err := node.startWS(node.wsEndpoint, apis, nil, node.config.WSOrigins, node.config.WSExposeAll || auth != nil, auth, limiter); err != nil {
 			node.stopInProcess()
 			node.stopIPC()
 			node.stopHTTP()
 			return err
 		}
 	}
+
+	if node.config.GRPCEnabled {
+		_, auth, err := node.getEndpointApis()
+		if err != nil {
+			node.stopInProcess()
+			node.stopIPC()
+			node.stopHTTP()
+			node.stopWS()
+			return err
+		}
+		if err := node.startGRPC(node.grpcEndpoint, auth, limiter); err != nil {
+			node.stopInProcess()
+			node.stopIPC()
+			node.stopHTTP()
+			node.stopWS()
+			return err
+		}
+	}
+
+	if node.config.RESTEnabled {
+		_, auth, err := node.getEndpointApis()
+		if err != nil {
+			node.stopInProcess()
+			node.stopIPC()
+			node.stopHTTP()
+			node.stopWS()
+			node.stopGRPC()
+			return err
+		}
+		if err := node.startREST(node.restEndpoint, node.config.HTTPCors, node.config.HttpVirtualHosts, auth, limiter); err != nil {
+			node.stopInProcess()
+			node.stopIPC()
+			node.stopHTTP()
+			node.stopWS()
+			node.stopGRPC()
+			return err
+		}
+	}
+
 	if len(node.config.DashboardTargetURL) > 0 {
 		apis := rpcapi.GetPublicApis(node.viteServer)
 		if len(node.config.PublicModules) != 0 {
@@ -457,6 +530,8 @@ func (node *Node) stopVite() error {
 }
 
 func (node *Node) stopRPC() error {
+	node.stopREST()
+	node.stopGRPC()
 	node.stopWS()
 	node.stopHTTP()
 	node.stopIPC()
@@ -1,26 +1,30 @@
 package node
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"strings"
 
 	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/rpcapi"
+	"google.golang.org/grpc"
 )
 
 //In-proc apis
 func (node *Node) GetInProcessApis() []rpc.API {
-	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx")
+	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "private_debug", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "subscribe")
 }
 
 //Ipc apis
 func (node *Node) GetIpcApis() []rpc.API {
-	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx")
+	return rpcapi.GetApis(node.viteServer, "ledger", "wallet", "private_onroad", "private_debug", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "subscribe")
 }
 
 //Http apis
 func (node *Node) GetHttpApis() []rpc.API {
-	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx"}
+	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx", "subscribe"}
 	if node.Config().NetID > 1 {
 		apiModules = append(apiModules, "testapi")
 	}
@@ -29,13 +33,84 @@ func (node *Node) GetHttpApis() []rpc.API {
 
 //WS apis
 func (node *Node) GetWSApis() []rpc.API {
-	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx"}
+	apiModules := []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx", "subscribe"}
 	if node.Config().NetID > 1 {
 		apiModules = append(apiModules, "testapi")
 	}
 	return rpcapi.GetApis(node.viteServer, apiModules...)
 }
 
+// getEndpointApis returns the apis of the HTTP and WS endpoints. If the authentication is enabled all the apis are
+// registered, the anonymous modules narrowed by PublicModules are allowed without credential and the others are
+// allowed by the permissions of the credentials.
+func (node *Node) getEndpointApis() ([]rpc.API, *rpc.Auth, error) {
+	apis := rpcapi.GetPublicApis(node.viteServer)
+	if len(node.config.PublicModules) != 0 {
+		apis = rpcapi.GetApis(node.viteServer, node.config.PublicModules...)
+	}
+	if node.config.RPCAuth == nil {
+		return apis, nil, nil
+	}
+
+	anonymousModules := rpcapi.GetAnonymousApiModules()
+	if node.config.NetID > 1 {
+		anonymousModules = append(anonymousModules, "testapi")
+	}
+	if len(node.config.PublicModules) != 0 {
+		allowed := make(map[string]bool, len(anonymousModules))
+		for _, m := range anonymousModules {
+			allowed[m] = true
+		}
+		anonymousModules = anonymousModules[:0]
+		for _, m := range node.config.PublicModules {
+			if allowed[m] {
+				anonymousModules = append(anonymousModules, m)
+			} else {
+				log.Warn("the module needs credential if the authentication is enabled", "module", m)
+			}
+		}
+	}
+	anonymous := rpcapi.GetApis(node.viteServer, anonymousModules...)
+
+	auth, err := rpc.NewAuth(node.config.RPCAuth, anonymous, rpcapi.GetAllApiMap(node.viteServer))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the anonymous apis are registered last, so the methods shared by the public and private apis of a namespace,
+	// like onroad_getOnroadBlocksByAddress, are served by the public ones
+	isAnonymous := make(map[string]bool, len(anonymousModules))
+	for _, m := range anonymousModules {
+		isAnonymous[m] = true
+	}
+	var allApis []rpc.API
+	for _, m := range rpcapi.GetAllApiModules() {
+		if !isAnonymous[m] {
+			allApis = append(allApis, rpcapi.GetApi(node.viteServer, m))
+		}
+	}
+	return append(allApis, anonymous...), auth, nil
+}
+
+// writeDiscovery writes the rpc_discover document of all the apis to file.
+func (node *Node) writeDiscovery(file string) error {
+	server := rpc.NewServer()
+	for _, api := range rpcapi.GetAllApis(node.viteServer) {
+		if err := server.RegisterApi(api); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(server.Discover(nil), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return err
+	}
+	log.Info("rpc_discover document written", "file", file)
+	return nil
+}
+
 // startIPC initializes and starts the IPC RPC endpoint.
 func (node *Node) startIPC(apis []rpc.API) error {
 	if node.ipcEndpoint == "" {
@@ -66,16 +141,16 @@ func (node *Node) stopIPC() {
 }
 
 // startHTTP initializes and starts the HTTP RPC endpoint.
-func (node *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts, exposeAll bool) error {
+func (node *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors []string, vhosts []string, timeouts rpc.HTTPTimeouts, exposeAll bool, auth *rpc.Auth, limiter *rpc.RateLimiter) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, timeouts, exposeAll)
+	listener, handler, err := rpc.StartHTTPEndpoint(endpoint, apis, modules, cors, vhosts, timeouts, exposeAll, auth, limiter)
 	if err != nil {
 		return err
 	}
-	log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","))
+	log.Info("HTTP endpoint opened", "url", fmt.Sprintf("http://%s", endpoint), "cors", strings.Join(cors, ","), "vhosts", strings.Join(vhosts, ","), "auth", auth != nil, "ratelimit", limiter != nil)
 	// All listeners booted successfully
 	node.httpEndpoint = endpoint
 	node.httpListener = listener
@@ -99,16 +174,16 @@ func (node *Node) stopHTTP() {
 }
 
 // startWS initializes and starts the websocket RPC endpoint.
-func (node *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins []string, exposeAll bool) error {
+func (node *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins []string, exposeAll bool, auth *rpc.Auth, limiter *rpc.RateLimiter) error {
 	// Short circuit if the WS endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
 	}
-	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll)
+	listener, handler, err := rpc.StartWSEndpoint(endpoint, apis, modules, wsOrigins, exposeAll, auth, limiter)
 	if err != nil {
 		return err
 	}
-	log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()))
+	log.Info("WebSocket endpoint opened", "url", fmt.Sprintf("ws://%s", listener.Addr()), "auth", auth != nil, "ratelimit", limiter != nil)
 	// All listeners booted successfully
 	node.wsEndpoint = endpoint
 	node.wsListener = listener
@@ -133,6 +208,65 @@ func (node *Node) stopWS() {
 	}
 }
 
+// the concurrent streams of a gRPC connection, the subscriptions are streams
+const grpcMaxConcurrentStreams = 64
+
+// startGRPC initializes and starts the gRPC endpoint, the methods are authorized and rate limited as the JSON-RPC
+// methods serving them.
+func (node *Node) startGRPC(endpoint string, auth *rpc.Auth, limiter *rpc.RateLimiter) error {
+	// Short circuit if the gRPC endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer(rpc.GRPCServerOptions(auth, limiter, rpcapi.GetGrpcMethods(), grpcMaxConcurrentStreams)...)
+	rpcapi.RegisterGrpcApis(server, node.viteServer)
+	go server.Serve(listener)
+	log.Info("gRPC endpoint opened", "url", listener.Addr(), "auth", auth != nil, "ratelimit", limiter != nil)
+
+	node.grpcEndpoint = endpoint
+	node.grpcServer = server
+	return nil
+}
+
+// stopGRPC terminates the gRPC endpoint, the streams are closed.
+func (node *Node) stopGRPC() {
+	if node.grpcServer != nil {
+		node.grpcServer.Stop()
+		node.grpcServer = nil
+		log.Info("gRPC endpoint closed", "url", node.grpcEndpoint)
+	}
+}
+
+// startREST initializes and starts the REST gateway of the read-only ledger queries.
+func (node *Node) startREST(endpoint string, cors []string, vhosts []string, auth *rpc.Auth, limiter *rpc.RateLimiter) error {
+	// Short circuit if the REST endpoint isn't being exposed
+	if endpoint == "" {
+		return nil
+	}
+	listener, err := rpc.StartRESTEndpoint(endpoint, rpcapi.GetRESTApis(node.viteServer), rpcapi.GetRESTRoutes(), cors, vhosts, rpc.HTTPTimeouts{}, auth, limiter)
+	if err != nil {
+		return err
+	}
+	log.Info("REST endpoint opened", "url", fmt.Sprintf("http://%s", listener.Addr()), "openapi", rpc.OpenAPIPath, "auth", auth != nil, "ratelimit", limiter != nil)
+
+	node.restEndpoint = endpoint
+	node.restListener = listener
+	return nil
+}
+
+// stopREST terminates the REST gateway.
+func (node *Node) stopREST() {
+	if node.restListener != nil {
+		node.restListener.Close()
+		node.restListener = nil
+		log.Info("REST endpoint closed", "url", fmt.Sprintf("http://%s", node.restEndpoint))
+	}
+}
+
 func (node *Node) Attach() (*rpc.Client, error) {
 	node.lock.RLock()
 	defer node.lock.RUnlock()
@@ -149,7 +283,7 @@ func (node *Node) startInProcess(apis []rpc.API) error {
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
 	for _, api := range apis {
-		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+		if err := handler.RegisterApi(api); err != nil {
 			return err
 		}
 		log.Debug("InProc registered", "service", api.Service, "namespace", api.Namespace)
@@ -2,12 +2,12 @@ package onroad_test
 
 import (
 	"fmt"
-	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain/unittest"
 	"github.com/vitelabs/go-vite/common"
 	"github.com/vitelabs/go-vite/common/types"
-	"github.com/vitelabs/go-vite/config"
 	"github.com/vitelabs/go-vite/onroad"
 	"github.com/vitelabs/go-vite/wallet"
+	"io/ioutil"
 	"testing"
 	"time"
 )
@@ -28,11 +28,11 @@ func generateAddress() types.Address {
 func startManager() (*onroad.Manager, types.Address) {
 	addr := generateAddress()
 
-	c := chain.NewChain(&config.Config{
-		Net:     nil,
-		DataDir: common.GoViteTestDataDir(),
-	})
-	c.Init()
+	dataDir, err := ioutil.TempDir("", "onroad_test")
+	if err != nil {
+		panic(err)
+	}
+	c := chain_unittest.NewMemChainInstance(dataDir)
 
 	prod := new(testProducer)
 	prod.Addr = addr
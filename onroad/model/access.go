@@ -2,8 +2,8 @@ package model
 
 import (
 	"github.com/pkg/errors"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
@@ -39,7 +39,7 @@ func (access *UAccess) GetContractAddrListByGid(gid *types.Gid) ([]types.Address
 	return addrList, nil
 }
 
-func (access *UAccess) WriteContractAddrToGid(batch *leveldb.Batch, gid types.Gid, address types.Address) error {
+func (access *UAccess) WriteContractAddrToGid(batch *database.Batch, gid types.Gid, address types.Address) error {
 	var addrList []types.Address
 	var err error
 
@@ -62,7 +62,7 @@ func (access *UAccess) WriteContractAddrToGid(batch *leveldb.Batch, gid types.Gi
 	}
 }
 
-func (access *UAccess) DeleteContractAddrFromGid(batch *leveldb.Batch, gid types.Gid, address types.Address) error {
+func (access *UAccess) DeleteContractAddrFromGid(batch *database.Batch, gid types.Gid, address types.Address) error {
 	var addrList []types.Address
 	var err error
 
@@ -85,7 +85,7 @@ func (access *UAccess) DeleteContractAddrFromGid(batch *leveldb.Batch, gid types
 	}
 }
 
-func (access *UAccess) writeOnroadMeta(batch *leveldb.Batch, block *ledger.AccountBlock) error {
+func (access *UAccess) writeOnroadMeta(batch *database.Batch, block *ledger.AccountBlock) error {
 	if block.IsSendBlock() {
 		// call from the common WriteOnroad func to add new onRoadTx, sendBlock
 		return access.store.WriteMeta(batch, &block.ToAddress, &block.Hash)
@@ -113,7 +113,7 @@ func (access *UAccess) writeOnroadMeta(batch *leveldb.Batch, block *ledger.Accou
 	}
 }
 
-func (access *UAccess) deleteOnroadMeta(batch *leveldb.Batch, block *ledger.AccountBlock) error {
+func (access *UAccess) deleteOnroadMeta(batch *database.Batch, block *ledger.AccountBlock) error {
 	if block.IsReceiveBlock() {
 		// call from the WriteOnroad func to handle the onRoadTx's receiveBlock
 		addr := &block.AccountAddress
@@ -136,7 +136,7 @@ func (access *UAccess) deleteOnroadMeta(batch *leveldb.Batch, block *ledger.Acco
 func (access *UAccess) GetOnroadHashs(index, num, count uint64, addr *types.Address) ([]*types.Hash, error) {
 	totalCount := (index + num) * count
 	maxCount, err := access.store.GetCountByAddress(addr)
-	if err != nil && err != leveldb.ErrNotFound {
+	if err != nil && err != database.ErrNotFound {
 		access.log.Error("GetOnroadHashs", "error", err)
 		return nil, err
 	}
@@ -229,4 +229,4 @@ func (access *UAccess) GetCommonAccTokenInfoMap(addr *types.Address) (map[types.
 
 	}
 	return infoMap, uint64(len(hashList)), nil
-}
\ No newline at end of file
+}
@@ -4,8 +4,6 @@
 package model
 
 import (
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
@@ -15,7 +13,7 @@ type OnroadSet struct {
 	chain chain.Chain
 }
 
-func (o OnroadSet) db() *leveldb.DB {
+func (o OnroadSet) db() database.Store {
 	return o.chain.ChainDb().Db()
 }
 func NewOnroadSet(chain chain.Chain) *OnroadSet {
@@ -32,7 +30,7 @@ func (ucf *OnroadSet) GetCountByAddress(addr *types.Address) (count uint64, err
 		return 0, err
 	}
 
-	iter := ucf.db().NewIterator(util.BytesPrefix(key), nil)
+	iter := ucf.db().NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 
 	for iter.Next() {
@@ -47,7 +45,7 @@ func (ucf *OnroadSet) GetHashsByCount(count uint64, addr *types.Address) (hashs
 		return nil, err
 	}
 
-	iter := ucf.db().NewIterator(util.BytesPrefix(key), nil)
+	iter := ucf.db().NewIterator(database.BytesPrefix(key))
 	defer iter.Release()
 	i := uint64(1)
 	for iter.Next() {
@@ -62,7 +60,7 @@ func (ucf *OnroadSet) GetHashsByCount(count uint64, addr *types.Address) (hashs
 		hashs = append(hashs, &hash)
 		i++
 	}
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 	return hashs, nil
@@ -74,7 +72,7 @@ func (ucf *OnroadSet) GetHashList(addr *types.Address) (hashs []*types.Hash, err
 		return nil, err
 	}
 
-	iter := ucf.db().NewIterator(util.BytesPrefix(createKey), nil)
+	iter := ucf.db().NewIterator(database.BytesPrefix(createKey))
 	defer iter.Release()
 
 	for iter.Next() {
@@ -85,13 +83,13 @@ func (ucf *OnroadSet) GetHashList(addr *types.Address) (hashs []*types.Hash, err
 		}
 		hashs = append(hashs, &hash)
 	}
-	if err := iter.Error(); err != nil && err != leveldb.ErrNotFound {
+	if err := iter.Error(); err != nil && err != database.ErrNotFound {
 		return nil, err
 	}
 	return hashs, nil
 }
 
-func (ucf *OnroadSet) WriteMeta(batch *leveldb.Batch, addr *types.Address, hash *types.Hash) error {
+func (ucf *OnroadSet) WriteMeta(batch *database.Batch, addr *types.Address, hash *types.Hash) error {
 	value := []byte{byte(0)}
 
 	key, err := database.EncodeKey(database.DBKP_ONROADMETA, addr.Bytes(), hash.Bytes())
@@ -99,7 +97,7 @@ func (ucf *OnroadSet) WriteMeta(batch *leveldb.Batch, addr *types.Address, hash
 		return err
 	}
 	if batch == nil {
-		if err := ucf.db().Put(key, value, nil); err != nil {
+		if err := ucf.db().Put(key, value); err != nil {
 			return err
 		}
 	} else {
@@ -108,13 +106,13 @@ func (ucf *OnroadSet) WriteMeta(batch *leveldb.Batch, addr *types.Address, hash
 	return nil
 }
 
-func (ucf *OnroadSet) DeleteMeta(batch *leveldb.Batch, addr *types.Address, hash *types.Hash) error {
+func (ucf *OnroadSet) DeleteMeta(batch *database.Batch, addr *types.Address, hash *types.Hash) error {
 	key, err := database.EncodeKey(database.DBKP_ONROADMETA, addr.Bytes(), hash.Bytes())
 	if err != nil {
 		return err
 	}
 	if batch == nil {
-		if err := ucf.db().Delete(key, nil); err != nil {
+		if err := ucf.db().Delete(key); err != nil {
 			return err
 		}
 	} else {
@@ -123,7 +121,7 @@ func (ucf *OnroadSet) DeleteMeta(batch *leveldb.Batch, addr *types.Address, hash
 	return nil
 }
 
-func (ucf *OnroadSet) WriteGidAddrList(batch *leveldb.Batch, gid *types.Gid, addrList []types.Address) error {
+func (ucf *OnroadSet) WriteGidAddrList(batch *database.Batch, gid *types.Gid, addrList []types.Address) error {
 	key, err := database.EncodeKey(database.DBKP_GID_ADDR, gid.Bytes())
 	if err != nil {
 		return err
@@ -134,7 +132,7 @@ func (ucf *OnroadSet) WriteGidAddrList(batch *leveldb.Batch, gid *types.Gid, add
 	}
 
 	if batch == nil {
-		if err := ucf.db().Put(key, data, nil); err != nil {
+		if err := ucf.db().Put(key, data); err != nil {
 			return err
 		}
 	} else {
@@ -150,9 +148,9 @@ func (ucf *OnroadSet) GetContractAddrList(gid *types.Gid) ([]types.Address, erro
 		return nil, err
 	}
 
-	data, err := ucf.db().Get(key, nil)
+	data, err := ucf.db().Get(key)
 	if err != nil {
-		if err != leveldb.ErrNotFound {
+		if err != database.ErrNotFound {
 			return nil, err
 		}
 		return nil, nil
@@ -171,4 +169,4 @@ func (ucf *OnroadSet) GetContractAddrList(gid *types.Gid) ([]types.Address, erro
 		return commonAddrList, nil
 	}
 	return addrList, nil
-}
\ No newline at end of file
+}
@@ -3,11 +3,11 @@ package model
 import (
 	"container/list"
 	"fmt"
+	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/vm/util"
 	"sync"
 	"time"
 
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
@@ -280,7 +280,7 @@ func (p *OnroadBlocksPool) WriteOnroadSuccess(blocks []*vm_context.VmAccountBloc
 	}
 }
 
-func (p *OnroadBlocksPool) WriteOnroad(batch *leveldb.Batch, blockList []*vm_context.VmAccountBlock) error {
+func (p *OnroadBlocksPool) WriteOnroad(batch *database.Batch, blockList []*vm_context.VmAccountBlock) error {
 	for _, v := range blockList {
 		if v.AccountBlock.IsSendBlock() {
 			// basic writeMeta func
@@ -332,7 +332,7 @@ func (p *OnroadBlocksPool) RevertOnroadSuccess(subLedger map[types.Address][]*le
 }
 
 // RevertOnroad means to revert according to bifurcation
-func (p *OnroadBlocksPool) RevertOnroad(batch *leveldb.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error {
+func (p *OnroadBlocksPool) RevertOnroad(batch *database.Batch, subLedger map[types.Address][]*ledger.AccountBlock) error {
 	revertLog := p.log.New("method", "RevertOnroad")
 
 	cutMap := excludeSubordinate(subLedger)
@@ -5,32 +5,35 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/vitelabs/go-vite/chain"
-	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/chain/unittest"
 	"github.com/vitelabs/go-vite/common/types"
-	"github.com/vitelabs/go-vite/config"
 	"github.com/vitelabs/go-vite/crypto/ed25519"
 	"github.com/vitelabs/go-vite/generator"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/vm"
 	"github.com/vitelabs/go-vite/vm/util"
+	"io/ioutil"
 	"math/big"
-	"os"
-	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 )
 
+// newTestChain creates a chain with the ledger in memory, the ledger files are written into a temp dir.
+func newTestChain() chain.Chain {
+	dataDir, err := ioutil.TempDir("", "onroad_model_test")
+	if err != nil {
+		panic(err)
+	}
+	return chain_unittest.NewMemChainInstance(dataDir)
+}
+
 func newOnroadBlocksPool() *OnroadBlocksPool {
-	chain := chain.NewChain(&config.Config{
-		Net:     nil,
-		DataDir: common.GoViteTestDataDir(),
-	})
+	chain := newTestChain()
 
 	uAccess := NewUAccess()
 	uAccess.Init(chain)
 
-	chain.Init()
 	chain.Start()
 
 	fullCacheExpireTime = 5 * time.Second
@@ -88,12 +91,8 @@ func init() {
 }
 
 func PrepareVite() *VitePrepared {
-	dataDir := filepath.Join(common.HomeDir(), "testvite")
-	fmt.Printf("\n----dataDir:%+v\n", dataDir)
-	os.RemoveAll(filepath.Join(common.HomeDir(), "ledger"))
-	c := chain.NewChain(&config.Config{DataDir: dataDir})
+	c := newTestChain()
 
-	c.Init()
 	uAccess := NewUAccess()
 	uAccess.Init(c)
 	orPool := NewOnroadBlocksPool(uAccess)
@@ -0,0 +1,299 @@
+package rpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyHeader = "X-Api-Key"
+	bearerPrefix = "Bearer "
+)
+
+// Permission is the set of the methods a credential may call.
+type Permission struct {
+	all        bool
+	namespaces map[string]bool
+	// namespace_method
+	methods map[string]bool
+}
+
+func newPermission() *Permission {
+	return &Permission{
+		namespaces: make(map[string]bool),
+		methods:    make(map[string]bool),
+	}
+}
+
+// addApi allows all the methods and subscriptions of api, but not the methods of the other apis of the same
+// namespace, e.g. the private and public onroad apis share the namespace onroad.
+func (p *Permission) addApi(api API) {
+	if api.Service == nil {
+		return
+	}
+	rcvr := reflect.ValueOf(api.Service)
+	methods, subscriptions := suitableCallbacks(rcvr, rcvr.Type())
+	for name := range methods {
+		p.methods[api.Namespace+serviceMethodSeparator+name] = true
+	}
+	for name := range subscriptions {
+		p.methods[api.Namespace+serviceMethodSeparator+name] = true
+	}
+}
+
+// Allow reports whether the method of namespace can be called, the meta information of the server is always allowed.
+func (p *Permission) Allow(namespace, method string) bool {
+	if p.all || namespace == MetadataApi || p.namespaces[namespace] {
+		return true
+	}
+	return p.methods[namespace+serviceMethodSeparator+method]
+}
+
+type permissionKey struct{}
+
+func withPermission(ctx context.Context, p *Permission) context.Context {
+	return context.WithValue(ctx, permissionKey{}, p)
+}
+
+// PermissionFromContext returns the permission of the credential of the request, nil means the authentication isn't
+// enabled and all the methods are allowed.
+func PermissionFromContext(ctx context.Context) *Permission {
+	p, _ := ctx.Value(permissionKey{}).(*Permission)
+	return p
+}
+
+// Authenticator checks one kind of credentials of http requests, it returns nil without error if the request has no
+// credential of its kind. The client is the identity of the credential for the rate limiter, empty means the
+// requests are limited by the remote ip.
+type Authenticator interface {
+	Authenticate(r *http.Request) (p *Permission, client string, err error)
+}
+
+// ApiKeyAuthenticator checks the static api key in the X-Api-Key header.
+type ApiKeyAuthenticator struct {
+	keys        [][]byte
+	permissions []*Permission
+}
+
+func (a *ApiKeyAuthenticator) Authenticate(r *http.Request) (*Permission, string, error) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		return nil, "", nil
+	}
+	for i, k := range a.keys {
+		if subtle.ConstantTimeCompare(k, []byte(key)) == 1 {
+			return a.permissions[i], "apikey/" + strconv.Itoa(i), nil
+		}
+	}
+	return nil, "", errors.New("invalid api key")
+}
+
+// JwtAuthenticator checks the json web token in the Authorization header, the token is signed by HS256 with the shared
+// secret. The methods allowed by a token are listed by its permissions claim, the token without the claim is allowed
+// the default permission.
+type JwtAuthenticator struct {
+	secret     []byte
+	permission *Permission
+	parse      func(entries []string) (*Permission, error)
+}
+
+type jwtClaims struct {
+	ExpiresAt   *int64   `json:"exp,omitempty"`
+	NotBefore   *int64   `json:"nbf,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+func (a *JwtAuthenticator) Authenticate(r *http.Request) (*Permission, string, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return nil, "", nil
+	}
+	claims, err := a.verify(strings.TrimPrefix(header, bearerPrefix))
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().Unix()
+	if claims.ExpiresAt != nil && now >= *claims.ExpiresAt {
+		return nil, "", errors.New("the token is expired")
+	}
+	if claims.NotBefore != nil && now < *claims.NotBefore {
+		return nil, "", errors.New("the token isn't valid yet")
+	}
+	if claims.Permissions == nil {
+		return a.permission, "", nil
+	}
+	p, err := a.parse(claims.Permissions)
+	return p, "", err
+}
+
+func (a *JwtAuthenticator) verify(token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("malformed token header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); err != nil || header.Alg != "HS256" {
+		return nil, errors.New("the token should be signed by HS256")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed token signature")
+	}
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, errors.New("invalid token signature")
+	}
+
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+	claims := &jwtClaims{}
+	if err := json.Unmarshal(claimsBytes, claims); err != nil {
+		return nil, errors.New("malformed token claims")
+	}
+	return claims, nil
+}
+
+// AuthConfig configures the authentication of the HTTP and WS endpoints. A permission entry is "*" for all the
+// methods, a module like "private_onroad", a namespace like "ledger" or a method like "wallet_getEntropyFilesInStandardDir".
+type AuthConfig struct {
+	// the file of the shared secret of the json web tokens, the tokens are rejected if it's empty
+	JwtSecretFile  string          `json:"JwtSecretFile"`
+	JwtPermissions []string        `json:"JwtPermissions"`
+	ApiKeys        []*ApiKeyConfig `json:"ApiKeys"`
+}
+
+type ApiKeyConfig struct {
+	Key         string   `json:"Key"`
+	Permissions []string `json:"Permissions"`
+}
+
+// Auth resolves the permission of the http requests, the request without credential is allowed the anonymous
+// permission.
+type Auth struct {
+	authenticators []Authenticator
+	anonymous      *Permission
+}
+
+// NewAuth creates the Auth of cfg, anonymous are the apis allowed without credential except the ones not Public,
+// modules are the apis which can be referred by their module names in the permissions.
+func NewAuth(cfg *AuthConfig, anonymous []API, modules map[string]API) (*Auth, error) {
+	parse := func(entries []string) (*Permission, error) {
+		p := newPermission()
+		for _, entry := range entries {
+			if entry == "*" {
+				p.all = true
+			} else if api, ok := modules[entry]; ok {
+				p.addApi(api)
+			} else if strings.Contains(entry, serviceMethodSeparator) {
+				p.methods[entry] = true
+			} else if entry != "" {
+				p.namespaces[entry] = true
+			} else {
+				return nil, errors.New("empty permission entry")
+			}
+		}
+		return p, nil
+	}
+
+	auth := &Auth{anonymous: newPermission()}
+	for _, api := range anonymous {
+		if api.Public {
+			auth.anonymous.addApi(api)
+		}
+	}
+
+	if len(cfg.ApiKeys) > 0 {
+		apiKeyAuth := &ApiKeyAuthenticator{}
+		for _, keyCfg := range cfg.ApiKeys {
+			if keyCfg.Key == "" {
+				return nil, errors.New("empty api key")
+			}
+			p, err := parse(keyCfg.Permissions)
+			if err != nil {
+				return nil, err
+			}
+			apiKeyAuth.keys = append(apiKeyAuth.keys, []byte(keyCfg.Key))
+			apiKeyAuth.permissions = append(apiKeyAuth.permissions, p)
+		}
+		auth.authenticators = append(auth.authenticators, apiKeyAuth)
+	}
+
+	if cfg.JwtSecretFile != "" {
+		secret, err := ioutil.ReadFile(cfg.JwtSecretFile)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("read jwt secret file failed, error is %s", err))
+		}
+		secret = []byte(strings.TrimSpace(string(secret)))
+		if len(secret) == 0 {
+			return nil, errors.New("the jwt secret is empty")
+		}
+		p, err := parse(cfg.JwtPermissions)
+		if err != nil {
+			return nil, err
+		}
+		auth.authenticators = append(auth.authenticators, &JwtAuthenticator{secret: secret, permission: p, parse: parse})
+	}
+	return auth, nil
+}
+
+// authenticate returns the permission of the first credential of r, or the anonymous permission if r has no
+// credential.
+func (auth *Auth) authenticate(r *http.Request) (*Permission, string, error) {
+	for _, authenticator := range auth.authenticators {
+		p, client, err := authenticator.Authenticate(r)
+		if err != nil {
+			return nil, "", err
+		}
+		if p != nil {
+			return p, client, nil
+		}
+	}
+	return auth.anonymous, "", nil
+}
+
+// handler rejects the requests with invalid credentials by a JSON-RPC error, the permission of the others is passed
+// to next by the context.
+func (auth *Auth) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, client, err := auth.authenticate(r)
+		if err != nil {
+			w.Header().Set("content-type", contentType)
+			w.WriteHeader(http.StatusUnauthorized)
+			rpcErr := &unauthorizedError{err.Error()}
+			json.NewEncoder(w).Encode(&jsonErrResponse{
+				Version: jsonrpcVersion,
+				Error:   jsonError{Code: rpcErr.ErrorCode(), Message: rpcErr.Error()},
+			})
+			return
+		}
+		ctx := withPermission(r.Context(), p)
+		if client != "" {
+			ctx = withClient(ctx, client)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
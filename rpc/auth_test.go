@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestJwt(secret string, claims map[string]interface{}) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claimsBytes, _ := json.Marshal(claims)
+	payload := header + "." + base64.RawURLEncoding.EncodeToString(claimsBytes)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuth(t *testing.T) {
+	secretFile, err := ioutil.TempFile("", "jwt_secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(secretFile.Name())
+	secretFile.WriteString("secret\n")
+	secretFile.Close()
+
+	publicApi := API{Namespace: "test", Service: new(Service), Public: true}
+	privateApi := API{Namespace: "private", Service: new(Service)}
+	auth, err := NewAuth(&AuthConfig{
+		JwtSecretFile:  secretFile.Name(),
+		JwtPermissions: []string{"private_rets"},
+		ApiKeys: []*ApiKeyConfig{
+			{Key: "all", Permissions: []string{"*"}},
+			{Key: "module", Permissions: []string{"private_module"}},
+			{Key: "method", Permissions: []string{"private_echo"}},
+		},
+	}, []API{publicApi, privateApi}, map[string]API{"private_module": privateApi})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer()
+	server.auth = auth
+	server.RegisterName(publicApi.Namespace, publicApi.Service)
+	server.RegisterName(privateApi.Namespace, privateApi.Service)
+	httpServer := httptest.NewServer(NewHTTPServer(nil, nil, HTTPTimeouts{}, server).Handler)
+	defer httpServer.Close()
+
+	call := func(method string, header string, value string) (int, *jsonError) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":["a",1,{"S":"b"}]}`
+		req, _ := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(body))
+		req.Header.Set("content-type", contentType)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var result struct {
+			Error *jsonError `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, result.Error
+	}
+
+	expired := newTestJwt("secret", map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})
+	forged := newTestJwt("forged", map[string]interface{}{"permissions": []string{"*"}})
+	cases := []struct {
+		method string
+		header string
+		value  string
+		status int
+		code   int
+	}{
+		{"test_echo", "", "", http.StatusOK, 0},
+		{"rpc_modules", "", "", http.StatusOK, 0},
+		{"private_echo", "", "", http.StatusOK, -32003},
+		{"private_echo", apiKeyHeader, "all", http.StatusOK, 0},
+		{"private_echo", apiKeyHeader, "module", http.StatusOK, 0},
+		{"private_echo", apiKeyHeader, "method", http.StatusOK, 0},
+		{"private_rets", apiKeyHeader, "method", http.StatusOK, -32003},
+		{"test_echo", apiKeyHeader, "method", http.StatusOK, -32003},
+		{"test_echo", apiKeyHeader, "wrong", http.StatusUnauthorized, -32001},
+		{"private_rets", "Authorization", bearerPrefix + newTestJwt("secret", map[string]interface{}{}), http.StatusOK, 0},
+		{"private_echo", "Authorization", bearerPrefix + newTestJwt("secret", map[string]interface{}{}), http.StatusOK, -32003},
+		{"private_echo", "Authorization", bearerPrefix + newTestJwt("secret", map[string]interface{}{"permissions": []string{"private"}}), http.StatusOK, 0},
+		{"test_echo", "Authorization", bearerPrefix + expired, http.StatusUnauthorized, -32001},
+		{"test_echo", "Authorization", bearerPrefix + forged, http.StatusUnauthorized, -32001},
+	}
+	for i, c := range cases {
+		status, rpcErr := call(c.method, c.header, c.value)
+		if status != c.status {
+			t.Fatalf("case %d: the status is %d", i, status)
+		}
+		if c.code == 0 && rpcErr != nil || c.code != 0 && (rpcErr == nil || rpcErr.Code != c.code) {
+			t.Fatalf("case %d: the error is %v", i, rpcErr)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const openRPCVersion = "1.2.6"
+
+// MethodDoc annotates a method of a service in the rpc_discover document.
+type MethodDoc struct {
+	Summary string
+	// the names of the parameters in order, the unnamed ones are named by their positions like param0
+	Params []string
+}
+
+// Documented is implemented by the services which annotate their methods, the docs are keyed by the method names
+// like getBlockByHash. MethodDocs itself isn't exposed as a method.
+type Documented interface {
+	MethodDocs() map[string]MethodDoc
+}
+
+const methodDocsName = "MethodDocs"
+
+var documentedType = reflect.TypeOf((*Documented)(nil)).Elem()
+
+// annotate marks the callbacks of rcvr public or private and attaches their docs, the docs of the unknown methods
+// are rejected.
+func annotate(rcvr interface{}, public bool, methods callbacks, subscriptions subscriptions) error {
+	var docs map[string]MethodDoc
+	if documented, ok := rcvr.(Documented); ok {
+		docs = documented.MethodDocs()
+	}
+	for name := range docs {
+		if methods[name] == nil && subscriptions[name] == nil {
+			return fmt.Errorf("%T has no method %s to annotate", rcvr, name)
+		}
+	}
+	for _, cbs := range []map[string]*callback{methods, subscriptions} {
+		for name, callb := range cbs {
+			callb.public = public
+			if doc, ok := docs[name]; ok {
+				callb.doc = &doc
+			}
+		}
+	}
+	return nil
+}
+
+// Discover returns the OpenRPC document of the methods and subscriptions registered to the server which p allows,
+// sorted by their names, nil p allows all of them. The subscriptions are marked by x-subscription, they're called by
+// namespace_subscribe with their names as the first parameter.
+func (s *Server) Discover(p *Permission) map[string]interface{} {
+	schemas := newSchemaBuilder("#/components/schemas/")
+	var methods []map[string]interface{}
+	for _, svc := range s.services {
+		for _, cbs := range []map[string]*callback{svc.callbacks, svc.subscriptions} {
+			for name, callb := range cbs {
+				if p == nil || p.Allow(svc.name, name) {
+					methods = append(methods, describeMethod(schemas, svc.name+serviceMethodSeparator+name, callb))
+				}
+			}
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i]["name"].(string) < methods[j]["name"].(string)
+	})
+
+	return map[string]interface{}{
+		"openrpc": openRPCVersion,
+		"info": map[string]interface{}{
+			"title":   "go-vite JSON-RPC",
+			"version": "1.0",
+		},
+		"methods":    methods,
+		"components": map[string]interface{}{"schemas": schemas.definitions},
+	}
+}
+
+func describeMethod(schemas *schemaBuilder, name string, callb *callback) map[string]interface{} {
+	// the trailing pointer parameters can be omitted
+	required := len(callb.argTypes)
+	for required > 0 && callb.argTypes[required-1].Kind() == reflect.Ptr {
+		required--
+	}
+	params := make([]interface{}, len(callb.argTypes))
+	for i, argType := range callb.argTypes {
+		paramName := fmt.Sprintf("param%d", i)
+		if callb.doc != nil && i < len(callb.doc.Params) && callb.doc.Params[i] != "" {
+			paramName = callb.doc.Params[i]
+		}
+		params[i] = map[string]interface{}{
+			"name":     paramName,
+			"required": i < required,
+			"schema":   schemas.schema(argType),
+		}
+	}
+
+	result := map[string]interface{}{"name": "result"}
+	switch {
+	case callb.isSubscribe:
+		result["name"] = "subscriptionId"
+		result["schema"] = map[string]interface{}{"type": "string"}
+	case callb.method.Type.NumOut() == 0 || callb.errPos == 0:
+		result["schema"] = map[string]interface{}{"type": "null"}
+	default:
+		result["schema"] = schemas.schema(callb.method.Type.Out(0))
+	}
+
+	method := map[string]interface{}{
+		"name":           name,
+		"params":         params,
+		"result":         result,
+		"x-public":       callb.public,
+		"x-subscription": callb.isSubscribe,
+	}
+	if callb.doc != nil && callb.doc.Summary != "" {
+		method["summary"] = callb.doc.Summary
+	}
+	return method
+}
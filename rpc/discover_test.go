@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type DocumentedService struct {
+	docs map[string]MethodDoc
+}
+
+func (s *DocumentedService) Echo(str string, i int, args *Args) Result {
+	return Result{str, i, args}
+}
+
+func (s *DocumentedService) MethodDocs() map[string]MethodDoc {
+	return s.docs
+}
+
+func TestDiscover(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterApi(API{Namespace: "doc", Service: &DocumentedService{docs: map[string]MethodDoc{
+		"echo": {Summary: "echoes the parameters", Params: []string{"str", "i"}},
+	}}, Public: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+
+	client := DialInProc(server)
+	defer client.Close()
+	var doc struct {
+		Methods []struct {
+			Name    string `json:"name"`
+			Summary string `json:"summary"`
+			Params  []struct {
+				Name     string                 `json:"name"`
+				Required bool                   `json:"required"`
+				Schema   map[string]interface{} `json:"schema"`
+			} `json:"params"`
+			Result struct {
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"result"`
+			Public       bool `json:"x-public"`
+			Subscription bool `json:"x-subscription"`
+		} `json:"methods"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, method := range doc.Methods {
+		names = append(names, method.Name)
+	}
+	// the methods are sorted and MethodDocs isn't a method
+	want := []string{"doc_echo", "rpc_discover", "rpc_modules", "test_echo", "test_echoWithCtx", "test_noArgsRets", "test_rets", "test_sleep", "test_subscription"}
+	if len(names) != len(want) {
+		t.Fatalf("the methods are %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("the methods are %v, want %v", names, want)
+		}
+	}
+
+	echo := doc.Methods[0]
+	if echo.Summary != "echoes the parameters" || !echo.Public || echo.Subscription {
+		t.Fatalf("unexpected doc_echo %+v", echo)
+	}
+	for i, param := range []struct {
+		name     string
+		required bool
+		typ      string
+	}{{"str", true, "string"}, {"i", true, "integer"}, {"param2", false, ""}} {
+		if echo.Params[i].Name != param.name || echo.Params[i].Required != param.required || (param.typ != "" && echo.Params[i].Schema["type"] != param.typ) {
+			t.Fatalf("unexpected parameter %d %+v", i, echo.Params[i])
+		}
+	}
+	if echo.Params[2].Schema["$ref"] != "#/components/schemas/rpc.Args" || echo.Result.Schema["$ref"] != "#/components/schemas/rpc.Result" {
+		t.Fatalf("unexpected schemas of doc_echo %+v", echo)
+	}
+	if _, ok := doc.Components.Schemas["rpc.Result"]; !ok {
+		t.Fatal("the result schema is missing")
+	}
+	if !doc.Methods[1].Public || doc.Methods[3].Public {
+		t.Fatal("the metadata methods should be public and the methods registered by name private")
+	}
+	if sub := doc.Methods[len(doc.Methods)-1]; !sub.Subscription || sub.Result.Schema["type"] != "string" {
+		t.Fatalf("unexpected subscription %+v", sub)
+	}
+
+	// the methods not allowed are left out
+	p := newPermission()
+	p.methods["doc_echo"] = true
+	allowed := server.Discover(p)["methods"].([]map[string]interface{})
+	if len(allowed) != 3 || allowed[0]["name"] != "doc_echo" || allowed[1]["name"] != "rpc_discover" {
+		t.Fatalf("unexpected methods allowed %v", allowed)
+	}
+
+	err := NewServer().RegisterName("doc", &DocumentedService{docs: map[string]MethodDoc{"unknown": {}}})
+	if err == nil {
+		t.Fatal("the doc of the unknown method should be rejected")
+	}
+}
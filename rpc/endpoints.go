@@ -26,8 +26,9 @@ import (
 	log "github.com/vitelabs/go-vite/log15"
 )
 
-// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules
-func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, timeouts HTTPTimeouts, exposeAll bool) (net.Listener, *Server, error) {
+// StartHTTPEndpoint starts the HTTP RPC endpoint, configured with cors/vhosts/modules, the requests are authenticated
+// by auth and limited by limiter if they aren't nil
+func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []string, vhosts []string, timeouts HTTPTimeouts, exposeAll bool, auth *Auth, limiter *RateLimiter) (net.Listener, *Server, error) {
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
 	for _, module := range modules {
@@ -35,9 +36,11 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	}
 	// Register all the APIs exposed by the services
 	handler := NewServer()
+	handler.auth = auth
+	handler.limiter = limiter
 	for _, api := range apis {
 		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
-			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+			if err := handler.RegisterApi(api); err != nil {
 				return nil, nil, err
 			}
 			log.Debug("HTTP registered", "namespace", api.Namespace)
@@ -57,8 +60,9 @@ func StartHTTPEndpoint(endpoint string, apis []API, modules []string, cors []str
 	return listener, handler, err
 }
 
-// StartWSEndpoint starts a websocket endpoint
-func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool) (net.Listener, *Server, error) {
+// StartWSEndpoint starts a websocket endpoint, the connections are authenticated by auth and the requests are limited
+// by limiter if they aren't nil
+func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []string, exposeAll bool, auth *Auth, limiter *RateLimiter) (net.Listener, *Server, error) {
 
 	// Generate the whitelist based on the allowed modules
 	whitelist := make(map[string]bool)
@@ -67,9 +71,11 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 	}
 	// Register all the APIs exposed by the services
 	handler := NewServer()
+	handler.auth = auth
+	handler.limiter = limiter
 	for _, api := range apis {
 		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
-			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+			if err := handler.RegisterApi(api); err != nil {
 				return nil, nil, err
 			}
 			log.Debug("WebSocket registered", "service", api.Service, "namespace", api.Namespace)
@@ -90,12 +96,29 @@ func StartWSEndpoint(endpoint string, apis []API, modules []string, wsOrigins []
 
 }
 
+// StartRESTEndpoint starts the REST gateway of routes, configured with cors/vhosts, the requests are authenticated by
+// auth and limited by limiter if they aren't nil
+func StartRESTEndpoint(endpoint string, apis []API, routes []RESTRoute, cors []string, vhosts []string, timeouts HTTPTimeouts, auth *Auth, limiter *RateLimiter) (net.Listener, error) {
+	gateway, err := NewRESTGateway(apis, routes, limiter)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	go newHTTPServer(cors, vhosts, timeouts, auth, gateway).Serve(listener)
+
+	return listener, nil
+}
+
 // StartIPCEndpoint starts an IPC endpoint.
 func StartIPCEndpoint(ipcEndpoint string, apis []API) (net.Listener, *Server, error) {
 	// Register all the APIs exposed by the services.
 	handler := NewServer()
 	for _, api := range apis {
-		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+		if err := handler.RegisterApi(api); err != nil {
 			return nil, nil, err
 		}
 		log.Debug("IPC registered", "namespace", api.Namespace)
@@ -134,7 +157,7 @@ func StartWSCliEndpoint(u *url.URL, apis []API, modules []string, exposeAll bool
 	handler := NewServer()
 	for _, api := range apis {
 		if exposeAll || whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
-			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+			if err := handler.RegisterApi(api); err != nil {
 				return nil, nil, err
 			}
 			log.Debug("WebSocket registered", "service", api.Service, "namespace", api.Namespace)
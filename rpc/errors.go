@@ -64,3 +64,34 @@ type shutdownError struct{}
 func (e *shutdownError) ErrorCode() int { return -32000 }
 
 func (e *shutdownError) Error() string { return "server is shutting down" }
+
+// the credential of the request is invalid
+type unauthorizedError struct{ message string }
+
+func (e *unauthorizedError) ErrorCode() int { return -32001 }
+
+func (e *unauthorizedError) Error() string { return "unauthorized: " + e.message }
+
+// the credential of the request isn't allowed to call the method
+type forbiddenError struct {
+	service string
+	method  string
+}
+
+func (e *forbiddenError) ErrorCode() int { return -32003 }
+
+func (e *forbiddenError) Error() string {
+	return fmt.Sprintf("The method %s%s%s is not allowed", e.service, serviceMethodSeparator, e.method)
+}
+
+// the client has exceeded the rate limit of the method
+type rateLimitedError struct {
+	service string
+	method  string
+}
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("The method %s%s%s is rate limited", e.service, serviceMethodSeparator, e.method)
+}
@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// the credential headers copied from the metadata of the gRPC requests
+var grpcCredentialHeaders = []string{"authorization", apiKeyHeader}
+
+// grpcGuard applies the authentication and the rate limiter of the HTTP and WS endpoints to the gRPC methods, a gRPC
+// method like /vitepb.Ledger/GetAccountBlockByHash is checked as the JSON-RPC method it's mapped to, so it has the
+// same permission and quota.
+type grpcGuard struct {
+	auth    *Auth
+	limiter *RateLimiter
+	// full gRPC method => namespace_method
+	methods map[string]string
+}
+
+// GRPCServerOptions returns the options of the gRPC server checking the requests by auth and limiter, either can be
+// nil. The gRPC methods are mapped to the JSON-RPC methods by methods, the unmapped ones are rejected. The credentials
+// are read from the metadata like the http headers, and every connection has at most maxStreams concurrent streams.
+func GRPCServerOptions(auth *Auth, limiter *RateLimiter, methods map[string]string, maxStreams uint32) []grpc.ServerOption {
+	guard := &grpcGuard{auth: auth, limiter: limiter, methods: methods}
+	return []grpc.ServerOption{
+		grpc.MaxConcurrentStreams(maxStreams),
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := guard.check(ctx, info.FullMethod); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := guard.check(ss.Context(), info.FullMethod); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+func (g *grpcGuard) check(ctx context.Context, fullMethod string) error {
+	name, ok := g.methods[fullMethod]
+	elems := strings.SplitN(name, serviceMethodSeparator, 2)
+	if !ok || len(elems) != 2 {
+		return status.Errorf(codes.Unimplemented, "the method %s is not available", fullMethod)
+	}
+	namespace, method := elems[0], elems[1]
+
+	client := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		client = p.Addr.String()
+		if host, _, err := net.SplitHostPort(client); err == nil {
+			client = host
+		}
+	}
+
+	if g.auth != nil {
+		r := &http.Request{Header: make(http.Header)}
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, header := range grpcCredentialHeaders {
+			if values := md.Get(header); len(values) > 0 {
+				r.Header.Set(header, values[0])
+			}
+		}
+		p, authClient, err := g.auth.authenticate(r)
+		if err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		if !p.Allow(namespace, method) {
+			return status.Errorf(codes.PermissionDenied, "the method %s is not allowed", fullMethod)
+		}
+		if authClient != "" {
+			client = authClient
+		}
+	}
+
+	if g.limiter != nil && !g.limiter.allow(client, namespace, method) {
+		return status.Errorf(codes.ResourceExhausted, "the method %s is rate limited", fullMethod)
+	}
+	return nil
+}
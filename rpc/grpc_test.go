@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCGuard(t *testing.T) {
+	auth, err := NewAuth(&AuthConfig{ApiKeys: []*ApiKeyConfig{{Key: "key", Permissions: []string{"private"}}}},
+		[]API{{Namespace: "test", Service: new(Service), Public: true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := &grpcGuard{
+		auth:    auth,
+		limiter: NewRateLimiter(&RateLimitConfig{Methods: map[string]*RateLimit{"test_echo": {Burst: 1}}}),
+		methods: map[string]string{
+			"/test.Test/Echo":    "test_echo",
+			"/test.Private/Echo": "private_echo",
+		},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}})
+	withKey := metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "key"))
+	for i, test := range []struct {
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{ctx, "/test.Unknown/Echo", codes.Unimplemented},
+		{ctx, "/test.Private/Echo", codes.PermissionDenied},
+		{metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "invalid")), "/test.Test/Echo", codes.Unauthenticated},
+		{withKey, "/test.Private/Echo", codes.OK},
+		{ctx, "/test.Test/Echo", codes.OK},
+		{ctx, "/test.Test/Echo", codes.ResourceExhausted},
+	} {
+		if code := status.Code(guard.check(test.ctx, test.method)); code != test.code {
+			t.Fatalf("test %d: the code of %s is %s, want %s", i, test.method, code, test.code)
+		}
+	}
+}
@@ -195,8 +195,16 @@ func (t *httpReadWriteNopCloser) Close() error {
 //
 // Deprecated: Server implements http.Handler
 func NewHTTPServer(cors []string, vhosts []string, timeouts HTTPTimeouts, srv *Server) *http.Server {
-	// Wrap the CORS-handler within a host-handler
-	handler := newCorsHandler(srv, cors)
+	return newHTTPServer(cors, vhosts, timeouts, srv.auth, srv)
+}
+
+// newHTTPServer creates the HTTP server of handler, the requests are authenticated by auth if it isn't nil.
+func newHTTPServer(cors []string, vhosts []string, timeouts HTTPTimeouts, auth *Auth, handler http.Handler) *http.Server {
+	// Wrap the CORS-handler within a host-handler, the preflight requests are answered before the authentication
+	if auth != nil {
+		handler = auth.handler(handler)
+	}
+	handler = newCorsHandler(handler, cors)
 	handler = newVHostHandler(vhosts, handler)
 
 	// Make sure timeout values are meaningful
@@ -235,6 +243,7 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// untilEOF and writes the response to w and order the server to process a
 	// single request.
 	ctx := r.Context()
+	ctx = withClient(ctx, clientOf(r))
 	ctx = context.WithValue(ctx, "remote", r.RemoteAddr)
 	ctx = context.WithValue(ctx, "scheme", r.Proto)
 	ctx = context.WithValue(ctx, "local", r.Host)
@@ -265,7 +274,7 @@ func validateRequest(r *http.Request) (int, error) {
 	return 0, nil
 }
 
-func newCorsHandler(srv *Server, allowedOrigins []string) http.Handler {
+func newCorsHandler(srv http.Handler, allowedOrigins []string) http.Handler {
 	// disable CORS support if user has not specified a custom CORS configuration
 	if len(allowedOrigins) == 0 {
 		return srv
@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vitelabs/go-vite/metrics"
+)
+
+const (
+	// the interval of removing the full buckets
+	bucketSweepInterval = time.Minute
+	// the buckets never refilled are removed if they're unused for bucketIdleTimeout
+	bucketIdleTimeout = time.Hour
+)
+
+var rateLimitRegistry = metrics.NewPrefixedChildRegistry(metrics.DefaultRegistry, "/rpc/ratelimit")
+
+// RateLimit is a token bucket, Rate tokens are added per second and at most Burst tokens are kept, a request takes one
+// token. The bucket of zero Rate is refilled after it's unused for an hour.
+type RateLimit struct {
+	Rate  float64 `json:"Rate"`
+	Burst int     `json:"Burst"`
+}
+
+// RateLimitConfig configures the rate limiter of the HTTP and WS endpoints. Every client, an api key or a remote ip,
+// has its own buckets.
+type RateLimitConfig struct {
+	// the limit of every method not listed in Methods, the methods aren't limited if it's nil
+	Default *RateLimit `json:"Default"`
+	// the limits by method like "ledger_getBlocksByAccAddr", or by namespace like "testapi" which is shared by all the
+	// methods of the namespace
+	Methods map[string]*RateLimit `json:"Methods"`
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	// the time when the bucket is refilled, zero if it's never refilled
+	full time.Time
+}
+
+// RateLimiter limits the requests of the clients by token buckets, it's shared by the endpoints so a client has the
+// same quota on all of them.
+type RateLimiter struct {
+	cfg *RateLimitConfig
+
+	lock      sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func NewRateLimiter(cfg *RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		cfg:       cfg,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// limitOf returns the limit of the method and the name of its bucket.
+func (l *RateLimiter) limitOf(namespace, method string) (*RateLimit, string) {
+	name := namespace + serviceMethodSeparator + method
+	if limit, ok := l.cfg.Methods[name]; ok {
+		return limit, name
+	}
+	if limit, ok := l.cfg.Methods[namespace]; ok {
+		return limit, namespace
+	}
+	return l.cfg.Default, name
+}
+
+// allow takes a token of the bucket of the method for client, it returns false if the bucket is empty.
+func (l *RateLimiter) allow(client, namespace, method string) bool {
+	limit, name := l.limitOf(namespace, method)
+	if limit == nil {
+		return true
+	}
+
+	now := time.Now()
+	l.lock.Lock()
+	if now.Sub(l.lastSweep) > bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	key := client + "|" + name
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limit.Rate
+	if bucket.tokens > float64(limit.Burst) {
+		bucket.tokens = float64(limit.Burst)
+	}
+	bucket.last = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	if limit.Rate > 0 {
+		bucket.full = now.Add(time.Duration((float64(limit.Burst) - bucket.tokens) / limit.Rate * float64(time.Second)))
+	}
+	l.lock.Unlock()
+
+	if allowed {
+		metrics.GetOrRegisterCounter("/"+name+"/allowed", rateLimitRegistry).Inc(1)
+	} else {
+		metrics.GetOrRegisterCounter("/"+name+"/limited", rateLimitRegistry).Inc(1)
+	}
+	return allowed
+}
+
+// sweep removes the refilled buckets, they are the same as the new ones, and the idle buckets never refilled.
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.full.IsZero() {
+			if now.Sub(bucket.last) > bucketIdleTimeout {
+				delete(l.buckets, key)
+			}
+		} else if now.After(bucket.full) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+type clientKey struct{}
+
+func withClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client of the request for the rate limiter, the authenticated api key or the remote
+// ip, empty if the request isn't from the HTTP or WS endpoints.
+func ClientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
+
+// clientOf returns the client of r for the rate limiter, the authenticated api key or the remote ip.
+func clientOf(r *http.Request) string {
+	if client, ok := r.Context().Value(clientKey{}).(string); ok {
+		return client
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
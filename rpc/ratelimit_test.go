@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(&RateLimitConfig{
+		Default: &RateLimit{Rate: 1000, Burst: 1},
+		Methods: map[string]*RateLimit{
+			"test_echo": {Rate: 0, Burst: 2},
+			"private":   {Rate: 0, Burst: 1},
+		},
+	})
+
+	if !limiter.allow("a", "test", "echo") || !limiter.allow("a", "test", "echo") || limiter.allow("a", "test", "echo") {
+		t.Fatal("test_echo should be limited after 2 requests")
+	}
+	if !limiter.allow("b", "test", "echo") {
+		t.Fatal("the clients should be limited separately")
+	}
+	if !limiter.allow("a", "private", "echo") || limiter.allow("a", "private", "rets") {
+		t.Fatal("the methods of a namespace should share the namespace limit")
+	}
+	if !limiter.allow("a", "test", "rets") || limiter.allow("a", "test", "rets") {
+		t.Fatal("the default limit should be applied")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if !limiter.allow("a", "test", "rets") {
+		t.Fatal("the bucket should be refilled")
+	}
+	if limiter.cfg.Default = nil; !limiter.allow("a", "test", "noArgsRets") || !limiter.allow("a", "test", "noArgsRets") {
+		t.Fatal("the methods without limit shouldn't be limited")
+	}
+
+	limiter.sweep(time.Now().Add(time.Second))
+	if _, ok := limiter.buckets["a|test_rets"]; ok {
+		t.Fatal("the refilled bucket should be removed")
+	}
+	if _, ok := limiter.buckets["a|test_echo"]; !ok {
+		t.Fatal("the bucket never refilled should be kept")
+	}
+	limiter.sweep(time.Now().Add(2 * bucketIdleTimeout))
+	if len(limiter.buckets) != 0 {
+		t.Fatalf("the idle buckets should be removed, %v", limiter.buckets)
+	}
+}
+
+func TestRateLimitedBatch(t *testing.T) {
+	auth, err := NewAuth(&AuthConfig{ApiKeys: []*ApiKeyConfig{{Key: "key", Permissions: []string{"*"}}}}, []API{{Namespace: "test", Service: new(Service), Public: true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer()
+	server.auth = auth
+	server.limiter = NewRateLimiter(&RateLimitConfig{Default: &RateLimit{Burst: 100}, Methods: map[string]*RateLimit{"test_rets": {Burst: 2}}})
+	server.RegisterName("test", new(Service))
+	httpServer := httptest.NewServer(NewHTTPServer(nil, nil, HTTPTimeouts{}, server).Handler)
+	defer httpServer.Close()
+
+	batch := func(apiKey string) []int {
+		body := `[{"jsonrpc":"2.0","id":1,"method":"test_rets"},{"jsonrpc":"2.0","id":2,"method":"test_rets"},{"jsonrpc":"2.0","id":3,"method":"test_rets"},{"jsonrpc":"2.0","id":4,"method":"test_unknown"}]`
+		req, _ := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(body))
+		req.Header.Set("content-type", contentType)
+		if apiKey != "" {
+			req.Header.Set(apiKeyHeader, apiKey)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var results []struct {
+			Error *jsonError `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			t.Fatal(err)
+		}
+		var codes []int
+		for _, result := range results {
+			if result.Error == nil {
+				codes = append(codes, 0)
+			} else {
+				codes = append(codes, result.Error.Code)
+			}
+		}
+		return codes
+	}
+
+	// the requests of a batch are counted one by one, the api key has its own quota
+	for _, apiKey := range []string{"", "key"} {
+		codes := batch(apiKey)
+		if len(codes) != 4 || codes[0] != 0 || codes[1] != 0 || codes[2] != -32005 || codes[3] == 0 {
+			t.Fatalf("the codes of %q are %v", apiKey, codes)
+		}
+	}
+	// the unknown methods aren't limited
+	for key := range server.limiter.buckets {
+		if strings.HasSuffix(key, "test_unknown") {
+			t.Fatalf("the bucket %s of the unknown method shouldn't be created", key)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package rpc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	log "github.com/vitelabs/go-vite/log15"
+)
+
+// OpenAPIPath is the path of the OpenAPI document of the REST gateway.
+const OpenAPIPath = "/openapi.json"
+
+const (
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	mutableCacheControl   = "no-cache"
+)
+
+// RESTRoute maps the GET requests of a resource path to a read-only rpc method. The segments of Path like {address}
+// are bound to the parameters of the same names, the other parameters are read from the query string.
+type RESTRoute struct {
+	Path    string   // e.g. /accounts/{address}/blocks
+	Method  string   // e.g. ledger_getBlocksByAccAddr
+	Params  []string // the names of the parameters of the method in order
+	Summary string   // the summary of the operation in the OpenAPI document
+
+	// the values of the omitted query parameters, in JSON or plain text like the query parameters
+	Defaults map[string]string
+
+	// Immutable reports whether the result never changes, e.g. a confirmed block, the immutable results are cached
+	// by the clients without revalidation. Nil means the results are always revalidated by their etags.
+	Immutable func(result interface{}) bool
+}
+
+type restRoute struct {
+	*RESTRoute
+	segments []string
+	service  string
+	method   string
+	callb    *callback
+}
+
+// RESTGateway serves the read-only rpc methods as resources by their routes, the first matched route of a request
+// is served. The results are the plain JSON of the rpc results, and the errors are the JSON-RPC errors in the error
+// field of an object.
+type RESTGateway struct {
+	routes  []*restRoute
+	limiter *RateLimiter
+	openAPI []byte
+}
+
+// NewRESTGateway creates the gateway of the routes to the methods of apis, the requests are limited by limiter if
+// it isn't nil. The permission of the authenticated requests is checked as the JSON-RPC requests.
+func NewRESTGateway(apis []API, routes []RESTRoute, limiter *RateLimiter) (*RESTGateway, error) {
+	server := NewServer()
+	for _, api := range apis {
+		if err := server.RegisterApi(api); err != nil {
+			return nil, err
+		}
+	}
+
+	g := &RESTGateway{limiter: limiter}
+	for i := range routes {
+		route := &restRoute{RESTRoute: &routes[i], segments: splitRESTPath(routes[i].Path)}
+		elem := strings.SplitN(route.Method, serviceMethodSeparator, 2)
+		if len(elem) != 2 {
+			return nil, fmt.Errorf("invalid method %s of %s", route.Method, route.Path)
+		}
+		route.service, route.method = elem[0], elem[1]
+		svc, ok := server.services[route.service]
+		if ok {
+			route.callb, ok = svc.callbacks[route.method]
+		}
+		if !ok {
+			return nil, fmt.Errorf("the method %s of %s isn't registered", route.Method, route.Path)
+		}
+		if len(route.Params) != len(route.callb.argTypes) {
+			return nil, fmt.Errorf("%s expects %d parameters, %s has %d", route.Method, len(route.callb.argTypes), route.Path, len(route.Params))
+		}
+		if route.callb.method.Type.NumOut() == 0 || route.callb.errPos == 0 {
+			return nil, fmt.Errorf("%s of %s has no result", route.Method, route.Path)
+		}
+		for _, segment := range route.segments {
+			if name, ok := pathVariable(segment); ok && !route.hasParam(name) {
+				return nil, fmt.Errorf("%s has no parameter %s of %s", route.Method, name, route.Path)
+			}
+		}
+		g.routes = append(g.routes, route)
+		log.Debug("REST registered", "path", route.Path, "method", route.Method)
+	}
+
+	doc, err := json.Marshal(g.openAPIDocument())
+	if err != nil {
+		return nil, err
+	}
+	g.openAPI = doc
+	return g, nil
+}
+
+func splitRESTPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// pathVariable returns the name of the variable segment like {name}.
+func pathVariable(segment string) (string, bool) {
+	if len(segment) > 2 && segment[0] == '{' && segment[len(segment)-1] == '}' {
+		return segment[1 : len(segment)-1], true
+	}
+	return "", false
+}
+
+func (route *restRoute) hasParam(name string) bool {
+	for _, param := range route.Params {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (route *restRoute) isPathVariable(name string) bool {
+	for _, segment := range route.segments {
+		if v, ok := pathVariable(segment); ok && v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequired reports whether the request must provide the parameter, the omitted pointer parameters are nil.
+func (route *restRoute) isRequired(i int) bool {
+	if route.isPathVariable(route.Params[i]) {
+		return true
+	}
+	_, hasDefault := route.Defaults[route.Params[i]]
+	return !hasDefault && route.callb.argTypes[i].Kind() != reflect.Ptr
+}
+
+func (g *RESTGateway) match(path string) (*restRoute, map[string]string) {
+	segments := splitRESTPath(path)
+	for _, route := range g.routes {
+		if len(route.segments) != len(segments) {
+			continue
+		}
+		vars := make(map[string]string)
+		matched := true
+		for i, segment := range route.segments {
+			if name, ok := pathVariable(segment); ok {
+				vars[name] = segments[i]
+			} else if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route, vars
+		}
+	}
+	return nil, nil
+}
+
+// parseRESTParam parses the value as JSON, or as a JSON string if it isn't valid JSON of t, so that the strings
+// needn't be quoted in the paths and query strings.
+func parseRESTParam(t reflect.Type, value string) (reflect.Value, error) {
+	v := reflect.New(t)
+	if err := json.Unmarshal([]byte(value), v.Interface()); err != nil {
+		quoted, _ := json.Marshal(value)
+		if err := json.Unmarshal(quoted, v.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return v.Elem(), nil
+}
+
+func (route *restRoute) parseArgs(vars map[string]string, query url.Values) ([]reflect.Value, error) {
+	args := make([]reflect.Value, len(route.Params))
+	for i, name := range route.Params {
+		value, ok := vars[name]
+		if !ok {
+			if values := query[name]; len(values) > 0 {
+				value, ok = values[0], true
+			}
+		}
+		if !ok {
+			value, ok = route.Defaults[name]
+		}
+		if !ok {
+			if route.isRequired(i) {
+				return nil, fmt.Errorf("missing value for required parameter %s", name)
+			}
+			args[i] = reflect.Zero(route.callb.argTypes[i])
+			continue
+		}
+		arg, err := parseRESTParam(route.callb.argTypes[i], value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %s: %v", name, err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// ServeHTTP serves the GET requests of the resources and the OpenAPI document.
+func (g *RESTGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeRESTError(w, http.StatusMethodNotAllowed, &invalidRequestError{"method not allowed"})
+		return
+	}
+	if r.URL.Path == OpenAPIPath {
+		writeRESTResult(w, r, g.openAPI, false)
+		return
+	}
+
+	route, vars := g.match(r.URL.Path)
+	if route == nil {
+		writeRESTError(w, http.StatusNotFound, &invalidRequestError{fmt.Sprintf("The resource %s does not exist", r.URL.Path)})
+		return
+	}
+	if permission := PermissionFromContext(r.Context()); permission != nil && !permission.Allow(route.service, route.method) {
+		writeRESTError(w, http.StatusForbidden, &forbiddenError{route.service, route.method})
+		return
+	}
+	if g.limiter != nil && !g.limiter.allow(clientOf(r), route.service, route.method) {
+		writeRESTError(w, http.StatusTooManyRequests, &rateLimitedError{route.service, route.method})
+		return
+	}
+	args, err := route.parseArgs(vars, r.URL.Query())
+	if err != nil {
+		writeRESTError(w, http.StatusBadRequest, &invalidParamsError{err.Error()})
+		return
+	}
+
+	arguments := []reflect.Value{route.callb.rcvr}
+	if route.callb.hasCtx {
+		arguments = append(arguments, reflect.ValueOf(r.Context()))
+	}
+	reply := route.callb.method.Func.Call(append(arguments, args...))
+	if route.callb.errPos >= 0 && !reply[route.callb.errPos].IsNil() {
+		e := reply[route.callb.errPos].Interface().(error)
+		if ne, ok := e.(Error); ok {
+			writeRESTError(w, http.StatusInternalServerError, ne)
+		} else {
+			writeRESTError(w, http.StatusInternalServerError, &callbackError{e.Error()})
+		}
+		return
+	}
+
+	result := reply[0]
+	switch result.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		if result.IsNil() {
+			writeRESTError(w, http.StatusNotFound, &invalidRequestError{fmt.Sprintf("The resource %s does not exist", r.URL.Path)})
+			return
+		}
+	case reflect.Slice:
+		if result.IsNil() {
+			result = reflect.MakeSlice(result.Type(), 0, 0)
+		}
+	}
+	body, err := json.Marshal(result.Interface())
+	if err != nil {
+		writeRESTError(w, http.StatusInternalServerError, &callbackError{err.Error()})
+		return
+	}
+	writeRESTResult(w, r, body, route.Immutable != nil && route.Immutable(reply[0].Interface()))
+}
+
+// writeRESTResult writes body with its etag, the request revalidating the same etag is answered without body.
+func writeRESTResult(w http.ResponseWriter, r *http.Request, body []byte, immutable bool) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	if immutable {
+		w.Header().Set("Cache-Control", immutableCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", mutableCacheControl)
+	}
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if tag = strings.TrimSpace(tag); tag == etag || tag == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+func writeRESTError(w http.ResponseWriter, status int, err Error) {
+	w.Header().Set("content-type", contentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": jsonError{Code: err.ErrorCode(), Message: err.Error()},
+	})
+}
+
+// openAPIDocument describes the routes by OpenAPI 3, the schemas are derived from the types of the parameters and
+// results of the methods.
+func (g *RESTGateway) openAPIDocument() map[string]interface{} {
+	schemas := newSchemaBuilder("#/components/schemas/")
+	schemas.definitions["Error"] = map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"error": schemas.schema(reflect.TypeOf(jsonError{})),
+		},
+	}
+	errorResponse := map[string]interface{}{
+		"description": "the JSON-RPC error",
+		"content": map[string]interface{}{
+			contentType: map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"}},
+		},
+	}
+
+	paths := make(map[string]interface{})
+	for _, route := range g.routes {
+		if _, ok := paths[route.Path]; ok {
+			continue // shadowed by the former route
+		}
+		params := make([]interface{}, len(route.Params))
+		for i, name := range route.Params {
+			param := map[string]interface{}{
+				"name":     name,
+				"in":       "query",
+				"required": route.isRequired(i),
+				"schema":   schemas.schema(route.callb.argTypes[i]),
+			}
+			if route.isPathVariable(name) {
+				param["in"] = "path"
+			} else if def, ok := route.Defaults[name]; ok {
+				param["description"] = "defaults to " + def
+			}
+			params[i] = param
+		}
+		operation := map[string]interface{}{
+			"operationId": route.Method,
+			"tags":        []string{route.service},
+			"parameters":  params,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "the result of " + route.Method,
+					"content": map[string]interface{}{
+						contentType: map[string]interface{}{"schema": schemas.schema(route.callb.method.Type.Out(0))},
+					},
+				},
+				"304":     map[string]interface{}{"description": "the result isn't modified since the etag"},
+				"default": errorResponse,
+			},
+		}
+		if route.Summary != "" {
+			operation["summary"] = route.Summary
+		}
+		paths[route.Path] = map[string]interface{}{"get": operation}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "go-vite REST gateway",
+			"version": "1.0",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas.definitions},
+	}
+}
@@ -0,0 +1,163 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type RESTService struct{}
+
+type RESTBlock struct {
+	Hash      string `json:"hash"`
+	Height    int    `json:"height"`
+	Confirmed bool   `json:"confirmed"`
+}
+
+func (s *RESTService) GetBlock(hash string) (*RESTBlock, error) {
+	if hash == "missing" {
+		return nil, nil
+	}
+	return &RESTBlock{Hash: hash, Confirmed: hash == "confirmed"}, nil
+}
+
+func (s *RESTService) GetBlocks(owner string, index int, count int, token *string) ([]*RESTBlock, error) {
+	var blocks []*RESTBlock
+	for i := 0; i < count; i++ {
+		blocks = append(blocks, &RESTBlock{Hash: owner, Height: index + i})
+	}
+	return blocks, nil
+}
+
+func (s *RESTService) Fail() (string, error) {
+	return "", errors.New("failed")
+}
+
+var restTestRoutes = []RESTRoute{
+	{Path: "/blocks/{hash}", Method: "test_getBlock", Params: []string{"hash"}, Immutable: func(result interface{}) bool {
+		return result.(*RESTBlock).Confirmed
+	}},
+	{Path: "/owners/{owner}/blocks", Method: "test_getBlocks", Params: []string{"owner", "index", "count", "token"}, Defaults: map[string]string{"index": "0"}},
+	{Path: "/fail", Method: "test_fail"},
+}
+
+func TestRESTGateway(t *testing.T) {
+	gateway, err := NewRESTGateway([]API{{Namespace: "test", Service: new(RESTService)}}, restTestRoutes, NewRateLimiter(&RateLimitConfig{
+		Methods: map[string]*RateLimit{"test_fail": {Burst: 1}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+
+	get := func(path string, etag string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp, strings.TrimSpace(string(body))
+	}
+
+	resp, body := get("/blocks/abc", "")
+	if resp.StatusCode != http.StatusOK || body != `{"hash":"abc","height":0,"confirmed":false}` {
+		t.Fatalf("unexpected response %d %s", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Cache-Control") != mutableCacheControl {
+		t.Fatalf("the unconfirmed block should be revalidated, Cache-Control is %s", resp.Header.Get("Cache-Control"))
+	}
+	if resp, _ := get("/blocks/abc", resp.Header.Get("ETag")); resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("the same etag should be not modified, got %d", resp.StatusCode)
+	}
+	if resp, _ := get("/blocks/confirmed", ""); resp.Header.Get("Cache-Control") != immutableCacheControl {
+		t.Fatalf("the confirmed block should be immutable, Cache-Control is %s", resp.Header.Get("Cache-Control"))
+	}
+
+	for path, want := range map[string]string{
+		"/owners/abc/blocks?count=2":         `[{"hash":"abc","height":0,"confirmed":false},{"hash":"abc","height":1,"confirmed":false}]`,
+		"/owners/abc/blocks?count=1&index=5": `[{"hash":"abc","height":5,"confirmed":false}]`,
+		"/owners/abc/blocks?count=0":         `[]`,
+	} {
+		if resp, body := get(path, ""); resp.StatusCode != http.StatusOK || body != want {
+			t.Fatalf("unexpected response of %s: %d %s", path, resp.StatusCode, body)
+		}
+	}
+
+	for path, status := range map[string]int{
+		"/blocks/missing":              http.StatusNotFound,
+		"/unknown":                     http.StatusNotFound,
+		"/owners/abc/blocks":           http.StatusBadRequest,
+		"/owners/abc/blocks?count=abc": http.StatusBadRequest,
+		"/fail":                        http.StatusInternalServerError,
+	} {
+		if resp, _ := get(path, ""); resp.StatusCode != status {
+			t.Fatalf("the status of %s should be %d, got %d", path, status, resp.StatusCode)
+		}
+	}
+	if resp, _ := get("/fail", ""); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("the second request of test_fail should be limited, got %d", resp.StatusCode)
+	}
+	if resp, err := http.Post(server.URL+"/blocks/abc", contentType, nil); err != nil || resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("the POST requests should be rejected, %v", err)
+	}
+
+	var doc struct {
+		Paths map[string]struct {
+			Get struct {
+				OperationId string `json:"operationId"`
+				Parameters  []struct {
+					Name     string                 `json:"name"`
+					In       string                 `json:"in"`
+					Required bool                   `json:"required"`
+					Schema   map[string]interface{} `json:"schema"`
+				} `json:"parameters"`
+			} `json:"get"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if _, body := get(OpenAPIPath, ""); json.Unmarshal([]byte(body), &doc) != nil {
+		t.Fatalf("invalid OpenAPI document %s", body)
+	}
+	operation := doc.Paths["/owners/{owner}/blocks"].Get
+	if operation.OperationId != "test_getBlocks" || len(operation.Parameters) != 4 {
+		t.Fatalf("unexpected operation %+v", operation)
+	}
+	for i, want := range []struct {
+		in       string
+		required bool
+		typ      string
+	}{{"path", true, "string"}, {"query", false, "integer"}, {"query", true, "integer"}, {"query", false, "string"}} {
+		param := operation.Parameters[i]
+		if param.In != want.in || param.Required != want.required || param.Schema["type"] != want.typ {
+			t.Fatalf("unexpected parameter %+v", param)
+		}
+	}
+	if _, ok := doc.Components.Schemas["rpc.RESTBlock"]; !ok {
+		t.Fatalf("the result schema is missing, %v", doc.Components.Schemas)
+	}
+}
+
+func TestRESTGatewayInvalidRoutes(t *testing.T) {
+	apis := []API{{Namespace: "test", Service: new(RESTService)}}
+	for _, route := range []RESTRoute{
+		{Path: "/blocks", Method: "test_getBlock"},
+		{Path: "/blocks/{id}", Method: "test_getBlock", Params: []string{"hash"}},
+		{Path: "/blocks/{hash}", Method: "test_unknown", Params: []string{"hash"}},
+	} {
+		if _, err := NewRESTGateway(apis, []RESTRoute{route}, nil); err == nil {
+			t.Fatalf("the route %s of %s should be rejected", route.Path, route.Method)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	bigIntType        = reflect.TypeOf(big.Int{})
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaBuilder derives the JSON schemas of the go types as encoding/json marshals them. The named structs are
+// collected in definitions and referred by refPrefix+name, so are the recursive ones.
+type schemaBuilder struct {
+	refPrefix   string
+	definitions map[string]interface{}
+}
+
+func newSchemaBuilder(refPrefix string) *schemaBuilder {
+	return &schemaBuilder{
+		refPrefix:   refPrefix,
+		definitions: make(map[string]interface{}),
+	}
+}
+
+func implements(t reflect.Type, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+// schema returns the schema of t, the pointers are the schemas of their elements.
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == bigIntType:
+		return map[string]interface{}{"type": "integer"}
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() != reflect.Interface && implements(t, textMarshalerType):
+		// e.g. the addresses, hashes and token ids
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() != reflect.Interface && implements(t, jsonMarshalerType):
+		// the shape is unknown
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := t.String()
+		if _, ok := b.definitions[name]; !ok {
+			// the placeholder stops the recursion of the self-referencing types
+			b.definitions[name] = nil
+			b.definitions[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": b.refPrefix + name}
+	default:
+		// interface{} and the types which can't be marshaled accept anything
+		return map[string]interface{}{}
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	b.addProperties(t, properties)
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// addProperties adds the json fields of the struct t to properties, the fields of the embedded structs are promoted
+// unless they're shadowed by the fields of t.
+func (b *schemaBuilder) addProperties(t reflect.Type, properties map[string]interface{}) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = b.schema(field.Type)
+	}
+	for _, et := range embedded {
+		promoted := make(map[string]interface{})
+		b.addProperties(et, promoted)
+		for name, s := range promoted {
+			if _, ok := properties[name]; !ok {
+				properties[name] = s
+			}
+		}
+	}
+}
@@ -53,7 +53,7 @@ func NewServer() *Server {
 	// register a default service which will provide meta information about the RPC service such as the services and
 	// methods it offers.
 	rpcService := &RPCService{server}
-	server.RegisterName(MetadataApi, rpcService)
+	server.register(MetadataApi, rpcService, true)
 
 	return server
 }
@@ -73,10 +73,26 @@ func (s *RPCService) Modules() map[string]string {
 	return modules
 }
 
+// Discover returns the OpenRPC document of the methods and subscriptions of the server which the caller is allowed
+// to call.
+func (s *RPCService) Discover(ctx context.Context) map[string]interface{} {
+	return s.server.Discover(PermissionFromContext(ctx))
+}
+
 // RegisterName will create a service for the given rcvr type under the given name. When no methods on the given rcvr
 // match the criteria to be either a RPC method or a subscription an error is returned. Otherwise a new service is
-// created and added to the service collection this server instance serves.
+// created and added to the service collection this server instance serves. The methods are described as private by
+// rpc_discover, use RegisterApi for the public ones.
 func (s *Server) RegisterName(name string, rcvr interface{}) error {
+	return s.register(name, rcvr, false)
+}
+
+// RegisterApi registers the service of api under its namespace like RegisterName.
+func (s *Server) RegisterApi(api API) error {
+	return s.register(api.Namespace, api.Service, api.Public)
+}
+
+func (s *Server) register(name string, rcvr interface{}, public bool) error {
 	if s.services == nil {
 		s.services = make(serviceRegistry)
 	}
@@ -97,6 +113,9 @@ func (s *Server) RegisterName(name string, rcvr interface{}) error {
 	if len(methods) == 0 && len(subscriptions) == 0 {
 		return fmt.Errorf("Service %T doesn't have any suitable methods/subscriptions to expose", rcvr)
 	}
+	if err := annotate(rcvr, public, methods, subscriptions); err != nil {
+		return err
+	}
 
 	// already a previous service register under given name, merge methods/subscriptions
 	if regsvc, present := s.services[name]; present {
@@ -157,7 +176,7 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 
 	// test if the server is ordered to stop
 	for atomic.LoadInt32(&s.run) == 1 {
-		reqs, batch, err := s.readRequest(codec)
+		reqs, batch, err := s.readRequest(ctx, codec)
 		if err != nil {
 			// If a parsing error occurred, send an error
 			if err.Error() != "EOF" {
@@ -212,8 +231,12 @@ func (s *Server) serveRequest(ctx context.Context, codec ServerCodec, singleShot
 // response back using the given codec. It will block until the codec is closed or the server is
 // stopped. In either case the codec is closed.
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) error {
+	return s.serveCodec(context.Background(), codec, options)
+}
+
+func (s *Server) serveCodec(ctx context.Context, codec ServerCodec, options CodecOption) error {
 	defer codec.Close()
-	return s.serveRequest(context.Background(), codec, false, options)
+	return s.serveRequest(ctx, codec, false, options)
 }
 
 // ServeSingleRequest reads and processes a single RPC request from the given codec. It will not
@@ -375,12 +398,20 @@ func (s *Server) execBatch(ctx context.Context, codec ServerCodec, requests []*s
 
 // readRequest requests the next (batch) request from the codec. It will return the collection
 // of requests, an indication if the request was a batch, the invalid request identifier and an
-// error when the request could not be read/parsed.
-func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error) {
+// error when the request could not be read/parsed. The requests not allowed by the permission
+// of ctx are rejected, so are the requests exceeding the rate limit of the client of ctx, every
+// request of a batch is counted.
+func (s *Server) readRequest(ctx context.Context, codec ServerCodec) ([]*serverRequest, bool, Error) {
 	reqs, batch, err := codec.ReadRequestHeaders()
 	if err != nil {
 		return nil, batch, err
 	}
+	permission := PermissionFromContext(ctx)
+	client := ClientFromContext(ctx)
+	// the limiter is applied after the method is resolved, so the unknown methods create no bucket
+	limited := func(r rpcRequest) bool {
+		return s.limiter != nil && client != "" && !s.limiter.allow(client, r.service, r.method)
+	}
 
 	requests := make([]*serverRequest, len(reqs))
 
@@ -410,8 +441,17 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 			continue
 		}
 
+		if permission != nil && !permission.Allow(r.service, r.method) {
+			requests[i] = &serverRequest{id: r.id, err: &forbiddenError{r.service, r.method}}
+			continue
+		}
+
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
+				if limited(r) {
+					requests[i] = &serverRequest{id: r.id, err: &rateLimitedError{r.service, r.method}}
+					continue
+				}
 				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
 				if r.params != nil && len(callb.argTypes) > 0 {
 					argTypes := []reflect.Type{reflect.TypeOf("")}
@@ -429,6 +469,10 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 		}
 
 		if callb, ok := svc.callbacks[r.method]; ok { // lookup RPC method
+			if limited(r) {
+				requests[i] = &serverRequest{id: r.id, err: &rateLimitedError{r.service, r.method}}
+				continue
+			}
 			requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
 			if r.params != nil && len(callb.argTypes) > 0 {
 				if args, err := codec.ParseRequestArguments(callb.argTypes, r.params); err == nil {
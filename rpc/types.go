@@ -43,6 +43,8 @@ type callback struct {
 	hasCtx      bool           // method's first argument is a context (not included in argTypes)
 	errPos      int            // err return idx, of -1 when method cannot return error
 	isSubscribe bool           // indication if the callback is a subscription
+	public      bool           // indication if the callback is registered by a public api
+	doc         *MethodDoc     // the annotation of the callback, nil if it isn't documented
 }
 
 // service represents a registered object
@@ -70,6 +72,10 @@ type subscriptions map[string]*callback  // collection of subscription callbacks
 // Server represents a RPC server
 type Server struct {
 	services serviceRegistry
+	// nil means the http and websocket requests aren't authenticated
+	auth *Auth
+	// nil means the http and websocket requests aren't rate limited
+	limiter *RateLimiter
 
 	run      int32
 	codecsMu sync.Mutex
@@ -119,6 +119,9 @@ METHODS:
 		if method.PkgPath != "" { // method must be exported
 			continue
 		}
+		if method.Name == methodDocsName && typ.Implements(documentedType) { // the annotations aren't a method
+			continue
+		}
 
 		var h callback
 		h.isSubscribe = isPubSub(mtype)
@@ -55,8 +55,11 @@ var websocketJSONCodec = websocket.Codec{
 //
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
+//
+// If the authentication is enabled, the credential is checked before the upgrade and its permission is
+// kept by the connection.
 func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
-	return websocket.Server{
+	handler := websocket.Server{
 		Handshake: wsHandshakeValidator(allowedOrigins),
 		Handler: func(conn *websocket.Conn) {
 			// Create a custom encode/decode pair to enforce payload size and number encoding
@@ -68,9 +71,17 @@ func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			decoder := func(v interface{}) error {
 				return websocketJSONCodec.Receive(conn, v)
 			}
-			srv.ServeCodec(NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
+			ctx := withClient(context.Background(), clientOf(conn.Request()))
+			if permission := PermissionFromContext(conn.Request().Context()); permission != nil {
+				ctx = withPermission(ctx, permission)
+			}
+			srv.serveCodec(ctx, NewCodec(conn, encoder, decoder), OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
+	if srv.auth != nil {
+		return srv.auth.handler(handler)
+	}
+	return handler
 }
 
 // NewWSServer creates a new websocket RPC server around an API provider.
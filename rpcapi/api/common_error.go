@@ -3,5 +3,9 @@ package api
 import "errors"
 
 var (
-	ErrStrToBigInt = errors.New("convert to big.Int failed")
+	ErrStrToBigInt    = errors.New("convert to big.Int failed")
+	ErrTooManyFilters = errors.New("too many filters")
+	ErrPageCount      = errors.New("the count of the page is too large")
+	ErrPageIndex      = errors.New("the index of the page is too large")
+	ErrJobRunning     = errors.New("the job is running")
 )
@@ -6,12 +6,14 @@ import (
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/common/helper"
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/vm"
 	"github.com/vitelabs/go-vite/vm/abi"
 	"github.com/vitelabs/go-vite/vm/util"
 	"github.com/vitelabs/go-vite/vm_context"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 	"strings"
 )
 
@@ -112,3 +114,33 @@ func (c *ContractApi) CallOffChainMethod(param CallOffChainMethodParam) ([]byte,
 	}
 	return vm.NewVM().OffChainReader(db, param.OffChainCode, param.Data)
 }
+
+// GetStorage returns the hex value of the storage key of a contract, key is hex too. The latest state is read if
+// snapshot is nil, otherwise the state confirmed by the snapshot block of snapshot, a snapshot height or hash.
+func (c *ContractApi) GetStorage(addr types.Address, key string, snapshot *string) (*string, error) {
+	keyBytes, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var db vmctxt_interface.VmDatabase
+	if snapshot == nil {
+		db, err = vm_context.NewVmContext(c.chain, nil, nil, &addr)
+	} else {
+		var snapshotBlock *ledger.SnapshotBlock
+		if snapshotBlock, err = getStateSnapshotBlock(c.chain, snapshot); err != nil {
+			return nil, err
+		}
+		db, err = newVmContextAtSnapshot(c.chain, snapshotBlock, &addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	value := db.GetStorage(&addr, keyBytes)
+	if value == nil {
+		return nil, nil
+	}
+	valueHex := hex.EncodeToString(value)
+	return &valueHex, nil
+}
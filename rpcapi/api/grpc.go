@@ -0,0 +1,342 @@
+package api
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vitepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The gRPC apis serve the services of vitepb/api.proto by the JSON-RPC apis, so a query returns the same blocks on
+// both of them. The receive blocks carry the token, amount and fee of their send blocks like the JSON-RPC blocks.
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func notFound(what string) error {
+	return status.Error(codes.NotFound, what+" not found")
+}
+
+func rpcBlockToProto(block *AccountBlock, err error) (*vitepb.AccountBlock, error) {
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, notFound("account block")
+	}
+	return block.AccountBlock.Proto(), nil
+}
+
+func rpcBlocksToProto(blocks []*AccountBlock, err error) (*vitepb.AccountBlocks, error) {
+	if err != nil {
+		return nil, err
+	}
+	pb := &vitepb.AccountBlocks{}
+	for _, block := range blocks {
+		pb.Blocks = append(pb.Blocks, block.AccountBlock.Proto())
+	}
+	return pb, nil
+}
+
+func snapshotBlockToProto(block *ledger.SnapshotBlock, err error) (*vitepb.SnapshotBlock, error) {
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, notFound("snapshot block")
+	}
+	return block.Proto(), nil
+}
+
+func accountInfoToProto(info *RpcAccountInfo, err error) (*vitepb.AccountInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, notFound("account")
+	}
+
+	totalNumber, err := strconv.ParseUint(info.TotalNumber, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	pb := &vitepb.AccountInfo{
+		Address:     info.AccountAddress.Bytes(),
+		TotalNumber: totalNumber,
+	}
+	for tokenId, balance := range info.TokenBalanceInfoMap {
+		amount, ok := new(big.Int).SetString(balance.TotalAmount, 10)
+		if !ok {
+			return nil, ErrStrToBigInt
+		}
+		tokenBalance := &vitepb.TokenBalance{
+			TokenId: tokenId.Bytes(),
+			Amount:  amount.Bytes(),
+		}
+		if balance.Number != nil {
+			if tokenBalance.Number, err = strconv.ParseUint(*balance.Number, 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		pb.Balances = append(pb.Balances, tokenBalance)
+	}
+	return pb, nil
+}
+
+func bytesToAddresses(list [][]byte) ([]types.Address, error) {
+	addrs := make([]types.Address, 0, len(list))
+	for _, b := range list {
+		addr, err := types.BytesToAddress(b)
+		if err != nil {
+			return nil, invalidArgument(err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+type GrpcLedgerApi struct {
+	api   *LedgerApi
+	chain chain.Chain
+}
+
+func NewGrpcLedgerApi(vite *vite.Vite) *GrpcLedgerApi {
+	return &GrpcLedgerApi{
+		api:   NewLedgerApi(vite),
+		chain: vite.Chain(),
+	}
+}
+
+func (l *GrpcLedgerApi) GetAccountBlockByHash(ctx context.Context, req *vitepb.HashRequest) (*vitepb.AccountBlock, error) {
+	hash, err := types.BytesToHash(req.Hash)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return rpcBlockToProto(l.api.GetBlockByHash(&hash))
+}
+
+func (l *GrpcLedgerApi) GetAccountBlockByHeight(ctx context.Context, req *vitepb.AccountBlockHeightRequest) (*vitepb.AccountBlock, error) {
+	addr, err := types.BytesToAddress(req.Address)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return rpcBlockToProto(l.api.GetBlockByHeight(addr, strconv.FormatUint(req.Height, 10)))
+}
+
+func (l *GrpcLedgerApi) GetAccountBlocksByAddress(ctx context.Context, req *vitepb.AccountBlocksRequest) (*vitepb.AccountBlocks, error) {
+	addr, err := types.BytesToAddress(req.Address)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return rpcBlocksToProto(l.api.GetBlocksByAccAddr(addr, int(req.Index), int(req.Count)))
+}
+
+func (l *GrpcLedgerApi) GetLatestAccountBlock(ctx context.Context, req *vitepb.AddressRequest) (*vitepb.AccountBlock, error) {
+	addr, err := types.BytesToAddress(req.Address)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return rpcBlockToProto(l.api.GetLatestBlock(addr))
+}
+
+func (l *GrpcLedgerApi) GetAccountInfo(ctx context.Context, req *vitepb.AddressRequest) (*vitepb.AccountInfo, error) {
+	addr, err := types.BytesToAddress(req.Address)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return accountInfoToProto(l.api.GetAccountByAccAddr(addr, nil))
+}
+
+func (l *GrpcLedgerApi) GetSnapshotBlockByHash(ctx context.Context, req *vitepb.HashRequest) (*vitepb.SnapshotBlock, error) {
+	hash, err := types.BytesToHash(req.Hash)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return snapshotBlockToProto(l.api.GetSnapshotBlockByHash(hash))
+}
+
+func (l *GrpcLedgerApi) GetSnapshotBlockByHeight(ctx context.Context, req *vitepb.SnapshotBlockHeightRequest) (*vitepb.SnapshotBlock, error) {
+	return snapshotBlockToProto(l.api.GetSnapshotBlockByHeight(req.Height))
+}
+
+func (l *GrpcLedgerApi) GetLatestSnapshotBlock(ctx context.Context, req *vitepb.EmptyRequest) (*vitepb.SnapshotBlock, error) {
+	log.Info("GetLatestSnapshotBlock")
+	return snapshotBlockToProto(l.chain.GetLatestSnapshotBlock(), nil)
+}
+
+type GrpcTxApi struct {
+	api *Tx
+}
+
+func NewGrpcTxApi(vite *vite.Vite) *GrpcTxApi {
+	return &GrpcTxApi{
+		api: NewTxApi(vite),
+	}
+}
+
+func (t *GrpcTxApi) SendRawTx(ctx context.Context, req *vitepb.AccountBlock) (*vitepb.SendRawTxResponse, error) {
+	log.Info("SendRawTx")
+	block := &ledger.AccountBlock{}
+	block.DeProto(req)
+	vmBlock, err := t.api.verifyLedgerBlock(block)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if err := t.api.vite.Pool().AddDirectAccountBlock(block.AccountAddress, vmBlock); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &vitepb.SendRawTxResponse{Hash: block.Hash.Bytes()}, nil
+}
+
+type GrpcOnroadApi struct {
+	api *PublicOnroadApi
+}
+
+func NewGrpcOnroadApi(vite *vite.Vite) *GrpcOnroadApi {
+	return &GrpcOnroadApi{
+		api: NewPublicOnroadApi(vite),
+	}
+}
+
+func (o *GrpcOnroadApi) GetOnroadBlocksByAddress(ctx context.Context, req *vitepb.AccountBlocksRequest) (*vitepb.AccountBlocks, error) {
+	addr, err := types.BytesToAddress(req.Address)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return rpcBlocksToProto(o.api.GetOnroadBlocksByAddress(addr, int(req.Index), int(req.Count)))
+}
+
+func (o *GrpcOnroadApi) GetAccountOnroadInfo(ctx context.Context, req *vitepb.AddressRequest) (*vitepb.AccountInfo, error) {
+	addr, err := types.BytesToAddress(req.Address)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	return accountInfoToProto(o.api.GetAccountOnroadInfo(addr))
+}
+
+// GrpcSubscribeApi streams the messages of the event system shared with SubscribeApi, a stream is a subscription
+// uninstalled when the client cancels it.
+type GrpcSubscribeApi struct {
+	es *eventSystem
+}
+
+func NewGrpcSubscribeApi(vite *vite.Vite) *GrpcSubscribeApi {
+	return &GrpcSubscribeApi{
+		es: getEventSystem(vite.Chain()),
+	}
+}
+
+func (s *GrpcSubscribeApi) stream(ctx context.Context, sub *subscription, send func(msg interface{}) error) error {
+	if sub.id == "" {
+		sub.id = rpc.NewID()
+	}
+	sub.notify = make(chan interface{}, subscriptionBufferSize)
+	s.es.install(sub)
+	defer s.es.uninstall(sub.id)
+
+	for {
+		select {
+		case msg := <-sub.notify:
+			if err := send(msg); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *GrpcSubscribeApi) NewSnapshotBlocks(req *vitepb.EmptyRequest, stream vitepb.Subscribe_NewSnapshotBlocksServer) error {
+	log.Info("NewSnapshotBlocks")
+	return s.stream(stream.Context(), &subscription{kind: SnapshotBlocksSubscription}, func(msg interface{}) error {
+		return stream.Send(msg.(*ledger.SnapshotBlock).Proto())
+	})
+}
+
+func (s *GrpcSubscribeApi) NewAccountBlocks(req *vitepb.AddressList, stream vitepb.Subscribe_NewAccountBlocksServer) error {
+	log.Info("NewAccountBlocks")
+	addrs, err := bytesToAddresses(req.AddressList)
+	if err != nil {
+		return err
+	}
+	return s.stream(stream.Context(), newAddressSubscription(AccountBlocksSubscription, addrs), func(msg interface{}) error {
+		return stream.Send(msg.(*AccountBlock).AccountBlock.Proto())
+	})
+}
+
+func (s *GrpcSubscribeApi) NewOnroadBlocks(req *vitepb.AddressList, stream vitepb.Subscribe_NewOnroadBlocksServer) error {
+	log.Info("NewOnroadBlocks")
+	addrs, err := bytesToAddresses(req.AddressList)
+	if err != nil {
+		return err
+	}
+	return s.stream(stream.Context(), newAddressSubscription(OnroadBlocksSubscription, addrs), func(msg interface{}) error {
+		return stream.Send(msg.(*AccountBlock).AccountBlock.Proto())
+	})
+}
+
+func (s *GrpcSubscribeApi) NewLogs(req *vitepb.LogFilter, stream vitepb.Subscribe_NewLogsServer) error {
+	log.Info("NewLogs")
+	addrs, err := bytesToAddresses(req.Addresses)
+	if err != nil {
+		return err
+	}
+	filter := LogFilter{Addresses: addrs}
+	for _, logTopics := range req.Topics {
+		topics := make([]types.Hash, 0, len(logTopics.Topics))
+		for _, b := range logTopics.Topics {
+			topic, err := types.BytesToHash(b)
+			if err != nil {
+				return invalidArgument(err)
+			}
+			topics = append(topics, topic)
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+	sub, err := newLogsSubscription(filter)
+	if err != nil {
+		return invalidArgument(err)
+	}
+
+	return s.stream(stream.Context(), sub, func(msg interface{}) error {
+		rpcLog := msg.(*Log)
+		pb := &vitepb.Log{
+			BlockHash: rpcLog.BlockHash.Bytes(),
+			LogIndex:  uint64(rpcLog.LogIndex),
+			Address:   rpcLog.Address.Bytes(),
+			Data:      rpcLog.Data,
+		}
+		for _, topic := range rpcLog.Topics {
+			pb.Topics = append(pb.Topics, topic.Bytes())
+		}
+		return stream.Send(pb)
+	})
+}
+
+func (s *GrpcSubscribeApi) NewRollbacks(req *vitepb.EmptyRequest, stream vitepb.Subscribe_NewRollbacksServer) error {
+	log.Info("NewRollbacks")
+	return s.stream(stream.Context(), &subscription{kind: RollbacksSubscription}, func(msg interface{}) error {
+		rollback := msg.(*RollbackMessage)
+		pb := &vitepb.Rollback{}
+		for _, hashHeight := range rollback.SnapshotBlocks {
+			pb.SnapshotBlocks = append(pb.SnapshotBlocks, &vitepb.HashHeight{Hash: hashHeight.Hash.Bytes(), Height: hashHeight.Height})
+		}
+		for addr, hashHeights := range rollback.AccountBlocks {
+			accountRollback := &vitepb.AccountRollback{Address: addr.Bytes()}
+			for _, hashHeight := range hashHeights {
+				accountRollback.Blocks = append(accountRollback.Blocks, &vitepb.HashHeight{Hash: hashHeight.Hash.Bytes(), Height: hashHeight.Height})
+			}
+			pb.AccountBlocks = append(pb.AccountBlocks, accountRollback)
+		}
+		return stream.Send(pb)
+	})
+}
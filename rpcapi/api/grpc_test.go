@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/vitepb"
+	"google.golang.org/grpc"
+)
+
+func TestGrpcSubscribe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &GrpcSubscribeApi{es: newEventSystem(&subscribeTestChain{})}
+	server := grpc.NewServer()
+	vitepb.RegisterSubscribeServer(server, s)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := vitepb.NewSubscribeClient(conn)
+
+	addr1, _, _ := types.CreateAddress()
+	addr2, _, _ := types.CreateAddress()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	onroadStream, err := client.NewOnroadBlocks(ctx, &vitepb.AddressList{AddressList: [][]byte{addr2.Bytes()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotCtx, snapshotCancel := context.WithCancel(ctx)
+	snapshotStream, err := client.NewSnapshotBlocks(snapshotCtx, &vitepb.EmptyRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for len(s.es.subscriptionsOf(OnroadBlocksSubscription)) == 0 || len(s.es.subscriptionsOf(SnapshotBlocksSubscription)) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	now := time.Now()
+	sendBlock := &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeSendCall,
+		Hash:           types.DataHash([]byte("send")),
+		Height:         1,
+		AccountAddress: addr1,
+		ToAddress:      addr2,
+		Amount:         big.NewInt(1),
+		Fee:            big.NewInt(0),
+		Timestamp:      &now,
+	}
+	s.es.handleEvent(&chainEvent{insertedAccountBlocks: []*ledger.AccountBlock{sendBlock}})
+	s.es.handleEvent(&chainEvent{insertedSnapshotBlocks: []*ledger.SnapshotBlock{{Height: 2, Timestamp: &now}}})
+
+	block, err := onroadStream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, _ := types.BytesToHash(block.Hash); hash != sendBlock.Hash {
+		t.Fatalf("wrong onroad block %v", block)
+	}
+	snapshotBlock, err := snapshotStream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshotBlock.Height != 2 {
+		t.Fatalf("wrong snapshot block %v", snapshotBlock)
+	}
+
+	// the subscription is uninstalled with its stream
+	snapshotCancel()
+	for len(s.es.subscriptionsOf(SnapshotBlocksSubscription)) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if len(s.es.subscriptionsOf(OnroadBlocksSubscription)) != 1 {
+		t.Fatal("the other stream should be kept")
+	}
+}
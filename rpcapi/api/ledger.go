@@ -1,15 +1,22 @@
 package api
 
 import (
+	"encoding/hex"
+	"fmt"
 	"github.com/pkg/errors"
 	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/chain/trie_gc"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/generator"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/trie"
 	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm/abi"
 	"strconv"
+	"strings"
 )
 
 // !!! Block = Transaction = TX
@@ -41,14 +48,56 @@ func (l LedgerApi) String() string {
 	return "LedgerApi"
 }
 
-func (l *LedgerApi) ledgerBlockToRpcBlock(block *ledger.AccountBlock) (*AccountBlock, error) {
-	return ledgerToRpcBlock(block, l.chain)
+// MethodDocs annotates the queries of the ledger for rpc_discover.
+func (l LedgerApi) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"getBlockByHash":              {Summary: "the account block of the hash", Params: []string{"blockHash"}},
+		"getBlocksByHash":             {Summary: "count account blocks backward from the origin block, the latest one by default", Params: []string{"addr", "originBlockHash", "count"}},
+		"getBlocksByHashInToken":      {Summary: "count account blocks of the token backward from the origin block", Params: []string{"addr", "originBlockHash", "tokenTypeId", "count"}},
+		"getBlocksByCounterparty":     {Summary: "the page of the blocks between the two addresses", Params: []string{"toAddr", "fromAddr", "index", "count"}},
+		"getBlocksByHeight":           {Summary: "count account blocks from the height", Params: []string{"addr", "height", "count", "forward"}},
+		"getBlockByHeight":            {Summary: "the account block of the height", Params: []string{"addr", "heightStr"}},
+		"getBlocksByAccAddr":          {Summary: "the page of the account blocks from the latest one", Params: []string{"addr", "index", "count"}},
+		"getAccountByAccAddr":         {Summary: "the account info at the snapshot height or hash, the latest by default", Params: []string{"addr", "snapshot"}},
+		"getAccountBalance":           {Summary: "the balance of the token at the snapshot height or hash, the latest by default", Params: []string{"addr", "tokenId", "snapshot"}},
+		"getProof":                    {Summary: "the merkle proofs of the account state and the storage keys", Params: []string{"addr", "keys", "snapshotHash"}},
+		"getSnapshotBlockByHash":      {Summary: "the snapshot block of the hash", Params: []string{"hash"}},
+		"getSnapshotBlockByHeight":    {Summary: "the snapshot block of the height", Params: []string{"height"}},
+		"getSnapshotBlockByTime":      {Summary: "the latest snapshot block not after the unix time in seconds", Params: []string{"timestamp"}},
+		"getAccountBlocksByTimeRange": {Summary: "the page of the account blocks between the unix times", Params: []string{"addr", "from", "to", "index", "count"}},
+		"getSnapshotChainHeight":      {Summary: "the height of the latest snapshot block"},
+		"getLatestSnapshotChainHash":  {Summary: "the hash of the latest snapshot block"},
+		"getLatestBlock":              {Summary: "the latest account block", Params: []string{"addr"}},
+		"getTokenMintage":             {Summary: "the token info", Params: []string{"tti"}},
+		"getVmLogListByHash":          {Summary: "the vm logs of the log hash", Params: []string{"logHash"}},
+		"getVmLogList":                {Summary: "the vm logs of the account block", Params: []string{"blockHash"}},
+		"getLogs":                     {Summary: "the page of the vm logs matching the filter", Params: []string{"filter", "index", "count"}},
+		"getFittestSnapshotHash":      {Summary: "the snapshot hash to refer by a new account block", Params: []string{"accAddr", "sendBlockHash"}},
+	}
+}
+
+// newReadView pins the ledger, so the queries of one request don't observe a block inserted or rolled back between them.
+func (l *LedgerApi) newReadView(method string) (chain.ReadView, error) {
+	view, err := l.chain.NewReadView()
+	if err != nil {
+		l.log.Error("NewReadView failed, error is "+err.Error(), "method", method)
+		return nil, err
+	}
+	return view, nil
+}
+
+func (l *LedgerApi) ledgerBlockToRpcBlock(view chain.ReadView, block *ledger.AccountBlock) (*AccountBlock, error) {
+	return ledgerToRpcBlock(block, view)
 }
 
-func (l *LedgerApi) ledgerBlocksToRpcBlocks(list []*ledger.AccountBlock) ([]*AccountBlock, error) {
+func (l *LedgerApi) ledgerBlocksToRpcBlocks(view chain.ReadView, list []*ledger.AccountBlock) ([]*AccountBlock, error) {
 	var blocks []*AccountBlock
 	for _, item := range list {
-		rpcBlock, err := l.ledgerBlockToRpcBlock(item)
+		// the blocks rolled back are nil
+		if item == nil {
+			continue
+		}
+		rpcBlock, err := l.ledgerBlockToRpcBlock(view, item)
 		if err != nil {
 			return nil, err
 		}
@@ -58,7 +107,13 @@ func (l *LedgerApi) ledgerBlocksToRpcBlocks(list []*ledger.AccountBlock) ([]*Acc
 }
 
 func (l *LedgerApi) GetBlockByHash(blockHash *types.Hash) (*AccountBlock, error) {
-	block, getError := l.chain.GetAccountBlockByHash(blockHash)
+	view, err := l.newReadView("GetBlockByHash")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	block, getError := view.GetArchivedAccountBlockByHash(blockHash)
 
 	if getError != nil {
 		l.log.Error("GetAccountBlockByHash failed, error is "+getError.Error(), "method", "GetBlockByHash")
@@ -69,18 +124,24 @@ func (l *LedgerApi) GetBlockByHash(blockHash *types.Hash) (*AccountBlock, error)
 		return nil, nil
 	}
 
-	return l.ledgerBlockToRpcBlock(block)
+	return l.ledgerBlockToRpcBlock(view, block)
 }
 
 func (l *LedgerApi) GetBlocksByHash(addr types.Address, originBlockHash *types.Hash, count uint64) ([]*AccountBlock, error) {
 	l.log.Info("GetBlocksByHash")
 
-	list, getError := l.chain.GetAccountBlocksByHash(addr, originBlockHash, count, false)
+	view, err := l.newReadView("GetBlocksByHash")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	list, getError := view.GetAccountBlocksByHash(addr, originBlockHash, count, false)
 	if getError != nil {
 		return nil, getError
 	}
 
-	if blocks, err := l.ledgerBlocksToRpcBlocks(list); err != nil {
+	if blocks, err := l.ledgerBlocksToRpcBlocks(view, list); err != nil {
 		l.log.Error("GetConfirmTimes failed, error is "+err.Error(), "method", "GetBlocksByHash")
 		return nil, err
 	} else {
@@ -91,13 +152,19 @@ func (l *LedgerApi) GetBlocksByHash(addr types.Address, originBlockHash *types.H
 
 func (l *LedgerApi) GetBlocksByHashInToken(addr types.Address, originBlockHash *types.Hash, tokenTypeId types.TokenTypeId, count uint64) ([]*AccountBlock, error) {
 	l.log.Info("GetBlocksByHashInToken")
-	fti := l.chain.Fti()
+	view, err := l.newReadView("GetBlocksByHashInToken")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	fti := view.Fti()
 	if fti == nil {
 		err := errors.New("config.OpenFilterTokenIndex is false, api can't work")
 		return nil, err
 	}
 
-	account, err := l.chain.GetAccount(&addr)
+	account, err := view.GetAccount(&addr)
 	if err != nil {
 		return nil, err
 	}
@@ -110,16 +177,67 @@ func (l *LedgerApi) GetBlocksByHashInToken(addr types.Address, originBlockHash *
 		return nil, err
 	}
 
-	blockList := make([]*ledger.AccountBlock, len(hashList))
-	for index, blockHash := range hashList {
-		block, err := l.chain.GetAccountBlockByHash(&blockHash)
+	blockList := make([]*ledger.AccountBlock, 0, len(hashList))
+	for _, blockHash := range hashList {
+		block, err := view.GetArchivedAccountBlockByHash(&blockHash)
 		if err != nil {
 			return nil, err
 		}
+		// the index is built in the background, the block may be newer than the view or rolled back
+		if block == nil {
+			continue
+		}
+		blockList = append(blockList, block)
+	}
+	return l.ledgerBlocksToRpcBlocks(view, blockList)
+}
+
+// GetBlocksByCounterparty returns the send blocks to toAddr, only the ones sent by fromAddr if fromAddr isn't nil.
+// The blocks are ordered by the sender address descending, then by the height descending, index is the page of count
+// blocks.
+func (l *LedgerApi) GetBlocksByCounterparty(toAddr types.Address, fromAddr *types.Address, index int, count int) ([]*AccountBlock, error) {
+	l.log.Info("GetBlocksByCounterparty")
+	var cpi *chain_index.CounterpartyIndex
+	if indexManager := l.chain.IndexManager(); indexManager != nil {
+		cpi, _ = indexManager.Index(chain_index.CounterpartyIndexName).(*chain_index.CounterpartyIndex)
+	}
+	if cpi == nil {
+		err := errors.New("config.OpenCounterpartyIndex is false, api can't work")
+		return nil, err
+	}
+	if index < 0 || count <= 0 {
+		return nil, nil
+	}
+	offset, err := pageOffset(index, count)
+	if err != nil {
+		return nil, err
+	}
 
-		blockList[index] = block
+	view, err := l.newReadView("GetBlocksByCounterparty")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	hashList, err := cpi.GetSendBlockHashList(toAddr, fromAddr, offset, count)
+	if err != nil {
+		l.log.Error("GetSendBlockHashList failed, error is "+err.Error(), "method", "GetBlocksByCounterparty")
+		return nil, err
 	}
-	return l.ledgerBlocksToRpcBlocks(blockList)
+
+	blockList := make([]*ledger.AccountBlock, 0, len(hashList))
+	for _, blockHash := range hashList {
+		block, err := view.GetArchivedAccountBlockByHash(&blockHash)
+		if err != nil {
+			return nil, err
+		}
+		// the index is built in the background, the block may be rolled back
+		if block == nil {
+			continue
+		}
+		blockList = append(blockList, block)
+	}
+	return l.ledgerBlocksToRpcBlocks(view, blockList)
 }
 
 type Statistics struct {
@@ -128,8 +246,14 @@ type Statistics struct {
 }
 
 func (l *LedgerApi) GetStatistics() (*Statistics, error) {
-	latestSnapshotBlock := l.chain.GetLatestSnapshotBlock()
-	allLatestAccountBlock, err := l.chain.GetAllLatestAccountBlock()
+	view, err := l.newReadView("GetStatistics")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	latestSnapshotBlock := view.GetLatestSnapshotBlock()
+	allLatestAccountBlock, err := view.GetAllLatestAccountBlock()
 
 	if err != nil {
 		return nil, err
@@ -154,8 +278,144 @@ func (l *LedgerApi) GetVmLogListByHash(logHash types.Hash) (ledger.VmLogList, er
 	return logList, err
 }
 
+type LogFilter struct {
+	Addresses  []types.Address `json:"addresses"`
+	Topics     [][]types.Hash  `json:"topics"`
+	FromHeight uint64          `json:"fromHeight"`
+	ToHeight   uint64          `json:"toHeight"`
+
+	// the json of the contract abi, the logs of the events in it are decoded
+	Abi string `json:"abi"`
+}
+
+type Log struct {
+	SnapshotHeight string        `json:"snapshotHeight"`
+	BlockHash      types.Hash    `json:"blockHash"`
+	LogIndex       int           `json:"logIndex"`
+	Address        types.Address `json:"address"`
+	Topics         []types.Hash  `json:"topics"`
+	Data           []byte        `json:"data"`
+
+	EventName *string   `json:"eventName,omitempty"`
+	Args      []*LogArg `json:"args,omitempty"`
+}
+
+type LogArg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// GetLogs returns the logs matched by filter, ordered by the snapshot height which confirmed them. Topics[i] lists the
+// alternatives of the i-th topic, empty means any topic. If filter.Abi is set, the logs of its events are decoded.
+// index is the page of count logs.
+func (l *LedgerApi) GetLogs(filter LogFilter, index int, count int) ([]*Log, error) {
+	l.log.Info("GetLogs")
+	var vli *chain_index.VmLogIndex
+	if indexManager := l.chain.IndexManager(); indexManager != nil {
+		vli, _ = indexManager.Index(chain_index.VmLogIndexName).(*chain_index.VmLogIndex)
+	}
+	if vli == nil {
+		err := errors.New("config.OpenVmLogIndex is false, api can't work")
+		return nil, err
+	}
+
+	var abiContract *abi.ABIContract
+	if filter.Abi != "" {
+		contract, err := abi.JSONToABIContract(strings.NewReader(filter.Abi))
+		if err != nil {
+			return nil, err
+		}
+		abiContract = &contract
+	}
+	if index < 0 || count <= 0 {
+		return nil, nil
+	}
+	offset, err := pageOffset(index, count)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := l.newReadView("GetLogs")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	items, err := vli.GetLogs(&chain_index.VmLogFilter{
+		Addresses:  filter.Addresses,
+		Topics:     filter.Topics,
+		FromHeight: filter.FromHeight,
+		ToHeight:   filter.ToHeight,
+	}, offset, count)
+	if err != nil {
+		l.log.Error("GetLogs failed, error is "+err.Error(), "method", "GetLogs")
+		return nil, err
+	}
+
+	logs := make([]*Log, 0, len(items))
+	for _, item := range items {
+		block, err := view.GetArchivedAccountBlockByHash(&item.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		// the index is built in the background, the block may be rolled back
+		if block == nil || block.LogHash == nil {
+			continue
+		}
+		logList, err := view.GetVmLogList(block.LogHash)
+		if err != nil {
+			return nil, err
+		}
+		if item.LogIndex >= uint64(len(logList)) {
+			continue
+		}
+		vmLog := logList[item.LogIndex]
+
+		log := &Log{
+			SnapshotHeight: strconv.FormatUint(item.SnapshotHeight, 10),
+			BlockHash:      item.BlockHash,
+			LogIndex:       int(item.LogIndex),
+			Address:        item.Address,
+			Topics:         vmLog.Topics,
+			Data:           vmLog.Data,
+		}
+		if abiContract != nil {
+			decodeLog(abiContract, log)
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// decodeLog sets the event name and the args of log, log is kept undecoded if it isn't an event of abiContract.
+func decodeLog(abiContract *abi.ABIContract, log *Log) {
+	if len(log.Topics) <= 0 {
+		return
+	}
+	event, err := abiContract.EventById(log.Topics[0])
+	if err != nil {
+		return
+	}
+	values, err := event.UnpackLog(log.Topics, log.Data)
+	if err != nil {
+		return
+	}
+
+	log.EventName = &event.Name
+	for i, input := range event.Inputs {
+		log.Args = append(log.Args, &LogArg{Name: input.Name, Type: input.Type.String(), Value: values[i]})
+	}
+}
+
 func (l *LedgerApi) GetBlocksByHeight(addr types.Address, height uint64, count uint64, forward bool) ([]*AccountBlock, error) {
-	accountBlocks, err := l.chain.GetAccountBlocksByHeight(addr, height, count, forward)
+	view, err := l.newReadView("GetBlocksByHeight")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	accountBlocks, err := view.GetAccountBlocksByHeight(addr, height, count, forward)
 	if err != nil {
 		l.log.Error("GetAccountBlocksByHeight failed, error is "+err.Error(), "method", "GetBlocksByHeight")
 		return nil, err
@@ -163,7 +423,7 @@ func (l *LedgerApi) GetBlocksByHeight(addr types.Address, height uint64, count u
 	if len(accountBlocks) <= 0 {
 		return nil, nil
 	}
-	return l.ledgerBlocksToRpcBlocks(accountBlocks)
+	return l.ledgerBlocksToRpcBlocks(view, accountBlocks)
 }
 
 func (l *LedgerApi) GetBlockByHeight(addr types.Address, heightStr string) (*AccountBlock, error) {
@@ -172,7 +432,13 @@ func (l *LedgerApi) GetBlockByHeight(addr types.Address, heightStr string) (*Acc
 		return nil, err
 	}
 
-	accountBlock, err := l.chain.GetAccountBlockByHeight(&addr, height)
+	view, err := l.newReadView("GetBlockByHeight")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	accountBlock, err := view.GetAccountBlockByHeight(&addr, height)
 	if err != nil {
 		l.log.Error("GetAccountBlockByHeight failed, error is "+err.Error(), "method", "GetBlockByHeight")
 		return nil, err
@@ -181,20 +447,26 @@ func (l *LedgerApi) GetBlockByHeight(addr types.Address, heightStr string) (*Acc
 	if accountBlock == nil {
 		return nil, nil
 	}
-	return l.ledgerBlockToRpcBlock(accountBlock)
+	return l.ledgerBlockToRpcBlock(view, accountBlock)
 }
 
 func (l *LedgerApi) GetBlocksByAccAddr(addr types.Address, index int, count int) ([]*AccountBlock, error) {
 	l.log.Info("GetBlocksByAccAddr")
 
-	list, getErr := l.chain.GetAccountBlocksByAddress(&addr, index, 1, count)
+	view, err := l.newReadView("GetBlocksByAccAddr")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	list, getErr := view.GetAccountBlocksByAddress(&addr, index, 1, count)
 
 	if getErr != nil {
 		l.log.Info("GetBlocksByAccAddr", "err", getErr)
 		return nil, getErr
 	}
 
-	if blocks, err := l.ledgerBlocksToRpcBlocks(list); err != nil {
+	if blocks, err := l.ledgerBlocksToRpcBlocks(view, list); err != nil {
 		l.log.Error("GetConfirmTimes failed, error is "+err.Error(), "method", "GetBlocksByAccAddr")
 		return nil, err
 	} else {
@@ -202,10 +474,21 @@ func (l *LedgerApi) GetBlocksByAccAddr(addr types.Address, index int, count int)
 	}
 }
 
-func (l *LedgerApi) GetAccountByAccAddr(addr types.Address) (*RpcAccountInfo, error) {
+// GetAccountByAccAddr returns the latest balances of addr, or the balances confirmed by the snapshot block if
+// snapshot, a snapshot height or hash, is given.
+func (l *LedgerApi) GetAccountByAccAddr(addr types.Address, snapshot *string) (*RpcAccountInfo, error) {
 	l.log.Info("GetAccountByAccAddr")
+	if snapshot != nil {
+		return l.getAccountAtSnapshot(addr, *snapshot)
+	}
 
-	account, err := l.chain.GetAccount(&addr)
+	view, err := l.newReadView("GetAccountByAccAddr")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	account, err := view.GetAccount(&addr)
 	if err != nil {
 		l.log.Error("GetAccount failed, error is "+err.Error(), "method", "GetAccountByAccAddr")
 		return nil, err
@@ -215,7 +498,7 @@ func (l *LedgerApi) GetAccountByAccAddr(addr types.Address) (*RpcAccountInfo, er
 		return nil, nil
 	}
 
-	latestAccountBlock, err := l.chain.GetLatestAccountBlock(&addr)
+	latestAccountBlock, err := view.GetLatestAccountBlock(&addr)
 	if err != nil {
 		l.log.Error("GetLatestAccountBlock failed, error is "+err.Error(), "method", "GetAccountByAccAddr")
 		return nil, err
@@ -226,29 +509,132 @@ func (l *LedgerApi) GetAccountByAccAddr(addr types.Address) (*RpcAccountInfo, er
 		totalNum = latestAccountBlock.Height
 	}
 
-	balanceMap, err := l.chain.GetAccountBalance(&addr)
+	balanceMap, err := view.GetAccountBalance(&addr)
 	if err != nil {
 		l.log.Error("GetAccountBalance failed, error is "+err.Error(), "method", "GetAccountByAccAddr")
 		return nil, err
 	}
 
-	tokenBalanceInfoMap := make(map[types.TokenTypeId]*RpcTokenBalanceInfo)
-	for tokenId, amount := range balanceMap {
-		token, _ := l.chain.GetTokenInfoById(&tokenId)
-		tokenBalanceInfoMap[tokenId] = &RpcTokenBalanceInfo{
-			TokenInfo:   RawTokenInfoToRpc(token, tokenId),
-			TotalAmount: amount.String(),
-			Number:      nil,
+	return createRpcAccountInfo(view, account.AccountAddress, totalNum, balanceMap), nil
+}
+
+func (l *LedgerApi) getAccountAtSnapshot(addr types.Address, snapshot string) (*RpcAccountInfo, error) {
+	snapshotBlock, err := getStateSnapshotBlock(l.chain, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmedBlock, err := l.chain.GetConfirmAccountBlock(snapshotBlock.Height, &addr)
+	if err != nil {
+		l.log.Error("GetConfirmAccountBlock failed, error is "+err.Error(), "method", "GetAccountByAccAddr")
+		return nil, err
+	}
+	if confirmedBlock == nil {
+		return nil, nil
+	}
+
+	balanceMap, err := getBalanceMapAtSnapshot(l.chain, snapshotBlock, addr)
+	if err != nil {
+		l.log.Error("getBalanceMapAtSnapshot failed, error is "+err.Error(), "method", "GetAccountByAccAddr")
+		return nil, err
+	}
+
+	return createRpcAccountInfo(l.chain, addr, confirmedBlock.Height, balanceMap), nil
+}
+
+// GetAccountBalance returns the latest balance of tokenId, or the balance confirmed by the snapshot block if
+// snapshot, a snapshot height or hash, is given.
+func (l *LedgerApi) GetAccountBalance(addr types.Address, tokenId types.TokenTypeId, snapshot *string) (string, error) {
+	l.log.Info("GetAccountBalance")
+	if snapshot == nil {
+		balance, err := l.chain.GetAccountBalanceByTokenId(&addr, &tokenId)
+		if err != nil {
+			return "", err
+		}
+		if balance == nil {
+			return "0", nil
 		}
+		return *bigIntToString(balance), nil
+	}
+
+	snapshotBlock, err := getStateSnapshotBlock(l.chain, snapshot)
+	if err != nil {
+		return "", err
+	}
+	vmContext, err := newVmContextAtSnapshot(l.chain, snapshotBlock, nil)
+	if err != nil {
+		return "", err
+	}
+	return *bigIntToString(vmContext.GetBalance(&addr, &tokenId)), nil
+}
+
+// GetProof returns the merkle proof of the account state of addr and the storage keys, keys are hex, against the
+// StateHash of the snapshot block of snapshotHash, nil means the latest snapshot block. The account proof proves the
+// state root of addr, which is the root of the storage proofs. A missing account or key is proved by the path where
+// it diverges from the trie, see trie.VerifyProof.
+func (l *LedgerApi) GetProof(addr types.Address, keys []string, snapshotHash *types.Hash) (*AccountProof, error) {
+	l.log.Info("GetProof")
+	var snapshot *string
+	if snapshotHash != nil {
+		hashStr := snapshotHash.String()
+		snapshot = &hashStr
+	}
+	snapshotBlock, err := getStateSnapshotBlock(l.chain, snapshot)
+	if err != nil {
+		return nil, err
 	}
 
-	rpcAccount := &RpcAccountInfo{
-		AccountAddress:      account.AccountAddress,
-		TotalNumber:         strconv.FormatUint(totalNum, 10),
-		TokenBalanceInfoMap: tokenBalanceInfoMap,
+	stateTrie := l.chain.GetStateTrie(&snapshotBlock.StateHash)
+	if stateTrie == nil || stateTrie.Root == nil {
+		return nil, errors.New(fmt.Sprintf("the state trie of snapshot block %s is missing", snapshotBlock.Hash))
+	}
+	accountProof, err := stateTrie.Prove(addr.Bytes())
+	if err != nil {
+		l.log.Error("Prove account failed, error is "+err.Error(), "method", "GetProof")
+		return nil, err
+	}
+
+	result := &AccountProof{
+		SnapshotHash:   snapshotBlock.Hash,
+		SnapshotHeight: uint64ToString(snapshotBlock.Height),
+		StateHash:      snapshotBlock.StateHash,
+		Address:        addr,
+		AccountProof:   accountProof,
+		StorageProof:   make([]*StorageProof, 0, len(keys)),
 	}
 
-	return rpcAccount, nil
+	var storageTrie *trie.Trie
+	if stateHashBytes := stateTrie.GetValue(addr.Bytes()); len(stateHashBytes) > 0 {
+		storageHash, err := types.BytesToHash(stateHashBytes)
+		if err != nil {
+			return nil, err
+		}
+		result.StorageHash = &storageHash
+		if storageTrie = l.chain.GetStateTrie(&storageHash); storageTrie == nil || storageTrie.Root == nil {
+			return nil, errors.New(fmt.Sprintf("the state trie of %s is missing", addr))
+		}
+	}
+
+	for _, key := range keys {
+		keyBytes, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, err
+		}
+		storageProof := &StorageProof{Key: key}
+		// the keys of a missing account are proved missing by the account proof
+		if storageTrie != nil {
+			if storageProof.Proof, err = storageTrie.Prove(keyBytes); err != nil {
+				l.log.Error("Prove storage failed, error is "+err.Error(), "method", "GetProof")
+				return nil, err
+			}
+			if value := storageTrie.GetValue(keyBytes); value != nil {
+				valueHex := hex.EncodeToString(value)
+				storageProof.Value = &valueHex
+			}
+		}
+		result.StorageProof = append(result.StorageProof, storageProof)
+	}
+	return result, nil
 }
 
 func (l *LedgerApi) GetSnapshotBlockByHash(hash types.Hash) (*ledger.SnapshotBlock, error) {
@@ -260,13 +646,141 @@ func (l *LedgerApi) GetSnapshotBlockByHash(hash types.Hash) (*ledger.SnapshotBlo
 }
 
 func (l *LedgerApi) GetSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error) {
-	block, err := l.chain.GetSnapshotBlockByHeight(height)
+	block, err := l.chain.GetArchivedSnapshotBlockByHeight(height)
 	if err != nil {
 		l.log.Error("GetSnapshotBlockByHash failed, error is "+err.Error(), "method", "GetSnapshotBlockByHeight")
 	}
 	return block, err
 }
 
+func (l *LedgerApi) getTimeIndex() (*chain_index.TimeIndex, error) {
+	var ti *chain_index.TimeIndex
+	if indexManager := l.chain.IndexManager(); indexManager != nil {
+		ti, _ = indexManager.Index(chain_index.TimeIndexName).(*chain_index.TimeIndex)
+	}
+	if ti == nil {
+		return nil, errors.New("config.OpenTimeIndex is false, api can't work")
+	}
+	return ti, nil
+}
+
+func timestampToUint64(timestamp int64) uint64 {
+	if timestamp < 0 {
+		return 0
+	}
+	return uint64(timestamp)
+}
+
+// GetSnapshotBlockByTime returns the latest snapshot block whose timestamp isn't after timestamp, timestamp is in
+// seconds.
+func (l *LedgerApi) GetSnapshotBlockByTime(timestamp int64) (*ledger.SnapshotBlock, error) {
+	l.log.Info("GetSnapshotBlockByTime")
+	ti, err := l.getTimeIndex()
+	if err != nil {
+		return nil, err
+	}
+	if timestamp < 0 {
+		return nil, nil
+	}
+
+	hash, err := ti.GetSnapshotBlockHashByTime(uint64(timestamp))
+	if err != nil {
+		l.log.Error("GetSnapshotBlockHashByTime failed, error is "+err.Error(), "method", "GetSnapshotBlockByTime")
+		return nil, err
+	}
+	if hash == nil {
+		return nil, nil
+	}
+	block, err := l.chain.GetSnapshotBlockByHash(hash)
+	if err != nil {
+		l.log.Error("GetSnapshotBlockByHash failed, error is "+err.Error(), "method", "GetSnapshotBlockByTime")
+	}
+	return block, err
+}
+
+// GetAccountBlocksByTimeRange returns the blocks of addr confirmed by the snapshot blocks whose timestamps are in
+// [from, to], in seconds. The blocks are ordered by the confirmed time, then by the height, index is the page of
+// count blocks.
+func (l *LedgerApi) GetAccountBlocksByTimeRange(addr types.Address, from int64, to int64, index int, count int) ([]*AccountBlock, error) {
+	l.log.Info("GetAccountBlocksByTimeRange")
+	ti, err := l.getTimeIndex()
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || count <= 0 || to < from || to < 0 {
+		return nil, nil
+	}
+	offset, err := pageOffset(index, count)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := l.newReadView("GetAccountBlocksByTimeRange")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	hashList, err := ti.GetAccountBlockHashList(addr, timestampToUint64(from), uint64(to), offset, count)
+	if err != nil {
+		l.log.Error("GetAccountBlockHashList failed, error is "+err.Error(), "method", "GetAccountBlocksByTimeRange")
+		return nil, err
+	}
+
+	blockList := make([]*ledger.AccountBlock, 0, len(hashList))
+	for _, blockHash := range hashList {
+		block, err := view.GetArchivedAccountBlockByHash(&blockHash)
+		if err != nil {
+			return nil, err
+		}
+		// the index is built in the background, the block may be rolled back
+		if block == nil {
+			continue
+		}
+		blockList = append(blockList, block)
+	}
+	return l.ledgerBlocksToRpcBlocks(view, blockList)
+}
+
+type DailyStatistics struct {
+	Date              string `json:"date"`
+	Timestamp         int64  `json:"timestamp"`
+	TxCount           uint64 `json:"txCount"`
+	UniqueSenderCount uint64 `json:"uniqueSenderCount"`
+}
+
+// GetDailyStatistics returns the count of the transactions and of the unique senders of every day in UTC from the
+// day of from to the day of to, in seconds. The days without transactions are omitted. TxCount counts both the send
+// and the receive blocks confirmed in the day.
+func (l *LedgerApi) GetDailyStatistics(from int64, to int64) ([]*DailyStatistics, error) {
+	l.log.Info("GetDailyStatistics")
+	ti, err := l.getTimeIndex()
+	if err != nil {
+		return nil, err
+	}
+	if to < from || to < 0 {
+		return nil, nil
+	}
+
+	statList, err := ti.GetDailyStatistics(timestampToUint64(from)/chain_index.SecondsPerDay, uint64(to)/chain_index.SecondsPerDay)
+	if err != nil {
+		l.log.Error("GetDailyStatistics failed, error is "+err.Error(), "method", "GetDailyStatistics")
+		return nil, err
+	}
+
+	result := make([]*DailyStatistics, 0, len(statList))
+	for _, stat := range statList {
+		startTime := stat.StartTime()
+		result = append(result, &DailyStatistics{
+			Date:              startTime.Format("2006-01-02"),
+			Timestamp:         startTime.Unix(),
+			TxCount:           stat.TxCount,
+			UniqueSenderCount: stat.SenderCount,
+		})
+	}
+	return result, nil
+}
+
 func (l *LedgerApi) GetSnapshotChainHeight() string {
 	l.log.Info("GetLatestSnapshotChainHeight")
 	return strconv.FormatUint(l.chain.GetLatestSnapshotBlock().Height, 10)
@@ -279,7 +793,14 @@ func (l *LedgerApi) GetLatestSnapshotChainHash() *types.Hash {
 
 func (l *LedgerApi) GetLatestBlock(addr types.Address) (*AccountBlock, error) {
 	l.log.Info("GetLatestBlock")
-	block, getError := l.chain.GetLatestAccountBlock(&addr)
+
+	view, err := l.newReadView("GetLatestBlock")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	block, getError := view.GetLatestAccountBlock(&addr)
 	if getError != nil {
 		l.log.Error("GetLatestAccountBlock failed, error is "+getError.Error(), "method", "GetLatestBlock")
 		return nil, getError
@@ -289,7 +810,7 @@ func (l *LedgerApi) GetLatestBlock(addr types.Address) (*AccountBlock, error) {
 		return nil, nil
 	}
 
-	return l.ledgerBlockToRpcBlock(block)
+	return l.ledgerBlockToRpcBlock(view, block)
 }
 
 func (l *LedgerApi) GetTokenMintage(tti types.TokenTypeId) (*RpcTokenInfo, error) {
@@ -303,7 +824,7 @@ func (l *LedgerApi) GetTokenMintage(tti types.TokenTypeId) (*RpcTokenInfo, error
 
 func (l *LedgerApi) GetSenderInfo() (*KafkaSendInfo, error) {
 	l.log.Info("GetSenderInfo")
-	if l.chain.KafkaSender() == nil {
+	if l.chain.Sender() == nil {
 		return nil, nil
 	}
 	senderInfo := &KafkaSendInfo{}
@@ -316,11 +837,11 @@ func (l *LedgerApi) GetSenderInfo() (*KafkaSendInfo, error) {
 		return nil, totalErr
 	}
 
-	for _, producer := range l.chain.KafkaSender().Producers() {
+	for _, producer := range l.chain.Sender().Producers() {
 		senderInfo.Producers = append(senderInfo.Producers, createKafkaProducerInfo(producer))
 	}
 
-	for _, producer := range l.chain.KafkaSender().RunProducers() {
+	for _, producer := range l.chain.Sender().RunProducers() {
 		senderInfo.RunProducers = append(senderInfo.RunProducers, createKafkaProducerInfo(producer))
 	}
 
@@ -420,19 +941,19 @@ func (l *LedgerApi) GetNeedSnapshotContent() map[types.Address]*ledger.HashHeigh
 func (l *LedgerApi) SetSenderHasSend(producerId uint8, hasSend uint64) {
 	l.log.Info("SetSenderHasSend")
 
-	if l.chain.KafkaSender() == nil {
+	if l.chain.Sender() == nil {
 		return
 	}
-	l.chain.KafkaSender().SetHasSend(producerId, hasSend)
+	l.chain.Sender().SetHasSend(producerId, hasSend)
 }
 
 func (l *LedgerApi) StopSender(producerId uint8) {
 	l.log.Info("StopSender")
 
-	if l.chain.KafkaSender() == nil {
+	if l.chain.Sender() == nil {
 		return
 	}
-	l.chain.KafkaSender().StopById(producerId)
+	l.chain.Sender().StopById(producerId)
 }
 
 func (l *LedgerApi) AccountType(addr types.Address) (uint64, error) {
@@ -440,7 +961,13 @@ func (l *LedgerApi) AccountType(addr types.Address) (uint64, error) {
 }
 
 func (l *LedgerApi) GetVmLogList(blockHash types.Hash) (ledger.VmLogList, error) {
-	block, err := l.chain.GetAccountBlockByHash(&blockHash)
+	view, err := l.newReadView("GetVmLogList")
+	if err != nil {
+		return nil, err
+	}
+	defer view.Release()
+
+	block, err := view.GetAccountBlockByHash(&blockHash)
 	if block == nil {
 		if err != nil {
 			return nil, err
@@ -448,7 +975,7 @@ func (l *LedgerApi) GetVmLogList(blockHash types.Hash) (ledger.VmLogList, error)
 		return nil, errors.New("get block failed")
 	}
 	if block.LogHash == nil {
-		code, err2 := l.chain.AccountType(&block.AccountAddress)
+		code, err2 := view.AccountType(&block.AccountAddress)
 		if err2 != nil {
 			return nil, err
 		}
@@ -457,7 +984,7 @@ func (l *LedgerApi) GetVmLogList(blockHash types.Hash) (ledger.VmLogList, error)
 		}
 		return nil, nil
 	}
-	return l.chain.GetVmLogList(block.LogHash)
+	return view.GetVmLogList(block.LogHash)
 }
 
 func (l *LedgerApi) GetGcStatus() *GcStatus {
@@ -2,7 +2,6 @@ package api
 
 import (
 	"errors"
-	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/chain/sender"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
@@ -127,6 +126,47 @@ type RpcAccountInfo struct {
 	TokenBalanceInfoMap map[types.TokenTypeId]*RpcTokenBalanceInfo `json:"tokenBalanceInfoMap,omitempty"`
 }
 
+type tokenInfoReader interface {
+	GetTokenInfoById(tokenId *types.TokenTypeId) (*types.TokenInfo, error)
+}
+
+func createRpcAccountInfo(reader tokenInfoReader, addr types.Address, totalNum uint64, balanceMap map[types.TokenTypeId]*big.Int) *RpcAccountInfo {
+	tokenBalanceInfoMap := make(map[types.TokenTypeId]*RpcTokenBalanceInfo)
+	for tokenId, amount := range balanceMap {
+		token, _ := reader.GetTokenInfoById(&tokenId)
+		tokenBalanceInfoMap[tokenId] = &RpcTokenBalanceInfo{
+			TokenInfo:   RawTokenInfoToRpc(token, tokenId),
+			TotalAmount: amount.String(),
+			Number:      nil,
+		}
+	}
+
+	return &RpcAccountInfo{
+		AccountAddress:      addr,
+		TotalNumber:         strconv.FormatUint(totalNum, 10),
+		TokenBalanceInfoMap: tokenBalanceInfoMap,
+	}
+}
+
+// AccountProof is the result of ledger_getProof, the proofs are the serialized trie nodes from the root, they're
+// verified by trie.VerifyProof.
+type AccountProof struct {
+	SnapshotHash   types.Hash    `json:"snapshotHash"`
+	SnapshotHeight string        `json:"snapshotHeight"` // uint64
+	StateHash      types.Hash    `json:"stateHash"`
+	Address        types.Address `json:"address"`
+	AccountProof   [][]byte      `json:"accountProof"`
+	// the state root of the account, nil if the account doesn't exist at the snapshot block
+	StorageHash  *types.Hash     `json:"storageHash,omitempty"`
+	StorageProof []*StorageProof `json:"storageProof"`
+}
+
+type StorageProof struct {
+	Key   string   `json:"key"`             // hex
+	Value *string  `json:"value,omitempty"` // hex
+	Proof [][]byte `json:"proof,omitempty"`
+}
+
 type RpcTokenBalanceInfo struct {
 	TokenInfo   *RpcTokenInfo `json:"tokenInfo,omitempty"`
 	TotalAmount string        `json:"totalAmount"`      // big int
@@ -188,8 +228,12 @@ type KafkaSendInfo struct {
 
 type KafkaProducerInfo struct {
 	ProducerId uint8    `json:"producerId"`
+	Kind       string   `json:"kind"`
+	Format     string   `json:"format,omitempty"`
 	BrokerList []string `json:"brokerList"`
 	Topic      string   `json:"topic"`
+	Path       string   `json:"path,omitempty"`
+	Url        string   `json:"url,omitempty"`
 	HasSend    uint64   `json:"hasSend"`
 	Status     string   `json:"status"`
 }
@@ -203,10 +247,15 @@ func createKafkaProducerInfo(producer *sender.Producer) *KafkaProducerInfo {
 		status = "running"
 	}
 
+	sinkConfig := producer.SinkConfig()
 	producerInfo := &KafkaProducerInfo{
 		ProducerId: producer.ProducerId(),
-		BrokerList: producer.BrokerList(),
-		Topic:      producer.Topic(),
+		Kind:       sinkConfig.Kind,
+		Format:     sinkConfig.Format,
+		BrokerList: sinkConfig.BrokerList,
+		Topic:      sinkConfig.Topic,
+		Path:       sinkConfig.Path,
+		Url:        sinkConfig.Url,
 		HasSend:    producer.HasSend(),
 		Status:     status,
 	}
@@ -214,7 +263,18 @@ func createKafkaProducerInfo(producer *sender.Producer) *KafkaProducerInfo {
 	return producerInfo
 }
 
-func ledgerToRpcBlock(block *ledger.AccountBlock, chain chain.Chain) (*AccountBlock, error) {
+// accountBlockReader is the part of chain.Chain and chain.ReadView used to convert blocks.
+type accountBlockReader interface {
+	GetConfirmTimes(accountBlockHash *types.Hash) (uint64, error)
+	GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error)
+	GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error)
+	GetTokenInfoById(tokenId *types.TokenTypeId) (*types.TokenInfo, error)
+}
+
+func ledgerToRpcBlock(block *ledger.AccountBlock, chain accountBlockReader) (*AccountBlock, error) {
+	if block == nil {
+		return nil, nil
+	}
 	confirmTimes, err := chain.GetConfirmTimes(&block.Hash)
 
 	if err != nil {
@@ -248,7 +308,7 @@ func ledgerToRpcBlock(block *ledger.AccountBlock, chain chain.Chain) (*AccountBl
 	if block.IsSendBlock() {
 		if block.Meta == nil {
 			var err error
-			block.Meta, err = chain.ChainDb().Ac.GetBlockMeta(&block.Hash)
+			block.Meta, err = chain.GetAccountBlockMetaByHash(&block.Hash)
 			if err != nil {
 				return nil, err
 			}
@@ -1,9 +1,12 @@
 package api
 
 import (
+	"errors"
 	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain/index"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/vm/contracts/abi"
 	"github.com/vitelabs/go-vite/vm_context"
@@ -26,6 +29,18 @@ func (m MintageApi) String() string {
 	return "MintageApi"
 }
 
+// MethodDocs annotates the token queries for rpc_discover.
+func (m MintageApi) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"getTokenInfoList":        {Summary: "the page of the tokens", Params: []string{"index", "count"}},
+		"getTokenInfoById":        {Summary: "the token info", Params: []string{"tokenId"}},
+		"getTokenInfoListByOwner": {Summary: "the tokens owned by the address", Params: []string{"owner"}},
+		"getTokenHolders":         {Summary: "the page of the holders of the token", Params: []string{"tokenId", "index", "count"}},
+		"getTokenHolderCount":     {Summary: "the count of the holders of the token", Params: []string{"tokenId"}},
+		"getTokenSupply":          {Summary: "the total supply of the token at the snapshot height, the latest if empty", Params: []string{"tokenId", "snapshotHeight"}},
+	}
+}
+
 type NewTokenIdParams struct {
 	SelfAddr     types.Address
 	Height       string
@@ -176,3 +191,106 @@ func (m *MintageApi) GetTokenInfoListByOwner(owner types.Address) ([]*RpcTokenIn
 	}
 	return tokenList, nil
 }
+
+func (m *MintageApi) getTokenHolderIndex() (*chain_index.TokenHolderIndex, error) {
+	var thi *chain_index.TokenHolderIndex
+	if indexManager := m.chain.IndexManager(); indexManager != nil {
+		thi, _ = indexManager.Index(chain_index.TokenHolderIndexName).(*chain_index.TokenHolderIndex)
+	}
+	if thi == nil {
+		return nil, errors.New("config.OpenTokenHolderIndex is false, api can't work")
+	}
+	return thi, nil
+}
+
+type RpcTokenHolder struct {
+	Address types.Address `json:"address"`
+	Balance *string       `json:"balance"` // *big.Int
+}
+
+type TokenHolderList struct {
+	Count string            `json:"totalCount"` // uint64
+	List  []*RpcTokenHolder `json:"tokenHolderList"`
+}
+
+// GetTokenHolders returns the holders of the token ordered by the balance descending, index is the page number.
+func (m *MintageApi) GetTokenHolders(tokenId types.TokenTypeId, index int, count int) (*TokenHolderList, error) {
+	thi, err := m.getTokenHolderIndex()
+	if err != nil {
+		return nil, err
+	}
+	stat, err := thi.GetTokenStat(tokenId, 0)
+	if err != nil {
+		m.log.Error("GetTokenStat failed, error is "+err.Error(), "method", "GetTokenHolders")
+		return nil, err
+	}
+
+	holderList := &TokenHolderList{Count: uint64ToString(stat.HolderCount), List: make([]*RpcTokenHolder, 0)}
+	if index < 0 || count <= 0 {
+		return holderList, nil
+	}
+	offset, err := pageOffset(index, count)
+	if err != nil {
+		return nil, err
+	}
+	holders, err := thi.GetTokenHolders(tokenId, offset, count)
+	if err != nil {
+		m.log.Error("GetTokenHolders failed, error is "+err.Error(), "method", "GetTokenHolders")
+		return nil, err
+	}
+	for _, holder := range holders {
+		holderList.List = append(holderList.List, &RpcTokenHolder{Address: holder.Address, Balance: bigIntToString(holder.Balance)})
+	}
+	return holderList, nil
+}
+
+func (m *MintageApi) GetTokenHolderCount(tokenId types.TokenTypeId) (string, error) {
+	thi, err := m.getTokenHolderIndex()
+	if err != nil {
+		return "", err
+	}
+	stat, err := thi.GetTokenStat(tokenId, 0)
+	if err != nil {
+		m.log.Error("GetTokenStat failed, error is "+err.Error(), "method", "GetTokenHolderCount")
+		return "", err
+	}
+	return uint64ToString(stat.HolderCount), nil
+}
+
+type RpcTokenSupply struct {
+	TokenId        types.TokenTypeId `json:"tokenId"`
+	SnapshotHeight string            `json:"snapshotHeight"` // uint64
+	HolderCount    string            `json:"holderCount"`    // uint64
+	Supply         *string           `json:"supply"`         // *big.Int
+}
+
+// GetTokenSupply returns the total supply of the token after the snapshot block of snapshotHeight, which is the minted
+// amount minus the burned amount recorded by the mintage contract, the amounts onroad included. Empty snapshotHeight
+// means the latest snapshot block.
+func (m *MintageApi) GetTokenSupply(tokenId types.TokenTypeId, snapshotHeight string) (*RpcTokenSupply, error) {
+	thi, err := m.getTokenHolderIndex()
+	if err != nil {
+		return nil, err
+	}
+	var height uint64
+	if snapshotHeight != "" {
+		if height, err = stringToUint64(snapshotHeight); err != nil {
+			return nil, err
+		}
+	}
+
+	stat, err := thi.GetTokenStat(tokenId, height)
+	if err != nil {
+		m.log.Error("GetTokenStat failed, error is "+err.Error(), "method", "GetTokenSupply")
+		return nil, err
+	}
+	if height == 0 {
+		height = m.chain.GetLatestSnapshotBlock().Height
+	}
+	return &RpcTokenSupply{
+		TokenId:        tokenId,
+		SnapshotHeight: uint64ToString(height),
+		HolderCount:    uint64ToString(stat.HolderCount),
+		Supply:         bigIntToString(stat.Supply),
+	}, nil
+}
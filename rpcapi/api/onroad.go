@@ -8,6 +8,7 @@ import (
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/onroad"
 	"github.com/vitelabs/go-vite/onroad/model"
+	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/vite"
 )
 
@@ -19,6 +20,14 @@ func (o PublicOnroadApi) String() string {
 	return "PublicOnroadApi"
 }
 
+// MethodDocs annotates the public onroad queries for rpc_discover.
+func (o PublicOnroadApi) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"getOnroadBlocksByAddress": {Summary: "the page of the onroad blocks of the address", Params: []string{"address", "index", "count"}},
+		"getAccountOnroadInfo":     {Summary: "the onroad balances of the address", Params: []string{"address"}},
+	}
+}
+
 func NewPublicOnroadApi(vite *vite.Vite) *PublicOnroadApi {
 	return &PublicOnroadApi{
 		api: NewPrivateOnroadApi(vite),
@@ -4,8 +4,10 @@ import (
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/vm/contracts/abi"
+	"github.com/vitelabs/go-vite/vm/quota"
 	"github.com/vitelabs/go-vite/vm/util"
 	"github.com/vitelabs/go-vite/vm_context"
 	"sort"
@@ -29,6 +31,16 @@ func (p PledgeApi) String() string {
 	return "PledgeApi"
 }
 
+// MethodDocs annotates the pledge queries for rpc_discover.
+func (p PledgeApi) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"getPledgeData":       {Summary: "the data of the pledge transaction", Params: []string{"beneficialAddr"}},
+		"getCancelPledgeData": {Summary: "the data of the transaction cancelling the pledge", Params: []string{"beneficialAddr", "amount"}},
+		"getPledgeQuota":      {Summary: "the quota of the address at the snapshot height or hash, the latest by default", Params: []string{"addr", "snapshot"}},
+		"getPledgeList":       {Summary: "the page of the pledges of the address", Params: []string{"addr", "index", "count", "snapshot"}},
+	}
+}
+
 func (p *PledgeApi) GetPledgeData(beneficialAddr types.Address) ([]byte, error) {
 	return abi.ABIPledge.PackMethod(abi.MethodNamePledge, beneficialAddr)
 }
@@ -46,7 +58,13 @@ type QuotaAndTxNum struct {
 	TxNum string `json:"txNum"`
 }
 
-func (p *PledgeApi) GetPledgeQuota(addr types.Address) (*QuotaAndTxNum, error) {
+// GetPledgeQuota returns the quota of addr at the fittest snapshot block, or at the snapshot block of snapshot, a
+// snapshot height or hash, if it's given.
+func (p *PledgeApi) GetPledgeQuota(addr types.Address, snapshot *string) (*QuotaAndTxNum, error) {
+	if snapshot != nil {
+		return p.getPledgeQuotaAtSnapshot(addr, snapshot)
+	}
+
 	hash, err := p.ledgerApi.GetFittestSnapshotHash(&addr, nil)
 	if err != nil {
 		return nil, err
@@ -58,6 +76,22 @@ func (p *PledgeApi) GetPledgeQuota(addr types.Address) (*QuotaAndTxNum, error) {
 	return &QuotaAndTxNum{uint64ToString(q), uint64ToString(q / util.TxGas)}, nil
 }
 
+func (p *PledgeApi) getPledgeQuotaAtSnapshot(addr types.Address, snapshot *string) (*QuotaAndTxNum, error) {
+	snapshotBlock, err := getStateSnapshotBlock(p.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	vmContext, err := newVmContextAtSnapshot(p.chain, snapshotBlock, &addr)
+	if err != nil {
+		return nil, err
+	}
+	q, err := quota.GetPledgeQuota(vmContext, addr, abi.GetPledgeBeneficialAmount(vmContext, addr))
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaAndTxNum{uint64ToString(q), uint64ToString(q / util.TxGas)}, nil
+}
+
 type PledgeInfoList struct {
 	TotalPledgeAmount string        `json:"totalPledgeAmount"`
 	Count             int           `json:"totalCount"`
@@ -80,8 +114,11 @@ func (a byWithdrawHeight) Less(i, j int) bool {
 	return a[i].WithdrawHeight < a[j].WithdrawHeight
 }
 
-func (p *PledgeApi) GetPledgeList(addr types.Address, index int, count int) (*PledgeInfoList, error) {
-	snapshotBlock := p.chain.GetLatestSnapshotBlock()
+func (p *PledgeApi) GetPledgeList(addr types.Address, index int, count int, snapshot *string) (*PledgeInfoList, error) {
+	snapshotBlock, err := getStateSnapshotBlock(p.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
 	vmContext, err := vm_context.NewVmContext(p.chain, &snapshotBlock.Hash, nil, nil)
 	if err != nil {
 		return nil, err
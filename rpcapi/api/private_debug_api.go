@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain/consistency"
+	"github.com/vitelabs/go-vite/chain/index"
+	"github.com/vitelabs/go-vite/chain_db/disk_usage_analysis"
+	"github.com/vitelabs/go-vite/vite"
+)
+
+// JobStatus is the status of a background job, Result and Error are of the last finished run.
+type JobStatus struct {
+	Running   bool        `json:"running"`
+	StartTime int64       `json:"startTime"`
+	EndTime   int64       `json:"endTime"`
+	Result    interface{} `json:"result"`
+	Error     string      `json:"error"`
+}
+
+// backgroundJob runs a long task in a goroutine, only one run at a time.
+type backgroundJob struct {
+	lock   sync.Mutex
+	status JobStatus
+}
+
+func (job *backgroundJob) start(run func() (interface{}, error)) error {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+
+	if job.status.Running {
+		return ErrJobRunning
+	}
+	job.status = JobStatus{
+		Running:   true,
+		StartTime: time.Now().Unix(),
+	}
+
+	go func() {
+		result, err := run()
+
+		job.lock.Lock()
+		defer job.lock.Unlock()
+		job.status.Running = false
+		job.status.EndTime = time.Now().Unix()
+		job.status.Result = result
+		if err != nil {
+			job.status.Error = err.Error()
+		}
+	}()
+	return nil
+}
+
+func (job *backgroundJob) getStatus() JobStatus {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.status
+}
+
+// the jobs are shared by the PrivateDebugApi of every endpoint
+var (
+	verifyLedgerJob = &backgroundJob{}
+	dbStatsJob      = &backgroundJob{}
+)
+
+// PrivateDebugApi serves the debug methods which walk the whole ledger, they're not public and need a credential if
+// the authentication is enabled.
+type PrivateDebugApi struct {
+	v *vite.Vite
+}
+
+func NewPrivateDebugApi(v *vite.Vite) *PrivateDebugApi {
+	return &PrivateDebugApi{
+		v: v,
+	}
+}
+
+func (api PrivateDebugApi) String() string {
+	return "PrivateDebugApi"
+}
+
+// VerifyLedger starts to walk the ledger and check its invariants in the background, VerifyLedgerStatus reports the
+// progress and the report.
+func (api PrivateDebugApi) VerifyLedger(maxViolations int) error {
+	c := api.v.Chain()
+	return verifyLedgerJob.start(func() (interface{}, error) {
+		checker := consistency.NewChecker(c, c.TrieGc().RetainMinHeight())
+		if maxViolations > 0 {
+			checker.SetMaxViolations(maxViolations)
+		}
+		return checker.Check()
+	})
+}
+
+// VerifyLedgerStatus reports whether VerifyLedger is running, the result is the report of the last run.
+func (api PrivateDebugApi) VerifyLedgerStatus() JobStatus {
+	return verifyLedgerJob.getStatus()
+}
+
+// DbStats starts to walk the database and count its usage in the background, DbStatsStatus reports the progress and
+// the stats. The hashes of the reachable trie nodes are kept in memory, they grow with the state tries from
+// trieRootFromHeight to the latest snapshot block.
+func (api PrivateDebugApi) DbStats(trieRootFromHeight uint64, topN int) error {
+	if topN <= 0 {
+		topN = 20
+	}
+	chainDb := api.v.Chain().ChainDb()
+	return dbStatsJob.start(func() (interface{}, error) {
+		return disk_usage_analysis.Analyze(chainDb, trieRootFromHeight, topN)
+	})
+}
+
+// DbStatsStatus reports whether DbStats is running, the result is the stats of the last run.
+func (api PrivateDebugApi) DbStatsStatus() JobStatus {
+	return dbStatsJob.getStatus()
+}
+
+// IndexStatus reports the progress of the opened indexes.
+func (api PrivateDebugApi) IndexStatus() ([]*chain_index.Status, error) {
+	indexManager := api.v.Chain().IndexManager()
+	if indexManager == nil {
+		return nil, errors.New("no index is opened")
+	}
+	return indexManager.Status()
+}
+
+// RebuildIndex clears the index, it is built again from the first block event in the background.
+func (api PrivateDebugApi) RebuildIndex(name string) error {
+	indexManager := api.v.Chain().IndexManager()
+	if indexManager == nil {
+		return errors.New("no index is opened")
+	}
+	return indexManager.Rebuild(name)
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackgroundJob(t *testing.T) {
+	job := &backgroundJob{}
+	release := make(chan struct{})
+	if err := job.start(func() (interface{}, error) {
+		<-release
+		return 1, errors.New("failed")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !job.getStatus().Running {
+		t.Fatal("the job isn't running")
+	}
+	if err := job.start(func() (interface{}, error) { return nil, nil }); err != ErrJobRunning {
+		t.Fatalf("error is %v, expected %v", err, ErrJobRunning)
+	}
+
+	close(release)
+	for i := 0; job.getStatus().Running; i++ {
+		if i >= 100 {
+			t.Fatal("the job isn't finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	status := job.getStatus()
+	if status.Result != 1 || status.Error != "failed" || status.EndTime < status.StartTime {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if err := job.start(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/consensus"
 	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/vm/contracts/abi"
 	"github.com/vitelabs/go-vite/vm_context"
@@ -31,6 +32,16 @@ func (r RegisterApi) String() string {
 	return "RegisterApi"
 }
 
+// MethodDocs annotates the registration queries for rpc_discover.
+func (r RegisterApi) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"getRegistrationList":   {Summary: "the registrations of the pledge address in the consensus group", Params: []string{"gid", "pledgeAddr", "snapshot"}},
+		"getRegistration":       {Summary: "the registration of the name in the consensus group", Params: []string{"name", "gid", "snapshot"}},
+		"getRegisterPledgeAddr": {Summary: "the pledge address of the registration", Params: []string{"name", "gid"}},
+		"getCandidateList":      {Summary: "the candidates of the consensus group", Params: []string{"gid"}},
+	}
+}
+
 func (r *RegisterApi) GetRegisterData(gid types.Gid, name string, nodeAddr types.Address) ([]byte, error) {
 	return abi.ABIRegister.PackMethod(abi.MethodNameRegister, gid, name, nodeAddr)
 }
@@ -65,8 +76,11 @@ func (a byRegistrationWithdrawHeight) Less(i, j int) bool {
 	return a[i].WithdrawHeight > a[j].WithdrawHeight
 }
 
-func (r *RegisterApi) GetRegistrationList(gid types.Gid, pledgeAddr types.Address) ([]*RegistrationInfo, error) {
-	snapshotBlock := r.chain.GetLatestSnapshotBlock()
+func (r *RegisterApi) GetRegistrationList(gid types.Gid, pledgeAddr types.Address, snapshot *string) ([]*RegistrationInfo, error) {
+	snapshotBlock, err := getStateSnapshotBlock(r.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
 	vmContext, err := vm_context.NewVmContext(r.chain, &snapshotBlock.Hash, nil, nil)
 	if err != nil {
 		return nil, err
@@ -90,8 +104,12 @@ func (r *RegisterApi) GetRegistrationList(gid types.Gid, pledgeAddr types.Addres
 	return targetList, nil
 }
 
-func (r *RegisterApi) GetRegistration(name string, gid types.Gid) (*types.Registration, error) {
-	vmContext, err := vm_context.NewVmContext(r.chain, nil, nil, nil)
+func (r *RegisterApi) GetRegistration(name string, gid types.Gid, snapshot *string) (*types.Registration, error) {
+	snapshotBlock, err := getStateSnapshotBlock(r.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	vmContext, err := vm_context.NewVmContext(r.chain, &snapshotBlock.Hash, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +124,7 @@ func (r *RegisterApi) GetRegisterPledgeAddr(name string, gid *types.Gid) (*types
 	} else {
 		g = *gid
 	}
-	registration, err := r.GetRegistration(name, g)
+	registration, err := r.GetRegistration(name, g, nil)
 	if err != nil {
 		return nil, err
 	}
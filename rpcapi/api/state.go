@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/vm_context"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
+)
+
+// getStateSnapshotBlock resolves the optional snapshot parameter of the state queries, it's the height or the hash of
+// a snapshot block, nil means the latest snapshot block. An error is returned if the state of the snapshot block has
+// been pruned by the trie gc.
+func getStateSnapshotBlock(c chain.Chain, snapshot *string) (*ledger.SnapshotBlock, error) {
+	if snapshot == nil {
+		return c.GetLatestSnapshotBlock(), nil
+	}
+
+	var snapshotBlock *ledger.SnapshotBlock
+	if len(*snapshot) == 2*types.HashSize {
+		hash, err := types.HexToHash(*snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if snapshotBlock, err = c.GetSnapshotBlockByHash(&hash); err != nil {
+			return nil, err
+		}
+	} else {
+		height, err := stringToUint64(*snapshot)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("%s is neither a snapshot height nor a snapshot hash", *snapshot))
+		}
+		if snapshotBlock, err = c.GetSnapshotBlockByHeight(height); err != nil {
+			return nil, err
+		}
+	}
+	if snapshotBlock == nil {
+		return nil, errors.New(fmt.Sprintf("snapshot block %s doesn't exist", *snapshot))
+	}
+
+	pruned, err := c.IsStatePruned(snapshotBlock)
+	if err != nil {
+		return nil, err
+	}
+	if pruned {
+		return nil, errors.New(fmt.Sprintf("the state of snapshot block %d has been pruned by the ledger gc, the states before snapshot block %d aren't kept",
+			snapshotBlock.Height, c.TrieGc().RetainMinHeight()))
+	}
+	return snapshotBlock, nil
+}
+
+// newVmContextAtSnapshot returns the vm context of snapshotBlock, the state of addr is the one confirmed by
+// snapshotBlock instead of the latest one.
+func newVmContextAtSnapshot(c chain.Chain, snapshotBlock *ledger.SnapshotBlock, addr *types.Address) (vmctxt_interface.VmDatabase, error) {
+	if addr == nil {
+		return vm_context.NewVmContext(c, &snapshotBlock.Hash, nil, nil)
+	}
+
+	prevHash := types.ZERO_HASH
+	confirmedBlock, err := c.GetConfirmAccountBlock(snapshotBlock.Height, addr)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedBlock != nil {
+		prevHash = confirmedBlock.Hash
+	}
+	return vm_context.NewVmContext(c, &snapshotBlock.Hash, &prevHash, addr)
+}
+
+// getBalanceMapAtSnapshot returns the balances of addr confirmed by snapshotBlock.
+func getBalanceMapAtSnapshot(c chain.Chain, snapshotBlock *ledger.SnapshotBlock, addr types.Address) (map[types.TokenTypeId]*big.Int, error) {
+	vmContext, err := vm_context.NewVmContext(c, &snapshotBlock.Hash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	balanceMap := make(map[types.TokenTypeId]*big.Int)
+	iter := vmContext.NewStorageIterator(&addr, vm_context.STORAGE_KEY_BALANCE)
+	if iter == nil {
+		return balanceMap, nil
+	}
+
+	prefixKeyLen := len(vm_context.STORAGE_KEY_BALANCE)
+	for {
+		key, value, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		tokenId, err := types.BytesToTokenTypeId(key[prefixKeyLen:])
+		if err != nil {
+			return nil, err
+		}
+		balanceMap[tokenId] = new(big.Int).SetBytes(value)
+	}
+	return balanceMap, nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain/trie_gc"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+)
+
+type stateTestGc struct {
+	trie_gc.Collector
+	retainMinHeight uint64
+}
+
+func (gc *stateTestGc) RetainMinHeight() uint64 {
+	return gc.retainMinHeight
+}
+
+// stateTestChain keeps the snapshot blocks in memory, the states below retainMinHeight are pruned.
+type stateTestChain struct {
+	chain.Chain
+	snapshotBlocks []*ledger.SnapshotBlock
+	gc             *stateTestGc
+}
+
+func (c *stateTestChain) GetLatestSnapshotBlock() *ledger.SnapshotBlock {
+	return c.snapshotBlocks[len(c.snapshotBlocks)-1]
+}
+
+func (c *stateTestChain) GetSnapshotBlockByHeight(height uint64) (*ledger.SnapshotBlock, error) {
+	if height < 1 || height > uint64(len(c.snapshotBlocks)) {
+		return nil, nil
+	}
+	return c.snapshotBlocks[height-1], nil
+}
+
+func (c *stateTestChain) GetSnapshotBlockByHash(hash *types.Hash) (*ledger.SnapshotBlock, error) {
+	for _, block := range c.snapshotBlocks {
+		if block.Hash == *hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *stateTestChain) IsStatePruned(snapshotBlock *ledger.SnapshotBlock) (bool, error) {
+	return snapshotBlock.Height < c.gc.retainMinHeight, nil
+}
+
+func (c *stateTestChain) TrieGc() trie_gc.Collector {
+	return c.gc
+}
+
+func TestGetStateSnapshotBlock(t *testing.T) {
+	c := &stateTestChain{gc: &stateTestGc{retainMinHeight: 2}}
+	for i := 1; i <= 3; i++ {
+		c.snapshotBlocks = append(c.snapshotBlocks, &ledger.SnapshotBlock{
+			Height: uint64(i),
+			Hash:   types.DataHash([]byte{byte(i)}),
+		})
+	}
+
+	if block, err := getStateSnapshotBlock(c, nil); err != nil || block.Height != 3 {
+		t.Fatal("nil should be the latest snapshot block")
+	}
+	height := "2"
+	if block, err := getStateSnapshotBlock(c, &height); err != nil || block.Height != 2 {
+		t.Fatalf("wrong snapshot block of height 2, error is %v", err)
+	}
+	hash := c.snapshotBlocks[2].Hash.String()
+	if block, err := getStateSnapshotBlock(c, &hash); err != nil || block.Height != 3 {
+		t.Fatalf("wrong snapshot block of hash %s, error is %v", hash, err)
+	}
+
+	for _, snapshot := range []string{"1", "4", "abc", types.DataHash([]byte("none")).String()} {
+		if _, err := getStateSnapshotBlock(c, &snapshot); err == nil {
+			t.Fatalf("snapshot %s should fail", snapshot)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/vitelabs/go-vite/chain/index"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vm/abi"
+)
+
+// SubscribeApi pushes the new blocks, logs, onroad blocks and rollbacks to the websocket clients by
+// subscribe_subscribe, the http clients create a filter by newXxxFilter and poll it by getFilterChanges instead.
+//
+// The messages are *ledger.SnapshotBlock for newSnapshotBlocks, *AccountBlock for newAccountBlocks and
+// newOnroadBlocks, *Log for newLogs and *RollbackMessage for newRollbacks.
+type SubscribeApi struct {
+	es *eventSystem
+}
+
+func NewSubscribeApi(vite *vite.Vite) *SubscribeApi {
+	return &SubscribeApi{
+		es: getEventSystem(vite.Chain()),
+	}
+}
+
+func (s SubscribeApi) String() string {
+	return "SubscribeApi"
+}
+
+// MethodDocs annotates the subscriptions and filters for rpc_discover.
+func (s SubscribeApi) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"newSnapshotBlocks":       {Summary: "the new snapshot blocks"},
+		"newAccountBlocks":        {Summary: "the new account blocks of the addresses, empty means any address", Params: []string{"addrs"}},
+		"newLogs":                 {Summary: "the vm logs of the new account blocks matching the filter", Params: []string{"filter"}},
+		"newOnroadBlocks":         {Summary: "the send blocks to the addresses, empty means any address", Params: []string{"addrs"}},
+		"newRollbacks":            {Summary: "the rolled back snapshot and account blocks"},
+		"newSnapshotBlocksFilter": {Summary: "installs the polling filter of newSnapshotBlocks"},
+		"newAccountBlocksFilter":  {Summary: "installs the polling filter of newAccountBlocks", Params: []string{"addrs"}},
+		"newLogsFilter":           {Summary: "installs the polling filter of newLogs", Params: []string{"filter"}},
+		"newOnroadBlocksFilter":   {Summary: "installs the polling filter of newOnroadBlocks", Params: []string{"addrs"}},
+		"newRollbacksFilter":      {Summary: "installs the polling filter of newRollbacks"},
+		"getFilterChanges":        {Summary: "the events of the filter since the last poll", Params: []string{"id"}},
+		"uninstallFilter":         {Summary: "uninstalls the filter", Params: []string{"id"}},
+	}
+}
+
+func newAddressSubscription(kind int, addrs []types.Address) *subscription {
+	sub := &subscription{
+		id:      rpc.NewID(),
+		kind:    kind,
+		addrSet: make(map[types.Address]struct{}, len(addrs)),
+	}
+	for _, addr := range addrs {
+		sub.addrSet[addr] = struct{}{}
+	}
+	return sub
+}
+
+// newLogsSubscription selects the logs by the addresses and the topics of filter, the heights are ignored because
+// the logs are pushed when the blocks are inserted.
+func newLogsSubscription(filter LogFilter) (*subscription, error) {
+	sub := &subscription{
+		id:   rpc.NewID(),
+		kind: LogsSubscription,
+		logFilter: &chain_index.VmLogFilter{
+			Addresses: filter.Addresses,
+			Topics:    filter.Topics,
+		},
+	}
+	if filter.Abi != "" {
+		contract, err := abi.JSONToABIContract(strings.NewReader(filter.Abi))
+		if err != nil {
+			return nil, err
+		}
+		sub.abiContract = &contract
+	}
+	return sub, nil
+}
+
+func (s *SubscribeApi) subscribe(ctx context.Context, sub *subscription) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	sub.id = rpcSub.ID
+	sub.notify = make(chan interface{}, subscriptionBufferSize)
+	s.es.install(sub)
+
+	go func() {
+		defer s.es.uninstall(sub.id)
+		for {
+			select {
+			case msg := <-sub.notify:
+				if err := notifier.Notify(rpcSub.ID, msg); err != nil {
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+func (s *SubscribeApi) NewSnapshotBlocks(ctx context.Context) (*rpc.Subscription, error) {
+	log.Info("NewSnapshotBlocks")
+	return s.subscribe(ctx, &subscription{kind: SnapshotBlocksSubscription})
+}
+
+// NewAccountBlocks pushes the account blocks of addrs, empty addrs means any address.
+func (s *SubscribeApi) NewAccountBlocks(ctx context.Context, addrs []types.Address) (*rpc.Subscription, error) {
+	log.Info("NewAccountBlocks")
+	return s.subscribe(ctx, newAddressSubscription(AccountBlocksSubscription, addrs))
+}
+
+func (s *SubscribeApi) NewLogs(ctx context.Context, filter LogFilter) (*rpc.Subscription, error) {
+	log.Info("NewLogs")
+	sub, err := newLogsSubscription(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.subscribe(ctx, sub)
+}
+
+// NewOnroadBlocks pushes the send blocks to addrs, empty addrs means any address.
+func (s *SubscribeApi) NewOnroadBlocks(ctx context.Context, addrs []types.Address) (*rpc.Subscription, error) {
+	log.Info("NewOnroadBlocks")
+	return s.subscribe(ctx, newAddressSubscription(OnroadBlocksSubscription, addrs))
+}
+
+func (s *SubscribeApi) NewRollbacks(ctx context.Context) (*rpc.Subscription, error) {
+	log.Info("NewRollbacks")
+	return s.subscribe(ctx, &subscription{kind: RollbacksSubscription})
+}
+
+// newFilter installs the filter for the client of ctx, a client has at most 16 filters.
+func (s *SubscribeApi) newFilter(ctx context.Context, sub *subscription) (rpc.ID, error) {
+	if sub.id == "" {
+		sub.id = rpc.NewID()
+	}
+	sub.client = rpc.ClientFromContext(ctx)
+	if err := s.es.installFilter(sub); err != nil {
+		return "", err
+	}
+	return sub.id, nil
+}
+
+func (s *SubscribeApi) NewSnapshotBlocksFilter(ctx context.Context) (rpc.ID, error) {
+	log.Info("NewSnapshotBlocksFilter")
+	return s.newFilter(ctx, &subscription{kind: SnapshotBlocksSubscription})
+}
+
+func (s *SubscribeApi) NewAccountBlocksFilter(ctx context.Context, addrs []types.Address) (rpc.ID, error) {
+	log.Info("NewAccountBlocksFilter")
+	return s.newFilter(ctx, newAddressSubscription(AccountBlocksSubscription, addrs))
+}
+
+func (s *SubscribeApi) NewLogsFilter(ctx context.Context, filter LogFilter) (rpc.ID, error) {
+	log.Info("NewLogsFilter")
+	sub, err := newLogsSubscription(filter)
+	if err != nil {
+		return "", err
+	}
+	return s.newFilter(ctx, sub)
+}
+
+func (s *SubscribeApi) NewOnroadBlocksFilter(ctx context.Context, addrs []types.Address) (rpc.ID, error) {
+	log.Info("NewOnroadBlocksFilter")
+	return s.newFilter(ctx, newAddressSubscription(OnroadBlocksSubscription, addrs))
+}
+
+func (s *SubscribeApi) NewRollbacksFilter(ctx context.Context) (rpc.ID, error) {
+	log.Info("NewRollbacksFilter")
+	return s.newFilter(ctx, &subscription{kind: RollbacksSubscription})
+}
+
+// GetFilterChanges returns the messages of the filter since the last poll, a filter not polled in 5 minutes is
+// uninstalled.
+func (s *SubscribeApi) GetFilterChanges(id rpc.ID) ([]interface{}, error) {
+	log.Info("GetFilterChanges")
+	sub := s.es.getSubscription(id)
+	if sub == nil || sub.notify != nil {
+		return nil, errors.New("filter not found")
+	}
+	return sub.takeChanges(), nil
+}
+
+func (s *SubscribeApi) UninstallFilter(id rpc.ID) bool {
+	log.Info("UninstallFilter")
+	sub := s.es.getSubscription(id)
+	if sub == nil || sub.notify != nil {
+		return false
+	}
+	return s.es.uninstall(id)
+}
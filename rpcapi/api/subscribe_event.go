@@ -0,0 +1,406 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/chain/index"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/log15"
+	"github.com/vitelabs/go-vite/metrics"
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/vm/abi"
+	"github.com/vitelabs/go-vite/vm_context"
+)
+
+const (
+	SnapshotBlocksSubscription = iota
+	AccountBlocksSubscription
+	LogsSubscription
+	OnroadBlocksSubscription
+	RollbacksSubscription
+)
+
+const (
+	// the messages waiting to be written to a websocket, the messages exceeding it are dropped
+	subscriptionBufferSize = 1024
+	// the changes kept by a filter between two polls, the oldest changes are dropped
+	maxFilterChanges = 1000
+	// a filter is uninstalled if it isn't polled in filterTimeout
+	filterTimeout = 5 * time.Minute
+	// the filters installed by a client, an api key or a remote ip, and by all the clients
+	maxFiltersPerClient = 16
+	maxFilters          = 1024
+	// the chain events waiting to be dispatched, the events exceeding it are dropped
+	eventQueueSize = 1024
+)
+
+var droppedEventCounter = metrics.GetOrRegisterCounter("/rpcapi/subscribe/droppedEvents", nil)
+
+// RollbackMessage lists the blocks deleted from the chain, the account blocks are grouped by their addresses.
+type RollbackMessage struct {
+	SnapshotBlocks []*ledger.HashHeight                   `json:"snapshotBlocks,omitempty"`
+	AccountBlocks  map[types.Address][]*ledger.HashHeight `json:"accountBlocks,omitempty"`
+}
+
+// subscribeChain is the part of chain.Chain used by the subscriptions.
+type subscribeChain interface {
+	accountBlockReader
+	GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error)
+
+	RegisterInsertAccountBlocksSuccess(processor chain.InsertProcessorFuncSuccess) uint64
+	RegisterDeleteAccountBlocksSuccess(processor chain.DeleteProcessorFuncSuccess) uint64
+	RegisterInsertSnapshotBlocksSuccess(processor chain.InsertSnapshotBlocksSuccess) uint64
+	RegisterDeleteSnapshotBlocksSuccess(processor chain.DeleteSnapshotBlocksSuccess) uint64
+}
+
+// subscription is a websocket subscription if notify isn't nil, or a filter polled by getFilterChanges.
+type subscription struct {
+	id   rpc.ID
+	kind int
+	// the client installing the filter
+	client string
+
+	// empty means any address
+	addrSet     map[types.Address]struct{}
+	logFilter   *chain_index.VmLogFilter
+	abiContract *abi.ABIContract
+
+	notify chan interface{}
+
+	lock     sync.Mutex
+	changes  []interface{}
+	deadline time.Time
+}
+
+func (sub *subscription) matchAddress(addr types.Address) bool {
+	if len(sub.addrSet) <= 0 {
+		return true
+	}
+	_, ok := sub.addrSet[addr]
+	return ok
+}
+
+func (sub *subscription) deliver(msg interface{}) bool {
+	if sub.notify != nil {
+		select {
+		case sub.notify <- msg:
+			return true
+		default:
+			return false
+		}
+	}
+
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+	sub.changes = append(sub.changes, msg)
+	if len(sub.changes) > maxFilterChanges {
+		sub.changes = sub.changes[len(sub.changes)-maxFilterChanges:]
+	}
+	return true
+}
+
+// takeChanges returns the changes since the last call and extends the deadline of the filter.
+func (sub *subscription) takeChanges() []interface{} {
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+
+	changes := sub.changes
+	sub.changes = nil
+	sub.deadline = time.Now().Add(filterTimeout)
+	if changes == nil {
+		changes = make([]interface{}, 0)
+	}
+	return changes
+}
+
+func (sub *subscription) isExpired(now time.Time) bool {
+	if sub.notify != nil {
+		return false
+	}
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+	return now.After(sub.deadline)
+}
+
+// chainEvent is one callback of the chain, only one of the fields is set.
+type chainEvent struct {
+	insertedAccountBlocks  []*ledger.AccountBlock
+	deletedAccountBlocks   map[types.Address][]*ledger.AccountBlock
+	insertedSnapshotBlocks []*ledger.SnapshotBlock
+	deletedSnapshotBlocks  []*ledger.SnapshotBlock
+}
+
+// eventSystem receives the callbacks of the chain and dispatches them to the subscriptions. The callbacks are queued
+// and handled in one goroutine, so the chain isn't blocked by reading the blocks and the logs. The queue is never
+// waited for, the events are dropped if it's full.
+type eventSystem struct {
+	chain subscribeChain
+
+	lock          sync.RWMutex
+	subscriptions map[rpc.ID]*subscription
+	// the filters by client
+	filters     map[string]int
+	filterCount int
+
+	eventCh chan *chainEvent
+	log     log15.Logger
+}
+
+var (
+	globalEventSystem     *eventSystem
+	globalEventSystemOnce sync.Once
+)
+
+// getEventSystem returns the event system shared by the subscribe apis of all the endpoints.
+func getEventSystem(chain subscribeChain) *eventSystem {
+	globalEventSystemOnce.Do(func() {
+		globalEventSystem = newEventSystem(chain)
+		globalEventSystem.start()
+	})
+	return globalEventSystem
+}
+
+func newEventSystem(chain subscribeChain) *eventSystem {
+	return &eventSystem{
+		chain:         chain,
+		subscriptions: make(map[rpc.ID]*subscription),
+		filters:       make(map[string]int),
+		eventCh:       make(chan *chainEvent, eventQueueSize),
+		log:           log15.New("module", "rpc_api/subscribe_api"),
+	}
+}
+
+func (es *eventSystem) start() {
+	es.chain.RegisterInsertAccountBlocksSuccess(func(blocks []*vm_context.VmAccountBlock) {
+		if !es.hasSubscription() {
+			return
+		}
+		accountBlocks := make([]*ledger.AccountBlock, 0, len(blocks))
+		for _, block := range blocks {
+			accountBlocks = append(accountBlocks, block.AccountBlock)
+		}
+		es.post(&chainEvent{insertedAccountBlocks: accountBlocks})
+	})
+	es.chain.RegisterDeleteAccountBlocksSuccess(func(subLedger map[types.Address][]*ledger.AccountBlock) {
+		if es.hasSubscription() {
+			es.post(&chainEvent{deletedAccountBlocks: subLedger})
+		}
+	})
+	es.chain.RegisterInsertSnapshotBlocksSuccess(func(snapshotBlocks []*ledger.SnapshotBlock) {
+		if es.hasSubscription() {
+			es.post(&chainEvent{insertedSnapshotBlocks: snapshotBlocks})
+		}
+	})
+	es.chain.RegisterDeleteSnapshotBlocksSuccess(func(snapshotBlocks []*ledger.SnapshotBlock) {
+		if es.hasSubscription() {
+			es.post(&chainEvent{deletedSnapshotBlocks: snapshotBlocks})
+		}
+	})
+
+	go func() {
+		ticker := time.NewTicker(filterTimeout / 5)
+		defer ticker.Stop()
+		for {
+			select {
+			case event := <-es.eventCh:
+				es.handleEvent(event)
+			case now := <-ticker.C:
+				es.uninstallExpired(now)
+			}
+		}
+	}()
+}
+
+// post queues the event without blocking the chain callback, which is called in the insertion with the chain locked.
+func (es *eventSystem) post(event *chainEvent) {
+	select {
+	case es.eventCh <- event:
+	default:
+		droppedEventCounter.Inc(1)
+		es.log.Warn("the event queue is full, a chain event is dropped")
+	}
+}
+
+func (es *eventSystem) hasSubscription() bool {
+	es.lock.RLock()
+	defer es.lock.RUnlock()
+	return len(es.subscriptions) > 0
+}
+
+func (es *eventSystem) install(sub *subscription) {
+	sub.deadline = time.Now().Add(filterTimeout)
+
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	es.subscriptions[sub.id] = sub
+}
+
+// installFilter installs the filter unless its client or all the clients have too many filters.
+func (es *eventSystem) installFilter(sub *subscription) error {
+	sub.deadline = time.Now().Add(filterTimeout)
+
+	es.lock.Lock()
+	defer es.lock.Unlock()
+	if es.filterCount >= maxFilters || es.filters[sub.client] >= maxFiltersPerClient {
+		return ErrTooManyFilters
+	}
+	es.filters[sub.client]++
+	es.filterCount++
+	es.subscriptions[sub.id] = sub
+	return nil
+}
+
+func (es *eventSystem) uninstall(id rpc.ID) bool {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+
+	sub, ok := es.subscriptions[id]
+	if !ok {
+		return false
+	}
+	es.remove(sub)
+	return true
+}
+
+// remove deletes sub with the lock held.
+func (es *eventSystem) remove(sub *subscription) {
+	delete(es.subscriptions, sub.id)
+	if sub.notify != nil {
+		return
+	}
+	es.filterCount--
+	if es.filters[sub.client]--; es.filters[sub.client] <= 0 {
+		delete(es.filters, sub.client)
+	}
+}
+
+func (es *eventSystem) getSubscription(id rpc.ID) *subscription {
+	es.lock.RLock()
+	defer es.lock.RUnlock()
+	return es.subscriptions[id]
+}
+
+func (es *eventSystem) uninstallExpired(now time.Time) {
+	es.lock.Lock()
+	defer es.lock.Unlock()
+
+	for _, sub := range es.subscriptions {
+		if sub.isExpired(now) {
+			es.remove(sub)
+		}
+	}
+}
+
+func (es *eventSystem) subscriptionsOf(kind int) []*subscription {
+	es.lock.RLock()
+	defer es.lock.RUnlock()
+
+	var subs []*subscription
+	for _, sub := range es.subscriptions {
+		if sub.kind == kind {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+func (es *eventSystem) dispatch(sub *subscription, msg interface{}) {
+	if !sub.deliver(msg) {
+		es.log.Warn("the subscription is too slow, a message is dropped", "id", sub.id)
+	}
+}
+
+func (es *eventSystem) handleEvent(event *chainEvent) {
+	switch {
+	case event.insertedAccountBlocks != nil:
+		es.handleInsertedAccountBlocks(event.insertedAccountBlocks)
+	case event.deletedAccountBlocks != nil:
+		msg := &RollbackMessage{AccountBlocks: make(map[types.Address][]*ledger.HashHeight)}
+		for addr, blocks := range event.deletedAccountBlocks {
+			for _, block := range blocks {
+				msg.AccountBlocks[addr] = append(msg.AccountBlocks[addr], &ledger.HashHeight{Hash: block.Hash, Height: block.Height})
+			}
+		}
+		for _, sub := range es.subscriptionsOf(RollbacksSubscription) {
+			es.dispatch(sub, msg)
+		}
+	case event.insertedSnapshotBlocks != nil:
+		for _, sub := range es.subscriptionsOf(SnapshotBlocksSubscription) {
+			for _, block := range event.insertedSnapshotBlocks {
+				es.dispatch(sub, block)
+			}
+		}
+	case event.deletedSnapshotBlocks != nil:
+		msg := &RollbackMessage{}
+		for _, block := range event.deletedSnapshotBlocks {
+			msg.SnapshotBlocks = append(msg.SnapshotBlocks, &ledger.HashHeight{Hash: block.Hash, Height: block.Height})
+		}
+		for _, sub := range es.subscriptionsOf(RollbacksSubscription) {
+			es.dispatch(sub, msg)
+		}
+	}
+}
+
+func (es *eventSystem) handleInsertedAccountBlocks(blocks []*ledger.AccountBlock) {
+	accountSubs := es.subscriptionsOf(AccountBlocksSubscription)
+	onroadSubs := es.subscriptionsOf(OnroadBlocksSubscription)
+	logSubs := es.subscriptionsOf(LogsSubscription)
+
+	for _, block := range blocks {
+		if len(accountSubs) > 0 || block.IsSendBlock() && len(onroadSubs) > 0 {
+			rpcBlock, err := ledgerToRpcBlock(block.Copy(), es.chain)
+			if err != nil {
+				es.log.Error("ledgerToRpcBlock failed, error is "+err.Error(), "method", "handleInsertedAccountBlocks")
+				continue
+			}
+			for _, sub := range accountSubs {
+				if sub.matchAddress(block.AccountAddress) {
+					es.dispatch(sub, rpcBlock)
+				}
+			}
+			if block.IsSendBlock() {
+				for _, sub := range onroadSubs {
+					if sub.matchAddress(block.ToAddress) {
+						es.dispatch(sub, rpcBlock)
+					}
+				}
+			}
+		}
+
+		if len(logSubs) > 0 && block.LogHash != nil {
+			logList, err := es.chain.GetVmLogList(block.LogHash)
+			if err != nil {
+				es.log.Error("GetVmLogList failed, error is "+err.Error(), "method", "handleInsertedAccountBlocks")
+				continue
+			}
+			for logIndex, vmLog := range logList {
+				item := &chain_index.VmLogItem{
+					BlockHash: block.Hash,
+					LogIndex:  uint64(logIndex),
+					Address:   block.AccountAddress,
+					Topics:    vmLog.Topics,
+				}
+				for _, sub := range logSubs {
+					if !sub.logFilter.Match(item) {
+						continue
+					}
+					// the logs of the unconfirmed blocks have no snapshot height
+					rpcLog := &Log{
+						BlockHash: block.Hash,
+						LogIndex:  logIndex,
+						Address:   block.AccountAddress,
+						Topics:    vmLog.Topics,
+						Data:      vmLog.Data,
+					}
+					if sub.abiContract != nil {
+						decodeLog(sub.abiContract, rpcLog)
+					}
+					es.dispatch(sub, rpcLog)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vitelabs/go-vite/chain"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpc"
+)
+
+type subscribeTestChain struct {
+	vmLogLists map[types.Hash]ledger.VmLogList
+}
+
+func (c *subscribeTestChain) GetConfirmTimes(accountBlockHash *types.Hash) (uint64, error) {
+	return 0, nil
+}
+
+func (c *subscribeTestChain) GetAccountBlockByHash(blockHash *types.Hash) (*ledger.AccountBlock, error) {
+	return nil, nil
+}
+
+func (c *subscribeTestChain) GetAccountBlockMetaByHash(hash *types.Hash) (*ledger.AccountBlockMeta, error) {
+	return nil, nil
+}
+
+func (c *subscribeTestChain) GetTokenInfoById(tokenId *types.TokenTypeId) (*types.TokenInfo, error) {
+	return nil, nil
+}
+
+func (c *subscribeTestChain) GetVmLogList(logListHash *types.Hash) (ledger.VmLogList, error) {
+	return c.vmLogLists[*logListHash], nil
+}
+
+func (c *subscribeTestChain) RegisterInsertAccountBlocksSuccess(processor chain.InsertProcessorFuncSuccess) uint64 {
+	return 0
+}
+
+func (c *subscribeTestChain) RegisterDeleteAccountBlocksSuccess(processor chain.DeleteProcessorFuncSuccess) uint64 {
+	return 0
+}
+
+func (c *subscribeTestChain) RegisterInsertSnapshotBlocksSuccess(processor chain.InsertSnapshotBlocksSuccess) uint64 {
+	return 0
+}
+
+func (c *subscribeTestChain) RegisterDeleteSnapshotBlocksSuccess(processor chain.DeleteSnapshotBlocksSuccess) uint64 {
+	return 0
+}
+
+func TestSubscribeFilters(t *testing.T) {
+	addr1, _, _ := types.CreateAddress()
+	addr2, _, _ := types.CreateAddress()
+	topic := types.DataHash([]byte("topic"))
+	logHash := types.DataHash([]byte("logs"))
+
+	c := &subscribeTestChain{
+		vmLogLists: map[types.Hash]ledger.VmLogList{
+			logHash: {
+				{Topics: []types.Hash{topic}, Data: []byte{1}},
+				{Topics: []types.Hash{types.DataHash([]byte("other"))}},
+			},
+		},
+	}
+	s := &SubscribeApi{es: newEventSystem(c)}
+
+	ctx := context.Background()
+	accountId, _ := s.NewAccountBlocksFilter(ctx, []types.Address{addr1})
+	onroadId, _ := s.NewOnroadBlocksFilter(ctx, []types.Address{addr2})
+	logsId, err := s.NewLogsFilter(ctx, LogFilter{Topics: [][]types.Hash{{topic}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotId, _ := s.NewSnapshotBlocksFilter(ctx)
+	rollbackId, err := s.NewRollbacksFilter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	sendBlock := &ledger.AccountBlock{
+		BlockType:      ledger.BlockTypeSendCall,
+		Hash:           types.DataHash([]byte("send")),
+		Height:         1,
+		AccountAddress: addr1,
+		ToAddress:      addr2,
+		LogHash:        &logHash,
+		Timestamp:      &now,
+	}
+	s.es.handleEvent(&chainEvent{insertedAccountBlocks: []*ledger.AccountBlock{sendBlock}})
+	s.es.handleEvent(&chainEvent{insertedSnapshotBlocks: []*ledger.SnapshotBlock{{Height: 2}}})
+	s.es.handleEvent(&chainEvent{deletedAccountBlocks: map[types.Address][]*ledger.AccountBlock{addr1: {sendBlock}}})
+
+	for _, id := range []rpc.ID{accountId, onroadId, snapshotId} {
+		changes, err := s.GetFilterChanges(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 1 {
+			t.Fatalf("filter %s has %d changes", id, len(changes))
+		}
+	}
+
+	changes, err := s.GetFilterChanges(logsId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].(*Log).LogIndex != 0 || changes[0].(*Log).Address != addr1 {
+		t.Fatalf("wrong logs %v", changes)
+	}
+	if changes, _ := s.GetFilterChanges(logsId); len(changes) != 0 {
+		t.Fatal("the changes should be taken only once")
+	}
+
+	changes, err = s.GetFilterChanges(rollbackId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].(*RollbackMessage).AccountBlocks[addr1][0].Hash != sendBlock.Hash {
+		t.Fatalf("wrong rollbacks %v", changes)
+	}
+
+	if !s.UninstallFilter(snapshotId) || s.UninstallFilter(snapshotId) {
+		t.Fatal("the filter should be uninstalled once")
+	}
+
+	s.es.uninstallExpired(time.Now().Add(2 * filterTimeout))
+	if _, err := s.GetFilterChanges(accountId); err == nil {
+		t.Fatal("the filter should be expired")
+	}
+
+	for i := 0; i < maxFiltersPerClient; i++ {
+		if _, err := s.NewSnapshotBlocksFilter(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.NewSnapshotBlocksFilter(ctx); err != ErrTooManyFilters {
+		t.Fatalf("the filters of a client should be limited, error is %v", err)
+	}
+	s.es.uninstallExpired(time.Now().Add(2 * filterTimeout))
+	if _, err := s.NewSnapshotBlocksFilter(ctx); err != nil {
+		t.Fatalf("the expired filters should be released, error is %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/vitelabs/go-vite/crypto/ed25519"
 	"github.com/vitelabs/go-vite/generator"
 	"github.com/vitelabs/go-vite/ledger"
+	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/verifier"
 	"github.com/vitelabs/go-vite/vite"
 	"github.com/vitelabs/go-vite/vm"
@@ -29,6 +30,15 @@ func NewTxApi(vite *vite.Vite) *Tx {
 	}
 }
 
+// MethodDocs annotates the transaction methods for rpc_discover.
+func (t Tx) MethodDocs() map[string]rpc.MethodDoc {
+	return map[string]rpc.MethodDoc{
+		"sendRawTx":            {Summary: "verifies the signed account block and adds it to the pool", Params: []string{"block"}},
+		"sendTxWithPrivateKey": {Summary: "creates, signs and sends the account block by the private key", Params: []string{"param"}},
+		"calcPoWDifficulty":    {Summary: "the PoW difficulty of the transaction without enough quota", Params: []string{"param"}},
+	}
+}
+
 func (t Tx) SendRawTx(block *AccountBlock) error {
 	log.Info("SendRawTx")
 	if block == nil {
@@ -48,20 +58,33 @@ func (t Tx) SendRawTx(block *AccountBlock) error {
 	//	return ErrorNotSupportRecvAddNote
 	//}
 
+	return t.sendLedgerBlock(lb)
+}
+
+// sendLedgerBlock verifies the signed block and adds it to the pool, it's shared by the JSON-RPC and gRPC apis.
+func (t Tx) sendLedgerBlock(lb *ledger.AccountBlock) error {
+	block, err := t.verifyLedgerBlock(lb)
+	if err != nil {
+		return err
+	}
+	return t.vite.Pool().AddDirectAccountBlock(lb.AccountAddress, block)
+}
+
+// verifyLedgerBlock verifies lb for sending, the invalid block is rejected by the concerned error.
+func (t Tx) verifyLedgerBlock(lb *ledger.AccountBlock) (*vm_context.VmAccountBlock, error) {
 	v := verifier.NewAccountVerifier(t.vite.Chain(), t.vite.Consensus())
 
 	blocks, err := v.VerifyforRPC(lb)
 	if err != nil {
 		newerr, _ := TryMakeConcernedError(err)
-		return newerr
+		return nil, newerr
 	}
 
 	if len(blocks) > 0 && blocks[0] != nil {
-		return t.vite.Pool().AddDirectAccountBlock(block.AccountAddress, blocks[0])
+		return blocks[0], nil
 	} else {
-		return errors.New("generator gen an empty block")
+		return nil, errors.New("generator gen an empty block")
 	}
-	return nil
 }
 
 func (t Tx) SendTxWithPrivateKey(param SendTxWithPrivateKeyParam) (*AccountBlock, error) {
@@ -125,6 +125,21 @@ func getWithdrawTime(snapshotTime *time.Time, snapshotHeight uint64, withdrawHei
 	return snapshotTime.Unix() + int64(withdrawHeight-snapshotHeight)*secondBetweenSnapshotBlocks
 }
 
+// maxPageCount is the max count of the items in a page of the paged queries
+const maxPageCount = 1000
+
+// pageOffset returns the offset of the page index of count items, the count over maxPageCount and the offset
+// overflowing int are rejected.
+func pageOffset(index, count int) (int, error) {
+	if count > maxPageCount {
+		return 0, ErrPageCount
+	}
+	if count > 0 && index > int(^uint(0)>>1)/count {
+		return 0, ErrPageIndex
+	}
+	return index * count, nil
+}
+
 func getRange(index, count, listLen int) (int, int) {
 	start := index * count
 	if start >= listLen {
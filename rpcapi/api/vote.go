@@ -1,6 +1,8 @@
 package api
 
 import (
+	"math/big"
+
 	"github.com/vitelabs/go-vite/chain"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
@@ -44,14 +46,22 @@ type VoteInfo struct {
 	Balance    string `json:"balance"`
 }
 
-func (v *VoteApi) GetVoteInfo(gid types.Gid, addr types.Address) (*VoteInfo, error) {
-	vmContext, err := vm_context.NewVmContext(v.chain, nil, nil, nil)
+// GetVoteInfo returns the latest vote of addr, or the vote at the snapshot block of snapshot, a snapshot height or
+// hash, if it's given.
+func (v *VoteApi) GetVoteInfo(gid types.Gid, addr types.Address, snapshot *string) (*VoteInfo, error) {
+	snapshotBlock, err := getStateSnapshotBlock(v.chain, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	vmContext, err := vm_context.NewVmContext(v.chain, &snapshotBlock.Hash, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	if voteInfo := abi.GetVote(vmContext, gid, addr); voteInfo != nil {
-		balance, err := v.chain.GetAccountBalanceByTokenId(&addr, &ledger.ViteTokenId)
-		if err != nil {
+		var balance *big.Int
+		if snapshot != nil {
+			balance = vmContext.GetBalance(&addr, &ledger.ViteTokenId)
+		} else if balance, err = v.chain.GetAccountBalanceByTokenId(&addr, &ledger.ViteTokenId); err != nil {
 			return nil, err
 		}
 		if abi.IsActiveRegistration(vmContext, voteInfo.NodeName, gid) {
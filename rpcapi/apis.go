@@ -4,6 +4,8 @@ import (
 	"github.com/vitelabs/go-vite/rpc"
 	"github.com/vitelabs/go-vite/rpcapi/api"
 	"github.com/vitelabs/go-vite/vite"
+	"github.com/vitelabs/go-vite/vitepb"
+	"google.golang.org/grpc"
 )
 
 func Init(dir, lvl string, testApi_prikey, testApi_tti string, netId uint) {
@@ -30,6 +32,13 @@ func GetApi(vite *vite.Vite, apiModule string) rpc.API {
 			Service:   api.NewPrivateOnroadApi(vite),
 			Public:    false,
 		}
+	case "private_debug":
+		return rpc.API{
+			Namespace: "debug",
+			Version:   "1.0",
+			Service:   api.NewPrivateDebugApi(vite),
+			Public:    false,
+		}
 		// public  WS HTTP IPC
 	case "pow":
 		return rpc.API{
@@ -109,6 +118,13 @@ func GetApi(vite *vite.Vite, apiModule string) rpc.API {
 			Service:   api.NewTxApi(vite),
 			Public:    true,
 		}
+	case "subscribe":
+		return rpc.API{
+			Namespace: "subscribe",
+			Version:   "1.0",
+			Service:   api.NewSubscribeApi(vite),
+			Public:    true,
+		}
 		// test
 	case "testapi":
 		return rpc.API{
@@ -152,9 +168,77 @@ func GetApis(vite *vite.Vite, apiModule ...string) []rpc.API {
 }
 
 func GetPublicApis(vite *vite.Vite) []rpc.API {
-	return GetApis(vite, "ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "debug", "dashboard")
+	return GetApis(vite, "ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "subscribe", "debug", "dashboard")
 }
 
+var allApiModules = []string{"ledger", "wallet", "private_onroad", "private_debug", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "subscribe", "debug", "dashboard", "vmdebug"}
+
+// anonymousApiModules are the modules allowed without credential if the authentication is enabled, the debug,
+// wallet and private modules are left out.
+var anonymousApiModules = []string{"ledger", "public_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "pow", "tx", "subscribe"}
+
 func GetAllApis(vite *vite.Vite) []rpc.API {
-	return GetApis(vite, "ledger", "wallet", "private_onroad", "net", "contract", "pledge", "register", "vote", "mintage", "consensusGroup", "testapi", "pow", "tx", "debug", "dashboard", "vmdebug")
+	return GetApis(vite, allApiModules...)
+}
+
+// GetAllApiModules returns the names of all the modules.
+func GetAllApiModules() []string {
+	modules := make([]string, len(allApiModules))
+	copy(modules, allApiModules)
+	return modules
+}
+
+// GetAnonymousApiModules returns the names of the modules which may be allowed without credential.
+func GetAnonymousApiModules() []string {
+	modules := make([]string, len(anonymousApiModules))
+	copy(modules, anonymousApiModules)
+	return modules
+}
+
+// GetAllApiMap returns the apis of all the modules by their module names.
+func GetAllApiMap(vite *vite.Vite) map[string]rpc.API {
+	apiMap := make(map[string]rpc.API, len(allApiModules))
+	for _, m := range allApiModules {
+		apiMap[m] = GetApi(vite, m)
+	}
+	return apiMap
+}
+
+// grpcMethods maps the gRPC methods to the JSON-RPC methods serving them, the gRPC requests are authorized and rate
+// limited as the JSON-RPC ones.
+var grpcMethods = map[string]string{
+	"/vitepb.Ledger/GetAccountBlockByHash":     "ledger_getBlockByHash",
+	"/vitepb.Ledger/GetAccountBlockByHeight":   "ledger_getBlockByHeight",
+	"/vitepb.Ledger/GetAccountBlocksByAddress": "ledger_getBlocksByAccAddr",
+	"/vitepb.Ledger/GetLatestAccountBlock":     "ledger_getLatestBlock",
+	"/vitepb.Ledger/GetAccountInfo":            "ledger_getAccountByAccAddr",
+	"/vitepb.Ledger/GetSnapshotBlockByHash":    "ledger_getSnapshotBlockByHash",
+	"/vitepb.Ledger/GetSnapshotBlockByHeight":  "ledger_getSnapshotBlockByHeight",
+	"/vitepb.Ledger/GetLatestSnapshotBlock":    "ledger_getLatestSnapshotChainHash",
+	"/vitepb.Tx/SendRawTx":                     "tx_sendRawTx",
+	"/vitepb.Onroad/GetOnroadBlocksByAddress":  "onroad_getOnroadBlocksByAddress",
+	"/vitepb.Onroad/GetAccountOnroadInfo":      "onroad_getAccountOnroadInfo",
+	"/vitepb.Subscribe/NewSnapshotBlocks":      "subscribe_newSnapshotBlocks",
+	"/vitepb.Subscribe/NewAccountBlocks":       "subscribe_newAccountBlocks",
+	"/vitepb.Subscribe/NewOnroadBlocks":        "subscribe_newOnroadBlocks",
+	"/vitepb.Subscribe/NewLogs":                "subscribe_newLogs",
+	"/vitepb.Subscribe/NewRollbacks":           "subscribe_newRollbacks",
+}
+
+// GetGrpcMethods returns the JSON-RPC methods of the gRPC methods registered by RegisterGrpcApis.
+func GetGrpcMethods() map[string]string {
+	methods := make(map[string]string, len(grpcMethods))
+	for k, v := range grpcMethods {
+		methods[k] = v
+	}
+	return methods
+}
+
+// RegisterGrpcApis registers the services of vitepb/api.proto to server, they're the public ledger, tx, onroad and
+// subscribe apis.
+func RegisterGrpcApis(server *grpc.Server, vite *vite.Vite) {
+	vitepb.RegisterLedgerServer(server, api.NewGrpcLedgerApi(vite))
+	vitepb.RegisterTxServer(server, api.NewGrpcTxApi(vite))
+	vitepb.RegisterOnroadServer(server, api.NewGrpcOnroadApi(vite))
+	vitepb.RegisterSubscribeServer(server, api.NewGrpcSubscribeApi(vite))
 }
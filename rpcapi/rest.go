@@ -0,0 +1,110 @@
+package rpcapi
+
+import (
+	"strconv"
+
+	"github.com/vitelabs/go-vite/rpc"
+	"github.com/vitelabs/go-vite/rpcapi/api"
+	"github.com/vitelabs/go-vite/vite"
+)
+
+// the routes of the REST gateway, the literal routes are listed before the variable ones of the same shape
+var restRoutes = []rpc.RESTRoute{
+	// ledger
+	{Path: "/snapshot-blocks/latest/height", Method: "ledger_getSnapshotChainHeight", Params: []string{},
+		Summary: "the height of the latest snapshot block"},
+	{Path: "/snapshot-blocks/latest/hash", Method: "ledger_getLatestSnapshotChainHash", Params: []string{},
+		Summary: "the hash of the latest snapshot block"},
+	{Path: "/snapshot-blocks/hash/{hash}", Method: "ledger_getSnapshotBlockByHash", Params: []string{"hash"},
+		Summary: "the snapshot block of the hash", Immutable: alwaysImmutable},
+	{Path: "/snapshot-blocks/{height}", Method: "ledger_getSnapshotBlockByHeight", Params: []string{"height"},
+		Summary: "the snapshot block of the height"},
+	{Path: "/account-blocks/{hash}", Method: "ledger_getBlockByHash", Params: []string{"hash"},
+		Summary: "the account block of the hash", Immutable: confirmedAccountBlock},
+	{Path: "/accounts/{address}", Method: "ledger_getAccountByAccAddr", Params: []string{"address", "snapshot"},
+		Summary: "the account info at the snapshot block of the snapshot height or hash, the latest by default"},
+	{Path: "/accounts/{address}/balances/{tokenId}", Method: "ledger_getAccountBalance", Params: []string{"address", "tokenId", "snapshot"},
+		Summary: "the balance of the token at the snapshot block of the snapshot height or hash, the latest by default"},
+	{Path: "/accounts/{address}/blocks", Method: "ledger_getBlocksByAccAddr", Params: []string{"address", "index", "count"},
+		Defaults: map[string]string{"index": "0", "count": "10"},
+		Summary:  "the page of the account blocks from the latest one"},
+	{Path: "/accounts/{address}/blocks/latest", Method: "ledger_getLatestBlock", Params: []string{"address"},
+		Summary: "the latest account block"},
+	{Path: "/accounts/{address}/blocks/{height}", Method: "ledger_getBlockByHeight", Params: []string{"address", "height"},
+		Summary: "the account block of the height", Immutable: confirmedAccountBlock},
+	{Path: "/accounts/{address}/tokens", Method: "mintage_getTokenInfoListByOwner", Params: []string{"address"},
+		Summary: "the tokens owned by the address"},
+	{Path: "/accounts/{address}/quota", Method: "pledge_getPledgeQuota", Params: []string{"address", "snapshot"},
+		Summary: "the quota of the address at the snapshot block of the snapshot height or hash, the latest by default"},
+	{Path: "/accounts/{address}/pledges", Method: "pledge_getPledgeList", Params: []string{"address", "index", "count", "snapshot"},
+		Defaults: map[string]string{"index": "0", "count": "10"},
+		Summary:  "the page of the pledges of the address"},
+
+	// mintage
+	{Path: "/tokens", Method: "mintage_getTokenInfoList", Params: []string{"index", "count"},
+		Defaults: map[string]string{"index": "0", "count": "10"},
+		Summary:  "the page of the tokens"},
+	{Path: "/tokens/{tokenId}", Method: "mintage_getTokenInfoById", Params: []string{"tokenId"},
+		Summary: "the token info"},
+	{Path: "/tokens/{tokenId}/holders", Method: "mintage_getTokenHolders", Params: []string{"tokenId", "index", "count"},
+		Defaults: map[string]string{"index": "0", "count": "10"},
+		Summary:  "the page of the holders of the token"},
+	{Path: "/tokens/{tokenId}/holder-count", Method: "mintage_getTokenHolderCount", Params: []string{"tokenId"},
+		Summary: "the count of the holders of the token"},
+	{Path: "/tokens/{tokenId}/supply", Method: "mintage_getTokenSupply", Params: []string{"tokenId", "snapshotHeight"},
+		Defaults: map[string]string{"snapshotHeight": ""},
+		Summary:  "the total supply of the token at the snapshot height, the latest by default"},
+
+	// register
+	{Path: "/consensus-groups/{gid}/candidates", Method: "register_getCandidateList", Params: []string{"gid"},
+		Summary: "the candidates of the consensus group"},
+	{Path: "/consensus-groups/{gid}/registrations", Method: "register_getRegistrationList", Params: []string{"gid", "pledgeAddr", "snapshot"},
+		Summary: "the registrations of the pledge address in the consensus group"},
+	{Path: "/consensus-groups/{gid}/registrations/{name}", Method: "register_getRegistration", Params: []string{"name", "gid", "snapshot"},
+		Summary: "the registration of the name in the consensus group"},
+	{Path: "/consensus-groups/{gid}/registrations/{name}/pledge-address", Method: "register_getRegisterPledgeAddr", Params: []string{"name", "gid"},
+		Summary: "the pledge address of the registration"},
+
+	// consensus group, the data of the conditions and the transactions which need no body
+	{Path: "/consensus-group-conditions/register-of-pledge", Method: "consensusGroup_getConditionRegisterOfPledge", Params: []string{"amount", "tokenId", "height"},
+		Summary: "the register condition data of pledging amount of the token for height snapshot blocks"},
+	{Path: "/consensus-group-conditions/vote-of-default", Method: "consensusGroup_getConditionVoteOfDefault", Params: []string{},
+		Summary: "the default vote condition data"},
+	{Path: "/consensus-group-conditions/vote-of-keep-token", Method: "consensusGroup_getConditionVoteOfKeepToken", Params: []string{"amount", "tokenId"},
+		Summary: "the vote condition data of keeping amount of the token"},
+	{Path: "/consensus-groups/{gid}/cancel-data", Method: "consensusGroup_getCancelConsensusGroupData", Params: []string{"gid"},
+		Summary: "the data of the transaction cancelling the consensus group"},
+	{Path: "/consensus-groups/{gid}/recreate-data", Method: "consensusGroup_getReCreateConsensusGroupData", Params: []string{"gid"},
+		Summary: "the data of the transaction recreating the consensus group"},
+}
+
+func alwaysImmutable(result interface{}) bool {
+	return true
+}
+
+// the account block confirmed by this many snapshot blocks is final, the pool doesn't switch to a fork which is
+// longer than its LIMIT_HEIGHT of 150 snapshot blocks
+const finalConfirmedTimes = 150
+
+// confirmedAccountBlock reports whether the account block is confirmed by finalConfirmedTimes snapshot blocks, it's
+// never rolled back since then. The blocks confirmed by fewer snapshot blocks are revalidated by the etag.
+func confirmedAccountBlock(result interface{}) bool {
+	block, ok := result.(*api.AccountBlock)
+	if !ok || block.ConfirmedTimes == nil {
+		return false
+	}
+	confirmedTimes, err := strconv.ParseUint(*block.ConfirmedTimes, 10, 64)
+	return err == nil && confirmedTimes >= finalConfirmedTimes
+}
+
+// GetRESTApis returns the read-only apis served by the REST gateway.
+func GetRESTApis(vite *vite.Vite) []rpc.API {
+	return GetApis(vite, "ledger", "mintage", "pledge", "register", "consensusGroup")
+}
+
+// GetRESTRoutes returns the routes of the REST gateway to the methods of GetRESTApis.
+func GetRESTRoutes() []rpc.RESTRoute {
+	routes := make([]rpc.RESTRoute, len(restRoutes))
+	copy(routes, restRoutes)
+	return routes
+}
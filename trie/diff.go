@@ -0,0 +1,91 @@
+package trie
+
+import (
+	"bytes"
+)
+
+// Diff calls f with every key whose value in trie differs from the value in old, nil value means the key is absent.
+// The subtrees of the same hash are skipped, so the cost is proportional to the changes rather than the size.
+func (trie *Trie) Diff(old *Trie, f func(key, value, oldValue []byte)) {
+	var oldRoot *TrieNode
+	if old != nil {
+		oldRoot = old.Root
+	}
+	trie.diffNodes(old, trie.Root, oldRoot, nil, f)
+}
+
+func (trie *Trie) diffNodes(old *Trie, node, oldNode *TrieNode, prefix []byte, f func(key, value, oldValue []byte)) {
+	if node == nil && oldNode == nil {
+		return
+	}
+	if node != nil && oldNode != nil {
+		if *node.Hash() == *oldNode.Hash() {
+			return
+		}
+
+		switch {
+		case node.NodeType() == TRIE_FULL_NODE && oldNode.NodeType() == TRIE_FULL_NODE:
+			trie.diffNodes(old, node.child, oldNode.child, prefix, f)
+			for key, child := range node.children {
+				trie.diffNodes(old, child, oldNode.children[key], appendKey(prefix, []byte{key}), f)
+			}
+			for key, oldChild := range oldNode.children {
+				if _, ok := node.children[key]; !ok {
+					trie.diffNodes(old, nil, oldChild, appendKey(prefix, []byte{key}), f)
+				}
+			}
+			return
+		case node.NodeType() == TRIE_SHORT_NODE && oldNode.NodeType() == TRIE_SHORT_NODE && bytes.Equal(node.key, oldNode.key):
+			trie.diffNodes(old, node.child, oldNode.child, appendKey(prefix, node.key), f)
+			return
+		case node.IsLeafNode() && oldNode.IsLeafNode():
+			if value, oldValue := trie.LeafNodeValue(node), old.LeafNodeValue(oldNode); !bytes.Equal(value, oldValue) {
+				f(prefix, value, oldValue)
+			}
+			return
+		}
+	}
+
+	// the subtrees are shaped differently, compare their leaves
+	leaves := make(map[string][]byte)
+	trie.collectLeaves(node, prefix, leaves)
+	oldLeaves := make(map[string][]byte)
+	if old != nil {
+		old.collectLeaves(oldNode, prefix, oldLeaves)
+	}
+
+	for key, value := range leaves {
+		if oldValue, ok := oldLeaves[key]; !ok || !bytes.Equal(value, oldValue) {
+			f([]byte(key), value, oldValue)
+		}
+	}
+	for key, oldValue := range oldLeaves {
+		if _, ok := leaves[key]; !ok {
+			f([]byte(key), nil, oldValue)
+		}
+	}
+}
+
+func (trie *Trie) collectLeaves(node *TrieNode, prefix []byte, leaves map[string][]byte) {
+	if node == nil {
+		return
+	}
+
+	switch node.NodeType() {
+	case TRIE_FULL_NODE:
+		trie.collectLeaves(node.child, prefix, leaves)
+		for key, child := range node.children {
+			trie.collectLeaves(child, appendKey(prefix, []byte{key}), leaves)
+		}
+	case TRIE_SHORT_NODE:
+		trie.collectLeaves(node.child, appendKey(prefix, node.key), leaves)
+	default:
+		leaves[string(prefix)] = trie.LeafNodeValue(node)
+	}
+}
+
+func appendKey(prefix []byte, key []byte) []byte {
+	newKey := make([]byte, len(prefix), len(prefix)+len(key))
+	copy(newKey, prefix)
+	return append(newKey, key...)
+}
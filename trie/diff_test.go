@@ -0,0 +1,52 @@
+package trie
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old, _, close := getTrieOfNewContext()
+	defer close()
+
+	for i := 0; i < 100; i++ {
+		old.SetValue([]byte("key"+strconv.Itoa(i)), []byte("value"+strconv.Itoa(i)))
+	}
+	old.SetValue(nil, []byte("root"))
+
+	trie := old.Copy()
+	trie.SetValue([]byte("key5"), []byte("changed"))
+	trie.SetValue([]byte("key50"), nil)
+	trie.SetValue([]byte("key100"), []byte("added"))
+	trie.SetValue([]byte("ke"), []byte("split"))
+	trie.SetValue(nil, []byte("new root"))
+
+	want := map[string][2][]byte{
+		"key5":   {[]byte("changed"), []byte("value5")},
+		"key50":  {nil, []byte("value50")},
+		"key100": {[]byte("added"), nil},
+		"ke":     {[]byte("split"), nil},
+		"":       {[]byte("new root"), []byte("root")},
+	}
+	changes := make(map[string][2][]byte)
+	trie.Diff(old, func(key, value, oldValue []byte) {
+		if len(value) == 0 {
+			value = nil
+		}
+		changes[string(key)] = [2][]byte{value, oldValue}
+	})
+
+	if len(changes) != len(want) {
+		t.Fatalf("the changes are %q, want %q", changes, want)
+	}
+	for key, change := range want {
+		if !bytes.Equal(changes[key][0], change[0]) || !bytes.Equal(changes[key][1], change[1]) {
+			t.Fatalf("the change of %q is %q, want %q", key, changes[key], change)
+		}
+	}
+
+	trie.Diff(trie.Copy(), func(key, value, oldValue []byte) {
+		t.Fatalf("the same tries should have no change, got %q", key)
+	})
+}
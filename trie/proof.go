@@ -0,0 +1,141 @@
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/crypto"
+)
+
+// Prove returns the proof of key, it's the serialized nodes on the path of key from the root. If key is in the trie the
+// last node is the leaf node of key, followed by the referenced value when the leaf is a hash node. If key isn't in the
+// trie the path ends at the node where key diverges from the trie, that is the proof of non-inclusion.
+func (trie *Trie) Prove(key []byte) ([][]byte, error) {
+	var proof [][]byte
+	node := trie.Root
+	for node != nil {
+		// the nodes missing in the database are loaded as nil, the trie may be pruned
+		for _, child := range node.children {
+			if child == nil {
+				return nil, errors.New("the trie is incomplete, a child of a full node is missing")
+			}
+		}
+		if node.NodeType() == TRIE_SHORT_NODE && node.child == nil {
+			return nil, errors.New("the trie is incomplete, the child of a short node is missing")
+		}
+
+		data, err := node.DbSerialize()
+		if err != nil {
+			return nil, errors.New("DbSerialize trie node failed, error is " + err.Error())
+		}
+		proof = append(proof, data)
+
+		var next *TrieNode
+		switch node.NodeType() {
+		case TRIE_FULL_NODE:
+			if len(key) == 0 {
+				next = node.child
+			} else {
+				next = node.children[key[0]]
+				key = key[1:]
+			}
+			if next == nil {
+				return proof, nil
+			}
+		case TRIE_SHORT_NODE:
+			if !bytes.HasPrefix(key, node.key) {
+				return proof, nil
+			}
+			next = node.child
+			key = key[len(node.key):]
+		case TRIE_HASH_NODE:
+			if len(key) == 0 {
+				value, err := trie.getRefValue(node.value)
+				if err != nil {
+					return nil, err
+				}
+				proof = append(proof, value)
+			}
+			return proof, nil
+		default:
+			return proof, nil
+		}
+		node = next
+	}
+	return proof, nil
+}
+
+// VerifyProof checks proof against the trie of rootHash, and returns the value of key, nil means key isn't in the
+// trie. An error is returned if proof doesn't belong to the trie of rootHash or doesn't cover the whole path of key.
+func VerifyProof(rootHash types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	if len(proof) <= 0 {
+		return nil, errors.New("the proof is empty")
+	}
+
+	expectedHash := rootHash
+	for index := 0; index < len(proof); index++ {
+		node := &TrieNode{}
+		if err := node.DbDeserialize(proof[index]); err != nil {
+			return nil, errors.New(fmt.Sprintf("the node %d of the proof can't be deserialized, error is %s", index, err.Error()))
+		}
+		if *node.Hash() != expectedHash {
+			return nil, errors.New(fmt.Sprintf("the hash of the node %d of the proof is %s, expected %s", index, node.Hash(), expectedHash))
+		}
+		isLast := index == len(proof)-1
+
+		var next *TrieNode
+		switch node.NodeType() {
+		case TRIE_FULL_NODE:
+			if len(key) == 0 {
+				next = node.child
+			} else {
+				next = node.children[key[0]]
+				key = key[1:]
+			}
+		case TRIE_SHORT_NODE:
+			if bytes.HasPrefix(key, node.key) {
+				next = node.child
+				key = key[len(node.key):]
+			}
+		case TRIE_VALUE_NODE:
+			if !isLast {
+				return nil, errors.New("the proof has redundant nodes")
+			}
+			if len(key) != 0 {
+				return nil, nil
+			}
+			return node.value, nil
+		case TRIE_HASH_NODE:
+			if len(key) != 0 {
+				if !isLast {
+					return nil, errors.New("the proof has redundant nodes")
+				}
+				return nil, nil
+			}
+			if index != len(proof)-2 {
+				return nil, errors.New("the proof should end with the value referenced by the hash node")
+			}
+			value := proof[index+1]
+			if !bytes.Equal(crypto.Hash256(value), node.value) {
+				return nil, errors.New("the value doesn't match the hash node")
+			}
+			return value, nil
+		default:
+			return nil, errors.New(fmt.Sprintf("the node %d of the proof has unknown node type %d", index, node.NodeType()))
+		}
+
+		if next == nil {
+			if !isLast {
+				return nil, errors.New("the proof has redundant nodes")
+			}
+			return nil, nil
+		}
+		if isLast {
+			return nil, errors.New("the proof is incomplete")
+		}
+		expectedHash = *next.Hash()
+	}
+	return nil, errors.New("the proof is incomplete")
+}
@@ -0,0 +1,83 @@
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vitelabs/go-vite/chain_db/database"
+	"github.com/vitelabs/go-vite/common/types"
+)
+
+func TestProve(t *testing.T) {
+	trie, db, close := getTrieOfNewContext()
+	defer close()
+
+	kvs := map[string][]byte{
+		"":        []byte("empty key"),
+		"IamG":    []byte("short"),
+		"IamGood": []byte("a value longer than 32 bytes is referenced by a hash node"),
+		"IamGo":   []byte("prefix"),
+		"tesab":   []byte("tesab"),
+		"tesabcd": types.DataHash([]byte("tesabcd")).Bytes(),
+	}
+	for key, value := range kvs {
+		trie.SetValue([]byte(key), value)
+	}
+	rootHash := *trie.Hash()
+
+	// prove by the trie loaded from the database
+	batch := new(database.Batch)
+	callback, err := trie.Save(batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Write(batch)
+	callback()
+	trie = NewTrie(db, &rootHash, NewTrieNodePool())
+
+	for key, value := range kvs {
+		proof, err := trie.Prove([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		provedValue, err := VerifyProof(rootHash, []byte(key), proof)
+		if err != nil {
+			t.Fatalf("verify %s failed, error is %s", key, err)
+		}
+		if !bytes.Equal(provedValue, value) {
+			t.Fatalf("the proved value of %s is %s", key, provedValue)
+		}
+	}
+
+	for _, key := range []string{"I", "IamGoo", "IamGoods", "tesb", "x", "tesabcde"} {
+		proof, err := trie.Prove([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		provedValue, err := VerifyProof(rootHash, []byte(key), proof)
+		if err != nil {
+			t.Fatalf("verify missing key %s failed, error is %s", key, err)
+		}
+		if provedValue != nil {
+			t.Fatalf("missing key %s has value %s", key, provedValue)
+		}
+	}
+
+	proof, _ := trie.Prove([]byte("IamGood"))
+	if _, err := VerifyProof(types.DataHash([]byte("root")), []byte("IamGood"), proof); err == nil {
+		t.Fatal("the proof shouldn't match another root")
+	}
+	if _, err := VerifyProof(rootHash, []byte("IamGood"), proof[:len(proof)-1]); err == nil {
+		t.Fatal("the incomplete proof should fail")
+	}
+	proof[len(proof)-1] = []byte("a forged value")
+	if _, err := VerifyProof(rootHash, []byte("IamGood"), proof); err == nil {
+		t.Fatal("the forged value should fail")
+	}
+
+	// a key can't be proved missing by cutting the proof of another key
+	proof, _ = trie.Prove([]byte("tesabcd"))
+	if _, err := VerifyProof(rootHash, []byte("tesabcd"), proof[:len(proof)-1]); err == nil {
+		t.Fatal("the truncated proof should fail")
+	}
+}
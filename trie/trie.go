@@ -3,7 +3,6 @@ package trie
 import (
 	"bytes"
 	"github.com/pkg/errors"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain_db/database"
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/crypto"
@@ -11,7 +10,7 @@ import (
 )
 
 type Trie struct {
-	db        *leveldb.DB
+	db        database.Store
 	cachePool *TrieNodePool
 	log       log15.Logger
 
@@ -20,21 +19,21 @@ type Trie struct {
 	unSavedRefValueMap map[types.Hash][]byte
 }
 
-func DeleteNodes(db *leveldb.DB, hashList []types.Hash) error {
-	batch := new(leveldb.Batch)
+func DeleteNodes(db database.Store, hashList []types.Hash) error {
+	batch := new(database.Batch)
 	for _, hash := range hashList {
 		dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, hash.Bytes())
 		batch.Delete(dbKey)
 	}
-	return db.Write(batch, nil)
+	return db.Write(batch)
 }
 
-func ShallowCheck(db *leveldb.DB, rootHash *types.Hash) (bool, error) {
+func ShallowCheck(db database.Store, rootHash *types.Hash) (bool, error) {
 	dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, rootHash.Bytes())
-	return db.Has(dbKey, nil)
+	return db.Has(dbKey)
 }
 
-func NewTrie(db *leveldb.DB, rootHash *types.Hash, pool *TrieNodePool) *Trie {
+func NewTrie(db database.Store, rootHash *types.Hash, pool *TrieNodePool) *Trie {
 	trie := &Trie{
 		db:        db,
 		cachePool: pool,
@@ -52,9 +51,9 @@ func (trie *Trie) getNodeFromDb(key *types.Hash) *TrieNode {
 		return nil
 	}
 	dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, key.Bytes())
-	value, err := trie.db.Get(dbKey, nil)
+	value, err := trie.db.Get(dbKey)
 	if err != nil {
-		if err != leveldb.ErrNotFound {
+		if err != database.ErrNotFound {
 			trie.log.Error("Query trie node failed from the database, error is "+err.Error(), "method", "getNodeFromDb")
 		}
 
@@ -70,7 +69,7 @@ func (trie *Trie) getNodeFromDb(key *types.Hash) *TrieNode {
 	return trieNode
 }
 
-func (trie *Trie) saveNodeInDb(batch *leveldb.Batch, node *TrieNode) error {
+func (trie *Trie) saveNodeInDb(batch *database.Batch, node *TrieNode) error {
 	dbKey, _ := database.EncodeKey(database.DBKP_TRIE_NODE, node.Hash().Bytes())
 	data, err := node.DbSerialize()
 
@@ -98,7 +97,7 @@ func (trie *Trie) deleteUnSavedRefValueMap(node *TrieNode) {
 
 }
 
-func (trie *Trie) saveRefValueMap(batch *leveldb.Batch) {
+func (trie *Trie) saveRefValueMap(batch *database.Batch) {
 	for key, value := range trie.unSavedRefValueMap {
 		dbKey, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE, key.Bytes())
 		batch.Put(dbKey, value)
@@ -120,7 +119,7 @@ func (trie *Trie) getRefValue(key []byte) ([]byte, error) {
 	}
 
 	dbKey, _ := database.EncodeKey(database.DBKP_TRIE_REF_VALUE, key)
-	return trie.db.Get(dbKey, nil)
+	return trie.db.Get(dbKey)
 }
 
 func (trie *Trie) getNode(key *types.Hash) *TrieNode {
@@ -193,7 +192,7 @@ func (trie *Trie) Copy() *Trie {
 	return newTrie
 }
 
-func (trie *Trie) Save(batch *leveldb.Batch) (successCallback func(), returnErr error) {
+func (trie *Trie) Save(batch *database.Batch) (successCallback func(), returnErr error) {
 	err := trie.traverseSave(batch, trie.Root)
 	if err != nil {
 		return nil, err
@@ -206,7 +205,7 @@ func (trie *Trie) Save(batch *leveldb.Batch) (successCallback func(), returnErr
 	}, nil
 }
 
-func (trie *Trie) traverseSave(batch *leveldb.Batch, node *TrieNode) error {
+func (trie *Trie) traverseSave(batch *database.Batch, node *TrieNode) error {
 	if node == nil {
 		return nil
 	}
@@ -3,24 +3,16 @@ package trie
 import (
 	"bytes"
 	"fmt"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/vitelabs/go-vite/chain_db/database"
-	"github.com/vitelabs/go-vite/common"
 	"github.com/vitelabs/go-vite/common/types"
-	"os"
-	"path/filepath"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 )
 
-func getTrieOfNewContext() (*Trie, *leveldb.DB, func()) {
-	trieDbFile := filepath.Join(common.GoViteTestDataDir(), "trie")
-	os.RemoveAll(trieDbFile)
-
-	db, _ := database.NewLevelDb(trieDbFile)
-
+func getTrieOfNewContext() (*Trie, database.Store, func()) {
+	db := database.NewMemStore()
 	pool := NewTrieNodePool()
 
 	return NewTrie(db, nil, pool), db, func() { db.Close() }
@@ -83,7 +75,7 @@ func TestSetGetCase2(t *testing.T) {
 }
 
 func TestNewTrie(t *testing.T) {
-	db, _ := database.NewLevelDb(filepath.Join(common.GoViteTestDataDir(), "trie"))
+	db := database.NewMemStore()
 	defer db.Close()
 
 	pool := NewTrieNodePool()
@@ -383,7 +375,7 @@ func TestNewTrie(t *testing.T) {
 }
 
 func TestTrieHash(t *testing.T) {
-	db, _ := database.NewLevelDb(filepath.Join(common.GoViteTestDataDir(), "trie"))
+	db := database.NewMemStore()
 	defer db.Close()
 
 	pool := NewTrieNodePool()
@@ -448,10 +440,8 @@ func TestTrieHash(t *testing.T) {
 }
 
 func TestTrieSaveAndLoadCase1(t *testing.T) {
-	trieDbFile := filepath.Join(common.GoViteTestDataDir(), "trie")
-	os.RemoveAll(trieDbFile)
 
-	db, _ := database.NewLevelDb(trieDbFile)
+	db := database.NewMemStore()
 	defer db.Close()
 
 	pool := NewTrieNodePool()
@@ -485,9 +475,9 @@ func TestTrieSaveAndLoadCase1(t *testing.T) {
 	}
 
 	// save db
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 	callback, _ := trie.Save(batch)
-	db.Write(batch, nil)
+	db.Write(batch)
 	callback()
 
 	rootHash := trie.Hash()
@@ -510,10 +500,8 @@ func TestTrieSaveAndLoadCase1(t *testing.T) {
 }
 
 func TestTrieSaveAndLoad(t *testing.T) {
-	trieDbFile := filepath.Join(common.GoViteTestDataDir(), "trie")
-	os.RemoveAll(trieDbFile)
 
-	db, _ := database.NewLevelDb(filepath.Join(common.GoViteTestDataDir(), "trie"))
+	db := database.NewMemStore()
 	defer db.Close()
 
 	pool := NewTrieNodePool()
@@ -533,9 +521,9 @@ func TestTrieSaveAndLoad(t *testing.T) {
 	fmt.Println(trie.Hash())
 	fmt.Println()
 
-	batch := new(leveldb.Batch)
+	batch := new(database.Batch)
 	callback, _ := trie.Save(batch)
-	db.Write(batch, nil)
+	db.Write(batch)
 	callback()
 
 	rootHash := trie.Hash()
@@ -588,9 +576,9 @@ func TestTrieSaveAndLoad(t *testing.T) {
 	fmt.Println(newTri2.Hash())
 	fmt.Println()
 
-	batch2 := new(leveldb.Batch)
+	batch2 := new(database.Batch)
 	callback2, _ := newTri2.Save(batch2)
-	if err := db.Write(batch2, nil); err != nil {
+	if err := db.Write(batch2); err != nil {
 		t.Fatal(err)
 	}
 	callback2()
@@ -619,7 +607,7 @@ func TestTrieSaveAndLoad(t *testing.T) {
 }
 
 func TestTrieConcurrence(t *testing.T) {
-	db, _ := database.NewLevelDb(filepath.Join(common.GoViteTestDataDir(), "trie"))
+	db := database.NewMemStore()
 	defer db.Close()
 
 	pool := NewTrieNodePool()
@@ -644,9 +632,9 @@ func TestTrieConcurrence(t *testing.T) {
 		sw.Add(1)
 		go func() {
 			defer sw.Done()
-			batch := new(leveldb.Batch)
+			batch := new(database.Batch)
 			trie.Save(batch)
-			db.Write(batch, nil)
+			db.Write(batch)
 		}()
 	}
 	sw.Wait()
@@ -5,14 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	net2 "net"
+	"os"
 	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
 
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
@@ -43,6 +47,7 @@ const (
 	downloadReceiveErr
 	downloadParseErr
 	downloadOtherErr
+	downloadVerifyErr
 )
 
 type downloadError struct {
@@ -51,7 +56,7 @@ type downloadError struct {
 }
 
 func (e downloadError) Fatal() bool {
-	return e.code == downloadReceiveErr
+	return e.code == downloadReceiveErr || e.code == downloadVerifyErr
 }
 
 func (e downloadError) Error() string {
@@ -59,7 +64,7 @@ func (e downloadError) Error() string {
 }
 
 type fileParser interface {
-	BlockParser(reader io.Reader, blockNum uint64, processFunc func(block ledger.Block, err error))
+	BlockParser(reader io.Reader, blockNum uint64, processFunc func(block ledger.Block, err error)) error
 }
 
 type FileConnStatus struct {
@@ -106,6 +111,22 @@ func (f *fileConn) download(file File, rec blockReceiver) (outerr *downloadError
 
 	f.log.Info(fmt.Sprintf("begin download <file %s> from %s", file.Filename, f.RemoteAddr()))
 
+	// blocks are decoded by BlockParser according to the file header, reject codec we can't decode before request
+	if codec := compress.Codec(file.Codec); !codec.IsValid() {
+		return &downloadError{
+			code: downloadParseErr,
+			err:  fmt.Sprintf("unsupported codec %s of <file %s>", codec, file.Filename),
+		}
+	}
+
+	// the whole file is written to a temporary file for verification, the size advertised by the peer is bounded
+	if file.FileSize <= 0 || file.FileSize > compress.MaxFileSize {
+		return &downloadError{
+			code: downloadVerifyErr,
+			err:  fmt.Sprintf("<file %s> size %d is out of range (0, %d]", file.Filename, file.FileSize, compress.MaxFileSize),
+		}
+	}
+
 	getFiles := &message.GetFiles{
 		Names: []string{file.Filename},
 	}
@@ -133,7 +154,58 @@ func (f *fileConn) download(file File, rec blockReceiver) (outerr *downloadError
 	start := time.Now()
 	// todo fileTimeout can be a flexible value, like calc through fileSize and download speed
 	f.Conn.SetReadDeadline(time.Now().Add(fileTimeout))
-	f.parser.BlockParser(f.Conn, file.BlockNumbers, func(block ledger.Block, err error) {
+
+	var reader io.Reader = f.Conn
+	// peers which advertise file hash, the whole file is streamed to a temporary file and verified
+	// before any block is fed to receiver, then the blocks are parsed from the temporary file
+	if !file.Hash.IsZero() {
+		tmpFile, err := ioutil.TempFile("", "subgraph_download_")
+		if err != nil {
+			f.log.Error(fmt.Sprintf("create temporary file of <file %s> error: %v", file.Filename, err))
+			return &downloadError{
+				code: downloadIncompleteErr,
+				err:  err.Error(),
+			}
+		}
+		defer func() {
+			tmpFile.Close()
+			os.Remove(tmpFile.Name())
+		}()
+
+		var hash types.Hash
+		var size int64
+		hash, err = compress.FileHash(io.TeeReader(io.LimitReader(f.Conn, file.FileSize), tmpFile))
+		if err == nil {
+			size, err = tmpFile.Seek(0, io.SeekCurrent)
+		}
+		if err == nil && size != file.FileSize {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			f.log.Error(fmt.Sprintf("read <file %s> from %s error: %v", file.Filename, f.RemoteAddr(), err))
+			return &downloadError{
+				code: downloadIncompleteErr,
+				err:  err.Error(),
+			}
+		}
+
+		if hash != file.Hash {
+			return &downloadError{
+				code: downloadVerifyErr,
+				err:  fmt.Sprintf("<file %s> hash is %s, expected %s", file.Filename, hash, file.Hash),
+			}
+		}
+
+		if _, err = tmpFile.Seek(0, io.SeekStart); err != nil {
+			return &downloadError{
+				code: downloadIncompleteErr,
+				err:  err.Error(),
+			}
+		}
+		reader = tmpFile
+	}
+
+	parseErr := f.parser.BlockParser(reader, file.BlockNumbers, func(block ledger.Block, err error) {
 		// Fatal error, then close the connection to interrupt the stream
 		if outerr != nil && outerr.Fatal() {
 			f.log.Error(fmt.Sprintf("download <file %s> from %s error: %v, close connection", file.Filename, f.RemoteAddr(), outerr))
@@ -164,6 +236,13 @@ func (f *fileConn) download(file File, rec blockReceiver) (outerr *downloadError
 		}
 	})
 
+	if parseErr != nil && outerr == nil {
+		outerr = &downloadError{
+			code: downloadIncompleteErr,
+			err:  fmt.Sprintf("parse <file %s> error: %v", file.Filename, parseErr),
+		}
+	}
+
 	sTotal := file.EndHeight - file.StartHeight + 1
 	aTotal := file.BlockNumbers - sTotal
 
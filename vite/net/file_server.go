@@ -178,6 +178,7 @@ func (s *fileServer) handleConn(conn net2.Conn) {
 			}
 
 			_, err = io.Copy(conn, reader)
+			reader.Close()
 
 			if err != nil {
 				s.log.Error(fmt.Sprintf("send file<%s> to %s error: %v", name, conn.RemoteAddr(), err))
@@ -11,6 +11,7 @@ type HandShake struct {
 	Port    uint16
 	Current types.Hash
 	Genesis types.Hash
+	Codecs  uint32 // bit set of the file codecs the node decodes, zero means only legacy files
 }
 
 func (h *HandShake) Serialize() ([]byte, error) {
@@ -20,6 +21,7 @@ func (h *HandShake) Serialize() ([]byte, error) {
 	pb.Port = uint32(h.Port)
 	pb.Current = h.Current[:]
 	pb.Genesis = h.Genesis[:]
+	pb.Codecs = h.Codecs
 
 	return proto.Marshal(pb)
 }
@@ -36,6 +38,7 @@ func (h *HandShake) Deserialize(data []byte) error {
 	h.Port = uint16(pb.Port)
 	copy(h.Current[:], pb.Current)
 	copy(h.Genesis[:], pb.Genesis)
+	h.Codecs = pb.Codecs
 
 	return nil
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/vitelabs/go-vite/vite/net/message"
 
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/p2p"
 )
@@ -103,6 +104,10 @@ func (mp *MockPeer) Head() types.Hash {
 	panic("implement me")
 }
 
+func (mp *MockPeer) Codecs() uint32 {
+	return compress.SupportedCodecs
+}
+
 func (mp *MockPeer) Disconnect(reason p2p.DiscReason) {
 	panic("implement me")
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/vitelabs/go-vite/common"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/log15"
 	"github.com/vitelabs/go-vite/monitor"
@@ -187,6 +188,7 @@ func (n *net) handlePeer(p *peer) (err error) {
 		Port:    port,
 		Current: current.Hash,
 		Genesis: genesis.Hash,
+		Codecs:  compress.SupportedCodecs,
 	})
 
 	if err != nil {
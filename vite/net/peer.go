@@ -34,6 +34,7 @@ type Peer interface {
 	ID() string
 	Height() uint64
 	Head() types.Hash
+	Codecs() uint32
 	Disconnect(reason p2p.DiscReason)
 }
 
@@ -44,6 +45,7 @@ type peer struct {
 	head        types.Hash // hash of the top snapshotblock in snapshotchain
 	height      uint64     // height of the snapshotchain
 	filePort    uint16     // fileServer port, for request file
+	codecs      uint32     // file codecs the peer decodes
 	CmdSet      p2p.CmdSet // which cmdSet it belongs
 	knownBlocks blockFilter
 	errChan     chan error
@@ -64,6 +66,10 @@ func (p *peer) ID() string {
 	return p.id
 }
 
+func (p *peer) Codecs() uint32 {
+	return p.codecs
+}
+
 func newPeer(p *p2p.Peer, mrw *p2p.ProtoFrame, cmdSet p2p.CmdSet) *peer {
 	return &peer{
 		Peer:        p,
@@ -109,6 +115,7 @@ func (p *peer) Handshake(our *message.HandShake) error {
 	}
 
 	p.SetHead(their.Current, their.Height)
+	p.codecs = their.Codecs
 	p.filePort = their.Port
 	if p.filePort == 0 {
 		p.filePort = DefaultPort
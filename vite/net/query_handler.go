@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/vitelabs/go-vite/common"
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/monitor"
 	"github.com/vitelabs/go-vite/p2p"
@@ -298,6 +299,8 @@ func (s *getSubLedgerHandler) Handle(msg *p2p.Msg, sender Peer) (err error) {
 		return sender.Send(ExceptionCode, msg.Id, message.Missing)
 	}
 
+	files, chunks = decodableFiles(files, chunks, sender.Codecs())
+
 	if len(files) == 0 {
 		fileList := &message.FileList{
 			Chunks: chunks,
@@ -336,6 +339,23 @@ func (s *getSubLedgerHandler) Handle(msg *p2p.Msg, sender Peer) (err error) {
 	return
 }
 
+// decodableFiles keeps the files the peer decodes, the blocks from the first file it can't decode are sent by chunks.
+func decodableFiles(files []*ledger.CompressedFileMeta, chunks [][2]uint64, codecs uint32) ([]*ledger.CompressedFileMeta, [][2]uint64) {
+	for i, file := range files {
+		if compress.Codec(file.Codec).SupportedBy(codecs) {
+			continue
+		}
+
+		rest := [2]uint64{file.StartHeight, files[len(files)-1].EndHeight}
+		if len(chunks) > 0 && chunks[0][0] == rest[1]+1 {
+			rest[1] = chunks[0][1]
+			chunks = chunks[1:]
+		}
+		return files[:i], append([][2]uint64{rest}, chunks...)
+	}
+	return files, chunks
+}
+
 func splitFiles(fs []*ledger.CompressedFileMeta, batch int) (fss [][]*ledger.CompressedFileMeta) {
 	total := len(fs)
 	i := 0
@@ -12,6 +12,7 @@ import (
 	"github.com/vitelabs/go-vite/p2p"
 
 	"github.com/vitelabs/go-vite/common/types"
+	"github.com/vitelabs/go-vite/compress"
 	"github.com/vitelabs/go-vite/ledger"
 )
 
@@ -170,6 +171,29 @@ func (c *chain_getSubLedger) GetSubLedgerByHash(origin *types.Hash, count uint64
 	return
 }
 
+func Test_DecodableFiles(t *testing.T) {
+	files := []*ledger.CompressedFileMeta{
+		{StartHeight: 1, EndHeight: 3600},
+		{StartHeight: 3601, EndHeight: 7200, Codec: uint8(compress.CodecSnappy)},
+		{StartHeight: 7201, EndHeight: 10800},
+	}
+	chunks := [][2]uint64{{10801, 11000}}
+
+	fs, cs := decodableFiles(files, chunks, compress.SupportedCodecs)
+	if len(fs) != 3 || len(cs) != 1 {
+		t.Fatalf("all files should be kept, but get %d files and %d chunks", len(fs), len(cs))
+	}
+
+	// the peer before the codec gets the legacy file and the rest by chunk
+	fs, cs = decodableFiles(files, chunks, 0)
+	if len(fs) != 1 || fs[0].EndHeight != 3600 {
+		t.Fatalf("only the first file should be kept, but get %d files", len(fs))
+	}
+	if len(cs) != 1 || cs[0] != [2]uint64{3601, 11000} {
+		t.Fatalf("the chunks should be [3601, 11000], but get %v", cs)
+	}
+}
+
 func Test_getSubLedgerHandler(t *testing.T) {
 	var count = uint64(10000000)
 	var from = uint64(1)
@@ -0,0 +1,1537 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: vitepb/api.proto
+
+package vitepb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type EmptyRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EmptyRequest) Reset()         { *m = EmptyRequest{} }
+func (m *EmptyRequest) String() string { return proto.CompactTextString(m) }
+func (*EmptyRequest) ProtoMessage()    {}
+func (*EmptyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{0}
+}
+func (m *EmptyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EmptyRequest.Unmarshal(m, b)
+}
+func (m *EmptyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EmptyRequest.Marshal(b, m, deterministic)
+}
+func (dst *EmptyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EmptyRequest.Merge(dst, src)
+}
+func (m *EmptyRequest) XXX_Size() int {
+	return xxx_messageInfo_EmptyRequest.Size(m)
+}
+func (m *EmptyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EmptyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EmptyRequest proto.InternalMessageInfo
+
+type HashRequest struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HashRequest) Reset()         { *m = HashRequest{} }
+func (m *HashRequest) String() string { return proto.CompactTextString(m) }
+func (*HashRequest) ProtoMessage()    {}
+func (*HashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{1}
+}
+func (m *HashRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HashRequest.Unmarshal(m, b)
+}
+func (m *HashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HashRequest.Marshal(b, m, deterministic)
+}
+func (dst *HashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HashRequest.Merge(dst, src)
+}
+func (m *HashRequest) XXX_Size() int {
+	return xxx_messageInfo_HashRequest.Size(m)
+}
+func (m *HashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HashRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HashRequest proto.InternalMessageInfo
+
+func (m *HashRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type AddressRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddressRequest) Reset()         { *m = AddressRequest{} }
+func (m *AddressRequest) String() string { return proto.CompactTextString(m) }
+func (*AddressRequest) ProtoMessage()    {}
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{2}
+}
+func (m *AddressRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddressRequest.Unmarshal(m, b)
+}
+func (m *AddressRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddressRequest.Marshal(b, m, deterministic)
+}
+func (dst *AddressRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddressRequest.Merge(dst, src)
+}
+func (m *AddressRequest) XXX_Size() int {
+	return xxx_messageInfo_AddressRequest.Size(m)
+}
+func (m *AddressRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddressRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddressRequest proto.InternalMessageInfo
+
+func (m *AddressRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+type AccountBlocksRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Count                uint32   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountBlocksRequest) Reset()         { *m = AccountBlocksRequest{} }
+func (m *AccountBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*AccountBlocksRequest) ProtoMessage()    {}
+func (*AccountBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{3}
+}
+func (m *AccountBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountBlocksRequest.Unmarshal(m, b)
+}
+func (m *AccountBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountBlocksRequest.Marshal(b, m, deterministic)
+}
+func (dst *AccountBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountBlocksRequest.Merge(dst, src)
+}
+func (m *AccountBlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_AccountBlocksRequest.Size(m)
+}
+func (m *AccountBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountBlocksRequest proto.InternalMessageInfo
+
+func (m *AccountBlocksRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *AccountBlocksRequest) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *AccountBlocksRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type AccountBlockHeightRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Height               uint64   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountBlockHeightRequest) Reset()         { *m = AccountBlockHeightRequest{} }
+func (m *AccountBlockHeightRequest) String() string { return proto.CompactTextString(m) }
+func (*AccountBlockHeightRequest) ProtoMessage()    {}
+func (*AccountBlockHeightRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{4}
+}
+func (m *AccountBlockHeightRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountBlockHeightRequest.Unmarshal(m, b)
+}
+func (m *AccountBlockHeightRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountBlockHeightRequest.Marshal(b, m, deterministic)
+}
+func (dst *AccountBlockHeightRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountBlockHeightRequest.Merge(dst, src)
+}
+func (m *AccountBlockHeightRequest) XXX_Size() int {
+	return xxx_messageInfo_AccountBlockHeightRequest.Size(m)
+}
+func (m *AccountBlockHeightRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountBlockHeightRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountBlockHeightRequest proto.InternalMessageInfo
+
+func (m *AccountBlockHeightRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *AccountBlockHeightRequest) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type SnapshotBlockHeightRequest struct {
+	Height               uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotBlockHeightRequest) Reset()         { *m = SnapshotBlockHeightRequest{} }
+func (m *SnapshotBlockHeightRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotBlockHeightRequest) ProtoMessage()    {}
+func (*SnapshotBlockHeightRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{5}
+}
+func (m *SnapshotBlockHeightRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotBlockHeightRequest.Unmarshal(m, b)
+}
+func (m *SnapshotBlockHeightRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotBlockHeightRequest.Marshal(b, m, deterministic)
+}
+func (dst *SnapshotBlockHeightRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotBlockHeightRequest.Merge(dst, src)
+}
+func (m *SnapshotBlockHeightRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotBlockHeightRequest.Size(m)
+}
+func (m *SnapshotBlockHeightRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotBlockHeightRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotBlockHeightRequest proto.InternalMessageInfo
+
+func (m *SnapshotBlockHeightRequest) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type TokenBalance struct {
+	TokenId []byte `protobuf:"bytes,1,opt,name=tokenId,proto3" json:"tokenId,omitempty"`
+	Amount  []byte `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// the count of the onroad blocks of the token, it's zero in the account info of the ledger
+	Number               uint64   `protobuf:"varint,3,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenBalance) Reset()         { *m = TokenBalance{} }
+func (m *TokenBalance) String() string { return proto.CompactTextString(m) }
+func (*TokenBalance) ProtoMessage()    {}
+func (*TokenBalance) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{6}
+}
+func (m *TokenBalance) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenBalance.Unmarshal(m, b)
+}
+func (m *TokenBalance) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenBalance.Marshal(b, m, deterministic)
+}
+func (dst *TokenBalance) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenBalance.Merge(dst, src)
+}
+func (m *TokenBalance) XXX_Size() int {
+	return xxx_messageInfo_TokenBalance.Size(m)
+}
+func (m *TokenBalance) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenBalance.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenBalance proto.InternalMessageInfo
+
+func (m *TokenBalance) GetTokenId() []byte {
+	if m != nil {
+		return m.TokenId
+	}
+	return nil
+}
+
+func (m *TokenBalance) GetAmount() []byte {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *TokenBalance) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type AccountInfo struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// the height of the account chain, or the count of the onroad blocks
+	TotalNumber          uint64          `protobuf:"varint,2,opt,name=totalNumber,proto3" json:"totalNumber,omitempty"`
+	Balances             []*TokenBalance `protobuf:"bytes,3,rep,name=balances,proto3" json:"balances,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *AccountInfo) Reset()         { *m = AccountInfo{} }
+func (m *AccountInfo) String() string { return proto.CompactTextString(m) }
+func (*AccountInfo) ProtoMessage()    {}
+func (*AccountInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{7}
+}
+func (m *AccountInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountInfo.Unmarshal(m, b)
+}
+func (m *AccountInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountInfo.Marshal(b, m, deterministic)
+}
+func (dst *AccountInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountInfo.Merge(dst, src)
+}
+func (m *AccountInfo) XXX_Size() int {
+	return xxx_messageInfo_AccountInfo.Size(m)
+}
+func (m *AccountInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountInfo proto.InternalMessageInfo
+
+func (m *AccountInfo) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *AccountInfo) GetTotalNumber() uint64 {
+	if m != nil {
+		return m.TotalNumber
+	}
+	return 0
+}
+
+func (m *AccountInfo) GetBalances() []*TokenBalance {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+type SendRawTxResponse struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendRawTxResponse) Reset()         { *m = SendRawTxResponse{} }
+func (m *SendRawTxResponse) String() string { return proto.CompactTextString(m) }
+func (*SendRawTxResponse) ProtoMessage()    {}
+func (*SendRawTxResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{8}
+}
+func (m *SendRawTxResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendRawTxResponse.Unmarshal(m, b)
+}
+func (m *SendRawTxResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendRawTxResponse.Marshal(b, m, deterministic)
+}
+func (dst *SendRawTxResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendRawTxResponse.Merge(dst, src)
+}
+func (m *SendRawTxResponse) XXX_Size() int {
+	return xxx_messageInfo_SendRawTxResponse.Size(m)
+}
+func (m *SendRawTxResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendRawTxResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendRawTxResponse proto.InternalMessageInfo
+
+func (m *SendRawTxResponse) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type LogTopics struct {
+	// empty means any topic
+	Topics               [][]byte `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogTopics) Reset()         { *m = LogTopics{} }
+func (m *LogTopics) String() string { return proto.CompactTextString(m) }
+func (*LogTopics) ProtoMessage()    {}
+func (*LogTopics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{9}
+}
+func (m *LogTopics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogTopics.Unmarshal(m, b)
+}
+func (m *LogTopics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogTopics.Marshal(b, m, deterministic)
+}
+func (dst *LogTopics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogTopics.Merge(dst, src)
+}
+func (m *LogTopics) XXX_Size() int {
+	return xxx_messageInfo_LogTopics.Size(m)
+}
+func (m *LogTopics) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogTopics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogTopics proto.InternalMessageInfo
+
+func (m *LogTopics) GetTopics() [][]byte {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+type LogFilter struct {
+	// empty means any address
+	Addresses [][]byte `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	// the topics by position
+	Topics               []*LogTopics `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *LogFilter) Reset()         { *m = LogFilter{} }
+func (m *LogFilter) String() string { return proto.CompactTextString(m) }
+func (*LogFilter) ProtoMessage()    {}
+func (*LogFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{10}
+}
+func (m *LogFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogFilter.Unmarshal(m, b)
+}
+func (m *LogFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogFilter.Marshal(b, m, deterministic)
+}
+func (dst *LogFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogFilter.Merge(dst, src)
+}
+func (m *LogFilter) XXX_Size() int {
+	return xxx_messageInfo_LogFilter.Size(m)
+}
+func (m *LogFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogFilter proto.InternalMessageInfo
+
+func (m *LogFilter) GetAddresses() [][]byte {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *LogFilter) GetTopics() []*LogTopics {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+type Log struct {
+	BlockHash            []byte   `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	LogIndex             uint64   `protobuf:"varint,2,opt,name=logIndex,proto3" json:"logIndex,omitempty"`
+	Address              []byte   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Topics               [][]byte `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"`
+	Data                 []byte   `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Log) Reset()         { *m = Log{} }
+func (m *Log) String() string { return proto.CompactTextString(m) }
+func (*Log) ProtoMessage()    {}
+func (*Log) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{11}
+}
+func (m *Log) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Log.Unmarshal(m, b)
+}
+func (m *Log) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Log.Marshal(b, m, deterministic)
+}
+func (dst *Log) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Log.Merge(dst, src)
+}
+func (m *Log) XXX_Size() int {
+	return xxx_messageInfo_Log.Size(m)
+}
+func (m *Log) XXX_DiscardUnknown() {
+	xxx_messageInfo_Log.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Log proto.InternalMessageInfo
+
+func (m *Log) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *Log) GetLogIndex() uint64 {
+	if m != nil {
+		return m.LogIndex
+	}
+	return 0
+}
+
+func (m *Log) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Log) GetTopics() [][]byte {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *Log) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type AccountRollback struct {
+	Address              []byte        `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Blocks               []*HashHeight `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *AccountRollback) Reset()         { *m = AccountRollback{} }
+func (m *AccountRollback) String() string { return proto.CompactTextString(m) }
+func (*AccountRollback) ProtoMessage()    {}
+func (*AccountRollback) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{12}
+}
+func (m *AccountRollback) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountRollback.Unmarshal(m, b)
+}
+func (m *AccountRollback) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountRollback.Marshal(b, m, deterministic)
+}
+func (dst *AccountRollback) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountRollback.Merge(dst, src)
+}
+func (m *AccountRollback) XXX_Size() int {
+	return xxx_messageInfo_AccountRollback.Size(m)
+}
+func (m *AccountRollback) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountRollback.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountRollback proto.InternalMessageInfo
+
+func (m *AccountRollback) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *AccountRollback) GetBlocks() []*HashHeight {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+type Rollback struct {
+	SnapshotBlocks       []*HashHeight      `protobuf:"bytes,1,rep,name=snapshotBlocks,proto3" json:"snapshotBlocks,omitempty"`
+	AccountBlocks        []*AccountRollback `protobuf:"bytes,2,rep,name=accountBlocks,proto3" json:"accountBlocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *Rollback) Reset()         { *m = Rollback{} }
+func (m *Rollback) String() string { return proto.CompactTextString(m) }
+func (*Rollback) ProtoMessage()    {}
+func (*Rollback) Descriptor() ([]byte, []int) {
+	return fileDescriptor_api_2b96be3679f5df51, []int{13}
+}
+func (m *Rollback) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Rollback.Unmarshal(m, b)
+}
+func (m *Rollback) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Rollback.Marshal(b, m, deterministic)
+}
+func (dst *Rollback) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Rollback.Merge(dst, src)
+}
+func (m *Rollback) XXX_Size() int {
+	return xxx_messageInfo_Rollback.Size(m)
+}
+func (m *Rollback) XXX_DiscardUnknown() {
+	xxx_messageInfo_Rollback.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Rollback proto.InternalMessageInfo
+
+func (m *Rollback) GetSnapshotBlocks() []*HashHeight {
+	if m != nil {
+		return m.SnapshotBlocks
+	}
+	return nil
+}
+
+func (m *Rollback) GetAccountBlocks() []*AccountRollback {
+	if m != nil {
+		return m.AccountBlocks
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*EmptyRequest)(nil), "vitepb.EmptyRequest")
+	proto.RegisterType((*HashRequest)(nil), "vitepb.HashRequest")
+	proto.RegisterType((*AddressRequest)(nil), "vitepb.AddressRequest")
+	proto.RegisterType((*AccountBlocksRequest)(nil), "vitepb.AccountBlocksRequest")
+	proto.RegisterType((*AccountBlockHeightRequest)(nil), "vitepb.AccountBlockHeightRequest")
+	proto.RegisterType((*SnapshotBlockHeightRequest)(nil), "vitepb.SnapshotBlockHeightRequest")
+	proto.RegisterType((*TokenBalance)(nil), "vitepb.TokenBalance")
+	proto.RegisterType((*AccountInfo)(nil), "vitepb.AccountInfo")
+	proto.RegisterType((*SendRawTxResponse)(nil), "vitepb.SendRawTxResponse")
+	proto.RegisterType((*LogTopics)(nil), "vitepb.LogTopics")
+	proto.RegisterType((*LogFilter)(nil), "vitepb.LogFilter")
+	proto.RegisterType((*Log)(nil), "vitepb.Log")
+	proto.RegisterType((*AccountRollback)(nil), "vitepb.AccountRollback")
+	proto.RegisterType((*Rollback)(nil), "vitepb.Rollback")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LedgerClient is the client API for Ledger service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LedgerClient interface {
+	GetAccountBlockByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*AccountBlock, error)
+	GetAccountBlockByHeight(ctx context.Context, in *AccountBlockHeightRequest, opts ...grpc.CallOption) (*AccountBlock, error)
+	GetAccountBlocksByAddress(ctx context.Context, in *AccountBlocksRequest, opts ...grpc.CallOption) (*AccountBlocks, error)
+	GetLatestAccountBlock(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AccountBlock, error)
+	GetAccountInfo(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+	GetSnapshotBlockByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*SnapshotBlock, error)
+	GetSnapshotBlockByHeight(ctx context.Context, in *SnapshotBlockHeightRequest, opts ...grpc.CallOption) (*SnapshotBlock, error)
+	GetLatestSnapshotBlock(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*SnapshotBlock, error)
+}
+
+type ledgerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLedgerClient(cc *grpc.ClientConn) LedgerClient {
+	return &ledgerClient{cc}
+}
+
+func (c *ledgerClient) GetAccountBlockByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*AccountBlock, error) {
+	out := new(AccountBlock)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetAccountBlockByHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetAccountBlockByHeight(ctx context.Context, in *AccountBlockHeightRequest, opts ...grpc.CallOption) (*AccountBlock, error) {
+	out := new(AccountBlock)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetAccountBlockByHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetAccountBlocksByAddress(ctx context.Context, in *AccountBlocksRequest, opts ...grpc.CallOption) (*AccountBlocks, error) {
+	out := new(AccountBlocks)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetAccountBlocksByAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetLatestAccountBlock(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AccountBlock, error) {
+	out := new(AccountBlock)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetLatestAccountBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetAccountInfo(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetAccountInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetSnapshotBlockByHash(ctx context.Context, in *HashRequest, opts ...grpc.CallOption) (*SnapshotBlock, error) {
+	out := new(SnapshotBlock)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetSnapshotBlockByHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetSnapshotBlockByHeight(ctx context.Context, in *SnapshotBlockHeightRequest, opts ...grpc.CallOption) (*SnapshotBlock, error) {
+	out := new(SnapshotBlock)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetSnapshotBlockByHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ledgerClient) GetLatestSnapshotBlock(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*SnapshotBlock, error) {
+	out := new(SnapshotBlock)
+	err := c.cc.Invoke(ctx, "/vitepb.Ledger/GetLatestSnapshotBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LedgerServer is the server API for Ledger service.
+type LedgerServer interface {
+	GetAccountBlockByHash(context.Context, *HashRequest) (*AccountBlock, error)
+	GetAccountBlockByHeight(context.Context, *AccountBlockHeightRequest) (*AccountBlock, error)
+	GetAccountBlocksByAddress(context.Context, *AccountBlocksRequest) (*AccountBlocks, error)
+	GetLatestAccountBlock(context.Context, *AddressRequest) (*AccountBlock, error)
+	GetAccountInfo(context.Context, *AddressRequest) (*AccountInfo, error)
+	GetSnapshotBlockByHash(context.Context, *HashRequest) (*SnapshotBlock, error)
+	GetSnapshotBlockByHeight(context.Context, *SnapshotBlockHeightRequest) (*SnapshotBlock, error)
+	GetLatestSnapshotBlock(context.Context, *EmptyRequest) (*SnapshotBlock, error)
+}
+
+func RegisterLedgerServer(s *grpc.Server, srv LedgerServer) {
+	s.RegisterService(&_Ledger_serviceDesc, srv)
+}
+
+func _Ledger_GetAccountBlockByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetAccountBlockByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetAccountBlockByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetAccountBlockByHash(ctx, req.(*HashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetAccountBlockByHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountBlockHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetAccountBlockByHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetAccountBlockByHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetAccountBlockByHeight(ctx, req.(*AccountBlockHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetAccountBlocksByAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountBlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetAccountBlocksByAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetAccountBlocksByAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetAccountBlocksByAddress(ctx, req.(*AccountBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetLatestAccountBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetLatestAccountBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetLatestAccountBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetLatestAccountBlock(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetAccountInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetAccountInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetAccountInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetAccountInfo(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetSnapshotBlockByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetSnapshotBlockByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetSnapshotBlockByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetSnapshotBlockByHash(ctx, req.(*HashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetSnapshotBlockByHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotBlockHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetSnapshotBlockByHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetSnapshotBlockByHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetSnapshotBlockByHeight(ctx, req.(*SnapshotBlockHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ledger_GetLatestSnapshotBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LedgerServer).GetLatestSnapshotBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Ledger/GetLatestSnapshotBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LedgerServer).GetLatestSnapshotBlock(ctx, req.(*EmptyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Ledger_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vitepb.Ledger",
+	HandlerType: (*LedgerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAccountBlockByHash",
+			Handler:    _Ledger_GetAccountBlockByHash_Handler,
+		},
+		{
+			MethodName: "GetAccountBlockByHeight",
+			Handler:    _Ledger_GetAccountBlockByHeight_Handler,
+		},
+		{
+			MethodName: "GetAccountBlocksByAddress",
+			Handler:    _Ledger_GetAccountBlocksByAddress_Handler,
+		},
+		{
+			MethodName: "GetLatestAccountBlock",
+			Handler:    _Ledger_GetLatestAccountBlock_Handler,
+		},
+		{
+			MethodName: "GetAccountInfo",
+			Handler:    _Ledger_GetAccountInfo_Handler,
+		},
+		{
+			MethodName: "GetSnapshotBlockByHash",
+			Handler:    _Ledger_GetSnapshotBlockByHash_Handler,
+		},
+		{
+			MethodName: "GetSnapshotBlockByHeight",
+			Handler:    _Ledger_GetSnapshotBlockByHeight_Handler,
+		},
+		{
+			MethodName: "GetLatestSnapshotBlock",
+			Handler:    _Ledger_GetLatestSnapshotBlock_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vitepb/api.proto",
+}
+
+// TxClient is the client API for Tx service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TxClient interface {
+	SendRawTx(ctx context.Context, in *AccountBlock, opts ...grpc.CallOption) (*SendRawTxResponse, error)
+}
+
+type txClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTxClient(cc *grpc.ClientConn) TxClient {
+	return &txClient{cc}
+}
+
+func (c *txClient) SendRawTx(ctx context.Context, in *AccountBlock, opts ...grpc.CallOption) (*SendRawTxResponse, error) {
+	out := new(SendRawTxResponse)
+	err := c.cc.Invoke(ctx, "/vitepb.Tx/SendRawTx", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TxServer is the server API for Tx service.
+type TxServer interface {
+	SendRawTx(context.Context, *AccountBlock) (*SendRawTxResponse, error)
+}
+
+func RegisterTxServer(s *grpc.Server, srv TxServer) {
+	s.RegisterService(&_Tx_serviceDesc, srv)
+}
+
+func _Tx_SendRawTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountBlock)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TxServer).SendRawTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Tx/SendRawTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TxServer).SendRawTx(ctx, req.(*AccountBlock))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Tx_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vitepb.Tx",
+	HandlerType: (*TxServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendRawTx",
+			Handler:    _Tx_SendRawTx_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vitepb/api.proto",
+}
+
+// OnroadClient is the client API for Onroad service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OnroadClient interface {
+	GetOnroadBlocksByAddress(ctx context.Context, in *AccountBlocksRequest, opts ...grpc.CallOption) (*AccountBlocks, error)
+	GetAccountOnroadInfo(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+}
+
+type onroadClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOnroadClient(cc *grpc.ClientConn) OnroadClient {
+	return &onroadClient{cc}
+}
+
+func (c *onroadClient) GetOnroadBlocksByAddress(ctx context.Context, in *AccountBlocksRequest, opts ...grpc.CallOption) (*AccountBlocks, error) {
+	out := new(AccountBlocks)
+	err := c.cc.Invoke(ctx, "/vitepb.Onroad/GetOnroadBlocksByAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *onroadClient) GetAccountOnroadInfo(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*AccountInfo, error) {
+	out := new(AccountInfo)
+	err := c.cc.Invoke(ctx, "/vitepb.Onroad/GetAccountOnroadInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OnroadServer is the server API for Onroad service.
+type OnroadServer interface {
+	GetOnroadBlocksByAddress(context.Context, *AccountBlocksRequest) (*AccountBlocks, error)
+	GetAccountOnroadInfo(context.Context, *AddressRequest) (*AccountInfo, error)
+}
+
+func RegisterOnroadServer(s *grpc.Server, srv OnroadServer) {
+	s.RegisterService(&_Onroad_serviceDesc, srv)
+}
+
+func _Onroad_GetOnroadBlocksByAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountBlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OnroadServer).GetOnroadBlocksByAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Onroad/GetOnroadBlocksByAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OnroadServer).GetOnroadBlocksByAddress(ctx, req.(*AccountBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Onroad_GetAccountOnroadInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OnroadServer).GetAccountOnroadInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vitepb.Onroad/GetAccountOnroadInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OnroadServer).GetAccountOnroadInfo(ctx, req.(*AddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Onroad_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vitepb.Onroad",
+	HandlerType: (*OnroadServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOnroadBlocksByAddress",
+			Handler:    _Onroad_GetOnroadBlocksByAddress_Handler,
+		},
+		{
+			MethodName: "GetAccountOnroadInfo",
+			Handler:    _Onroad_GetAccountOnroadInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vitepb/api.proto",
+}
+
+// SubscribeClient is the client API for Subscribe service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SubscribeClient interface {
+	NewSnapshotBlocks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (Subscribe_NewSnapshotBlocksClient, error)
+	// empty addressList means any address
+	NewAccountBlocks(ctx context.Context, in *AddressList, opts ...grpc.CallOption) (Subscribe_NewAccountBlocksClient, error)
+	NewOnroadBlocks(ctx context.Context, in *AddressList, opts ...grpc.CallOption) (Subscribe_NewOnroadBlocksClient, error)
+	NewLogs(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (Subscribe_NewLogsClient, error)
+	NewRollbacks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (Subscribe_NewRollbacksClient, error)
+}
+
+type subscribeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSubscribeClient(cc *grpc.ClientConn) SubscribeClient {
+	return &subscribeClient{cc}
+}
+
+func (c *subscribeClient) NewSnapshotBlocks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (Subscribe_NewSnapshotBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Subscribe_serviceDesc.Streams[0], "/vitepb.Subscribe/NewSnapshotBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscribeNewSnapshotBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Subscribe_NewSnapshotBlocksClient interface {
+	Recv() (*SnapshotBlock, error)
+	grpc.ClientStream
+}
+
+type subscribeNewSnapshotBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscribeNewSnapshotBlocksClient) Recv() (*SnapshotBlock, error) {
+	m := new(SnapshotBlock)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *subscribeClient) NewAccountBlocks(ctx context.Context, in *AddressList, opts ...grpc.CallOption) (Subscribe_NewAccountBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Subscribe_serviceDesc.Streams[1], "/vitepb.Subscribe/NewAccountBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscribeNewAccountBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Subscribe_NewAccountBlocksClient interface {
+	Recv() (*AccountBlock, error)
+	grpc.ClientStream
+}
+
+type subscribeNewAccountBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscribeNewAccountBlocksClient) Recv() (*AccountBlock, error) {
+	m := new(AccountBlock)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *subscribeClient) NewOnroadBlocks(ctx context.Context, in *AddressList, opts ...grpc.CallOption) (Subscribe_NewOnroadBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Subscribe_serviceDesc.Streams[2], "/vitepb.Subscribe/NewOnroadBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscribeNewOnroadBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Subscribe_NewOnroadBlocksClient interface {
+	Recv() (*AccountBlock, error)
+	grpc.ClientStream
+}
+
+type subscribeNewOnroadBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscribeNewOnroadBlocksClient) Recv() (*AccountBlock, error) {
+	m := new(AccountBlock)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *subscribeClient) NewLogs(ctx context.Context, in *LogFilter, opts ...grpc.CallOption) (Subscribe_NewLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Subscribe_serviceDesc.Streams[3], "/vitepb.Subscribe/NewLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscribeNewLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Subscribe_NewLogsClient interface {
+	Recv() (*Log, error)
+	grpc.ClientStream
+}
+
+type subscribeNewLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscribeNewLogsClient) Recv() (*Log, error) {
+	m := new(Log)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *subscribeClient) NewRollbacks(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (Subscribe_NewRollbacksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Subscribe_serviceDesc.Streams[4], "/vitepb.Subscribe/NewRollbacks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &subscribeNewRollbacksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Subscribe_NewRollbacksClient interface {
+	Recv() (*Rollback, error)
+	grpc.ClientStream
+}
+
+type subscribeNewRollbacksClient struct {
+	grpc.ClientStream
+}
+
+func (x *subscribeNewRollbacksClient) Recv() (*Rollback, error) {
+	m := new(Rollback)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SubscribeServer is the server API for Subscribe service.
+type SubscribeServer interface {
+	NewSnapshotBlocks(*EmptyRequest, Subscribe_NewSnapshotBlocksServer) error
+	// empty addressList means any address
+	NewAccountBlocks(*AddressList, Subscribe_NewAccountBlocksServer) error
+	NewOnroadBlocks(*AddressList, Subscribe_NewOnroadBlocksServer) error
+	NewLogs(*LogFilter, Subscribe_NewLogsServer) error
+	NewRollbacks(*EmptyRequest, Subscribe_NewRollbacksServer) error
+}
+
+func RegisterSubscribeServer(s *grpc.Server, srv SubscribeServer) {
+	s.RegisterService(&_Subscribe_serviceDesc, srv)
+}
+
+func _Subscribe_NewSnapshotBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EmptyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscribeServer).NewSnapshotBlocks(m, &subscribeNewSnapshotBlocksServer{stream})
+}
+
+type Subscribe_NewSnapshotBlocksServer interface {
+	Send(*SnapshotBlock) error
+	grpc.ServerStream
+}
+
+type subscribeNewSnapshotBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *subscribeNewSnapshotBlocksServer) Send(m *SnapshotBlock) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Subscribe_NewAccountBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AddressList)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscribeServer).NewAccountBlocks(m, &subscribeNewAccountBlocksServer{stream})
+}
+
+type Subscribe_NewAccountBlocksServer interface {
+	Send(*AccountBlock) error
+	grpc.ServerStream
+}
+
+type subscribeNewAccountBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *subscribeNewAccountBlocksServer) Send(m *AccountBlock) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Subscribe_NewOnroadBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AddressList)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscribeServer).NewOnroadBlocks(m, &subscribeNewOnroadBlocksServer{stream})
+}
+
+type Subscribe_NewOnroadBlocksServer interface {
+	Send(*AccountBlock) error
+	grpc.ServerStream
+}
+
+type subscribeNewOnroadBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *subscribeNewOnroadBlocksServer) Send(m *AccountBlock) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Subscribe_NewLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscribeServer).NewLogs(m, &subscribeNewLogsServer{stream})
+}
+
+type Subscribe_NewLogsServer interface {
+	Send(*Log) error
+	grpc.ServerStream
+}
+
+type subscribeNewLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *subscribeNewLogsServer) Send(m *Log) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Subscribe_NewRollbacks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EmptyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubscribeServer).NewRollbacks(m, &subscribeNewRollbacksServer{stream})
+}
+
+type Subscribe_NewRollbacksServer interface {
+	Send(*Rollback) error
+	grpc.ServerStream
+}
+
+type subscribeNewRollbacksServer struct {
+	grpc.ServerStream
+}
+
+func (x *subscribeNewRollbacksServer) Send(m *Rollback) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Subscribe_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vitepb.Subscribe",
+	HandlerType: (*SubscribeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "NewSnapshotBlocks",
+			Handler:       _Subscribe_NewSnapshotBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "NewAccountBlocks",
+			Handler:       _Subscribe_NewAccountBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "NewOnroadBlocks",
+			Handler:       _Subscribe_NewOnroadBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "NewLogs",
+			Handler:       _Subscribe_NewLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "NewRollbacks",
+			Handler:       _Subscribe_NewRollbacks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "vitepb/api.proto",
+}
+
+func init() { proto.RegisterFile("vitepb/api.proto", fileDescriptor_api_2b96be3679f5df51) }
+
+var fileDescriptor_api_2b96be3679f5df51 = []byte{
+	// 827 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x6f, 0x4f, 0xc3, 0x44,
+	0x18, 0x4f, 0xb7, 0x31, 0xd8, 0xb3, 0x31, 0xe0, 0x18, 0xa3, 0x54, 0x5e, 0x8c, 0x1a, 0x23, 0x62,
+	0x82, 0xcb, 0xf4, 0x85, 0x31, 0xa2, 0x61, 0x8a, 0x40, 0x32, 0x87, 0xe9, 0x66, 0xf4, 0x85, 0x09,
+	0xb9, 0xb6, 0xe7, 0xd6, 0xd0, 0xf5, 0x66, 0xef, 0xc6, 0x20, 0xbe, 0xf6, 0x6b, 0xf8, 0x35, 0xfc,
+	0x32, 0x7e, 0x18, 0xd3, 0xbb, 0x6b, 0xd7, 0x96, 0x0e, 0x42, 0xe2, 0xbb, 0x3e, 0xff, 0x7e, 0xf7,
+	0xfb, 0x3d, 0xf7, 0xdc, 0xb3, 0xc1, 0xee, 0xa3, 0xc7, 0xc9, 0xdc, 0xfe, 0x0c, 0xcf, 0xbd, 0xf3,
+	0x79, 0x48, 0x39, 0x45, 0x55, 0xe9, 0x31, 0x8c, 0x38, 0xe2, 0x38, 0x74, 0x11, 0xf0, 0x7b, 0xdb,
+	0xa7, 0xce, 0x83, 0xcc, 0x31, 0x3e, 0x50, 0x31, 0x16, 0xe0, 0x39, 0x9b, 0xd2, 0x6c, 0xb0, 0xa5,
+	0x82, 0x33, 0xc2, 0x18, 0x9e, 0x10, 0xe5, 0x8d, 0xe1, 0x16, 0x41, 0x48, 0x1c, 0xe2, 0x3d, 0x12,
+	0xf7, 0xde, 0xb5, 0x55, 0xec, 0xa3, 0x3c, 0x1c, 0x76, 0x5d, 0x8f, 0x7b, 0x34, 0xc0, 0xfe, 0xbd,
+	0xef, 0x31, 0x2e, 0xd3, 0xcc, 0x26, 0x34, 0xae, 0x66, 0x73, 0xfe, 0x6c, 0x91, 0x3f, 0x16, 0x84,
+	0x71, 0xf3, 0x04, 0xea, 0x37, 0x98, 0x4d, 0x95, 0x89, 0x10, 0x54, 0xa6, 0x98, 0x4d, 0x75, 0xad,
+	0xa3, 0x9d, 0x36, 0x2c, 0xf1, 0x6d, 0x9e, 0x41, 0xf3, 0xd2, 0x75, 0x43, 0xc2, 0x58, 0x9c, 0xa5,
+	0xc3, 0x26, 0x96, 0x1e, 0x95, 0x18, 0x9b, 0xe6, 0x6f, 0xd0, 0xba, 0x94, 0x5a, 0xfb, 0x91, 0x9a,
+	0xb7, 0x2b, 0x50, 0x0b, 0x36, 0xbc, 0xc0, 0x25, 0x4f, 0x7a, 0xa9, 0xa3, 0x9d, 0x6e, 0x5b, 0xd2,
+	0x88, 0xbc, 0x02, 0x45, 0x2f, 0x4b, 0xaf, 0x30, 0xcc, 0x1f, 0xe1, 0x28, 0x8d, 0x7e, 0x43, 0xbc,
+	0xc9, 0x94, 0xbf, 0x7d, 0x44, 0x1b, 0xaa, 0x53, 0x91, 0x2a, 0xce, 0xa8, 0x58, 0xca, 0x32, 0xbf,
+	0x00, 0x63, 0xa4, 0xba, 0x55, 0x80, 0xb7, 0xaa, 0xd2, 0x32, 0x55, 0xbf, 0x42, 0x63, 0x4c, 0x1f,
+	0x48, 0xd0, 0xc7, 0x3e, 0x0e, 0x1c, 0x12, 0x9d, 0xcb, 0x23, 0xfb, 0xd6, 0x8d, 0xcf, 0x55, 0x66,
+	0x84, 0x80, 0x67, 0x42, 0x45, 0x49, 0x04, 0x94, 0x15, 0xf9, 0x83, 0xc5, 0xcc, 0x26, 0xa1, 0x50,
+	0x57, 0xb1, 0x94, 0x65, 0xfe, 0x09, 0x75, 0x25, 0xef, 0x36, 0xf8, 0x9d, 0xbe, 0x22, 0xa8, 0x03,
+	0x75, 0x4e, 0x39, 0xf6, 0x87, 0x12, 0x45, 0xaa, 0x4a, 0xbb, 0x50, 0x17, 0xb6, 0x6c, 0xc9, 0x8f,
+	0xe9, 0xe5, 0x4e, 0xf9, 0xb4, 0xde, 0x6b, 0x9d, 0xcb, 0x01, 0x39, 0x4f, 0x93, 0xb7, 0x92, 0x2c,
+	0xf3, 0x63, 0xd8, 0x1b, 0x91, 0xc0, 0xb5, 0xf0, 0x72, 0xfc, 0x64, 0x11, 0x36, 0xa7, 0x01, 0x23,
+	0x85, 0xe3, 0xf0, 0x21, 0xd4, 0x06, 0x74, 0x32, 0xa6, 0x73, 0xcf, 0x11, 0xad, 0xe5, 0xe2, 0x4b,
+	0xd7, 0x3a, 0xe5, 0x48, 0xa2, 0xb4, 0xcc, 0xb1, 0x48, 0xfa, 0xc1, 0xf3, 0x39, 0x09, 0xd1, 0x31,
+	0xd4, 0x14, 0x73, 0x12, 0xe7, 0xad, 0x1c, 0xe8, 0x93, 0x04, 0xa2, 0x24, 0x88, 0xee, 0xc5, 0x44,
+	0x93, 0x53, 0x12, 0xd4, 0xbf, 0x34, 0x28, 0x0f, 0xe8, 0x24, 0x02, 0x14, 0x8f, 0xe5, 0x66, 0xc5,
+	0x6d, 0xe5, 0x40, 0x06, 0x6c, 0xf9, 0x74, 0x72, 0x9b, 0x0c, 0x55, 0xc5, 0x4a, 0xec, 0x74, 0x4f,
+	0xcb, 0x2f, 0x86, 0x44, 0xd1, 0xa8, 0xa4, 0x95, 0x44, 0x2d, 0x70, 0x31, 0xc7, 0xfa, 0x86, 0x6c,
+	0x41, 0xf4, 0x6d, 0xfe, 0x02, 0x3b, 0xea, 0xa2, 0x2c, 0xea, 0xfb, 0x36, 0x76, 0x1e, 0x5e, 0xb9,
+	0xac, 0x33, 0xa8, 0x0a, 0x6e, 0xb1, 0x3e, 0x14, 0xeb, 0x8b, 0xc8, 0xaa, 0x91, 0x53, 0x19, 0x91,
+	0xc0, 0xad, 0x04, 0xf2, 0x2b, 0x68, 0xb2, 0xf4, 0x78, 0xca, 0xde, 0x15, 0x03, 0xe4, 0x32, 0xd1,
+	0x05, 0x6c, 0xe3, 0xf4, 0x3b, 0x54, 0x67, 0x1f, 0xc6, 0xa5, 0x39, 0xfa, 0x56, 0x36, 0xbb, 0xf7,
+	0x6f, 0x05, 0xaa, 0x03, 0xe2, 0x4e, 0x48, 0x88, 0xfa, 0x70, 0x70, 0x4d, 0x78, 0xfa, 0xd9, 0xf5,
+	0x9f, 0x45, 0x9b, 0xf7, 0xd3, 0x34, 0xd4, 0xa3, 0x31, 0x5a, 0xb9, 0x03, 0x44, 0x01, 0xb2, 0xe0,
+	0xf0, 0x25, 0x86, 0x20, 0x8e, 0x4e, 0x8a, 0x0a, 0x32, 0x0f, 0x71, 0x0d, 0xe6, 0x4f, 0x70, 0x94,
+	0xc3, 0x64, 0xfd, 0x67, 0xb5, 0xa7, 0xd0, 0x71, 0x51, 0x49, 0xbc, 0x8c, 0x8c, 0x83, 0xc2, 0x28,
+	0xba, 0x12, 0x4a, 0x07, 0x98, 0x13, 0x96, 0xc1, 0x45, 0xed, 0x24, 0x3f, 0xb3, 0x06, 0xd7, 0x10,
+	0xbb, 0x80, 0xe6, 0x8a, 0x98, 0x78, 0xc8, 0xeb, 0xea, 0xf7, 0x73, 0xf5, 0x22, 0xf9, 0x7b, 0x68,
+	0x5f, 0x13, 0x9e, 0xd9, 0x4b, 0xaf, 0x35, 0x3c, 0xd1, 0x92, 0xa9, 0x40, 0x3f, 0x83, 0x5e, 0x80,
+	0x22, 0x5b, 0x6e, 0x16, 0x96, 0x64, 0x7b, 0xbe, 0x06, 0xf6, 0x0a, 0xda, 0x49, 0x8b, 0xb2, 0x91,
+	0xa4, 0x17, 0xe9, 0x5f, 0x97, 0x35, 0x30, 0xbd, 0x3e, 0x94, 0xc6, 0x4f, 0xe8, 0x6b, 0xa8, 0x25,
+	0x1b, 0x07, 0x15, 0xf6, 0xd2, 0x38, 0x4a, 0xea, 0xf3, 0xab, 0xa9, 0xf7, 0xb7, 0x06, 0xd5, 0xbb,
+	0x20, 0xa4, 0xd8, 0x45, 0x77, 0x42, 0xac, 0x34, 0xfe, 0x97, 0x49, 0xf8, 0x0e, 0x5a, 0xab, 0x2b,
+	0x94, 0xb8, 0xef, 0xbe, 0xc8, 0xde, 0x3f, 0x25, 0xa8, 0x8d, 0x16, 0x36, 0x73, 0x42, 0xcf, 0x26,
+	0xa8, 0x0f, 0x7b, 0x43, 0xb2, 0x1c, 0x65, 0x5f, 0xe9, 0x7b, 0x9a, 0xd6, 0xd5, 0xd0, 0xb7, 0xb0,
+	0x3b, 0x24, 0xcb, 0x2c, 0xd5, 0xfd, 0x1c, 0xa5, 0x81, 0xb7, 0x6e, 0x30, 0xbb, 0x1a, 0xfa, 0x06,
+	0x76, 0x86, 0x64, 0x99, 0x6e, 0xd4, 0xfb, 0xea, 0x3f, 0x85, 0xcd, 0x21, 0x59, 0x0e, 0xe8, 0x84,
+	0xa1, 0xf4, 0x96, 0x96, 0x6b, 0xde, 0xa8, 0xa7, 0x5c, 0x5d, 0x0d, 0x7d, 0x09, 0x8d, 0x21, 0x59,
+	0xc6, 0x1b, 0x66, 0x9d, 0xd8, 0xdd, 0xd8, 0x1b, 0x27, 0x76, 0x35, 0xbb, 0x2a, 0xfe, 0xaa, 0x7c,
+	0xfe, 0xdf, 0x00, 0x96, 0x47, 0xbc, 0x36, 0x58, 0x09, 0x00, 0x00,
+}
@@ -0,0 +1,284 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: vitepb/chain_event.proto
+
+package vitepb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ChainEvent_EventType int32
+
+const (
+	ChainEvent_UNKNOWN                ChainEvent_EventType = 0
+	ChainEvent_ADD_ACCOUNT_BLOCKS     ChainEvent_EventType = 1
+	ChainEvent_REVERT_ACCOUNT_BLOCKS  ChainEvent_EventType = 2
+	ChainEvent_ADD_SNAPSHOT_BLOCKS    ChainEvent_EventType = 3
+	ChainEvent_REVERT_SNAPSHOT_BLOCKS ChainEvent_EventType = 4
+)
+
+var ChainEvent_EventType_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "ADD_ACCOUNT_BLOCKS",
+	2: "REVERT_ACCOUNT_BLOCKS",
+	3: "ADD_SNAPSHOT_BLOCKS",
+	4: "REVERT_SNAPSHOT_BLOCKS",
+}
+var ChainEvent_EventType_value = map[string]int32{
+	"UNKNOWN":                0,
+	"ADD_ACCOUNT_BLOCKS":     1,
+	"REVERT_ACCOUNT_BLOCKS":  2,
+	"ADD_SNAPSHOT_BLOCKS":    3,
+	"REVERT_SNAPSHOT_BLOCKS": 4,
+}
+
+func (x ChainEvent_EventType) String() string {
+	return proto.EnumName(ChainEvent_EventType_name, int32(x))
+}
+func (ChainEvent_EventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_chain_event_48229436cd6a6ca7, []int{0, 0}
+}
+
+// ChainEvent is the envelope of a block event sent to the event sinks. The sinks deliver the events at least once,
+// they're chained by their hashes, a consumer can detect a lost or duplicated event by comparing prevEventHash with
+// the eventHash it handled last, and gets exactly-once processing by skipping the eventId it has handled.
+type ChainEvent struct {
+	Version       uint32               `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	EventId       uint64               `protobuf:"varint,2,opt,name=eventId,proto3" json:"eventId,omitempty"`
+	EventType     ChainEvent_EventType `protobuf:"varint,3,opt,name=eventType,proto3,enum=vitepb.ChainEvent_EventType" json:"eventType,omitempty"`
+	PrevEventHash []byte               `protobuf:"bytes,4,opt,name=prevEventHash,proto3" json:"prevEventHash,omitempty"`
+	EventHash     []byte               `protobuf:"bytes,5,opt,name=eventHash,proto3" json:"eventHash,omitempty"`
+	// the hashes of the added or reverted blocks
+	BlockHashList        [][]byte             `protobuf:"bytes,6,rep,name=blockHashList,proto3" json:"blockHashList,omitempty"`
+	SnapshotBlocks       []*SnapshotBlock     `protobuf:"bytes,8,rep,name=snapshotBlocks,proto3" json:"snapshotBlocks,omitempty"`
+	AccountBlocks        []*EventAccountBlock `protobuf:"bytes,9,rep,name=accountBlocks,proto3" json:"accountBlocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ChainEvent) Reset()         { *m = ChainEvent{} }
+func (m *ChainEvent) String() string { return proto.CompactTextString(m) }
+func (*ChainEvent) ProtoMessage()    {}
+func (*ChainEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chain_event_48229436cd6a6ca7, []int{0}
+}
+func (m *ChainEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainEvent.Unmarshal(m, b)
+}
+func (m *ChainEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainEvent.Marshal(b, m, deterministic)
+}
+func (dst *ChainEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainEvent.Merge(dst, src)
+}
+func (m *ChainEvent) XXX_Size() int {
+	return xxx_messageInfo_ChainEvent.Size(m)
+}
+func (m *ChainEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainEvent proto.InternalMessageInfo
+
+func (m *ChainEvent) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ChainEvent) GetEventId() uint64 {
+	if m != nil {
+		return m.EventId
+	}
+	return 0
+}
+
+func (m *ChainEvent) GetEventType() ChainEvent_EventType {
+	if m != nil {
+		return m.EventType
+	}
+	return ChainEvent_UNKNOWN
+}
+
+func (m *ChainEvent) GetPrevEventHash() []byte {
+	if m != nil {
+		return m.PrevEventHash
+	}
+	return nil
+}
+
+func (m *ChainEvent) GetEventHash() []byte {
+	if m != nil {
+		return m.EventHash
+	}
+	return nil
+}
+
+func (m *ChainEvent) GetBlockHashList() [][]byte {
+	if m != nil {
+		return m.BlockHashList
+	}
+	return nil
+}
+
+func (m *ChainEvent) GetSnapshotBlocks() []*SnapshotBlock {
+	if m != nil {
+		return m.SnapshotBlocks
+	}
+	return nil
+}
+
+func (m *ChainEvent) GetAccountBlocks() []*EventAccountBlock {
+	if m != nil {
+		return m.AccountBlocks
+	}
+	return nil
+}
+
+// EventAccountBlock is an account block with the fields of the json messages.
+type EventAccountBlock struct {
+	Block *AccountBlock `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	// the balance of the token after the block
+	Balance []byte `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// the sender of the transfer
+	FromAddress []byte `protobuf:"bytes,3,opt,name=fromAddress,proto3" json:"fromAddress,omitempty"`
+	// the token info of a mintage send block as json
+	ParsedData string `protobuf:"bytes,4,opt,name=parsedData,proto3" json:"parsedData,omitempty"`
+	// the data of the send block of a receive block
+	SendData []byte `protobuf:"bytes,5,opt,name=sendData,proto3" json:"sendData,omitempty"`
+	// the amount and the token of the transfer, the ones of the send block for a receive block
+	Amount               []byte   `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	TokenId              []byte   `protobuf:"bytes,7,opt,name=tokenId,proto3" json:"tokenId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventAccountBlock) Reset()         { *m = EventAccountBlock{} }
+func (m *EventAccountBlock) String() string { return proto.CompactTextString(m) }
+func (*EventAccountBlock) ProtoMessage()    {}
+func (*EventAccountBlock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chain_event_48229436cd6a6ca7, []int{1}
+}
+func (m *EventAccountBlock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventAccountBlock.Unmarshal(m, b)
+}
+func (m *EventAccountBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventAccountBlock.Marshal(b, m, deterministic)
+}
+func (dst *EventAccountBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventAccountBlock.Merge(dst, src)
+}
+func (m *EventAccountBlock) XXX_Size() int {
+	return xxx_messageInfo_EventAccountBlock.Size(m)
+}
+func (m *EventAccountBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventAccountBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventAccountBlock proto.InternalMessageInfo
+
+func (m *EventAccountBlock) GetBlock() *AccountBlock {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *EventAccountBlock) GetBalance() []byte {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *EventAccountBlock) GetFromAddress() []byte {
+	if m != nil {
+		return m.FromAddress
+	}
+	return nil
+}
+
+func (m *EventAccountBlock) GetParsedData() string {
+	if m != nil {
+		return m.ParsedData
+	}
+	return ""
+}
+
+func (m *EventAccountBlock) GetSendData() []byte {
+	if m != nil {
+		return m.SendData
+	}
+	return nil
+}
+
+func (m *EventAccountBlock) GetAmount() []byte {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *EventAccountBlock) GetTokenId() []byte {
+	if m != nil {
+		return m.TokenId
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChainEvent)(nil), "vitepb.ChainEvent")
+	proto.RegisterType((*EventAccountBlock)(nil), "vitepb.EventAccountBlock")
+	proto.RegisterEnum("vitepb.ChainEvent_EventType", ChainEvent_EventType_name, ChainEvent_EventType_value)
+}
+
+func init() {
+	proto.RegisterFile("vitepb/chain_event.proto", fileDescriptor_chain_event_48229436cd6a6ca7)
+}
+
+var fileDescriptor_chain_event_48229436cd6a6ca7 = []byte{
+	// 454 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x51, 0x6f, 0xd3, 0x30,
+	0x10, 0xc7, 0xc9, 0xd2, 0xa5, 0xed, 0xb5, 0x9d, 0xca, 0xc1, 0x8a, 0x17, 0x26, 0x14, 0x55, 0x3c,
+	0x44, 0x3c, 0x14, 0xa9, 0xbc, 0x21, 0x21, 0x94, 0xb5, 0x95, 0x36, 0x36, 0xa5, 0xc8, 0xed, 0xe0,
+	0xb1, 0x72, 0x13, 0xa3, 0x56, 0xdb, 0x92, 0x28, 0x0e, 0x95, 0x78, 0xe3, 0xeb, 0xf2, 0xc4, 0x57,
+	0x40, 0x76, 0xec, 0x36, 0x29, 0x6f, 0xfe, 0xdf, 0xef, 0x7f, 0xe7, 0xf3, 0xf9, 0x80, 0xec, 0xb6,
+	0x05, 0xcf, 0xd6, 0xef, 0xa3, 0x0d, 0xdb, 0x26, 0x2b, 0xbe, 0xe3, 0x49, 0x31, 0xca, 0xf2, 0xb4,
+	0x48, 0xd1, 0x29, 0x89, 0xeb, 0x6a, 0x07, 0x8b, 0xa2, 0xf4, 0x67, 0x52, 0xac, 0xd6, 0x8f, 0x69,
+	0xf4, 0x50, 0x7a, 0xdc, 0xd7, 0x9a, 0x89, 0x84, 0x65, 0x62, 0x93, 0xd6, 0xe0, 0xf0, 0xaf, 0x0d,
+	0x30, 0x91, 0x65, 0x67, 0xb2, 0x2a, 0x12, 0x68, 0xee, 0x78, 0x2e, 0xb6, 0x69, 0x42, 0x2c, 0xcf,
+	0xf2, 0x7b, 0xd4, 0x48, 0x49, 0xd4, 0xc5, 0x37, 0x31, 0x39, 0xf1, 0x2c, 0xbf, 0x41, 0x8d, 0xc4,
+	0x8f, 0xd0, 0x56, 0xc7, 0xe5, 0xaf, 0x8c, 0x13, 0xdb, 0xb3, 0xfc, 0xb3, 0xf1, 0xe5, 0xa8, 0xbc,
+	0x73, 0x74, 0x28, 0x3d, 0x9a, 0x19, 0x0f, 0x3d, 0xd8, 0xf1, 0x2d, 0xf4, 0xb2, 0x9c, 0xef, 0x14,
+	0xbb, 0x66, 0x62, 0x43, 0x1a, 0x9e, 0xe5, 0x77, 0x69, 0x3d, 0x88, 0x97, 0xfa, 0x06, 0xe5, 0x38,
+	0x55, 0x8e, 0x43, 0x40, 0xd6, 0x50, 0x2f, 0x92, 0xe2, 0x6e, 0x2b, 0x0a, 0xe2, 0x78, 0xb6, 0xac,
+	0x51, 0x0b, 0xe2, 0x27, 0x38, 0x33, 0x03, 0xb8, 0x92, 0x40, 0x90, 0x96, 0x67, 0xfb, 0x9d, 0xf1,
+	0xb9, 0x69, 0x75, 0x51, 0xa5, 0xf4, 0xc8, 0x8c, 0x9f, 0xa1, 0xa7, 0x67, 0xab, 0xb3, 0xdb, 0x2a,
+	0xfb, 0xc2, 0x64, 0xab, 0x66, 0x83, 0x8a, 0x83, 0xd6, 0xfd, 0xc3, 0xdf, 0x16, 0xb4, 0xf7, 0x23,
+	0xc0, 0x0e, 0x34, 0xef, 0xc3, 0xdb, 0x70, 0xfe, 0x3d, 0xec, 0x3f, 0xc3, 0x01, 0x60, 0x30, 0x9d,
+	0xae, 0x82, 0xc9, 0x64, 0x7e, 0x1f, 0x2e, 0x57, 0x57, 0x77, 0xf3, 0xc9, 0xed, 0xa2, 0x6f, 0xe1,
+	0x05, 0x9c, 0xd3, 0xd9, 0xb7, 0x19, 0x5d, 0x1e, 0xa3, 0x13, 0x7c, 0x05, 0x2f, 0x64, 0xca, 0x22,
+	0x0c, 0xbe, 0x2e, 0xae, 0xe7, 0x7b, 0x60, 0xa3, 0x0b, 0x03, 0x9d, 0x73, 0xcc, 0x1a, 0x5f, 0x1a,
+	0xad, 0x66, 0xbf, 0x35, 0xfc, 0x63, 0xc1, 0xf3, 0xff, 0xba, 0xc5, 0x77, 0x70, 0xaa, 0xe6, 0xa5,
+	0xbe, 0xbd, 0x33, 0x7e, 0x69, 0xde, 0x55, 0x7b, 0x52, 0x69, 0x91, 0xab, 0xb0, 0x66, 0x8f, 0x2c,
+	0x89, 0xb8, 0x5a, 0x85, 0x2e, 0x35, 0x12, 0x3d, 0xe8, 0xfc, 0xc8, 0xd3, 0xa7, 0x20, 0x8e, 0x73,
+	0x2e, 0x84, 0x5a, 0x86, 0x2e, 0xad, 0x86, 0xf0, 0x0d, 0x40, 0xc6, 0x72, 0xc1, 0xe3, 0x29, 0x2b,
+	0x98, 0xfa, 0xed, 0x36, 0xad, 0x44, 0xd0, 0x85, 0x96, 0xe0, 0x49, 0x49, 0xcb, 0x9f, 0xde, 0x6b,
+	0x1c, 0x80, 0xc3, 0x9e, 0x64, 0x37, 0xc4, 0x51, 0x44, 0x2b, 0xd9, 0x4f, 0x91, 0x3e, 0xf0, 0xe4,
+	0x26, 0x26, 0xcd, 0xb2, 0x1f, 0x2d, 0xd7, 0x8e, 0x5a, 0xf2, 0x0f, 0xff, 0x06, 0x00, 0xea, 0x55,
+	0x16, 0xbf, 0x41, 0x03, 0x00, 0x00,
+}
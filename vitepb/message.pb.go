@@ -24,6 +24,7 @@ type Handshake struct {
 	Port                 uint32   `protobuf:"varint,3,opt,name=Port,proto3" json:"Port,omitempty"`
 	Current              []byte   `protobuf:"bytes,4,opt,name=Current,proto3" json:"Current,omitempty"`
 	Genesis              []byte   `protobuf:"bytes,5,opt,name=Genesis,proto3" json:"Genesis,omitempty"`
+	Codecs               uint32   `protobuf:"varint,6,opt,name=Codecs,proto3" json:"Codecs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -33,7 +34,7 @@ func (m *Handshake) Reset()         { *m = Handshake{} }
 func (m *Handshake) String() string { return proto.CompactTextString(m) }
 func (*Handshake) ProtoMessage()    {}
 func (*Handshake) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{0}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{0}
 }
 func (m *Handshake) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Handshake.Unmarshal(m, b)
@@ -88,6 +89,13 @@ func (m *Handshake) GetGenesis() []byte {
 	return nil
 }
 
+func (m *Handshake) GetCodecs() uint32 {
+	if m != nil {
+		return m.Codecs
+	}
+	return 0
+}
+
 type BlockID struct {
 	Hash                 []byte   `protobuf:"bytes,1,opt,name=Hash,proto3" json:"Hash,omitempty"`
 	Height               uint64   `protobuf:"varint,2,opt,name=Height,proto3" json:"Height,omitempty"`
@@ -100,7 +108,7 @@ func (m *BlockID) Reset()         { *m = BlockID{} }
 func (m *BlockID) String() string { return proto.CompactTextString(m) }
 func (*BlockID) ProtoMessage()    {}
 func (*BlockID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{1}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{1}
 }
 func (m *BlockID) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockID.Unmarshal(m, b)
@@ -140,6 +148,8 @@ type CompressedFileMeta struct {
 	Filename             string   `protobuf:"bytes,3,opt,name=Filename,proto3" json:"Filename,omitempty"`
 	FileSize             int64    `protobuf:"varint,4,opt,name=FileSize,proto3" json:"FileSize,omitempty"`
 	BlockNumbers         uint64   `protobuf:"varint,5,opt,name=BlockNumbers,proto3" json:"BlockNumbers,omitempty"`
+	Codec                uint32   `protobuf:"varint,6,opt,name=Codec,proto3" json:"Codec,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,7,opt,name=Hash,proto3" json:"Hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -149,7 +159,7 @@ func (m *CompressedFileMeta) Reset()         { *m = CompressedFileMeta{} }
 func (m *CompressedFileMeta) String() string { return proto.CompactTextString(m) }
 func (*CompressedFileMeta) ProtoMessage()    {}
 func (*CompressedFileMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{2}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{2}
 }
 func (m *CompressedFileMeta) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CompressedFileMeta.Unmarshal(m, b)
@@ -204,6 +214,20 @@ func (m *CompressedFileMeta) GetBlockNumbers() uint64 {
 	return 0
 }
 
+func (m *CompressedFileMeta) GetCodec() uint32 {
+	if m != nil {
+		return m.Codec
+	}
+	return 0
+}
+
+func (m *CompressedFileMeta) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
 type FileList struct {
 	Files                []*CompressedFileMeta `protobuf:"bytes,1,rep,name=Files,proto3" json:"Files,omitempty"`
 	Chunks               []uint64              `protobuf:"varint,2,rep,packed,name=Chunks,proto3" json:"Chunks,omitempty"`
@@ -217,7 +241,7 @@ func (m *FileList) Reset()         { *m = FileList{} }
 func (m *FileList) String() string { return proto.CompactTextString(m) }
 func (*FileList) ProtoMessage()    {}
 func (*FileList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{3}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{3}
 }
 func (m *FileList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FileList.Unmarshal(m, b)
@@ -270,7 +294,7 @@ func (m *GetFiles) Reset()         { *m = GetFiles{} }
 func (m *GetFiles) String() string { return proto.CompactTextString(m) }
 func (*GetFiles) ProtoMessage()    {}
 func (*GetFiles) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{4}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{4}
 }
 func (m *GetFiles) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetFiles.Unmarshal(m, b)
@@ -316,7 +340,7 @@ func (m *GetChunk) Reset()         { *m = GetChunk{} }
 func (m *GetChunk) String() string { return proto.CompactTextString(m) }
 func (*GetChunk) ProtoMessage()    {}
 func (*GetChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{5}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{5}
 }
 func (m *GetChunk) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetChunk.Unmarshal(m, b)
@@ -363,7 +387,7 @@ func (m *SubLedger) Reset()         { *m = SubLedger{} }
 func (m *SubLedger) String() string { return proto.CompactTextString(m) }
 func (*SubLedger) ProtoMessage()    {}
 func (*SubLedger) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{6}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{6}
 }
 func (m *SubLedger) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SubLedger.Unmarshal(m, b)
@@ -417,7 +441,7 @@ func (m *GetSnapshotBlocks) Reset()         { *m = GetSnapshotBlocks{} }
 func (m *GetSnapshotBlocks) String() string { return proto.CompactTextString(m) }
 func (*GetSnapshotBlocks) ProtoMessage()    {}
 func (*GetSnapshotBlocks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{7}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{7}
 }
 func (m *GetSnapshotBlocks) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetSnapshotBlocks.Unmarshal(m, b)
@@ -469,7 +493,7 @@ func (m *SnapshotBlocks) Reset()         { *m = SnapshotBlocks{} }
 func (m *SnapshotBlocks) String() string { return proto.CompactTextString(m) }
 func (*SnapshotBlocks) ProtoMessage()    {}
 func (*SnapshotBlocks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{8}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{8}
 }
 func (m *SnapshotBlocks) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SnapshotBlocks.Unmarshal(m, b)
@@ -510,7 +534,7 @@ func (m *GetAccountBlocks) Reset()         { *m = GetAccountBlocks{} }
 func (m *GetAccountBlocks) String() string { return proto.CompactTextString(m) }
 func (*GetAccountBlocks) ProtoMessage()    {}
 func (*GetAccountBlocks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{9}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{9}
 }
 func (m *GetAccountBlocks) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetAccountBlocks.Unmarshal(m, b)
@@ -569,7 +593,7 @@ func (m *AccountBlocks) Reset()         { *m = AccountBlocks{} }
 func (m *AccountBlocks) String() string { return proto.CompactTextString(m) }
 func (*AccountBlocks) ProtoMessage()    {}
 func (*AccountBlocks) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_ab411b0053a36526, []int{10}
+	return fileDescriptor_message_a63665ad1b33e73e, []int{10}
 }
 func (m *AccountBlocks) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AccountBlocks.Unmarshal(m, b)
@@ -610,43 +634,44 @@ func init() {
 	proto.RegisterType((*AccountBlocks)(nil), "vitepb.AccountBlocks")
 }
 
-func init() { proto.RegisterFile("vitepb/message.proto", fileDescriptor_message_ab411b0053a36526) }
-
-var fileDescriptor_message_ab411b0053a36526 = []byte{
-	// 548 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0xc7, 0xe5, 0xd8, 0x79, 0x9b, 0xa4, 0xcf, 0x53, 0x56, 0x01, 0x59, 0x81, 0x83, 0x65, 0x2e,
-	0x39, 0x40, 0x8a, 0x82, 0xe0, 0x86, 0x50, 0x08, 0x4d, 0x82, 0x54, 0x2a, 0xb4, 0xfe, 0x00, 0x68,
-	0x1d, 0x8f, 0x62, 0x93, 0xda, 0x8e, 0x76, 0xd7, 0x20, 0x71, 0x43, 0xdc, 0xf8, 0x34, 0x7c, 0x44,
-	0xb4, 0x2f, 0x4e, 0xe2, 0x42, 0xa5, 0xde, 0xf6, 0x3f, 0x33, 0xff, 0x1d, 0xff, 0x76, 0x26, 0x81,
-	0xd1, 0xd7, 0x4c, 0xe2, 0x3e, 0xbe, 0xc8, 0x51, 0x08, 0xb6, 0xc5, 0xe9, 0x9e, 0x97, 0xb2, 0x24,
-	0x1d, 0x13, 0x1d, 0x8f, 0x6d, 0x96, 0x6d, 0x36, 0x65, 0x55, 0xc8, 0xcf, 0xf1, 0x4d, 0xb9, 0xd9,
-	0x99, 0x9a, 0xf1, 0x63, 0x9b, 0x13, 0x05, 0xdb, 0x8b, 0xb4, 0x6c, 0x24, 0xc3, 0x9f, 0x0e, 0xf4,
-	0xd7, 0xac, 0x48, 0x44, 0xca, 0x76, 0x48, 0x1e, 0x41, 0x67, 0x91, 0x27, 0x11, 0x4a, 0xdf, 0x09,
-	0x9c, 0x89, 0x47, 0xad, 0x52, 0xf1, 0x35, 0x66, 0xdb, 0x54, 0xfa, 0x2d, 0x13, 0x37, 0x8a, 0x10,
-	0xf0, 0x3e, 0x95, 0x5c, 0xfa, 0x6e, 0xe0, 0x4c, 0xce, 0xa8, 0x3e, 0x13, 0x1f, 0xba, 0x8b, 0x8a,
-	0x73, 0x2c, 0xa4, 0xef, 0x05, 0xce, 0x64, 0x48, 0x6b, 0xa9, 0x32, 0x2b, 0x2c, 0x50, 0x64, 0xc2,
-	0x6f, 0x9b, 0x8c, 0x95, 0xe1, 0x2b, 0xe8, 0xbe, 0x53, 0x1f, 0xf5, 0xe1, 0xbd, 0xba, 0x72, 0xcd,
-	0x44, 0xaa, 0x3f, 0x60, 0x48, 0xf5, 0xf9, 0xae, 0xf6, 0xe1, 0x6f, 0x07, 0xc8, 0xa2, 0xcc, 0xf7,
-	0x1c, 0x85, 0xc0, 0x64, 0x99, 0xdd, 0xe0, 0x47, 0x94, 0x8c, 0x04, 0x30, 0x88, 0x24, 0xe3, 0xd2,
-	0x7a, 0x0c, 0xca, 0x69, 0x88, 0x3c, 0x81, 0xfe, 0x65, 0x91, 0x34, 0xee, 0x3c, 0x06, 0xc8, 0x18,
-	0x7a, 0xea, 0xae, 0x82, 0xe5, 0xa8, 0xc9, 0xfa, 0xf4, 0xa0, 0xeb, 0x5c, 0x94, 0x7d, 0x47, 0x8d,
-	0xe7, 0xd2, 0x83, 0x26, 0x21, 0x0c, 0x35, 0xc5, 0x75, 0x95, 0xc7, 0xc8, 0x0d, 0xa4, 0x47, 0x1b,
-	0xb1, 0xf0, 0x8b, 0xf1, 0x5f, 0x65, 0x42, 0x92, 0x17, 0xd0, 0x56, 0x67, 0xe1, 0x3b, 0x81, 0x3b,
-	0x19, 0xcc, 0xc6, 0x53, 0x33, 0xa8, 0xe9, 0xdf, 0x48, 0xd4, 0x14, 0xea, 0xf9, 0xa4, 0x55, 0xb1,
-	0x13, 0x7e, 0x2b, 0x70, 0xf5, 0x7c, 0xb4, 0x22, 0x23, 0x68, 0x5f, 0x97, 0xc5, 0xc6, 0x7c, 0xae,
-	0x47, 0x8d, 0x08, 0x5f, 0x43, 0x6f, 0x85, 0xd2, 0x38, 0x55, 0x05, 0xcb, 0x6d, 0xaf, 0x3e, 0x35,
-	0xe2, 0xe8, 0x6b, 0x9d, 0xfa, 0x66, 0xda, 0xa7, 0xaf, 0x56, 0x15, 0xfa, 0xe1, 0xec, 0x2b, 0x1a,
-	0x41, 0xce, 0xc1, 0xbd, 0x2c, 0x12, 0xeb, 0x52, 0xc7, 0xf0, 0x97, 0x03, 0xfd, 0xa8, 0x8a, 0xaf,
-	0x30, 0xd9, 0x22, 0x27, 0x17, 0xd0, 0x8d, 0x34, 0x76, 0xcd, 0xf6, 0xb0, 0x66, 0x8b, 0xec, 0x12,
-	0xea, 0x2c, 0xad, 0xab, 0xc8, 0x14, 0xba, 0x73, 0x6b, 0x68, 0x69, 0xc3, 0xa8, 0x36, 0xcc, 0xcd,
-	0x46, 0xdb, 0x7a, 0x5b, 0xa4, 0x06, 0x38, 0x8f, 0xed, 0xbb, 0x5a, 0xe8, 0x63, 0x20, 0x4c, 0xe1,
-	0xc1, 0x0a, 0x65, 0xa3, 0x95, 0x20, 0x4f, 0xc1, 0x5b, 0xf2, 0x32, 0xd7, 0x20, 0x83, 0xd9, 0xff,
-	0xf5, 0xfd, 0x76, 0xef, 0xa8, 0x4e, 0x2a, 0xdc, 0x85, 0x6a, 0x57, 0x3f, 0x88, 0x16, 0x6a, 0x71,
-	0x97, 0x25, 0xff, 0xc6, 0x78, 0xa2, 0x7b, 0xf5, 0x68, 0x2d, 0xc3, 0xb7, 0xf0, 0xdf, 0xad, 0x36,
-	0xcf, 0xa1, 0x73, 0x1f, 0x72, 0x5b, 0x14, 0xfe, 0x70, 0xe0, 0x7c, 0x85, 0xf2, 0x94, 0x52, 0xa8,
-	0x7e, 0xf3, 0x24, 0x51, 0x2b, 0x60, 0x7f, 0x06, 0xb5, 0x3c, 0x40, 0xb4, 0xee, 0x05, 0xe1, 0xde,
-	0x01, 0xe1, 0x35, 0x21, 0xde, 0xc0, 0x59, 0xb3, 0xff, 0xb3, 0x5b, 0x0c, 0xff, 0x1e, 0x86, 0xad,
-	0x89, 0x3b, 0xfa, 0x9f, 0xe4, 0xe5, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x82, 0x97, 0xa9, 0x9d,
-	0xa2, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("vitepb/message.proto", fileDescriptor_message_a63665ad1b33e73e) }
+
+var fileDescriptor_message_a63665ad1b33e73e = []byte{
+	// 566 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xcd, 0x8e, 0xd3, 0x3c,
+	0x14, 0x86, 0x95, 0x26, 0xfd, 0x3b, 0xed, 0x7c, 0xdf, 0x60, 0x15, 0x14, 0x15, 0x16, 0x51, 0xd8,
+	0x74, 0x01, 0x1d, 0x54, 0x04, 0x3b, 0x84, 0x4a, 0x99, 0xb6, 0x48, 0xc3, 0x08, 0x39, 0x17, 0x80,
+	0x92, 0xe6, 0xa8, 0x09, 0x9d, 0x24, 0x55, 0xec, 0x80, 0xc4, 0x8e, 0x2d, 0xb7, 0xc0, 0x9d, 0x71,
+	0x35, 0xc8, 0xc7, 0x4e, 0xdb, 0x0c, 0x8c, 0x34, 0x3b, 0xbf, 0xe7, 0xc7, 0xaf, 0x1f, 0x1f, 0x27,
+	0x30, 0xfa, 0x9a, 0x4a, 0xdc, 0x47, 0x17, 0x19, 0x0a, 0x11, 0x6e, 0x71, 0xba, 0x2f, 0x0b, 0x59,
+	0xb0, 0x8e, 0x8e, 0x8e, 0xc7, 0x26, 0x1b, 0x6e, 0x36, 0x45, 0x95, 0xcb, 0xcf, 0xd1, 0x4d, 0xb1,
+	0xd9, 0xe9, 0x9a, 0xf1, 0x63, 0x93, 0x13, 0x79, 0xb8, 0x17, 0x49, 0xd1, 0x48, 0xfa, 0xbf, 0x2c,
+	0xe8, 0xaf, 0xc3, 0x3c, 0x16, 0x49, 0xb8, 0x43, 0xf6, 0x08, 0x3a, 0x8b, 0x2c, 0x0e, 0x50, 0xba,
+	0x96, 0x67, 0x4d, 0x1c, 0x6e, 0x94, 0x8a, 0xaf, 0x31, 0xdd, 0x26, 0xd2, 0x6d, 0xe9, 0xb8, 0x56,
+	0x8c, 0x81, 0xf3, 0xa9, 0x28, 0xa5, 0x6b, 0x7b, 0xd6, 0xe4, 0x8c, 0xd3, 0x9a, 0xb9, 0xd0, 0x5d,
+	0x54, 0x65, 0x89, 0xb9, 0x74, 0x1d, 0xcf, 0x9a, 0x0c, 0x79, 0x2d, 0x55, 0x66, 0x85, 0x39, 0x8a,
+	0x54, 0xb8, 0x6d, 0x9d, 0x31, 0x92, 0x7c, 0x8b, 0x18, 0x37, 0xc2, 0xed, 0xd0, 0x4e, 0x46, 0xf9,
+	0xaf, 0xa0, 0xfb, 0x4e, 0x1d, 0xf6, 0xc3, 0x7b, 0x65, 0xb5, 0x0e, 0x45, 0x42, 0x07, 0x1b, 0x72,
+	0x5a, 0xdf, 0x75, 0x2c, 0xff, 0xb7, 0x05, 0x6c, 0x51, 0x64, 0xfb, 0x12, 0x85, 0xc0, 0x78, 0x99,
+	0xde, 0xe0, 0x47, 0x94, 0x21, 0xf3, 0x60, 0x10, 0xc8, 0xb0, 0x94, 0xa6, 0x47, 0x23, 0x9e, 0x86,
+	0xd8, 0x13, 0xe8, 0x5f, 0xe6, 0x71, 0x63, 0xcf, 0x63, 0x80, 0x8d, 0xa1, 0xa7, 0xf6, 0xca, 0xc3,
+	0x0c, 0x89, 0xb8, 0xcf, 0x0f, 0xba, 0xce, 0x05, 0xe9, 0x77, 0x24, 0x6c, 0x9b, 0x1f, 0x34, 0xf3,
+	0x61, 0x48, 0x14, 0xd7, 0x55, 0x16, 0x61, 0xa9, 0xe1, 0x1d, 0xde, 0x88, 0xb1, 0x11, 0xb4, 0x89,
+	0xd9, 0x5c, 0x80, 0x16, 0x07, 0xe8, 0xee, 0x11, 0xda, 0xff, 0xa2, 0x9d, 0xae, 0x52, 0x21, 0xd9,
+	0x0b, 0x68, 0xab, 0xb5, 0x70, 0x2d, 0xcf, 0x9e, 0x0c, 0x66, 0xe3, 0xa9, 0x1e, 0xf5, 0xf4, 0x6f,
+	0x78, 0xae, 0x0b, 0xe9, 0xa6, 0x93, 0x2a, 0xdf, 0x09, 0xb7, 0xe5, 0xd9, 0x34, 0x61, 0x52, 0xca,
+	0xff, 0xba, 0xc8, 0x37, 0x1a, 0xcc, 0xe1, 0x5a, 0xf8, 0xaf, 0xa1, 0xb7, 0x42, 0xa9, 0x3b, 0x55,
+	0x45, 0x98, 0x19, 0xaf, 0x3e, 0xd7, 0xe2, 0xd8, 0xd7, 0x3a, 0xed, 0x9b, 0x51, 0x1f, 0x6d, 0xad,
+	0x2a, 0xe8, 0x8a, 0xcd, 0x7d, 0x6b, 0xc1, 0xce, 0xc1, 0xbe, 0xcc, 0x63, 0xd3, 0xa5, 0x96, 0xfe,
+	0x4f, 0x0b, 0xfa, 0x41, 0x15, 0x5d, 0x61, 0xbc, 0xc5, 0x92, 0x5d, 0x40, 0x37, 0xa0, 0x0b, 0xaa,
+	0xd9, 0x1e, 0xd6, 0x6c, 0x81, 0x79, 0xc6, 0x94, 0xe5, 0x75, 0x15, 0x9b, 0x42, 0x77, 0x6e, 0x1a,
+	0x5a, 0xd4, 0x30, 0xaa, 0x1b, 0xe6, 0xfa, 0x9b, 0x30, 0xf5, 0xa6, 0x48, 0x8d, 0x7a, 0x1e, 0x99,
+	0x09, 0x18, 0xe8, 0x63, 0xc0, 0x4f, 0xe0, 0xc1, 0x0a, 0x65, 0xc3, 0x4a, 0xb0, 0xa7, 0xe0, 0x2c,
+	0xcb, 0x22, 0x23, 0x90, 0xc1, 0xec, 0xff, 0x7a, 0x7f, 0xf3, 0x42, 0x39, 0x25, 0xf5, 0x20, 0xab,
+	0xbc, 0x7e, 0x3e, 0x5a, 0xa8, 0xa7, 0xbf, 0x2c, 0xca, 0x6f, 0x61, 0x19, 0x93, 0x57, 0x8f, 0xd7,
+	0xd2, 0x7f, 0x0b, 0xff, 0xdd, 0xb2, 0x79, 0x0e, 0x9d, 0xfb, 0x90, 0x9b, 0x22, 0xff, 0x87, 0x05,
+	0xe7, 0x2b, 0x94, 0xa7, 0x94, 0x42, 0xf9, 0xcd, 0xe3, 0x58, 0x3d, 0x01, 0xf3, 0xc1, 0xd4, 0xf2,
+	0x00, 0xd1, 0xba, 0x17, 0x84, 0x7d, 0x07, 0x84, 0xd3, 0x84, 0x78, 0x03, 0x67, 0x4d, 0xff, 0x67,
+	0xb7, 0x18, 0xfe, 0x3d, 0x0c, 0x53, 0x13, 0x75, 0xe8, 0x5f, 0xf4, 0xf2, 0xcf, 0x00, 0xb5, 0x24,
+	0xfe, 0xd4, 0xe4, 0x04, 0x00, 0x00,
 }
@@ -21,6 +21,7 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type Producer struct {
 	BrokerList           []string `protobuf:"bytes,1,rep,name=brokerList,proto3" json:"brokerList,omitempty"`
 	Topic                string   `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Format               string   `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -30,7 +31,7 @@ func (m *Producer) Reset()         { *m = Producer{} }
 func (m *Producer) String() string { return proto.CompactTextString(m) }
 func (*Producer) ProtoMessage()    {}
 func (*Producer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_producer_87d18ae861c53c3e, []int{0}
+	return fileDescriptor_producer_81bb83bec3e9472d, []int{0}
 }
 func (m *Producer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Producer.Unmarshal(m, b)
@@ -64,19 +65,27 @@ func (m *Producer) GetTopic() string {
 	return ""
 }
 
+func (m *Producer) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Producer)(nil), "vitepb.Producer")
 }
 
-func init() { proto.RegisterFile("vitepb/producer.proto", fileDescriptor_producer_87d18ae861c53c3e) }
+func init() { proto.RegisterFile("vitepb/producer.proto", fileDescriptor_producer_81bb83bec3e9472d) }
 
-var fileDescriptor_producer_87d18ae861c53c3e = []byte{
-	// 104 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_producer_81bb83bec3e9472d = []byte{
+	// 116 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2d, 0xcb, 0x2c, 0x49,
 	0x2d, 0x48, 0xd2, 0x2f, 0x28, 0xca, 0x4f, 0x29, 0x4d, 0x4e, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0x62, 0x83, 0x08, 0x2b, 0x39, 0x70, 0x71, 0x04, 0x40, 0x65, 0x84, 0xe4, 0xb8, 0xb8,
+	0xc9, 0x17, 0x62, 0x83, 0x08, 0x2b, 0x45, 0x70, 0x71, 0x04, 0x40, 0x65, 0x84, 0xe4, 0xb8, 0xb8,
 	0x92, 0x8a, 0xf2, 0xb3, 0x53, 0x8b, 0x7c, 0x32, 0x8b, 0x4b, 0x24, 0x18, 0x15, 0x98, 0x35, 0x38,
 	0x83, 0x90, 0x44, 0x84, 0x44, 0xb8, 0x58, 0x4b, 0xf2, 0x0b, 0x32, 0x93, 0x25, 0x98, 0x14, 0x18,
-	0x35, 0x38, 0x83, 0x20, 0x9c, 0x24, 0x36, 0xb0, 0x81, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff,
-	0x4c, 0x8c, 0x3d, 0x70, 0x69, 0x00, 0x00, 0x00,
+	0x35, 0x38, 0x83, 0x20, 0x1c, 0x21, 0x31, 0x2e, 0xb6, 0xb4, 0xfc, 0xa2, 0xdc, 0xc4, 0x12, 0x09,
+	0x66, 0xb0, 0x30, 0x94, 0x97, 0xc4, 0x06, 0xb6, 0xc8, 0x18, 0x30, 0x00, 0x3b, 0x16, 0x25, 0x64,
+	0x81, 0x00, 0x00, 0x00,
 }
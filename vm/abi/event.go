@@ -76,3 +76,48 @@ func (e Event) Pack(args ...interface{}) ([]types.Hash, []byte, error) {
 	}
 
 }
+
+// UnpackLog returns the values of the inputs in order. The indexed inputs are read from topics, the ones of the
+// dynamic types are hashed in topics, so their topics are returned. The others are unpacked from data.
+func (e Event) UnpackLog(topics []types.Hash, data []byte) ([]interface{}, error) {
+	if !e.Anonymous {
+		if len(topics) <= 0 || topics[0] != e.Id() {
+			return nil, fmt.Errorf("abi: log is not event %v", e.Name)
+		}
+		topics = topics[1:]
+	}
+	if len(topics) != e.Inputs.LengthIndexed() {
+		return nil, fmt.Errorf("abi: topic count mismatch: %d for %d", len(topics), e.Inputs.LengthIndexed())
+	}
+
+	var nonIndexedValues []interface{}
+	if e.Inputs.LengthNonIndexed() > 0 {
+		var err error
+		if nonIndexedValues, err = e.Inputs.UnpackValues(data); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]interface{}, 0, len(e.Inputs))
+	for _, input := range e.Inputs {
+		if !input.Indexed {
+			values = append(values, nonIndexedValues[0])
+			nonIndexedValues = nonIndexedValues[1:]
+			continue
+		}
+
+		topic := topics[0]
+		topics = topics[1:]
+		switch input.Type.T {
+		case StringTy, BytesTy, SliceTy, ArrayTy:
+			values = append(values, topic)
+		default:
+			value, err := toGoType(0, input.Type, topic.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
@@ -342,3 +342,26 @@ func TestEventIndexedWithArrayUnpack(t *testing.T) {
 	require.Equal(t, [2]uint8{0, 0}, rst.Value1)
 	require.Equal(t, stringOut, rst.Value2)
 }
+
+// TestEventUnpackLog verifies that the indexed inputs are read from topics and the others from data.
+func TestEventUnpackLog(t *testing.T) {
+	definition := `[{"name": "test", "type": "event", "inputs": [{"indexed": true, "name":"from", "type":"address"},{"indexed": true, "name":"memo", "type":"string"},{"indexed": false, "name":"value", "type":"uint256"}]}]`
+	abi, err := JSONToABIContract(strings.NewReader(definition))
+	require.NoError(t, err)
+	from := types.Address{1, 2, 3}
+	value := big.NewInt(1000000)
+	topics, data, err := abi.Events["test"].Pack(from, "abc", value)
+	require.NoError(t, err)
+
+	event, err := abi.EventById(topics[0])
+	require.NoError(t, err)
+	require.Equal(t, "test", event.Name)
+	values, err := event.UnpackLog(topics, data)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{from, topics[2], value}, values)
+
+	_, err = event.UnpackLog(topics[:2], data)
+	require.Error(t, err)
+	_, err = abi.EventById(types.Hash{})
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/vitelabs/go-vite/common/types"
 	"github.com/vitelabs/go-vite/ledger"
 	"github.com/vitelabs/go-vite/trie"
+	"github.com/vitelabs/go-vite/vm_context/vmctxt_interface"
 )
 
 type Chain interface {
@@ -29,6 +30,11 @@ type Chain interface {
 	GetStateTrie(hash *types.Hash) *trie.Trie
 
 	NewStateTrie() *trie.Trie
+
+	// GetFlatStorage and NewFlatStorageIterator read the flat state, ok is false when the flat state is closed or
+	// doesn't reflect the snapshot block, then the state tries must be read.
+	GetFlatStorage(snapshotHash *types.Hash, addr *types.Address, key []byte) (value []byte, ok bool)
+	NewFlatStorageIterator(snapshotHash *types.Hash, addr *types.Address, prefix []byte) (iter vmctxt_interface.StorageIterator, ok bool)
 	GetConfirmAccountBlock(snapshotHeight uint64, address *types.Address) (*ledger.AccountBlock, error)
 	GetContractGid(addr *types.Address) (*types.Gid, error)
 
@@ -320,6 +320,10 @@ func (context *VmContext) GetStorage(addr *types.Address, key []byte) []byte {
 		}
 		return context.trie.GetValue(key)
 	} else if context.chain != nil {
+		if value, ok := context.chain.GetFlatStorage(&context.currentSnapshotBlock.Hash, addr, key); ok {
+			return value
+		}
+
 		snapshotTrie := context.getSnapshotTrie()
 		stateHashBytes := snapshotTrie.GetValue(addr.Bytes())
 
@@ -432,6 +436,10 @@ func (context *VmContext) NewStorageIterator(addr *types.Address, prefix []byte)
 	if context.isSelf(addr) {
 		return NewStorageIterator(context.unsavedCache.Trie(), prefix)
 	} else {
+		if iter, ok := context.chain.NewFlatStorageIterator(&context.currentSnapshotBlock.Hash, addr, prefix); ok {
+			return iter
+		}
+
 		snapshotTrie := context.getSnapshotTrie()
 		stateHashBytes := snapshotTrie.GetValue(addr.Bytes())
 
@@ -455,6 +463,9 @@ func (context *VmContext) GetStorageBySnapshotHash(addr *types.Address, key []by
 	if snapshotHash == nil || *snapshotHash == context.currentSnapshotBlock.Hash {
 		return context.GetStorage(addr, key)
 	}
+	if value, ok := context.chain.GetFlatStorage(snapshotHash, addr, key); ok {
+		return value
+	}
 	if snapshotBlock := context.GetSnapshotBlockByHash(snapshotHash); snapshotBlock != nil {
 
 		snapshotTrie := context.chain.GetStateTrie(&snapshotBlock.StateHash)
@@ -480,6 +491,9 @@ func (context *VmContext) NewStorageIteratorBySnapshotHash(addr *types.Address,
 	if snapshotHash == nil || *snapshotHash == context.currentSnapshotBlock.Hash {
 		return context.NewStorageIterator(addr, prefix)
 	}
+	if iter, ok := context.chain.NewFlatStorageIterator(snapshotHash, addr, prefix); ok {
+		return iter
+	}
 	if snapshotBlock := context.GetSnapshotBlockByHash(snapshotHash); snapshotBlock != nil {
 		snapshotTrie := context.chain.GetStateTrie(&snapshotBlock.StateHash)
 		if snapshotTrie == nil {